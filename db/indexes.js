@@ -32,6 +32,28 @@ db.users.createIndex(
 );
 print("Created index: users.created_at");
 
+// SSO identity lookup, one account per IdP subject
+db.users.createIndex(
+  { oidc_issuer: 1, oidc_subject: 1 },
+  { 
+    unique: true,
+    name: "oidc_identity_unique",
+    partialFilterExpression: { oidc_subject: { $exists: true } },
+    background: true 
+  }
+);
+print("Created index: users.oidc_issuer + oidc_subject (unique)");
+
+// Admin user list filtered by role
+db.users.createIndex(
+  { role: 1, created_at: -1 },
+  { 
+    name: "role_created_at",
+    background: true 
+  }
+);
+print("Created index: users.role + created_at");
+
 print("Users indexes completed.\n");
 
 // Tasks Collection Indexes
@@ -149,8 +171,314 @@ db.tasks.createIndex(
 );
 print("Created index: tasks.priority");
 
+// Workspace task lists
+db.tasks.createIndex(
+  { workspace_id: 1, created_at: -1 },
+  { 
+    name: "workspace_id_created_at",
+    partialFilterExpression: { workspace_id: { $exists: true } },
+    background: true 
+  }
+);
+print("Created index: tasks.workspace_id + created_at (partial)");
+
+// Tasks assigned to a user (access checks, assigned_to=me)
+db.tasks.createIndex(
+  { assignee_ids: 1, created_at: -1 },
+  { 
+    name: "assignee_ids_created_at",
+    background: true 
+  }
+);
+print("Created index: tasks.assignee_ids + created_at");
+
+// Subtasks of a task, oldest first (listing, progress roll-up, cascade delete)
+db.tasks.createIndex(
+  { parent_id: 1, created_at: 1 },
+  { 
+    name: "parent_id_created_at",
+    partialFilterExpression: { parent_id: { $exists: true } },
+    background: true 
+  }
+);
+print("Created index: tasks.parent_id + created_at (partial)");
+
+// Tasks waiting for a blocker (dependency graph, cleanup on delete)
+db.tasks.createIndex(
+  { blocked_by: 1 },
+  { 
+    name: "blocked_by_1",
+    partialFilterExpression: { blocked_by: { $exists: true } },
+    background: true 
+  }
+);
+print("Created index: tasks.blocked_by (partial)");
+
+// Trash (scheduled purge of expired tasks)
+db.tasks.createIndex(
+  { deleted_at: 1 },
+  { 
+    name: "deleted_at_1",
+    partialFilterExpression: { deleted_at: { $exists: true } },
+    background: true 
+  }
+);
+print("Created index: tasks.deleted_at (partial)");
+
+// Auto-archive of tasks completed long ago
+db.tasks.createIndex(
+  { status: 1, completed_at: 1 },
+  { 
+    name: "status_completed_at",
+    partialFilterExpression: { completed_at: { $exists: true } },
+    background: true 
+  }
+);
+print("Created index: tasks.status + completed_at (partial)");
+
 print("Tasks indexes completed.\n");
 
+// Refresh Tokens Collection Indexes
+print("Creating indexes for refresh_tokens collection...");
+
+// Lookup by token hash on every refresh
+db.refresh_tokens.createIndex(
+  { token_hash: 1 },
+  { 
+    unique: true, 
+    name: "token_hash_unique",
+    background: true 
+  }
+);
+print("Created index: refresh_tokens.token_hash (unique)");
+
+// Family-wide revocation on reuse detection
+db.refresh_tokens.createIndex(
+  { family_id: 1 },
+  { 
+    name: "family_id_1",
+    background: true 
+  }
+);
+print("Created index: refresh_tokens.family_id");
+
+// Remove tokens once they expire
+db.refresh_tokens.createIndex(
+  { expires_at: 1 },
+  { 
+    name: "expires_at_ttl",
+    expireAfterSeconds: 0,
+    background: true 
+  }
+);
+print("Created index: refresh_tokens.expires_at (TTL)");
+
+print("Refresh tokens indexes completed.\n");
+
+// Revoked Tokens Collection Indexes
+print("Creating indexes for revoked_tokens collection...");
+
+// Entries are keyed by jti (or "user:<id>"), drop them once the tokens they revoke have expired
+db.revoked_tokens.createIndex(
+  { expires_at: 1 },
+  { 
+    name: "expires_at_ttl",
+    expireAfterSeconds: 0,
+    background: true 
+  }
+);
+print("Created index: revoked_tokens.expires_at (TTL)");
+
+print("Revoked tokens indexes completed.\n");
+
+// Password Resets Collection Indexes
+print("Creating indexes for password_resets collection...");
+
+// Lookup by token hash when a reset link is used
+db.password_resets.createIndex(
+  { token_hash: 1 },
+  { 
+    unique: true, 
+    name: "token_hash_unique",
+    background: true 
+  }
+);
+print("Created index: password_resets.token_hash (unique)");
+
+// Invalidate older links when a new one is requested
+db.password_resets.createIndex(
+  { user_id: 1 },
+  { 
+    name: "user_id_1",
+    background: true 
+  }
+);
+print("Created index: password_resets.user_id");
+
+// Remove tokens once they expire
+db.password_resets.createIndex(
+  { expires_at: 1 },
+  { 
+    name: "expires_at_ttl",
+    expireAfterSeconds: 0,
+    background: true 
+  }
+);
+print("Created index: password_resets.expires_at (TTL)");
+
+print("Password resets indexes completed.\n");
+
+// Login Attempts Collection Indexes
+print("Creating indexes for login_attempts collection...");
+
+// Remove counters once their window and lock have passed
+db.login_attempts.createIndex(
+  { expires_at: 1 },
+  { 
+    name: "expires_at_ttl",
+    expireAfterSeconds: 0,
+    background: true 
+  }
+);
+print("Created index: login_attempts.expires_at (TTL)");
+
+print("Login attempts indexes completed.\n");
+
+// Personal Access Tokens Collection Indexes
+print("Creating indexes for personal_access_tokens collection...");
+
+// Authenticate requests by token hash
+db.personal_access_tokens.createIndex(
+  { token_hash: 1 },
+  { 
+    unique: true,
+    name: "token_hash_unique",
+    background: true 
+  }
+);
+print("Created index: personal_access_tokens.token_hash (unique)");
+
+// List tokens of a user, newest first
+db.personal_access_tokens.createIndex(
+  { user_id: 1, created_at: -1 },
+  { 
+    name: "user_id_1_created_at_-1",
+    background: true 
+  }
+);
+print("Created index: personal_access_tokens.user_id + created_at");
+
+// Remove tokens once they expire (tokens without expires_at are kept)
+db.personal_access_tokens.createIndex(
+  { expires_at: 1 },
+  { 
+    name: "expires_at_ttl",
+    expireAfterSeconds: 0,
+    background: true 
+  }
+);
+print("Created index: personal_access_tokens.expires_at (TTL)");
+
+print("Personal access tokens indexes completed.\n");
+
+// OIDC States Collection Indexes
+print("Creating indexes for oidc_states collection...");
+
+// Remove abandoned SSO logins (_id is the state hash)
+db.oidc_states.createIndex(
+  { expires_at: 1 },
+  { 
+    name: "expires_at_ttl",
+    expireAfterSeconds: 0,
+    background: true 
+  }
+);
+print("Created index: oidc_states.expires_at (TTL)");
+
+print("OIDC states indexes completed.\n");
+
+// Workspace Members Collection Indexes
+print("Creating indexes for workspace_members collection...");
+
+// One membership per user and workspace
+db.workspace_members.createIndex(
+  { workspace_id: 1, user_id: 1 },
+  { 
+    name: "workspace_id_user_id_unique",
+    unique: true,
+    background: true 
+  }
+);
+print("Created index: workspace_members.workspace_id + user_id (unique)");
+
+// Workspaces of a user (task access checks)
+db.workspace_members.createIndex(
+  { user_id: 1 },
+  { 
+    name: "user_id_1",
+    background: true 
+  }
+);
+print("Created index: workspace_members.user_id");
+
+print("Workspace members indexes completed.\n");
+
+// Task Shares Collection Indexes
+print("Creating indexes for task_shares collection...");
+
+// One share per task and user
+db.task_shares.createIndex(
+  { task_id: 1, user_id: 1 },
+  { 
+    name: "task_id_user_id_unique",
+    unique: true,
+    background: true 
+  }
+);
+print("Created index: task_shares.task_id + user_id (unique)");
+
+// "Shared with me" listing
+db.task_shares.createIndex(
+  { user_id: 1, created_at: -1 },
+  { 
+    name: "user_id_created_at",
+    background: true 
+  }
+);
+print("Created index: task_shares.user_id + created_at");
+
+print("Task shares indexes completed.\n");
+
+// Comments Collection Indexes
+print("Creating indexes for comments collection...");
+
+// Thread of a task, _id is the pagination cursor
+db.comments.createIndex(
+  { task_id: 1, _id: 1 },
+  { 
+    name: "task_id_id",
+    background: true 
+  }
+);
+print("Created index: comments.task_id + _id");
+
+print("Comments indexes completed.\n");
+
+// Task Events Collection Indexes
+print("Creating indexes for task_events collection...");
+
+// History of a task newest first, _id is the pagination cursor
+db.task_events.createIndex(
+  { task_id: 1, _id: -1 },
+  { 
+    name: "task_id_id_desc",
+    background: true 
+  }
+);
+print("Created index: task_events.task_id + _id (desc)");
+
+print("Task events indexes completed.\n");
+
 // Verify created indexes
 print("===============================================");
 print("Verification");
@@ -162,6 +490,36 @@ printjson(db.users.getIndexes());
 print("\nTasks collection indexes:");
 printjson(db.tasks.getIndexes());
 
+print("\nRefresh tokens collection indexes:");
+printjson(db.refresh_tokens.getIndexes());
+
+print("\nRevoked tokens collection indexes:");
+printjson(db.revoked_tokens.getIndexes());
+
+print("\nPassword resets collection indexes:");
+printjson(db.password_resets.getIndexes());
+
+print("\nLogin attempts collection indexes:");
+printjson(db.login_attempts.getIndexes());
+
+print("\nPersonal access tokens collection indexes:");
+printjson(db.personal_access_tokens.getIndexes());
+
+print("\nOIDC states collection indexes:");
+printjson(db.oidc_states.getIndexes());
+
+print("\nWorkspace members collection indexes:");
+printjson(db.workspace_members.getIndexes());
+
+print("\nTask shares collection indexes:");
+printjson(db.task_shares.getIndexes());
+
+print("\nComments collection indexes:");
+printjson(db.comments.getIndexes());
+
+print("\nTask events collection indexes:");
+printjson(db.task_events.getIndexes());
+
 print("\n===============================================");
 print("Index creation completed successfully");
 print("===============================================");
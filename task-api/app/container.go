@@ -4,45 +4,133 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo"
 
 	"task-api/handlers"
+	"task-api/mailer"
+	"task-api/oidc"
 	"task-api/repositories"
 	"task-api/services"
+	"task-api/storage"
 )
 
 // Container - holds all dependencies
 type Container struct {
   // Repositories
-  UserRepo repositories.UserRepository
-  TaskRepo repositories.TaskRepository
+  UserRepo         repositories.UserRepository
+  RefreshTokenRepo repositories.RefreshTokenRepository
+  RevocationStore  repositories.RevocationStore
+  ResetRepo        repositories.PasswordResetRepository
+  LoginAttempts    repositories.LoginAttemptStore
+  TokenRepo        repositories.PersonalAccessTokenRepository
+  OIDCStateRepo    repositories.OIDCStateRepository
+  TaskRepo         repositories.TaskRepository
+  WorkspaceRepo    repositories.WorkspaceRepository
+  TaskShareRepo    repositories.TaskShareRepository
+  CommentRepo      repositories.CommentRepository
+  TaskEventRepo    repositories.TaskEventRepository
+
+  // Infrastructure
+  Mailer       mailer.Mailer
+  OIDCProvider oidc.Provider // nil when OIDC_ISSUER is not set
+  BlobStore    storage.BlobStore
 
   // Services
-  AuthService services.AuthService
-  TaskService services.TaskService
+  AuthService       services.AuthService
+  TokenService      services.PersonalAccessTokenService
+  OIDCService       services.OIDCService
+  TaskService       services.TaskService
+  WorkspaceService  services.WorkspaceService
+  AdminService      services.AdminService
+  CommentService    services.CommentService
+  AttachmentService services.AttachmentService
+  TaskPurger        services.TaskPurger
+  TaskArchiver      services.TaskArchiver
 
   // Handlers
-  AuthHandler   *handlers.AuthHandler
-  TaskHandler   *handlers.TaskHandler
+  AuthHandler       *handlers.AuthHandler
+  TokenHandler      *handlers.TokenHandler
+  OIDCHandler       *handlers.OIDCHandler
+  TaskHandler       *handlers.TaskHandler
+  WorkspaceHandler  *handlers.WorkspaceHandler
+  AdminHandler      *handlers.AdminHandler
+  CommentHandler    *handlers.CommentHandler
+  AttachmentHandler *handlers.AttachmentHandler
 }
 
 // NewContainer - initialize all dependencies
 func NewContainer(db *mongo.Database) *Container {
   // Initialize repositories
   userRepo := repositories.NewUserRepository(db)
+  refreshTokenRepo := repositories.NewRefreshTokenRepository(db)
+  revocationStore := repositories.NewMongoRevocationStore(db)
+  resetRepo := repositories.NewPasswordResetRepository(db)
+  loginAttempts := repositories.NewMongoLoginAttemptStore(db)
+  tokenRepo := repositories.NewPersonalAccessTokenRepository(db)
+  oidcStateRepo := repositories.NewOIDCStateRepository(db)
   taskRepo := repositories.NewTaskRepository(db)
+  workspaceRepo := repositories.NewWorkspaceRepository(db)
+  taskShareRepo := repositories.NewTaskShareRepository(db)
+  commentRepo := repositories.NewCommentRepository(db)
+  taskEventRepo := repositories.NewTaskEventRepository(db)
+
+  // Initialize infrastructure
+  mailSender := mailer.NewMailerFromEnv()
+  oidcProvider := oidc.NewProviderFromEnv()
+  blobStore := storage.NewBlobStoreFromEnv(db)
 
   // Initialize services
-  authService := services.NewAuthService(userRepo)
-  taskService := services.NewTaskService(taskRepo)
+  authService := services.NewAuthService(userRepo, refreshTokenRepo, revocationStore, resetRepo, mailSender, loginAttempts)
+  tokenService := services.NewPersonalAccessTokenService(tokenRepo, userRepo)
+  oidcService := services.NewOIDCService(oidcProvider, oidcStateRepo, userRepo, authService)
+  taskPurger := services.NewTaskPurger(taskRepo, taskShareRepo, commentRepo, taskEventRepo, blobStore)
+  taskArchiver := services.NewTaskArchiver(taskRepo)
+  taskService := services.NewTaskService(taskRepo, workspaceRepo, userRepo, taskShareRepo, taskEventRepo, taskPurger)
+  workspaceService := services.NewWorkspaceService(workspaceRepo, userRepo, taskRepo)
+  adminService := services.NewAdminService(userRepo, authService, taskService)
+  commentService := services.NewCommentService(commentRepo, taskRepo, taskShareRepo, userRepo)
+  attachmentService := services.NewAttachmentService(taskRepo, taskShareRepo, taskEventRepo, blobStore)
 
   // Initialize handlers
   authHandler := handlers.NewAuthHandler(authService)
+  tokenHandler := handlers.NewTokenHandler(tokenService)
+  oidcHandler := handlers.NewOIDCHandler(oidcService)
   taskHandler := handlers.NewTaskHandler(taskService)
+  workspaceHandler := handlers.NewWorkspaceHandler(workspaceService)
+  adminHandler := handlers.NewAdminHandler(adminService)
+  commentHandler := handlers.NewCommentHandler(commentService)
+  attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
 
   return &Container{
-    UserRepo:    userRepo,
-    TaskRepo:    taskRepo,
-    AuthService: authService,
-    TaskService: taskService,
-    AuthHandler: authHandler,
-    TaskHandler: taskHandler,
+    UserRepo:          userRepo,
+    RefreshTokenRepo:  refreshTokenRepo,
+    RevocationStore:   revocationStore,
+    ResetRepo:         resetRepo,
+    LoginAttempts:     loginAttempts,
+    TokenRepo:         tokenRepo,
+    OIDCStateRepo:     oidcStateRepo,
+    TaskRepo:          taskRepo,
+    WorkspaceRepo:     workspaceRepo,
+    TaskShareRepo:     taskShareRepo,
+    CommentRepo:       commentRepo,
+    TaskEventRepo:     taskEventRepo,
+    Mailer:            mailSender,
+    OIDCProvider:      oidcProvider,
+    BlobStore:         blobStore,
+    AuthService:       authService,
+    TokenService:      tokenService,
+    OIDCService:       oidcService,
+    TaskService:       taskService,
+    WorkspaceService:  workspaceService,
+    AdminService:      adminService,
+    CommentService:    commentService,
+    AttachmentService: attachmentService,
+    TaskPurger:        taskPurger,
+    TaskArchiver:      taskArchiver,
+    AuthHandler:       authHandler,
+    TokenHandler:      tokenHandler,
+    OIDCHandler:       oidcHandler,
+    TaskHandler:       taskHandler,
+    WorkspaceHandler:  workspaceHandler,
+    AdminHandler:      adminHandler,
+    CommentHandler:    commentHandler,
+    AttachmentHandler: attachmentHandler,
   }
 }
@@ -3,6 +3,9 @@ package configs
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
+
 	"github.com/joho/godotenv"
 )
 
@@ -20,3 +23,33 @@ func GetEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// GetDuration - read a duration (e.g. "15m", "720h") from env, falling back to defaultValue
+func GetDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("Warning: invalid duration for %s: %q, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// GetInt - read a positive integer from env, falling back to defaultValue
+func GetInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		log.Printf("Warning: invalid integer for %s: %q, using %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return number
+}
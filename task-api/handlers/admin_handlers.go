@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+	"task-api/utils"
+)
+
+// AdminHandler - struct for user administration handlers
+type AdminHandler struct {
+  adminService services.AdminService
+}
+
+// NewAdminHandler - constructor
+func NewAdminHandler(adminService services.AdminService) *AdminHandler {
+  return &AdminHandler{
+    adminService: adminService,
+  }
+}
+
+// ListUsers - GET /admin/users
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+  var query types.UserQueryParams
+
+  if err := c.ShouldBindQuery(&query); err != nil {
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+
+  response, err := h.adminService.ListUsers(ctx, query)
+  if err != nil {
+    log.Error().Err(err).Msg("Failed to list users")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgUsersRetrieved, response)
+}
+
+// DisableUser - POST /admin/users/:id/disable
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+  userID, ok := parseUserID(c)
+  if !ok {
+    return
+  }
+
+  actorID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.adminService.DisableUser(ctx, actorID.(bson.ObjectID), userID)
+  if err != nil {
+    h.handleError(c, err, "Failed to disable user")
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgUserDisabled, gin.H{"user": response})
+}
+
+// EnableUser - POST /admin/users/:id/enable
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+  userID, ok := parseUserID(c)
+  if !ok {
+    return
+  }
+
+  actorID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.adminService.EnableUser(ctx, actorID.(bson.ObjectID), userID)
+  if err != nil {
+    h.handleError(c, err, "Failed to enable user")
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgUserEnabled, gin.H{"user": response})
+}
+
+// UpdateUserRole - PATCH /admin/users/:id/role
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+  userID, ok := parseUserID(c)
+  if !ok {
+    return
+  }
+
+  var input types.UpdateUserRoleInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, http.StatusBadRequest, "Request body required", gin.H{
+        "error": "Please provide role",
+      })
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+
+  actorID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.adminService.UpdateUserRole(ctx, actorID.(bson.ObjectID), userID, input.Role)
+  if err != nil {
+    h.handleError(c, err, "Failed to update user role")
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgRoleUpdated, gin.H{"user": response})
+}
+
+// ListUserTasks - GET /admin/users/:id/tasks
+func (h *AdminHandler) ListUserTasks(c *gin.Context) {
+  userID, ok := parseUserID(c)
+  if !ok {
+    return
+  }
+
+  var query types.TaskQueryParams
+  if err := c.ShouldBindQuery(&query); err != nil {
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+
+  response, err := h.adminService.ListUserTasks(ctx, userID, query)
+  if err != nil {
+    h.handleError(c, err, "Failed to list user tasks")
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgTasksRetrieved, response)
+}
+
+// handleError - map admin service errors to responses
+func (h *AdminHandler) handleError(c *gin.Context, err error, msg string) {
+  switch {
+  case errors.Is(err, services.ErrUserNotFound):
+    utils.Fail(c, http.StatusNotFound, types.MsgUserNotFound, nil)
+  case errors.Is(err, services.ErrWorkspaceNotFound):
+    utils.Fail(c, http.StatusNotFound, types.MsgWorkspaceNotFound, nil)
+  case errors.Is(err, services.ErrCannotModifySelf):
+    utils.Fail(c, http.StatusConflict, types.MsgCannotModifySelf, nil)
+  default:
+    log.Error().Err(err).Msg(msg)
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+  }
+}
+
+// parseUserID - read :id path param, responds 400 when malformed
+func parseUserID(c *gin.Context) (bson.ObjectID, bool) {
+  userID, err := bson.ObjectIDFromHex(c.Param("id"))
+  if err != nil {
+    utils.Fail(c, http.StatusBadRequest, "Invalid user ID", gin.H{"error": "Invalid ID format"})
+    return bson.ObjectID{}, false
+  }
+  return userID, true
+}
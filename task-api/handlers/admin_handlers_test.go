@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/middleware"
+	"task-api/services"
+	"task-api/types"
+)
+
+// MockAdminService mocks the AdminService interface
+type MockAdminService struct {
+  mock.Mock
+}
+
+func (m *MockAdminService) ListUsers(ctx context.Context, query types.UserQueryParams) (*types.UserListResponse, error) {
+  args := m.Called(ctx, query)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.UserListResponse), args.Error(1)
+}
+
+func (m *MockAdminService) DisableUser(ctx context.Context, actorID, userID bson.ObjectID) (*types.AdminUserResponse, error) {
+  args := m.Called(ctx, actorID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.AdminUserResponse), args.Error(1)
+}
+
+func (m *MockAdminService) EnableUser(ctx context.Context, actorID, userID bson.ObjectID) (*types.AdminUserResponse, error) {
+  args := m.Called(ctx, actorID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.AdminUserResponse), args.Error(1)
+}
+
+func (m *MockAdminService) UpdateUserRole(ctx context.Context, actorID, userID bson.ObjectID, role string) (*types.AdminUserResponse, error) {
+  args := m.Called(ctx, actorID, userID, role)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.AdminUserResponse), args.Error(1)
+}
+
+func (m *MockAdminService) ListUserTasks(ctx context.Context, userID bson.ObjectID, query types.TaskQueryParams) (*types.TaskListResponse, error) {
+  args := m.Called(ctx, userID, query)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskListResponse), args.Error(1)
+}
+
+// withRole - session of a user with the given role
+func withRole(userID bson.ObjectID, role string) gin.HandlerFunc {
+  return func(c *gin.Context) {
+    c.Set("userID", userID)
+    c.Set("userRole", role)
+    c.Set("tokenID", "jti")
+    c.Set("tokenExpiresAt", time.Now().Add(time.Hour))
+    c.Next()
+  }
+}
+
+// setupAdminRouter - admin routes guarded like in production
+func setupAdminRouter(handler *AdminHandler, actorID bson.ObjectID, role string) *gin.Engine {
+  router := setupRouter()
+  admin := router.Group("/admin", withRole(actorID, role), middleware.RequireRole(types.RoleAdmin))
+  admin.GET("/users", handler.ListUsers)
+  admin.POST("/users/:id/disable", handler.DisableUser)
+  admin.PATCH("/users/:id/role", handler.UpdateUserRole)
+  admin.GET("/users/:id/tasks", handler.ListUserTasks)
+  return router
+}
+
+func TestAdminHandler_Authorization(t *testing.T) {
+  userID := bson.NewObjectID()
+  requests := []struct {
+    method string
+    path   string
+  }{
+    {"GET", "/admin/users"},
+    {"POST", "/admin/users/" + userID.Hex() + "/disable"},
+    {"PATCH", "/admin/users/" + userID.Hex() + "/role"},
+    {"GET", "/admin/users/" + userID.Hex() + "/tasks"},
+  }
+
+  for _, role := range []string{types.RoleMember, types.RoleViewer, ""} {
+    t.Run("should forbid role "+role, func(t *testing.T) {
+      mockService := new(MockAdminService)
+      router := setupAdminRouter(NewAdminHandler(mockService), bson.NewObjectID(), role)
+
+      for _, r := range requests {
+        req, _ := http.NewRequest(r.method, r.path, bytes.NewBufferString(`{"role":"admin"}`))
+        req.Header.Set("Content-Type", "application/json")
+        w := httptest.NewRecorder()
+        router.ServeHTTP(w, req)
+
+        assert.Equal(t, http.StatusForbidden, w.Code, r.method+" "+r.path)
+      }
+      assert.Empty(t, mockService.Calls)
+    })
+  }
+}
+
+func TestAdminHandler_ListUsers(t *testing.T) {
+  t.Run("should list users for admin", func(t *testing.T) {
+    mockService := new(MockAdminService)
+    router := setupAdminRouter(NewAdminHandler(mockService), bson.NewObjectID(), types.RoleAdmin)
+
+    mockService.On("ListUsers", mock.Anything, types.UserQueryParams{Status: "disabled"}).Return(&types.UserListResponse{
+      Users: []types.AdminUserResponse{{UserResponse: types.UserResponse{Email: "user@test.com"}, Disabled: true}},
+    }, nil)
+
+    req, _ := http.NewRequest("GET", "/admin/users?status=disabled", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "user@test.com")
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should reject unknown role filter", func(t *testing.T) {
+    router := setupAdminRouter(NewAdminHandler(new(MockAdminService)), bson.NewObjectID(), types.RoleAdmin)
+
+    req, _ := http.NewRequest("GET", "/admin/users?role=owner", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+}
+
+func TestAdminHandler_DisableUser(t *testing.T) {
+  t.Run("should disable user as acting admin", func(t *testing.T) {
+    mockService := new(MockAdminService)
+    adminID, userID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupAdminRouter(NewAdminHandler(mockService), adminID, types.RoleAdmin)
+
+    mockService.On("DisableUser", mock.Anything, adminID, userID).
+      Return(&types.AdminUserResponse{UserResponse: types.UserResponse{ID: userID.Hex()}, Disabled: true}, nil)
+
+    req, _ := http.NewRequest("POST", "/admin/users/"+userID.Hex()+"/disable", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), `"disabled":true`)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should map service errors", func(t *testing.T) {
+    cases := []struct {
+      err    error
+      status int
+    }{
+      {services.ErrUserNotFound, http.StatusNotFound},
+      {services.ErrCannotModifySelf, http.StatusConflict},
+    }
+
+    for _, tc := range cases {
+      mockService := new(MockAdminService)
+      router := setupAdminRouter(NewAdminHandler(mockService), bson.NewObjectID(), types.RoleAdmin)
+      mockService.On("DisableUser", mock.Anything, mock.Anything, mock.Anything).Return(nil, tc.err)
+
+      req, _ := http.NewRequest("POST", "/admin/users/"+bson.NewObjectID().Hex()+"/disable", nil)
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, req)
+
+      assert.Equal(t, tc.status, w.Code, tc.err.Error())
+    }
+  })
+
+  t.Run("should return 400 for invalid ID", func(t *testing.T) {
+    router := setupAdminRouter(NewAdminHandler(new(MockAdminService)), bson.NewObjectID(), types.RoleAdmin)
+
+    req, _ := http.NewRequest("POST", "/admin/users/invalid/disable", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+}
+
+func TestAdminHandler_UpdateUserRole(t *testing.T) {
+  t.Run("should update role", func(t *testing.T) {
+    mockService := new(MockAdminService)
+    adminID, userID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupAdminRouter(NewAdminHandler(mockService), adminID, types.RoleAdmin)
+
+    mockService.On("UpdateUserRole", mock.Anything, adminID, userID, types.RoleViewer).
+      Return(&types.AdminUserResponse{UserResponse: types.UserResponse{Role: types.RoleViewer}}, nil)
+
+    req, _ := http.NewRequest("PATCH", "/admin/users/"+userID.Hex()+"/role", bytes.NewBufferString(`{"role":"viewer"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should reject unknown role", func(t *testing.T) {
+    router := setupAdminRouter(NewAdminHandler(new(MockAdminService)), bson.NewObjectID(), types.RoleAdmin)
+
+    req, _ := http.NewRequest("PATCH", "/admin/users/"+bson.NewObjectID().Hex()+"/role", bytes.NewBufferString(`{"role":"owner"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+}
+
+func TestAdminHandler_ListUserTasks(t *testing.T) {
+  t.Run("should return tasks of user", func(t *testing.T) {
+    mockService := new(MockAdminService)
+    userID := bson.NewObjectID()
+    router := setupAdminRouter(NewAdminHandler(mockService), bson.NewObjectID(), types.RoleAdmin)
+
+    mockService.On("ListUserTasks", mock.Anything, userID, types.TaskQueryParams{Status: "pending"}).
+      Return(&types.TaskListResponse{Tasks: []types.TaskResponse{{Title: "Someone else's task"}}}, nil)
+
+    req, _ := http.NewRequest("GET", "/admin/users/"+userID.Hex()+"/tasks?status=pending", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "Someone else's task")
+    mockService.AssertExpectations(t)
+  })
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/types"
+	"task-api/utils"
+)
+
+// ArchiveTask - POST /tasks/:id/archive
+func (h *TaskHandler) ArchiveTask(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.ArchiveTask(ctx, taskID, userID.(bson.ObjectID))
+  if err != nil {
+    h.handleError(c, err, "Failed to archive task")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgTaskArchived, gin.H{"task": response})
+}
+
+// UnarchiveTask - POST /tasks/:id/unarchive
+func (h *TaskHandler) UnarchiveTask(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.UnarchiveTask(ctx, taskID, userID.(bson.ObjectID))
+  if err != nil {
+    h.handleError(c, err, "Failed to unarchive task")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgTaskUnarchived, gin.H{"task": response})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+func TestTaskHandler_ArchiveTask(t *testing.T) {
+  t.Run("should archive task", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("ArchiveTask", mock.Anything, taskID, userID).Return(&types.TaskResponse{ID: taskID.Hex()}, nil)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/archive", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgTaskArchived)
+  })
+
+  t.Run("should return conflict when already archived", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("ArchiveTask", mock.Anything, taskID, userID).Return(nil, services.ErrTaskArchived)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/archive", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusConflict, w.Code)
+  })
+}
+
+func TestTaskHandler_UnarchiveTask(t *testing.T) {
+  t.Run("should return conflict when not archived", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("UnarchiveTask", mock.Anything, taskID, userID).Return(nil, services.ErrTaskNotArchived)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/unarchive", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusConflict, w.Code)
+  })
+}
+
+func TestTaskHandler_GetTasksArchived(t *testing.T) {
+  t.Run("should pass the archived filter", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID := bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("GetTasks", mock.Anything, userID, types.TaskQueryParams{Archived: types.ArchivedOnly}).Return(&types.TaskListResponse{Tasks: []types.TaskResponse{}}, nil)
+
+    req, _ := http.NewRequest("GET", "/tasks?archived=true", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should reject unknown archived filter", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    req, _ := http.NewRequest("GET", "/tasks?archived=maybe", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "GetTasks", mock.Anything, mock.Anything, mock.Anything)
+  })
+}
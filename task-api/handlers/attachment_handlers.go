@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+	"task-api/utils"
+)
+
+// multipartOverhead - room for multipart headers and boundaries on top of the file size limit
+const multipartOverhead = 1 << 20
+
+// AttachmentHandler - struct for task attachment handlers
+type AttachmentHandler struct {
+  attachmentService services.AttachmentService
+  maxSize           int64
+}
+
+// NewAttachmentHandler - constructor
+func NewAttachmentHandler(attachmentService services.AttachmentService) *AttachmentHandler {
+  return &AttachmentHandler{
+    attachmentService: attachmentService,
+    maxSize:           services.AttachmentMaxSize(),
+  }
+}
+
+// UploadAttachment - POST /tasks/:id/attachments (multipart, field "file")
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+
+  // Stop reading bodies far over the limit, the service checks the exact file size
+  c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+
+  fileHeader, err := c.FormFile("file")
+  if err != nil {
+    var tooLarge *http.MaxBytesError
+    if errors.As(err, &tooLarge) {
+      utils.Fail(c, http.StatusRequestEntityTooLarge, types.MsgAttachmentTooLarge, gin.H{"max_size": h.maxSize})
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{"error": "Please provide a file in the file field"})
+    return
+  }
+
+  if fileHeader.Size > h.maxSize {
+    utils.Fail(c, http.StatusRequestEntityTooLarge, types.MsgAttachmentTooLarge, gin.H{"max_size": h.maxSize})
+    return
+  }
+
+  file, err := fileHeader.Open()
+  if err != nil {
+    log.Error().Err(err).Msg("Failed to open uploaded file")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+  defer file.Close()
+
+  userID, _ := c.Get("userID")
+
+  attachment, err := h.attachmentService.UploadAttachment(c.Request.Context(), taskID, userID.(bson.ObjectID), fileHeader.Filename, file)
+  if err != nil {
+    h.handleError(c, err, "Failed to upload attachment")
+    return
+  }
+
+  utils.Success(c, http.StatusCreated, types.MsgAttachmentUploaded, gin.H{"attachment": attachment})
+}
+
+// DownloadAttachment - GET /tasks/:id/attachments/:aid
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+  taskID, attachmentID, ok := parseAttachmentIDs(c)
+  if !ok {
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  // No timeout here, large files may take a while to stream
+  attachment, reader, err := h.attachmentService.OpenAttachment(c.Request.Context(), taskID, attachmentID, userID.(bson.ObjectID))
+  if err != nil {
+    h.handleError(c, err, "Failed to download attachment")
+    return
+  }
+  defer reader.Close()
+
+  disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})
+  if disposition == "" {
+    disposition = "attachment"
+  }
+
+  c.Header("X-Content-Type-Options", "nosniff")
+  c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, reader, map[string]string{
+    "Content-Disposition": disposition,
+  })
+}
+
+// DeleteAttachment - DELETE /tasks/:id/attachments/:aid
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+  taskID, attachmentID, ok := parseAttachmentIDs(c)
+  if !ok {
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  if err := h.attachmentService.DeleteAttachment(ctx, taskID, attachmentID, userID.(bson.ObjectID)); err != nil {
+    h.handleError(c, err, "Failed to delete attachment")
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgAttachmentDeleted, nil)
+}
+
+// handleError - map service errors to responses
+func (h *AttachmentHandler) handleError(c *gin.Context, err error, msg string) {
+  switch {
+  case errors.Is(err, services.ErrTaskNotFound):
+    utils.Fail(c, http.StatusNotFound, types.MsgTaskNotFound, nil)
+  case errors.Is(err, services.ErrTaskPermissionDenied):
+    utils.Fail(c, http.StatusForbidden, types.MsgTaskPermissionDenied, nil)
+  case errors.Is(err, services.ErrAttachmentNotFound):
+    utils.Fail(c, http.StatusNotFound, types.MsgAttachmentNotFound, nil)
+  case errors.Is(err, services.ErrAttachmentTooLarge):
+    utils.Fail(c, http.StatusRequestEntityTooLarge, types.MsgAttachmentTooLarge, gin.H{"max_size": h.maxSize})
+  case errors.Is(err, services.ErrAttachmentType):
+    utils.Fail(c, http.StatusUnsupportedMediaType, types.MsgAttachmentType, nil)
+  case errors.Is(err, services.ErrTooManyAttachments):
+    utils.Fail(c, http.StatusUnprocessableEntity, types.MsgTooManyAttachments, gin.H{"max_attachments": services.MaxAttachments})
+  default:
+    log.Error().Err(err).Msg(msg)
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+  }
+}
+
+// parseAttachmentIDs - read :id and :aid path params, responds 400 when malformed
+func parseAttachmentIDs(c *gin.Context) (bson.ObjectID, bson.ObjectID, bool) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return bson.ObjectID{}, bson.ObjectID{}, false
+  }
+
+  attachmentID, err := bson.ObjectIDFromHex(c.Param("aid"))
+  if err != nil {
+    utils.Fail(c, http.StatusBadRequest, "Invalid attachment ID", gin.H{"error": "Invalid ID format"})
+    return bson.ObjectID{}, bson.ObjectID{}, false
+  }
+  return taskID, attachmentID, true
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+// MockAttachmentService - mock for testing
+type MockAttachmentService struct {
+  mock.Mock
+}
+
+func (m *MockAttachmentService) UploadAttachment(ctx context.Context, taskID, userID bson.ObjectID, name string, content io.Reader) (*types.AttachmentResponse, error) {
+  args := m.Called(ctx, taskID, userID, name, content)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.AttachmentResponse), args.Error(1)
+}
+
+func (m *MockAttachmentService) OpenAttachment(ctx context.Context, taskID, attachmentID, userID bson.ObjectID) (*types.AttachmentResponse, io.ReadCloser, error) {
+  args := m.Called(ctx, taskID, attachmentID, userID)
+  if args.Get(0) == nil {
+    return nil, nil, args.Error(2)
+  }
+  return args.Get(0).(*types.AttachmentResponse), args.Get(1).(io.ReadCloser), args.Error(2)
+}
+
+func (m *MockAttachmentService) DeleteAttachment(ctx context.Context, taskID, attachmentID, userID bson.ObjectID) error {
+  args := m.Called(ctx, taskID, attachmentID, userID)
+  return args.Error(0)
+}
+
+// setupAttachmentRouter - attachment routes with a small size limit, authenticated as userID
+func setupAttachmentRouter(mockService *MockAttachmentService, userID bson.ObjectID) *gin.Engine {
+  handler := NewAttachmentHandler(mockService)
+  handler.maxSize = 64
+  router := setupRouter()
+  router.Use(func(c *gin.Context) {
+    c.Set("userID", userID)
+    c.Next()
+  })
+  router.POST("/tasks/:id/attachments", handler.UploadAttachment)
+  router.GET("/tasks/:id/attachments/:aid", handler.DownloadAttachment)
+  router.DELETE("/tasks/:id/attachments/:aid", handler.DeleteAttachment)
+  return router
+}
+
+// multipartFile - request body with content in the given form field
+func multipartFile(t *testing.T, field, name, content string) (*bytes.Buffer, string) {
+  body := &bytes.Buffer{}
+  writer := multipart.NewWriter(body)
+  part, err := writer.CreateFormFile(field, name)
+  assert.NoError(t, err)
+  part.Write([]byte(content))
+  writer.Close()
+  return body, writer.FormDataContentType()
+}
+
+func TestAttachmentHandler_UploadAttachment(t *testing.T) {
+  t.Run("should upload file", func(t *testing.T) {
+    mockService := new(MockAttachmentService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupAttachmentRouter(mockService, userID)
+
+    mockService.On("UploadAttachment", mock.Anything, taskID, userID, "photo.png", mock.Anything).
+      Return(&types.AttachmentResponse{ID: bson.NewObjectID().Hex(), Name: "photo.png"}, nil)
+
+    body, contentType := multipartFile(t, "file", "photo.png", "\x89PNG\r\n\x1a\n")
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/attachments", body)
+    req.Header.Set("Content-Type", contentType)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusCreated, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should require the file field", func(t *testing.T) {
+    mockService := new(MockAttachmentService)
+    router := setupAttachmentRouter(mockService, bson.NewObjectID())
+
+    body, contentType := multipartFile(t, "upload", "photo.png", "data")
+    req, _ := http.NewRequest("POST", "/tasks/"+bson.NewObjectID().Hex()+"/attachments", body)
+    req.Header.Set("Content-Type", contentType)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+
+  t.Run("should return 413 for oversized file", func(t *testing.T) {
+    mockService := new(MockAttachmentService)
+    router := setupAttachmentRouter(mockService, bson.NewObjectID())
+
+    body, contentType := multipartFile(t, "file", "big.pdf", strings.Repeat("x", 100))
+    req, _ := http.NewRequest("POST", "/tasks/"+bson.NewObjectID().Hex()+"/attachments", body)
+    req.Header.Set("Content-Type", contentType)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+    mockService.AssertNotCalled(t, "UploadAttachment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should return 415 for disallowed content", func(t *testing.T) {
+    mockService := new(MockAttachmentService)
+    router := setupAttachmentRouter(mockService, bson.NewObjectID())
+
+    mockService.On("UploadAttachment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrAttachmentType)
+
+    body, contentType := multipartFile(t, "file", "notes.txt", "plain text")
+    req, _ := http.NewRequest("POST", "/tasks/"+bson.NewObjectID().Hex()+"/attachments", body)
+    req.Header.Set("Content-Type", contentType)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+  })
+}
+
+func TestAttachmentHandler_DownloadAttachment(t *testing.T) {
+  t.Run("should stream file as download", func(t *testing.T) {
+    mockService := new(MockAttachmentService)
+    userID, taskID, attachmentID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+    router := setupAttachmentRouter(mockService, userID)
+
+    mockService.On("OpenAttachment", mock.Anything, taskID, attachmentID, userID).
+      Return(&types.AttachmentResponse{Name: "site plan.pdf", ContentType: "application/pdf", Size: 8}, io.NopCloser(strings.NewReader("%PDF-1.7")), nil)
+
+    req, _ := http.NewRequest("GET", "/tasks/"+taskID.Hex()+"/attachments/"+attachmentID.Hex(), nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+    assert.Equal(t, `attachment; filename="site plan.pdf"`, w.Header().Get("Content-Disposition"))
+    assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+    assert.Equal(t, "%PDF-1.7", w.Body.String())
+  })
+
+  t.Run("should return 404 for unknown attachment", func(t *testing.T) {
+    mockService := new(MockAttachmentService)
+    router := setupAttachmentRouter(mockService, bson.NewObjectID())
+
+    mockService.On("OpenAttachment", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, services.ErrAttachmentNotFound)
+
+    req, _ := http.NewRequest("GET", "/tasks/"+bson.NewObjectID().Hex()+"/attachments/"+bson.NewObjectID().Hex(), nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusNotFound, w.Code)
+  })
+}
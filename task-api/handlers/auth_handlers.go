@@ -2,12 +2,16 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
 
 	"task-api/services"
 	"task-api/types"
@@ -51,6 +55,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
   // Add logging
   log.Printf("Login attempt: email=%s, ip=%s", input.Email, c.ClientIP())
+  input.ClientIP = c.ClientIP()
 
   // Call service
   response, err := h.authService.Login(ctx, input)
@@ -61,6 +66,25 @@ func (h *AuthHandler) Login(c *gin.Context) {
       Str("email", input.Email).
       Str("ip", c.ClientIP()).
       Msg("Login failed")
+
+    var blocked *services.LoginBlockedError
+    if errors.As(err, &blocked) {
+      failLoginBlocked(c, blocked)
+      return
+    }
+
+    if errors.Is(err, services.ErrAccountDisabled) {
+      utils.Fail(c, http.StatusForbidden, types.MsgLoginFailed, gin.H{
+        "error": types.MsgAccountDisabled,
+      })
+      return
+    }
+
+    // Throttle state unknown, the attempt must not count as a wrong password
+    if errors.Is(err, services.ErrLoginUnavailable) {
+      utils.Error(c, http.StatusServiceUnavailable, types.MsgLoginUnavailable, 0, nil)
+      return
+    }
     
     utils.Fail(c, http.StatusUnauthorized, types.MsgLoginFailed, gin.H{
       "error": types.MsgInvalidCredentials,
@@ -68,6 +92,11 @@ func (h *AuthHandler) Login(c *gin.Context) {
     return
   }
 
+  if response.MFARequired {
+    utils.Success(c, http.StatusOK, types.MsgMFARequired, response)
+    return
+  }
+
   // Log successful login
 	log.Info().
 		Str("email", input.Email).
@@ -75,4 +104,456 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
   // Success response
   utils.Success(c, http.StatusOK, types.MsgLoginSuccess, response)
-}
\ No newline at end of file
+}
+
+// failLoginBlocked - 423 for locked accounts, 429 for throttled attempts, with Retry-After
+func failLoginBlocked(c *gin.Context, blocked *services.LoginBlockedError) {
+  retryAfter := int(math.Ceil(blocked.RetryAfter.Seconds()))
+  c.Header("Retry-After", strconv.Itoa(retryAfter))
+
+  status, message := http.StatusTooManyRequests, types.MsgTooManyAttempts
+  if errors.Is(blocked, services.ErrAccountLocked) {
+    status, message = http.StatusLocked, types.MsgAccountLocked
+  }
+
+  utils.Fail(c, status, types.MsgLoginFailed, gin.H{
+    "error":       message,
+    "retry_after": retryAfter,
+  })
+}
+
+// Register - handler for self-service registration
+func (h *AuthHandler) Register(c *gin.Context) {
+  var input types.RegisterInput
+
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, http.StatusBadRequest, "Request body required", gin.H{
+        "error": "Please provide email, password and name",
+      })
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+      "error": err.Error(),
+    })
+    return
+  }
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.authService.Register(ctx, input)
+  if err != nil {
+    switch {
+    case errors.Is(err, services.ErrWeakPassword):
+      utils.Fail(c, http.StatusBadRequest, types.MsgWeakPassword, gin.H{
+        "error": err.Error(),
+      })
+    case errors.Is(err, services.ErrEmailAlreadyRegistered):
+      log.Warn().
+        Str("email", input.Email).
+        Str("ip", c.ClientIP()).
+        Msg("Registration with existing email")
+
+      utils.Fail(c, http.StatusConflict, types.MsgRegisterFailed, gin.H{
+        "error": types.MsgEmailAlreadyRegistered,
+      })
+    default:
+      log.Error().Err(err).Str("email", input.Email).Msg("Registration failed")
+      utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    }
+    return
+  }
+
+  log.Info().
+    Str("email", response.User.Email).
+    Msg("User registered")
+
+  utils.Success(c, http.StatusCreated, types.MsgRegisterSuccess, response)
+}
+
+// Refresh - handler for rotating refresh token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+  var input types.RefreshInput
+
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, http.StatusBadRequest, "Request body required", gin.H{
+        "error": "Please provide refresh_token",
+      })
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+      "error": err.Error(),
+    })
+    return
+  }
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.authService.Refresh(ctx, input.RefreshToken)
+  if err != nil {
+    if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenReused) {
+      log.Warn().Err(err).Str("ip", c.ClientIP()).Msg("Token refresh rejected")
+
+      utils.Fail(c, http.StatusUnauthorized, types.MsgRefreshFailed, gin.H{
+        "error": types.MsgInvalidRefreshToken,
+      })
+      return
+    }
+
+    log.Error().Err(err).Msg("Token refresh failed")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgTokenRefreshed, response)
+}
+
+// Logout - handler for logging out current session
+func (h *AuthHandler) Logout(c *gin.Context) {
+  var input types.LogoutInput
+
+  // Body is optional
+  if c.Request.Body != nil && c.Request.ContentLength != 0 {
+    if err := c.ShouldBindJSON(&input); err != nil && err != io.EOF {
+      utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+        "error": err.Error(),
+      })
+      return
+    }
+  }
+
+  userID, tokenID, expiresAt := sessionFromContext(c)
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  if err := h.authService.Logout(ctx, userID, tokenID, expiresAt, input); err != nil {
+    log.Error().Err(err).Str("user_id", userID.Hex()).Msg("Logout failed")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  log.Info().Str("user_id", userID.Hex()).Msg("User logged out")
+
+  utils.Success(c, http.StatusOK, types.MsgLogoutSuccess, nil)
+}
+
+// LogoutAll - handler for logging out every session of current user
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+  userID, tokenID, expiresAt := sessionFromContext(c)
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  if err := h.authService.LogoutAll(ctx, userID, tokenID, expiresAt); err != nil {
+    log.Error().Err(err).Str("user_id", userID.Hex()).Msg("Logout all failed")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  log.Info().Str("user_id", userID.Hex()).Msg("User logged out from all sessions")
+
+  utils.Success(c, http.StatusOK, types.MsgLogoutAllSuccess, nil)
+}
+
+// sessionFromContext - user and token info set by AuthMiddleware
+func sessionFromContext(c *gin.Context) (bson.ObjectID, string, time.Time) {
+  userID, _ := c.Get("userID")
+  tokenID := c.GetString("tokenID")
+  expiresAt := c.GetTime("tokenExpiresAt")
+
+  return userID.(bson.ObjectID), tokenID, expiresAt
+}
+
+// ForgotPassword - handler for requesting a password reset link
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+  var input types.ForgotPasswordInput
+
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, http.StatusBadRequest, "Request body required", gin.H{
+        "error": "Please provide email",
+      })
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+      "error": err.Error(),
+    })
+    return
+  }
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+
+  log.Info().
+    Str("email", input.Email).
+    Str("ip", c.ClientIP()).
+    Msg("Password reset requested")
+
+  if err := h.authService.ForgotPassword(ctx, input); err != nil {
+    log.Error().Err(err).Str("email", input.Email).Msg("Password reset request failed")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  // Same response whether or not the email is registered
+  utils.Success(c, http.StatusOK, types.MsgPasswordResetRequested, nil)
+}
+
+// ResetPassword - handler for setting a new password with a reset token
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+  var input types.ResetPasswordInput
+
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, http.StatusBadRequest, "Request body required", gin.H{
+        "error": "Please provide token and password",
+      })
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+      "error": err.Error(),
+    })
+    return
+  }
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  if err := h.authService.ResetPassword(ctx, input); err != nil {
+    switch {
+    case errors.Is(err, services.ErrWeakPassword):
+      utils.Fail(c, http.StatusBadRequest, types.MsgWeakPassword, gin.H{
+        "error": err.Error(),
+      })
+    case errors.Is(err, services.ErrInvalidResetToken):
+      log.Warn().Str("ip", c.ClientIP()).Msg("Invalid password reset token")
+
+      utils.Fail(c, http.StatusBadRequest, types.MsgPasswordResetFailed, gin.H{
+        "error": types.MsgInvalidResetToken,
+      })
+    default:
+      log.Error().Err(err).Msg("Password reset failed")
+      utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    }
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgPasswordResetSuccess, nil)
+}
+
+// GetMe - GET /auth/me - current user profile
+func (h *AuthHandler) GetMe(c *gin.Context) {
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.authService.GetProfile(ctx, userID.(bson.ObjectID))
+  if err != nil {
+    if errors.Is(err, services.ErrUserNotFound) {
+      utils.Fail(c, http.StatusNotFound, types.MsgUserNotFound, nil)
+      return
+    }
+
+    log.Error().Err(err).Msg("Failed to get profile")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgProfileRetrieved, gin.H{"user": response})
+}
+
+// UpdateMe - PATCH /auth/me - update name, timezone, locale
+func (h *AuthHandler) UpdateMe(c *gin.Context) {
+  var input types.UpdateProfileInput
+
+  if err := c.ShouldBindJSON(&input); err != nil {
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+      "error": err.Error(),
+    })
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.authService.UpdateProfile(ctx, userID.(bson.ObjectID), input)
+  if err != nil {
+    switch {
+    case errors.Is(err, services.ErrInvalidTimezone):
+      utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+        "error": types.MsgInvalidTimezone,
+      })
+    case errors.Is(err, services.ErrUserNotFound):
+      utils.Fail(c, http.StatusNotFound, types.MsgUserNotFound, nil)
+    default:
+      log.Error().Err(err).Msg("Failed to update profile")
+      utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    }
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgProfileUpdated, gin.H{"user": response})
+}
+
+// ChangePassword - POST /auth/change-password
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+  var input types.ChangePasswordInput
+
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, http.StatusBadRequest, "Request body required", gin.H{
+        "error": "Please provide current_password and new_password",
+      })
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+      "error": err.Error(),
+    })
+    return
+  }
+
+  userID, tokenID, expiresAt := sessionFromContext(c)
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.authService.ChangePassword(ctx, userID, tokenID, expiresAt, input)
+  if err != nil {
+    switch {
+    case errors.Is(err, services.ErrInvalidCurrentPassword):
+      log.Warn().Str("user_id", userID.Hex()).Str("ip", c.ClientIP()).Msg("Change password with wrong current password")
+
+      utils.Fail(c, http.StatusBadRequest, types.MsgPasswordChangeFailed, gin.H{
+        "error": types.MsgInvalidCurrentPassword,
+      })
+    case errors.Is(err, services.ErrWeakPassword):
+      utils.Fail(c, http.StatusBadRequest, types.MsgWeakPassword, gin.H{
+        "error": err.Error(),
+      })
+    case errors.Is(err, services.ErrUserNotFound):
+      utils.Fail(c, http.StatusNotFound, types.MsgUserNotFound, nil)
+    default:
+      log.Error().Err(err).Str("user_id", userID.Hex()).Msg("Change password failed")
+      utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    }
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgPasswordChanged, response)
+}
+
+// SetupMFA - POST /auth/2fa/setup - start TOTP enrollment
+func (h *AuthHandler) SetupMFA(c *gin.Context) {
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.authService.SetupMFA(ctx, userID.(bson.ObjectID))
+  if err != nil {
+    switch {
+    case errors.Is(err, services.ErrMFAAlreadyEnabled):
+      utils.Fail(c, http.StatusConflict, types.MsgMFAAlreadyEnabled, nil)
+    case errors.Is(err, services.ErrUserNotFound):
+      utils.Fail(c, http.StatusNotFound, types.MsgUserNotFound, nil)
+    default:
+      log.Error().Err(err).Msg("Failed to start 2FA setup")
+      utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    }
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgMFASetupStarted, response)
+}
+
+// VerifyMFA - POST /auth/2fa/verify - confirm enrollment with a code, returns recovery codes
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+  var input types.MFAVerifyInput
+
+  if err := c.ShouldBindJSON(&input); err != nil {
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+      "error": err.Error(),
+    })
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.authService.VerifyMFASetup(ctx, userID.(bson.ObjectID), input)
+  if err != nil {
+    switch {
+    case errors.Is(err, services.ErrInvalidMFACode):
+      utils.Fail(c, http.StatusBadRequest, types.MsgMFAFailed, gin.H{
+        "error": types.MsgInvalidMFACode,
+      })
+    case errors.Is(err, services.ErrMFANotSetUp):
+      utils.Fail(c, http.StatusBadRequest, types.MsgMFANotSetUp, nil)
+    case errors.Is(err, services.ErrMFAAlreadyEnabled):
+      utils.Fail(c, http.StatusConflict, types.MsgMFAAlreadyEnabled, nil)
+    case errors.Is(err, services.ErrUserNotFound):
+      utils.Fail(c, http.StatusNotFound, types.MsgUserNotFound, nil)
+    default:
+      log.Error().Err(err).Msg("Failed to verify 2FA setup")
+      utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    }
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgMFAEnabled, response)
+}
+
+// LoginMFA - POST /auth/2fa/login - second login step
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+  var input types.MFALoginInput
+
+  if err := c.ShouldBindJSON(&input); err != nil {
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+      "error": err.Error(),
+    })
+    return
+  }
+  input.ClientIP = c.ClientIP()
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.authService.LoginMFA(ctx, input)
+  if err != nil {
+    var blocked *services.LoginBlockedError
+    switch {
+    case errors.As(err, &blocked):
+      failLoginBlocked(c, blocked)
+    case errors.Is(err, services.ErrInvalidMFAToken):
+      utils.Fail(c, http.StatusUnauthorized, types.MsgMFAFailed, gin.H{
+        "error": types.MsgInvalidMFAToken,
+      })
+    case errors.Is(err, services.ErrInvalidMFACode):
+      utils.Fail(c, http.StatusUnauthorized, types.MsgMFAFailed, gin.H{
+        "error": types.MsgInvalidMFACode,
+      })
+    case errors.Is(err, services.ErrLoginUnavailable):
+      utils.Error(c, http.StatusServiceUnavailable, types.MsgLoginUnavailable, 0, nil)
+    default:
+      log.Error().Err(err).Str("ip", c.ClientIP()).Msg("Two-factor login failed")
+      utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    }
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgLoginSuccess, response)
+}
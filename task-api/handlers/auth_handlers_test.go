@@ -5,15 +5,20 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
 
+	"task-api/models"
+	"task-api/services"
 	"task-api/types"
 )
 
@@ -32,6 +37,121 @@ func (m *MockAuthService) Login(ctx context.Context, input types.LoginInput) (*t
   return args.Get(0).(*types.LoginResponse), args.Error(1)
 }
 
+// Register mocks the Register method
+func (m *MockAuthService) Register(ctx context.Context, input types.RegisterInput) (*types.LoginResponse, error) {
+  args := m.Called(ctx, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.LoginResponse), args.Error(1)
+}
+
+// Refresh mocks the Refresh method
+func (m *MockAuthService) Refresh(ctx context.Context, refreshToken string) (*types.LoginResponse, error) {
+  args := m.Called(ctx, refreshToken)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.LoginResponse), args.Error(1)
+}
+
+// Logout mocks the Logout method
+func (m *MockAuthService) Logout(ctx context.Context, userID bson.ObjectID, tokenID string, expiresAt time.Time, input types.LogoutInput) error {
+  args := m.Called(ctx, userID, tokenID, expiresAt, input)
+  return args.Error(0)
+}
+
+// LogoutAll mocks the LogoutAll method
+func (m *MockAuthService) LogoutAll(ctx context.Context, userID bson.ObjectID, tokenID string, expiresAt time.Time) error {
+  args := m.Called(ctx, userID, tokenID, expiresAt)
+  return args.Error(0)
+}
+
+// ForgotPassword mocks the ForgotPassword method
+func (m *MockAuthService) ForgotPassword(ctx context.Context, input types.ForgotPasswordInput) error {
+  args := m.Called(ctx, input)
+  return args.Error(0)
+}
+
+// ResetPassword mocks the ResetPassword method
+func (m *MockAuthService) ResetPassword(ctx context.Context, input types.ResetPasswordInput) error {
+  args := m.Called(ctx, input)
+  return args.Error(0)
+}
+
+// GetProfile mocks the GetProfile method
+func (m *MockAuthService) GetProfile(ctx context.Context, userID bson.ObjectID) (*types.UserResponse, error) {
+  args := m.Called(ctx, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.UserResponse), args.Error(1)
+}
+
+// UpdateProfile mocks the UpdateProfile method
+func (m *MockAuthService) UpdateProfile(ctx context.Context, userID bson.ObjectID, input types.UpdateProfileInput) (*types.UserResponse, error) {
+  args := m.Called(ctx, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.UserResponse), args.Error(1)
+}
+
+// ChangePassword mocks the ChangePassword method
+func (m *MockAuthService) ChangePassword(ctx context.Context, userID bson.ObjectID, tokenID string, expiresAt time.Time, input types.ChangePasswordInput) (*types.LoginResponse, error) {
+  args := m.Called(ctx, userID, tokenID, expiresAt, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.LoginResponse), args.Error(1)
+}
+
+// SetupMFA mocks the SetupMFA method
+func (m *MockAuthService) SetupMFA(ctx context.Context, userID bson.ObjectID) (*types.MFASetupResponse, error) {
+  args := m.Called(ctx, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.MFASetupResponse), args.Error(1)
+}
+
+// VerifyMFASetup mocks the VerifyMFASetup method
+func (m *MockAuthService) VerifyMFASetup(ctx context.Context, userID bson.ObjectID, input types.MFAVerifyInput) (*types.MFAVerifyResponse, error) {
+  args := m.Called(ctx, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.MFAVerifyResponse), args.Error(1)
+}
+
+// LoginMFA mocks the LoginMFA method
+func (m *MockAuthService) LoginMFA(ctx context.Context, input types.MFALoginInput) (*types.LoginResponse, error) {
+  args := m.Called(ctx, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.LoginResponse), args.Error(1)
+}
+
+// LoginExternal mocks the LoginExternal method
+func (m *MockAuthService) LoginExternal(ctx context.Context, user *models.User) (*types.LoginResponse, error) {
+  args := m.Called(ctx, user)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.LoginResponse), args.Error(1)
+}
+
+// withSession - fake AuthMiddleware context for protected auth routes
+func withSession(userID bson.ObjectID, tokenID string, expiresAt time.Time) gin.HandlerFunc {
+  return func(c *gin.Context) {
+    c.Set("userID", userID)
+    c.Set("tokenID", tokenID)
+    c.Set("tokenExpiresAt", expiresAt)
+    c.Next()
+  }
+}
+
 // setupRouter creates a test router in test mode
 func setupRouter() *gin.Engine {
   gin.SetMode(gin.TestMode)
@@ -115,6 +235,69 @@ func TestAuthHandler_Login(t *testing.T) {
     mockService.AssertExpectations(t)
   })
 
+  t.Run("should return 423 with Retry-After when account is locked", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/login", handler.Login)
+
+    mockService.On("Login", mock.Anything, mock.MatchedBy(func(input types.LoginInput) bool {
+      return input.ClientIP == "10.0.0.1"
+    })).Return(nil, &services.LoginBlockedError{Err: services.ErrAccountLocked, RetryAfter: 90500 * time.Millisecond})
+
+    jsonBody, _ := json.Marshal(map[string]string{"email": "test@test.com", "password": "password123"})
+    req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    req.RemoteAddr = "10.0.0.1:1234"
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusLocked, w.Code)
+    assert.Equal(t, "91", w.Header().Get("Retry-After"))
+    assert.Contains(t, w.Body.String(), types.MsgAccountLocked)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 429 with Retry-After when throttled", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/login", handler.Login)
+
+    mockService.On("Login", mock.Anything, mock.Anything).
+      Return(nil, &services.LoginBlockedError{Err: services.ErrTooManyAttempts, RetryAfter: 2 * time.Second})
+
+    jsonBody, _ := json.Marshal(map[string]string{"email": "test@test.com", "password": "password123"})
+    req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusTooManyRequests, w.Code)
+    assert.Equal(t, "2", w.Header().Get("Retry-After"))
+    assert.Contains(t, w.Body.String(), `"status":"fail"`)
+  })
+
+  t.Run("should return 503 when the attempt store fails", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/login", handler.Login)
+
+    mockService.On("Login", mock.Anything, mock.Anything).
+      Return(nil, fmt.Errorf("%w: %v", services.ErrLoginUnavailable, errors.New("connection refused")))
+
+    jsonBody, _ := json.Marshal(map[string]string{"email": "test@test.com", "password": "password123"})
+    req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgLoginUnavailable)
+    assert.NotContains(t, w.Body.String(), types.MsgInvalidCredentials)
+  })
+
   t.Run("should return 400 on empty body", func(t *testing.T) {
     mockService := new(MockAuthService)
     handler := NewAuthHandler(mockService)
@@ -201,3 +384,694 @@ func TestAuthHandler_Login(t *testing.T) {
     assert.Equal(t, http.StatusBadRequest, w.Code)
   })
 }
+
+func TestAuthHandler_Register(t *testing.T) {
+  t.Run("should return 201 on successful registration", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/register", handler.Register)
+
+    expectedResponse := &types.LoginResponse{
+      Token: "test-jwt-token",
+      User: types.UserResponse{
+        Email: "new@test.com",
+        Name:  "New User",
+      },
+    }
+
+    mockService.On("Register", mock.Anything, mock.Anything).Return(expectedResponse, nil)
+
+    body := map[string]string{
+      "email":    "new@test.com",
+      "password": "Password123",
+      "name":     "New User",
+    }
+    jsonBody, _ := json.Marshal(body)
+
+    req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusCreated, w.Code)
+
+    var response map[string]interface{}
+    json.Unmarshal(w.Body.Bytes(), &response)
+
+    assert.Equal(t, "success", response["status"])
+    data := response["data"].(map[string]interface{})
+    assert.Equal(t, "test-jwt-token", data["token"])
+
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 409 when email already registered", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/register", handler.Register)
+
+    mockService.On("Register", mock.Anything, mock.Anything).
+      Return(nil, services.ErrEmailAlreadyRegistered)
+
+    body := map[string]string{
+      "email":    "taken@test.com",
+      "password": "Password123",
+      "name":     "Someone",
+    }
+    jsonBody, _ := json.Marshal(body)
+
+    req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusConflict, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgEmailAlreadyRegistered)
+
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 400 on weak password", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/register", handler.Register)
+
+    mockService.On("Register", mock.Anything, mock.Anything).
+      Return(nil, fmt.Errorf("%w: password must contain a digit", services.ErrWeakPassword))
+
+    body := map[string]string{
+      "email":    "new@test.com",
+      "password": "Passwordxx",
+      "name":     "New User",
+    }
+    jsonBody, _ := json.Marshal(body)
+
+    req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    assert.Contains(t, w.Body.String(), "contain a digit")
+
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 400 on missing name", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/register", handler.Register)
+
+    body := map[string]string{
+      "email":    "new@test.com",
+      "password": "Password123",
+    }
+    jsonBody, _ := json.Marshal(body)
+
+    req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "Register", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should return 500 on unexpected error", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/register", handler.Register)
+
+    mockService.On("Register", mock.Anything, mock.Anything).
+      Return(nil, errors.New("database error"))
+
+    body := map[string]string{
+      "email":    "new@test.com",
+      "password": "Password123",
+      "name":     "New User",
+    }
+    jsonBody, _ := json.Marshal(body)
+
+    req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusInternalServerError, w.Code)
+  })
+}
+
+func TestAuthHandler_Refresh(t *testing.T) {
+  t.Run("should return 200 with rotated tokens", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/refresh", handler.Refresh)
+
+    expectedResponse := &types.LoginResponse{
+      Token:        "new-access-token",
+      RefreshToken: "new-refresh-token",
+      ExpiresIn:    900,
+      User:         types.UserResponse{Email: "test@test.com", Name: "Test User"},
+    }
+
+    mockService.On("Refresh", mock.Anything, "old-refresh-token").Return(expectedResponse, nil)
+
+    jsonBody, _ := json.Marshal(map[string]string{"refresh_token": "old-refresh-token"})
+
+    req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "new-refresh-token")
+
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 401 when token was reused", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/refresh", handler.Refresh)
+
+    mockService.On("Refresh", mock.Anything, "stolen").Return(nil, services.ErrRefreshTokenReused)
+
+    jsonBody, _ := json.Marshal(map[string]string{"refresh_token": "stolen"})
+
+    req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnauthorized, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgInvalidRefreshToken)
+  })
+
+  t.Run("should return 400 without refresh_token", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/refresh", handler.Refresh)
+
+    req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer([]byte("{}")))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "Refresh", mock.Anything, mock.Anything)
+  })
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+  t.Run("should revoke session without body", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+
+    userID := bson.NewObjectID()
+    expiresAt := time.Now().Add(time.Hour)
+    router.Use(withSession(userID, "jti-1", expiresAt))
+    router.POST("/auth/logout", handler.Logout)
+
+    mockService.On("Logout", mock.Anything, userID, "jti-1", expiresAt, types.LogoutInput{}).Return(nil)
+
+    req, _ := http.NewRequest("POST", "/auth/logout", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should pass refresh token to service", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+
+    userID := bson.NewObjectID()
+    expiresAt := time.Now().Add(time.Hour)
+    router.Use(withSession(userID, "jti-1", expiresAt))
+    router.POST("/auth/logout", handler.Logout)
+
+    input := types.LogoutInput{RefreshToken: "refresh"}
+    mockService.On("Logout", mock.Anything, userID, "jti-1", expiresAt, input).Return(nil)
+
+    jsonBody, _ := json.Marshal(input)
+    req, _ := http.NewRequest("POST", "/auth/logout", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 500 when revocation fails", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+
+    router.Use(withSession(bson.NewObjectID(), "jti-1", time.Now().Add(time.Hour)))
+    router.POST("/auth/logout", handler.Logout)
+
+    mockService.On("Logout", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+      Return(errors.New("database error"))
+
+    req, _ := http.NewRequest("POST", "/auth/logout", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusInternalServerError, w.Code)
+  })
+}
+
+func TestAuthHandler_LogoutAll(t *testing.T) {
+  t.Run("should revoke every session", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+
+    userID := bson.NewObjectID()
+    expiresAt := time.Now().Add(time.Hour)
+    router.Use(withSession(userID, "jti-1", expiresAt))
+    router.POST("/auth/logout-all", handler.LogoutAll)
+
+    mockService.On("LogoutAll", mock.Anything, userID, "jti-1", expiresAt).Return(nil)
+
+    req, _ := http.NewRequest("POST", "/auth/logout-all", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgLogoutAllSuccess)
+    mockService.AssertExpectations(t)
+  })
+}
+
+func TestAuthHandler_ForgotPassword(t *testing.T) {
+  t.Run("should return 200", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/forgot-password", handler.ForgotPassword)
+
+    input := types.ForgotPasswordInput{Email: "test@test.com"}
+    mockService.On("ForgotPassword", mock.Anything, input).Return(nil)
+
+    jsonBody, _ := json.Marshal(input)
+    req, _ := http.NewRequest("POST", "/auth/forgot-password", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgPasswordResetRequested)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 400 on invalid email", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/forgot-password", handler.ForgotPassword)
+
+    jsonBody, _ := json.Marshal(map[string]string{"email": "not-an-email"})
+    req, _ := http.NewRequest("POST", "/auth/forgot-password", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+}
+
+func TestAuthHandler_ResetPassword(t *testing.T) {
+  t.Run("should return 200 on success", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/reset-password", handler.ResetPassword)
+
+    input := types.ResetPasswordInput{Token: "reset-token", Password: "NewPassword1"}
+    mockService.On("ResetPassword", mock.Anything, input).Return(nil)
+
+    jsonBody, _ := json.Marshal(input)
+    req, _ := http.NewRequest("POST", "/auth/reset-password", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 400 on invalid token", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/reset-password", handler.ResetPassword)
+
+    mockService.On("ResetPassword", mock.Anything, mock.Anything).Return(services.ErrInvalidResetToken)
+
+    jsonBody, _ := json.Marshal(map[string]string{"token": "used", "password": "NewPassword1"})
+    req, _ := http.NewRequest("POST", "/auth/reset-password", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgInvalidResetToken)
+  })
+
+  t.Run("should return 400 on missing token", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/reset-password", handler.ResetPassword)
+
+    jsonBody, _ := json.Marshal(map[string]string{"password": "NewPassword1"})
+    req, _ := http.NewRequest("POST", "/auth/reset-password", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything)
+  })
+}
+
+func TestAuthHandler_GetMe(t *testing.T) {
+  t.Run("should return current user", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+
+    userID := bson.NewObjectID()
+    router.GET("/auth/me", withSession(userID, "jti", time.Now().Add(time.Minute)), handler.GetMe)
+
+    mockService.On("GetProfile", mock.Anything, userID).
+      Return(&types.UserResponse{ID: userID.Hex(), Email: "test@example.com", Name: "Test"}, nil)
+
+    req, _ := http.NewRequest("GET", "/auth/me", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "test@example.com")
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 404 when user no longer exists", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.GET("/auth/me", withSession(bson.NewObjectID(), "jti", time.Now()), handler.GetMe)
+
+    mockService.On("GetProfile", mock.Anything, mock.Anything).Return(nil, services.ErrUserNotFound)
+
+    req, _ := http.NewRequest("GET", "/auth/me", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusNotFound, w.Code)
+  })
+}
+
+func TestAuthHandler_UpdateMe(t *testing.T) {
+  t.Run("should update profile", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+
+    userID := bson.NewObjectID()
+    router.PATCH("/auth/me", withSession(userID, "jti", time.Now()), handler.UpdateMe)
+
+    mockService.On("UpdateProfile", mock.Anything, userID, mock.AnythingOfType("types.UpdateProfileInput")).
+      Return(&types.UserResponse{Email: "test@example.com", Name: "New Name", Locale: "id-ID"}, nil)
+
+    jsonBody, _ := json.Marshal(map[string]string{"name": "New Name", "locale": "id-ID"})
+    req, _ := http.NewRequest("PATCH", "/auth/me", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "New Name")
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 400 on invalid timezone", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.PATCH("/auth/me", withSession(bson.NewObjectID(), "jti", time.Now()), handler.UpdateMe)
+
+    mockService.On("UpdateProfile", mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrInvalidTimezone)
+
+    jsonBody, _ := json.Marshal(map[string]string{"timezone": "Mars/Olympus"})
+    req, _ := http.NewRequest("PATCH", "/auth/me", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgInvalidTimezone)
+  })
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+  t.Run("should return new tokens on success", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+
+    userID := bson.NewObjectID()
+    expiresAt := time.Now().Add(time.Minute)
+    router.POST("/auth/change-password", withSession(userID, "jti", expiresAt), handler.ChangePassword)
+
+    input := types.ChangePasswordInput{CurrentPassword: "OldPassword1", NewPassword: "NewPassword1"}
+    mockService.On("ChangePassword", mock.Anything, userID, "jti", expiresAt, input).
+      Return(&types.LoginResponse{Token: "new-access", RefreshToken: "new-refresh"}, nil)
+
+    jsonBody, _ := json.Marshal(input)
+    req, _ := http.NewRequest("POST", "/auth/change-password", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "new-refresh")
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 400 on wrong current password", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/change-password", withSession(bson.NewObjectID(), "jti", time.Now()), handler.ChangePassword)
+
+    mockService.On("ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+      Return(nil, services.ErrInvalidCurrentPassword)
+
+    jsonBody, _ := json.Marshal(map[string]string{"current_password": "Wrong1234", "new_password": "NewPassword1"})
+    req, _ := http.NewRequest("POST", "/auth/change-password", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgInvalidCurrentPassword)
+  })
+
+  t.Run("should return 400 on missing fields", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/change-password", withSession(bson.NewObjectID(), "jti", time.Now()), handler.ChangePassword)
+
+    jsonBody, _ := json.Marshal(map[string]string{"new_password": "NewPassword1"})
+    req, _ := http.NewRequest("POST", "/auth/change-password", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+}
+
+func TestAuthHandler_SetupMFA(t *testing.T) {
+  t.Run("should return otpauth URI", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+
+    userID := bson.NewObjectID()
+    router.POST("/auth/2fa/setup", withSession(userID, "jti", time.Now()), handler.SetupMFA)
+
+    mockService.On("SetupMFA", mock.Anything, userID).
+      Return(&types.MFASetupResponse{Secret: "SECRET", OTPAuthURI: "otpauth://totp/x"}, nil)
+
+    req, _ := http.NewRequest("POST", "/auth/2fa/setup", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "otpauth://totp/x")
+  })
+
+  t.Run("should return 409 when already enabled", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/2fa/setup", withSession(bson.NewObjectID(), "jti", time.Now()), handler.SetupMFA)
+
+    mockService.On("SetupMFA", mock.Anything, mock.Anything).Return(nil, services.ErrMFAAlreadyEnabled)
+
+    req, _ := http.NewRequest("POST", "/auth/2fa/setup", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusConflict, w.Code)
+  })
+}
+
+func TestAuthHandler_VerifyMFA(t *testing.T) {
+  t.Run("should return recovery codes", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+
+    userID := bson.NewObjectID()
+    router.POST("/auth/2fa/verify", withSession(userID, "jti", time.Now()), handler.VerifyMFA)
+
+    mockService.On("VerifyMFASetup", mock.Anything, userID, types.MFAVerifyInput{Code: "123456"}).
+      Return(&types.MFAVerifyResponse{RecoveryCodes: []string{"abcde-fghij"}}, nil)
+
+    jsonBody, _ := json.Marshal(map[string]string{"code": "123456"})
+    req, _ := http.NewRequest("POST", "/auth/2fa/verify", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "abcde-fghij")
+  })
+
+  t.Run("should return 400 on malformed code", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/2fa/verify", withSession(bson.NewObjectID(), "jti", time.Now()), handler.VerifyMFA)
+
+    jsonBody, _ := json.Marshal(map[string]string{"code": "12ab"})
+    req, _ := http.NewRequest("POST", "/auth/2fa/verify", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "VerifyMFASetup", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should return 400 on wrong code", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/2fa/verify", withSession(bson.NewObjectID(), "jti", time.Now()), handler.VerifyMFA)
+
+    mockService.On("VerifyMFASetup", mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrInvalidMFACode)
+
+    jsonBody, _ := json.Marshal(map[string]string{"code": "000000"})
+    req, _ := http.NewRequest("POST", "/auth/2fa/verify", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgInvalidMFACode)
+  })
+}
+
+func TestAuthHandler_LoginMFA(t *testing.T) {
+  t.Run("should return session on valid code", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/2fa/login", handler.LoginMFA)
+
+    mockService.On("LoginMFA", mock.Anything, mock.MatchedBy(func(input types.MFALoginInput) bool {
+      return input.MFAToken == "mfa-token" && input.Code == "123456"
+    })).Return(&types.LoginResponse{Token: "access", RefreshToken: "refresh"}, nil)
+
+    jsonBody, _ := json.Marshal(map[string]string{"mfa_token": "mfa-token", "code": "123456"})
+    req, _ := http.NewRequest("POST", "/auth/2fa/login", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "refresh")
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 400 without code or recovery code", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/2fa/login", handler.LoginMFA)
+
+    jsonBody, _ := json.Marshal(map[string]string{"mfa_token": "mfa-token"})
+    req, _ := http.NewRequest("POST", "/auth/2fa/login", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+
+  t.Run("should return 401 on invalid code", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/2fa/login", handler.LoginMFA)
+
+    mockService.On("LoginMFA", mock.Anything, mock.Anything).Return(nil, services.ErrInvalidMFACode)
+
+    jsonBody, _ := json.Marshal(map[string]string{"mfa_token": "mfa-token", "recovery_code": "wrong"})
+    req, _ := http.NewRequest("POST", "/auth/2fa/login", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnauthorized, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgInvalidMFACode)
+  })
+
+  t.Run("should return 423 when account is locked", func(t *testing.T) {
+    mockService := new(MockAuthService)
+    handler := NewAuthHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/2fa/login", handler.LoginMFA)
+
+    mockService.On("LoginMFA", mock.Anything, mock.Anything).
+      Return(nil, &services.LoginBlockedError{Err: services.ErrAccountLocked, RetryAfter: time.Minute})
+
+    jsonBody, _ := json.Marshal(map[string]string{"mfa_token": "mfa-token", "code": "123456"})
+    req, _ := http.NewRequest("POST", "/auth/2fa/login", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusLocked, w.Code)
+    assert.Equal(t, "60", w.Header().Get("Retry-After"))
+  })
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/types"
+	"task-api/utils"
+)
+
+// BulkTasks - POST /tasks/bulk
+func (h *TaskHandler) BulkTasks(c *gin.Context) {
+  var input types.BulkTaskInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+  defer cancel()
+  
+  result, err := h.taskService.BulkTasks(ctx, userID.(bson.ObjectID), input)
+  if err != nil {
+    h.handleError(c, err, "Failed to apply bulk operation")
+    return
+  }
+  
+  // All or nothing, one failed change keeps the others from being applied
+  if input.Atomic && result.Failed > 0 {
+    utils.Fail(c, 409, types.MsgBulkAborted, result)
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgBulkCompleted, result)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+func TestTaskHandler_BulkTasks(t *testing.T) {
+  t.Run("should return a result per task", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    result := &types.BulkTaskResponse{Applied: 1, Results: []types.BulkTaskResult{{TaskID: taskID.Hex(), Status: types.BulkResultApplied}}}
+    mockService.On("BulkTasks", mock.Anything, userID, mock.MatchedBy(func(input types.BulkTaskInput) bool {
+      op := input.Operations[0]
+      return op.Action == types.BulkActionUpdate && *op.Priority == types.TaskPriorityHigh && op.Filter.Status == types.TaskStatusPending
+    })).Return(result, nil)
+
+    body := `{"operations":[{"action":"update","filter":{"status":"pending"},"priority":"high"}]}`
+    req, _ := http.NewRequest("POST", "/tasks/bulk", bytes.NewBufferString(body))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), taskID.Hex())
+  })
+
+  t.Run("should answer conflict when an atomic request failed", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    result := &types.BulkTaskResponse{Failed: 1, Results: []types.BulkTaskResult{{TaskID: taskID.Hex(), Status: types.BulkResultFailed, Error: "task not found"}}}
+    mockService.On("BulkTasks", mock.Anything, userID, mock.Anything).Return(result, nil)
+
+    body := `{"atomic":true,"operations":[{"action":"delete","ids":["` + taskID.Hex() + `"]}]}`
+    req, _ := http.NewRequest("POST", "/tasks/bulk", bytes.NewBufferString(body))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusConflict, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgBulkAborted)
+  })
+
+  t.Run("should reject unknown actions and invalid ids", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    for _, body := range []string{
+      `{"operations":[]}`,
+      `{"operations":[{"action":"rename","ids":["` + bson.NewObjectID().Hex() + `"]}]}`,
+      `{"operations":[{"action":"delete","ids":["nope"]}]}`,
+      `{"operations":[{"action":"update","filter":{"status":"done"},"priority":"high"}]}`,
+    } {
+      req, _ := http.NewRequest("POST", "/tasks/bulk", bytes.NewBufferString(body))
+      req.Header.Set("Content-Type", "application/json")
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, req)
+
+      assert.Equal(t, http.StatusBadRequest, w.Code, body)
+    }
+    mockService.AssertNotCalled(t, "BulkTasks", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should refuse requests over the task limit", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID := bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("BulkTasks", mock.Anything, userID, mock.Anything).Return(nil, services.ErrBulkTooLarge)
+
+    body := `{"operations":[{"action":"archive","filter":{}}]}`
+    req, _ := http.NewRequest("POST", "/tasks/bulk", bytes.NewBufferString(body))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+  })
+}
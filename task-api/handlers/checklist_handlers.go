@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/types"
+	"task-api/utils"
+)
+
+// AddChecklistItem - POST /tasks/:id/checklist
+func (h *TaskHandler) AddChecklistItem(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  var input types.AddChecklistItemInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, 400, "Request body required", gin.H{"error": "Please provide text"})
+      return
+    }
+    
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.AddChecklistItem(ctx, taskID, userID.(bson.ObjectID), input)
+  if err != nil {
+    h.handleError(c, err, "Failed to add checklist item")
+    return
+  }
+  
+  utils.Success(c, 201, types.MsgChecklistItemAdded, gin.H{"task": response})
+}
+
+// UpdateChecklistItem - PATCH /tasks/:id/checklist/:item
+func (h *TaskHandler) UpdateChecklistItem(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  itemID, ok := parseChecklistItemID(c)
+  if !ok {
+    return
+  }
+  
+  var input types.UpdateChecklistItemInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, 400, "Request body required", gin.H{"error": "Please provide text or done"})
+      return
+    }
+    
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
+  if input.Text == nil && input.Done == nil {
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": "Please provide text or done"})
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.UpdateChecklistItem(ctx, taskID, userID.(bson.ObjectID), itemID, input)
+  if err != nil {
+    h.handleError(c, err, "Failed to update checklist item")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgChecklistItemUpdated, gin.H{"task": response})
+}
+
+// RemoveChecklistItem - DELETE /tasks/:id/checklist/:item
+func (h *TaskHandler) RemoveChecklistItem(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  itemID, ok := parseChecklistItemID(c)
+  if !ok {
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.RemoveChecklistItem(ctx, taskID, userID.(bson.ObjectID), itemID)
+  if err != nil {
+    h.handleError(c, err, "Failed to remove checklist item")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgChecklistItemRemoved, gin.H{"task": response})
+}
+
+// ReorderChecklist - PUT /tasks/:id/checklist/order
+func (h *TaskHandler) ReorderChecklist(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  var input types.ReorderChecklistInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, 400, "Request body required", gin.H{"error": "Please provide item_ids"})
+      return
+    }
+    
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
+  // IDs are validated by binding
+  itemIDs := make([]bson.ObjectID, len(input.ItemIDs))
+  for i, id := range input.ItemIDs {
+    itemIDs[i], _ = bson.ObjectIDFromHex(id)
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.ReorderChecklist(ctx, taskID, userID.(bson.ObjectID), itemIDs)
+  if err != nil {
+    h.handleError(c, err, "Failed to reorder checklist")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgChecklistReordered, gin.H{"task": response})
+}
+
+// parseChecklistItemID - parse :item, responds 400 when malformed
+func parseChecklistItemID(c *gin.Context) (bson.ObjectID, bool) {
+  itemID, err := bson.ObjectIDFromHex(c.Param("item"))
+  if err != nil {
+    utils.Fail(c, 400, "Invalid checklist item ID", gin.H{"error": "Invalid ID format"})
+    return bson.ObjectID{}, false
+  }
+  return itemID, true
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+func TestTaskHandler_AddChecklistItem(t *testing.T) {
+  t.Run("should add checklist item", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("AddChecklistItem", mock.Anything, taskID, userID, types.AddChecklistItemInput{Text: "Write tests"}).
+      Return(&types.TaskResponse{ID: taskID.Hex()}, nil)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/checklist", bytes.NewBufferString(`{"text":"Write tests"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusCreated, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 422 for full checklist", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    mockService.On("AddChecklistItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrChecklistFull)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+bson.NewObjectID().Hex()+"/checklist", bytes.NewBufferString(`{"text":"One more"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+  })
+}
+
+func TestTaskHandler_UpdateChecklistItem(t *testing.T) {
+  t.Run("should reject empty update", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    req, _ := http.NewRequest("PATCH", "/tasks/"+bson.NewObjectID().Hex()+"/checklist/"+bson.NewObjectID().Hex(), bytes.NewBufferString(`{}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "UpdateChecklistItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should return 404 for unknown item", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    mockService.On("UpdateChecklistItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrChecklistItemNotFound)
+
+    req, _ := http.NewRequest("PATCH", "/tasks/"+bson.NewObjectID().Hex()+"/checklist/"+bson.NewObjectID().Hex(), bytes.NewBufferString(`{"done":true}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusNotFound, w.Code)
+  })
+}
+
+func TestTaskHandler_ReorderChecklist(t *testing.T) {
+  t.Run("should pass item ids in order", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    first, second := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("ReorderChecklist", mock.Anything, taskID, userID, []bson.ObjectID{second, first}).
+      Return(&types.TaskResponse{ID: taskID.Hex()}, nil)
+
+    req, _ := http.NewRequest("PUT", "/tasks/"+taskID.Hex()+"/checklist/order", bytes.NewBufferString(`{"item_ids":["`+second.Hex()+`","`+first.Hex()+`"]}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should reject duplicate item ids", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+    itemID := bson.NewObjectID().Hex()
+
+    req, _ := http.NewRequest("PUT", "/tasks/"+bson.NewObjectID().Hex()+"/checklist/order", bytes.NewBufferString(`{"item_ids":["`+itemID+`","`+itemID+`"]}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+
+  t.Run("should return 422 when items changed", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    mockService.On("ReorderChecklist", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrChecklistMismatch)
+
+    req, _ := http.NewRequest("PUT", "/tasks/"+bson.NewObjectID().Hex()+"/checklist/order", bytes.NewBufferString(`{"item_ids":["`+bson.NewObjectID().Hex()+`"]}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+  })
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+	"task-api/utils"
+)
+
+// CommentHandler - struct for task comment handlers
+type CommentHandler struct {
+  commentService services.CommentService
+}
+
+// NewCommentHandler - constructor
+func NewCommentHandler(commentService services.CommentService) *CommentHandler {
+  return &CommentHandler{
+    commentService: commentService,
+  }
+}
+
+// CreateComment - POST /tasks/:id/comments
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+
+  var input types.CommentInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, http.StatusBadRequest, "Request body required", gin.H{"error": "Please provide body"})
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  comment, err := h.commentService.CreateComment(ctx, taskID, userID.(bson.ObjectID), input)
+  if err != nil {
+    h.handleError(c, err, "Failed to create comment")
+    return
+  }
+
+  utils.Success(c, http.StatusCreated, types.MsgCommentCreated, gin.H{"comment": comment})
+}
+
+// GetComments - GET /tasks/:id/comments?cursor=&limit=
+func (h *CommentHandler) GetComments(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+
+  var query types.CommentQueryParams
+  if err := c.ShouldBindQuery(&query); err != nil {
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+
+  response, err := h.commentService.GetComments(ctx, taskID, userID.(bson.ObjectID), query)
+  if err != nil {
+    h.handleError(c, err, "Failed to get comments")
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgCommentsRetrieved, response)
+}
+
+// UpdateComment - PUT /tasks/:id/comments/:cid
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+  taskID, commentID, ok := parseCommentIDs(c)
+  if !ok {
+    return
+  }
+
+  var input types.CommentInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, http.StatusBadRequest, "Request body required", gin.H{"error": "Please provide body"})
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  comment, err := h.commentService.UpdateComment(ctx, taskID, commentID, userID.(bson.ObjectID), input)
+  if err != nil {
+    h.handleError(c, err, "Failed to update comment")
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgCommentUpdated, gin.H{"comment": comment})
+}
+
+// DeleteComment - DELETE /tasks/:id/comments/:cid
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+  taskID, commentID, ok := parseCommentIDs(c)
+  if !ok {
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  if err := h.commentService.DeleteComment(ctx, taskID, commentID, userID.(bson.ObjectID)); err != nil {
+    h.handleError(c, err, "Failed to delete comment")
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgCommentDeleted, nil)
+}
+
+// handleError - map service errors to responses
+func (h *CommentHandler) handleError(c *gin.Context, err error, msg string) {
+  switch {
+  case errors.Is(err, services.ErrTaskNotFound):
+    utils.Fail(c, http.StatusNotFound, types.MsgTaskNotFound, nil)
+  case errors.Is(err, services.ErrCommentNotFound):
+    utils.Fail(c, http.StatusNotFound, types.MsgCommentNotFound, nil)
+  case errors.Is(err, services.ErrCommentAuthorRequired):
+    utils.Fail(c, http.StatusForbidden, types.MsgCommentAuthorOnly, nil)
+  default:
+    log.Error().Err(err).Msg(msg)
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+  }
+}
+
+// parseCommentIDs - read :id and :cid path params, responds 400 when malformed
+func parseCommentIDs(c *gin.Context) (bson.ObjectID, bson.ObjectID, bool) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return bson.ObjectID{}, bson.ObjectID{}, false
+  }
+
+  commentID, err := bson.ObjectIDFromHex(c.Param("cid"))
+  if err != nil {
+    utils.Fail(c, http.StatusBadRequest, "Invalid comment ID", gin.H{"error": "Invalid ID format"})
+    return bson.ObjectID{}, bson.ObjectID{}, false
+  }
+  return taskID, commentID, true
+}
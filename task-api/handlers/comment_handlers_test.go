@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+// MockCommentService - mock for testing
+type MockCommentService struct {
+  mock.Mock
+}
+
+func (m *MockCommentService) CreateComment(ctx context.Context, taskID, userID bson.ObjectID, input types.CommentInput) (*types.CommentResponse, error) {
+  args := m.Called(ctx, taskID, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.CommentResponse), args.Error(1)
+}
+
+func (m *MockCommentService) GetComments(ctx context.Context, taskID, userID bson.ObjectID, query types.CommentQueryParams) (*types.CommentListResponse, error) {
+  args := m.Called(ctx, taskID, userID, query)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.CommentListResponse), args.Error(1)
+}
+
+func (m *MockCommentService) UpdateComment(ctx context.Context, taskID, commentID, userID bson.ObjectID, input types.CommentInput) (*types.CommentResponse, error) {
+  args := m.Called(ctx, taskID, commentID, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.CommentResponse), args.Error(1)
+}
+
+func (m *MockCommentService) DeleteComment(ctx context.Context, taskID, commentID, userID bson.ObjectID) error {
+  args := m.Called(ctx, taskID, commentID, userID)
+  return args.Error(0)
+}
+
+// setupCommentRouter - comment routes, authenticated as userID
+func setupCommentRouter(mockService *MockCommentService, userID bson.ObjectID) *gin.Engine {
+  handler := NewCommentHandler(mockService)
+  router := setupRouter()
+  router.Use(func(c *gin.Context) {
+    c.Set("userID", userID)
+    c.Next()
+  })
+  router.GET("/tasks/:id/comments", handler.GetComments)
+  router.POST("/tasks/:id/comments", handler.CreateComment)
+  router.PUT("/tasks/:id/comments/:cid", handler.UpdateComment)
+  router.DELETE("/tasks/:id/comments/:cid", handler.DeleteComment)
+  return router
+}
+
+func TestCommentHandler_CreateComment(t *testing.T) {
+  t.Run("should create comment", func(t *testing.T) {
+    mockService := new(MockCommentService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupCommentRouter(mockService, userID)
+
+    mockService.On("CreateComment", mock.Anything, taskID, userID, types.CommentInput{Body: "Hi @bob@example.com"}).
+      Return(&types.CommentResponse{ID: bson.NewObjectID().Hex(), Body: "Hi @bob@example.com"}, nil)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/comments", bytes.NewBufferString(`{"body":"Hi @bob@example.com"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusCreated, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should reject empty body", func(t *testing.T) {
+    mockService := new(MockCommentService)
+    router := setupCommentRouter(mockService, bson.NewObjectID())
+
+    req, _ := http.NewRequest("POST", "/tasks/"+bson.NewObjectID().Hex()+"/comments", bytes.NewBufferString(`{"body":""}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+
+  t.Run("should return 404 for invisible task", func(t *testing.T) {
+    mockService := new(MockCommentService)
+    router := setupCommentRouter(mockService, bson.NewObjectID())
+
+    mockService.On("CreateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrTaskNotFound)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+bson.NewObjectID().Hex()+"/comments", bytes.NewBufferString(`{"body":"Hello"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusNotFound, w.Code)
+  })
+}
+
+func TestCommentHandler_GetComments(t *testing.T) {
+  t.Run("should pass cursor and limit", func(t *testing.T) {
+    mockService := new(MockCommentService)
+    userID, taskID, cursor := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+    router := setupCommentRouter(mockService, userID)
+
+    mockService.On("GetComments", mock.Anything, taskID, userID, types.CommentQueryParams{Cursor: cursor.Hex(), Limit: 5}).
+      Return(&types.CommentListResponse{Comments: []types.CommentResponse{}, NextCursor: "next"}, nil)
+
+    req, _ := http.NewRequest("GET", "/tasks/"+taskID.Hex()+"/comments?cursor="+cursor.Hex()+"&limit=5", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+
+    var response map[string]any
+    json.Unmarshal(w.Body.Bytes(), &response)
+    data := response["data"].(map[string]any)
+    assert.Equal(t, "next", data["next_cursor"])
+  })
+
+  t.Run("should reject malformed cursor", func(t *testing.T) {
+    mockService := new(MockCommentService)
+    router := setupCommentRouter(mockService, bson.NewObjectID())
+
+    req, _ := http.NewRequest("GET", "/tasks/"+bson.NewObjectID().Hex()+"/comments?cursor=abc", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+}
+
+func TestCommentHandler_DeleteComment(t *testing.T) {
+  t.Run("should return 403 for another user's comment", func(t *testing.T) {
+    mockService := new(MockCommentService)
+    router := setupCommentRouter(mockService, bson.NewObjectID())
+
+    mockService.On("DeleteComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(services.ErrCommentAuthorRequired)
+
+    req, _ := http.NewRequest("DELETE", "/tasks/"+bson.NewObjectID().Hex()+"/comments/"+bson.NewObjectID().Hex(), nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusForbidden, w.Code)
+  })
+
+  t.Run("should reject invalid comment id", func(t *testing.T) {
+    mockService := new(MockCommentService)
+    router := setupCommentRouter(mockService, bson.NewObjectID())
+
+    req, _ := http.NewRequest("DELETE", "/tasks/"+bson.NewObjectID().Hex()+"/comments/bad", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "DeleteComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+}
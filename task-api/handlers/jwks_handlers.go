@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"task-api/types"
+	"task-api/utils"
+)
+
+// JWKS - public keys for verifying our access tokens.
+// Served as a plain JWK Set (RFC 7517) instead of JSend so standard JWT libraries can consume it.
+func JWKS(c *gin.Context) {
+  jwks, err := utils.CurrentJWKS()
+  if err != nil {
+    log.Error().Err(err).Msg("Failed to load JWKS")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  // Short cache so verifiers pick up a rotated key quickly
+  c.Header("Cache-Control", "public, max-age=300")
+  c.JSON(http.StatusOK, jwks)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWKS(t *testing.T) {
+  t.Run("should serve plain JWK set with cache header", func(t *testing.T) {
+    router := setupRouter()
+    router.GET("/.well-known/jwks.json", JWKS)
+
+    req, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+    // HS256 secret configured in TestMain is never published
+    assert.JSONEq(t, `{"keys":[]}`, w.Body.String())
+  })
+}
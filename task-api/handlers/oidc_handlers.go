@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"task-api/oidc"
+	"task-api/services"
+	"task-api/types"
+	"task-api/utils"
+)
+
+// oidcStateCookie - binds the login to the browser that started it (login CSRF protection)
+const (
+  oidcStateCookie     = "oidc_state"
+  oidcStateCookiePath = "/auth/oidc"
+)
+
+// OIDCHandler - struct for single sign-on handlers
+type OIDCHandler struct {
+  oidcService services.OIDCService
+}
+
+// NewOIDCHandler - constructor
+func NewOIDCHandler(oidcService services.OIDCService) *OIDCHandler {
+  return &OIDCHandler{
+    oidcService: oidcService,
+  }
+}
+
+// Login - GET /auth/oidc/login, redirect the browser to the identity provider
+func (h *OIDCHandler) Login(c *gin.Context) {
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+
+  start, err := h.oidcService.Start(ctx)
+  if err != nil {
+    switch {
+    case errors.Is(err, services.ErrOIDCDisabled):
+      utils.Fail(c, http.StatusNotFound, types.MsgOIDCDisabled, nil)
+    case errors.Is(err, oidc.ErrDiscovery):
+      log.Error().Err(err).Msg("OIDC discovery failed")
+      utils.Error(c, http.StatusServiceUnavailable, types.MsgOIDCUnavailable, 0, nil)
+    default:
+      log.Error().Err(err).Msg("Failed to start OIDC login")
+      utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    }
+    return
+  }
+
+  // Lax keeps the cookie on the IdP's top-level redirect back to the callback
+  c.SetSameSite(http.SameSiteLaxMode)
+  c.SetCookie(oidcStateCookie, start.State, start.ExpiresIn, oidcStateCookiePath, "", isSecureRequest(c), true)
+
+  c.Redirect(http.StatusFound, start.AuthorizationURL)
+}
+
+// Callback - GET /auth/oidc/callback, finish the login and return the usual token pair
+func (h *OIDCHandler) Callback(c *gin.Context) {
+  var input types.OIDCCallbackInput
+  if err := c.ShouldBindQuery(&input); err != nil {
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+      "error": err.Error(),
+    })
+    return
+  }
+
+  // The state cookie is single-use as well
+  cookieState, _ := c.Cookie(oidcStateCookie)
+  c.SetSameSite(http.SameSiteLaxMode)
+  c.SetCookie(oidcStateCookie, "", -1, oidcStateCookiePath, "", isSecureRequest(c), true)
+
+  if cookieState == "" || subtle.ConstantTimeCompare([]byte(cookieState), []byte(input.State)) != 1 {
+    utils.Fail(c, http.StatusBadRequest, types.MsgOIDCLoginFailed, gin.H{
+      "error": types.MsgOIDCInvalidState,
+    })
+    return
+  }
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+
+  response, err := h.oidcService.Callback(ctx, input)
+  if err != nil {
+    switch {
+    case errors.Is(err, services.ErrOIDCDisabled):
+      utils.Fail(c, http.StatusNotFound, types.MsgOIDCDisabled, nil)
+    case errors.Is(err, services.ErrInvalidOIDCState):
+      utils.Fail(c, http.StatusBadRequest, types.MsgOIDCLoginFailed, gin.H{
+        "error": types.MsgOIDCInvalidState,
+      })
+    case errors.Is(err, services.ErrOIDCLoginFailed):
+      log.Warn().Err(err).Msg("OIDC login failed")
+      utils.Fail(c, http.StatusUnauthorized, types.MsgOIDCLoginFailed, nil)
+    case errors.Is(err, services.ErrOIDCEmailNotVerified):
+      utils.Fail(c, http.StatusForbidden, types.MsgOIDCLoginFailed, gin.H{
+        "error": types.MsgOIDCEmailNotVerified,
+      })
+    case errors.Is(err, services.ErrAccountDisabled):
+      utils.Fail(c, http.StatusForbidden, types.MsgOIDCLoginFailed, gin.H{
+        "error": types.MsgAccountDisabled,
+      })
+    case errors.Is(err, services.ErrOIDCAccountConflict):
+      utils.Fail(c, http.StatusConflict, types.MsgOIDCLoginFailed, gin.H{
+        "error": types.MsgOIDCAccountConflict,
+      })
+    default:
+      log.Error().Err(err).Msg("OIDC callback failed")
+      utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    }
+    return
+  }
+
+  if response.MFARequired {
+    utils.Success(c, http.StatusOK, types.MsgMFARequired, response)
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgLoginSuccess, response)
+}
+
+// isSecureRequest - HTTPS directly or behind a TLS terminating proxy
+func isSecureRequest(c *gin.Context) bool {
+  return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
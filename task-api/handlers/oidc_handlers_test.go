@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+// MockOIDCService mocks the OIDCService interface
+type MockOIDCService struct {
+  mock.Mock
+}
+
+func (m *MockOIDCService) Start(ctx context.Context) (*types.OIDCLoginStart, error) {
+  args := m.Called(ctx)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.OIDCLoginStart), args.Error(1)
+}
+
+func (m *MockOIDCService) Callback(ctx context.Context, input types.OIDCCallbackInput) (*types.LoginResponse, error) {
+  args := m.Called(ctx, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.LoginResponse), args.Error(1)
+}
+
+// callbackRequest - callback request carrying the state cookie set by Login
+func callbackRequest(query, cookieState string) *http.Request {
+  req, _ := http.NewRequest("GET", "/auth/oidc/callback?"+query, nil)
+  if cookieState != "" {
+    req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: cookieState})
+  }
+  return req
+}
+
+func TestOIDCHandler_Login(t *testing.T) {
+  t.Run("should redirect to IdP and set state cookie", func(t *testing.T) {
+    mockService := new(MockOIDCService)
+    handler := NewOIDCHandler(mockService)
+    router := setupRouter()
+    router.GET("/auth/oidc/login", handler.Login)
+
+    mockService.On("Start", mock.Anything).Return(&types.OIDCLoginStart{
+      AuthorizationURL: "https://idp.example.com/authorize?state=abc",
+      State:            "abc",
+      ExpiresIn:        600,
+    }, nil)
+
+    req, _ := http.NewRequest("GET", "/auth/oidc/login", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusFound, w.Code)
+    assert.Equal(t, "https://idp.example.com/authorize?state=abc", w.Header().Get("Location"))
+
+    cookie := w.Header().Get("Set-Cookie")
+    assert.Contains(t, cookie, "oidc_state=abc")
+    assert.Contains(t, cookie, "HttpOnly")
+    assert.Contains(t, cookie, "SameSite=Lax")
+  })
+
+  t.Run("should return 404 when SSO is not configured", func(t *testing.T) {
+    mockService := new(MockOIDCService)
+    handler := NewOIDCHandler(mockService)
+    router := setupRouter()
+    router.GET("/auth/oidc/login", handler.Login)
+
+    mockService.On("Start", mock.Anything).Return(nil, services.ErrOIDCDisabled)
+
+    req, _ := http.NewRequest("GET", "/auth/oidc/login", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusNotFound, w.Code)
+  })
+}
+
+func TestOIDCHandler_Callback(t *testing.T) {
+  t.Run("should return token pair", func(t *testing.T) {
+    mockService := new(MockOIDCService)
+    handler := NewOIDCHandler(mockService)
+    router := setupRouter()
+    router.GET("/auth/oidc/callback", handler.Callback)
+
+    mockService.On("Callback", mock.Anything, types.OIDCCallbackInput{Code: "code-1", State: "abc"}).
+      Return(&types.LoginResponse{Token: "access", RefreshToken: "refresh"}, nil)
+
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, callbackRequest("code=code-1&state=abc", "abc"))
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "access")
+    assert.Contains(t, w.Header().Get("Set-Cookie"), "Max-Age=0")
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should reject state not matching the cookie", func(t *testing.T) {
+    mockService := new(MockOIDCService)
+    handler := NewOIDCHandler(mockService)
+    router := setupRouter()
+    router.GET("/auth/oidc/callback", handler.Callback)
+
+    for _, cookie := range []string{"", "other"} {
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, callbackRequest("code=code-1&state=abc", cookie))
+
+      assert.Equal(t, http.StatusBadRequest, w.Code)
+    }
+    mockService.AssertNotCalled(t, "Callback", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should map service errors", func(t *testing.T) {
+    cases := []struct {
+      err    error
+      status int
+    }{
+      {services.ErrInvalidOIDCState, http.StatusBadRequest},
+      {services.ErrOIDCLoginFailed, http.StatusUnauthorized},
+      {services.ErrOIDCEmailNotVerified, http.StatusForbidden},
+      {services.ErrOIDCAccountConflict, http.StatusConflict},
+      {services.ErrAccountDisabled, http.StatusForbidden},
+    }
+
+    for _, tc := range cases {
+      mockService := new(MockOIDCService)
+      handler := NewOIDCHandler(mockService)
+      router := setupRouter()
+      router.GET("/auth/oidc/callback", handler.Callback)
+
+      mockService.On("Callback", mock.Anything, mock.Anything).Return(nil, tc.err)
+
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, callbackRequest("code=code-1&state=abc", "abc"))
+
+      assert.Equal(t, tc.status, w.Code, tc.err.Error())
+    }
+  })
+
+  t.Run("should return 400 without state", func(t *testing.T) {
+    mockService := new(MockOIDCService)
+    handler := NewOIDCHandler(mockService)
+    router := setupRouter()
+    router.GET("/auth/oidc/callback", handler.Callback)
+
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, callbackRequest("code=code-1", "abc"))
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+}
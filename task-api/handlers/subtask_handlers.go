@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/types"
+	"task-api/utils"
+)
+
+// GetSubtasks - GET /tasks/:id/subtasks
+func (h *TaskHandler) GetSubtasks(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+  
+  subtasks, err := h.taskService.GetSubtasks(ctx, taskID, userID.(bson.ObjectID))
+  if err != nil {
+    h.handleError(c, err, "Failed to get subtasks")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgSubtasksRetrieved, gin.H{"subtasks": subtasks})
+}
+
+// CreateSubtask - POST /tasks/:id/subtasks
+func (h *TaskHandler) CreateSubtask(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  var input types.CreateTaskInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, 400, "Request body required", gin.H{"error": "Please provide task details"})
+      return
+    }
+    
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.CreateSubtask(ctx, taskID, userID.(bson.ObjectID), input)
+  if err != nil {
+    h.handleError(c, err, "Failed to create subtask")
+    return
+  }
+  
+  utils.Success(c, 201, types.MsgTaskCreated, gin.H{"task": response})
+}
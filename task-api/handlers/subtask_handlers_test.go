@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+func TestTaskHandler_GetSubtasks(t *testing.T) {
+  t.Run("should list subtasks", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("GetSubtasks", mock.Anything, taskID, userID).Return([]types.TaskResponse{{ID: bson.NewObjectID().Hex(), ParentID: taskID.Hex()}}, nil)
+
+    req, _ := http.NewRequest("GET", "/tasks/"+taskID.Hex()+"/subtasks", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), taskID.Hex())
+  })
+
+  t.Run("should return 404 for hidden task", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    mockService.On("GetSubtasks", mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrTaskNotFound)
+
+    req, _ := http.NewRequest("GET", "/tasks/"+bson.NewObjectID().Hex()+"/subtasks", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusNotFound, w.Code)
+  })
+}
+
+func TestTaskHandler_CreateSubtask(t *testing.T) {
+  t.Run("should create subtask", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("CreateSubtask", mock.Anything, taskID, userID, mock.MatchedBy(func(input types.CreateTaskInput) bool {
+      return input.Title == "Step"
+    })).Return(&types.TaskResponse{ID: bson.NewObjectID().Hex(), Title: "Step", ParentID: taskID.Hex()}, nil)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/subtasks", bytes.NewBufferString(`{"title":"Step"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusCreated, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 422 when too deep", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    mockService.On("CreateSubtask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrTaskDepthExceeded)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+bson.NewObjectID().Hex()+"/subtasks", bytes.NewBufferString(`{"title":"Step"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+  })
+}
+
+func TestTaskHandler_MoveTask(t *testing.T) {
+  t.Run("should map hierarchy errors", func(t *testing.T) {
+    tests := []struct {
+      err  error
+      code int
+    }{
+      {services.ErrTaskCycle, http.StatusConflict},
+      {services.ErrTaskDepthExceeded, http.StatusUnprocessableEntity},
+      {services.ErrInvalidParentTask, http.StatusUnprocessableEntity},
+      {services.ErrTaskNotFound, http.StatusNotFound},
+    }
+
+    for _, tt := range tests {
+      mockService := new(MockTaskService)
+      router := setupTaskRouter(mockService, bson.NewObjectID())
+      mockService.On("UpdateTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.err)
+
+      req, _ := http.NewRequest("PUT", "/tasks/"+bson.NewObjectID().Hex(), bytes.NewBufferString(`{"parent_id":"`+bson.NewObjectID().Hex()+`"}`))
+      req.Header.Set("Content-Type", "application/json")
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, req)
+
+      assert.Equal(t, tt.code, w.Code, tt.err.Error())
+    }
+  })
+
+  t.Run("should reject invalid parent id", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    req, _ := http.NewRequest("PUT", "/tasks/"+bson.NewObjectID().Hex(), bytes.NewBufferString(`{"parent_id":"nope"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "UpdateTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/types"
+	"task-api/utils"
+)
+
+// AddBlocker - POST /tasks/:id/blockers
+func (h *TaskHandler) AddBlocker(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  var input types.AddBlockerInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, 400, "Request body required", gin.H{"error": "Please provide task_id"})
+      return
+    }
+    
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
+  blockerID, _ := bson.ObjectIDFromHex(input.TaskID)
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.AddBlocker(ctx, taskID, userID.(bson.ObjectID), blockerID)
+  if err != nil {
+    h.handleError(c, err, "Failed to add blocker")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgBlockerAdded, gin.H{"task": response})
+}
+
+// RemoveBlocker - DELETE /tasks/:id/blockers/:bid
+func (h *TaskHandler) RemoveBlocker(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  blockerID, err := bson.ObjectIDFromHex(c.Param("bid"))
+  if err != nil {
+    utils.Fail(c, 400, "Invalid task ID", gin.H{"error": "Invalid ID format"})
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.RemoveBlocker(ctx, taskID, userID.(bson.ObjectID), blockerID)
+  if err != nil {
+    h.handleError(c, err, "Failed to remove blocker")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgBlockerRemoved, gin.H{"task": response})
+}
+
+// GetTaskDependencies - GET /tasks/:id/dependencies
+func (h *TaskHandler) GetTaskDependencies(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+  
+  graph, err := h.taskService.GetTaskDependencies(ctx, taskID, userID.(bson.ObjectID))
+  if err != nil {
+    h.handleError(c, err, "Failed to get task dependencies")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgDependenciesRetrieved, graph)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+func TestTaskHandler_AddBlocker(t *testing.T) {
+  t.Run("should add blocker", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID, blockerID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("AddBlocker", mock.Anything, taskID, userID, blockerID).
+      Return(&types.TaskResponse{ID: taskID.Hex(), BlockedBy: []string{blockerID.Hex()}}, nil)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/blockers", bytes.NewBufferString(`{"task_id":"`+blockerID.Hex()+`"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should map dependency errors", func(t *testing.T) {
+    tests := []struct {
+      err  error
+      code int
+    }{
+      {services.ErrDependencyCycle, http.StatusConflict},
+      {services.ErrInvalidDependency, http.StatusUnprocessableEntity},
+      {services.ErrTaskPermissionDenied, http.StatusForbidden},
+      {services.ErrTaskNotFound, http.StatusNotFound},
+    }
+
+    for _, tt := range tests {
+      mockService := new(MockTaskService)
+      router := setupTaskRouter(mockService, bson.NewObjectID())
+      mockService.On("AddBlocker", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.err)
+
+      req, _ := http.NewRequest("POST", "/tasks/"+bson.NewObjectID().Hex()+"/blockers", bytes.NewBufferString(`{"task_id":"`+bson.NewObjectID().Hex()+`"}`))
+      req.Header.Set("Content-Type", "application/json")
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, req)
+
+      assert.Equal(t, tt.code, w.Code, tt.err.Error())
+    }
+  })
+}
+
+func TestTaskHandler_RemoveBlocker(t *testing.T) {
+  t.Run("should reject invalid blocker id", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    req, _ := http.NewRequest("DELETE", "/tasks/"+bson.NewObjectID().Hex()+"/blockers/nope", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "RemoveBlocker", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+}
+
+func TestTaskHandler_GetTaskDependencies(t *testing.T) {
+  t.Run("should return dependency graph", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("GetTaskDependencies", mock.Anything, taskID, userID).Return(&types.TaskDependencyGraph{
+      TaskID: taskID.Hex(),
+      Nodes:  []types.TaskDependencyNode{{ID: taskID.Hex()}},
+      Edges:  []types.TaskDependencyEdge{},
+    }, nil)
+
+    req, _ := http.NewRequest("GET", "/tasks/"+taskID.Hex()+"/dependencies", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), `"edges":[]`)
+  })
+}
+
+func TestTaskHandler_UpdateBlockedTask(t *testing.T) {
+  t.Run("should return 409 with open blockers", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+    blocker := types.TaskDependencyNode{ID: bson.NewObjectID().Hex(), Title: "Design", Status: types.TaskStatusPending}
+
+    mockService.On("UpdateTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+      Return(nil, &services.TaskBlockedError{Blockers: []types.TaskDependencyNode{blocker}})
+
+    req, _ := http.NewRequest("PUT", "/tasks/"+bson.NewObjectID().Hex(), bytes.NewBufferString(`{"status":"in_progress"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusConflict, w.Code)
+
+    var body struct {
+      Data struct {
+        Blockers []types.TaskDependencyNode `json:"blockers"`
+      } `json:"data"`
+    }
+    json.Unmarshal(w.Body.Bytes(), &body)
+    assert.Equal(t, []types.TaskDependencyNode{blocker}, body.Data.Blockers)
+  })
+}
@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -47,6 +51,11 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
     return
   }
   
+  // POST /workspaces/:wid/tasks
+  if wid := c.Param("wid"); wid != "" {
+    input.WorkspaceID = wid
+  }
+  
   ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
   defer cancel()
   
@@ -57,6 +66,16 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
   
   response, err := h.taskService.CreateTask(ctx, userID.(bson.ObjectID), input)
   if err != nil {
+    if errors.Is(err, services.ErrWorkspaceNotFound) {
+      utils.Fail(c, 404, types.MsgWorkspaceNotFound, nil)
+      return
+    }
+    
+    if errors.Is(err, services.ErrInvalidRecurrence) {
+      utils.Fail(c, 422, types.MsgInvalidRecurrence, gin.H{"error": err.Error()})
+      return
+    }
+    
     log.Error().Err(err).Msg("Failed to create task")
     utils.Error(c, 500, types.MsgInternalError, 0, nil)
     return
@@ -79,6 +98,11 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
     return
   }
   
+  // GET /workspaces/:wid/tasks
+  if wid := c.Param("wid"); wid != "" {
+    query.WorkspaceID = wid
+  }
+  
   userID, _ := c.Get("userID")
   
   ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
@@ -86,6 +110,11 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
   
   response, err := h.taskService.GetTasks(ctx, userID.(bson.ObjectID), query)
   if err != nil {
+    if errors.Is(err, services.ErrWorkspaceNotFound) {
+      utils.Fail(c, 404, types.MsgWorkspaceNotFound, nil)
+      return
+    }
+    
     log.Error().Err(err).Msg("Failed to get tasks")
     utils.Error(c, 500, types.MsgInternalError, 0, nil)
     return
@@ -116,6 +145,7 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
     return
   }
   
+  setETag(c, response)
   utils.Success(c, 200, types.MsgTaskRetrieved, gin.H{"task": response})
 }
 
@@ -146,8 +176,13 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
     Str("user_id", userID.(bson.ObjectID).Hex()).
     Msg("Updating task")
   
-  response, err := h.taskService.UpdateTask(ctx, objectID, userID.(bson.ObjectID), input)
+  response, err := h.taskService.UpdateTask(ctx, objectID, userID.(bson.ObjectID), input, ifMatch(c))
   if err != nil {
+    if isTaskRuleError(err) {
+      h.handleError(c, err, "Failed to update task")
+      return
+    }
+    
     log.Error().Err(err).Str("task_id", taskID).Msg("Failed to update task")
     utils.Fail(c, 404, types.MsgTaskNotFound, nil)
     return
@@ -155,6 +190,7 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
   
   log.Info().Str("task_id", taskID).Msg("Task updated successfully")
   
+  setETag(c, response)
   utils.Success(c, 200, types.MsgTaskUpdated, gin.H{"task": response})
 }
 
@@ -168,24 +204,253 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
     return
   }
   
+  var query types.DeleteTaskQueryParams
+  if err := c.ShouldBindQuery(&query); err != nil {
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
   userID, _ := c.Get("userID")
   
-  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
   defer cancel()
   
   log.Info().
     Str("task_id", taskID).
     Str("user_id", userID.(bson.ObjectID).Hex()).
+    Bool("permanent", query.Permanent).
     Msg("Deleting task")
   
-  err = h.taskService.DeleteTask(ctx, objectID, userID.(bson.ObjectID))
+  // Deleted tasks go to the trash unless asked to delete them for good
+  msg := types.MsgTaskTrashed
+  versions := ifMatch(c)
+  if query.Permanent {
+    msg = types.MsgTaskPurged
+    err = h.taskService.PurgeTask(ctx, objectID, userID.(bson.ObjectID), versions)
+  } else {
+    err = h.taskService.DeleteTask(ctx, objectID, userID.(bson.ObjectID), versions)
+  }
   if err != nil {
+    if isTaskRuleError(err) {
+      h.handleError(c, err, "Failed to delete task")
+      return
+    }
+    
     log.Error().Err(err).Str("task_id", taskID).Msg("Failed to delete task")
     utils.Fail(c, 404, types.MsgTaskNotFound, nil)
     return
   }
   
-  log.Info().Str("task_id", taskID).Msg("Task deleted successfully")
+  log.Info().Str("task_id", taskID).Msg(msg)
+  
+  utils.Success(c, 200, msg, gin.H{"deleted_id": taskID, "permanent": query.Permanent})
+}
+
+// AssignTask - POST /tasks/:id/assignees
+func (h *TaskHandler) AssignTask(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  var input types.AssignTaskInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, 400, "Request body required", gin.H{"error": "Please provide user_id"})
+      return
+    }
+    
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
+  assigneeID, _ := bson.ObjectIDFromHex(input.UserID)
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.AssignTask(ctx, taskID, userID.(bson.ObjectID), assigneeID)
+  if err != nil {
+    h.handleError(c, err, "Failed to assign task")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgTaskAssigned, gin.H{"task": response})
+}
+
+// UnassignTask - DELETE /tasks/:id/assignees/:uid
+func (h *TaskHandler) UnassignTask(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  assigneeID, err := bson.ObjectIDFromHex(c.Param("uid"))
+  if err != nil {
+    utils.Fail(c, 400, "Invalid user ID", gin.H{"error": "Invalid ID format"})
+    return
+  }
   
-  utils.Success(c, 200, types.MsgTaskDeleted, gin.H{"deleted_id": taskID})
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.UnassignTask(ctx, taskID, userID.(bson.ObjectID), assigneeID)
+  if err != nil {
+    h.handleError(c, err, "Failed to unassign task")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgTaskUnassigned, gin.H{"task": response})
+}
+
+// WatchTask - POST /tasks/:id/watch
+func (h *TaskHandler) WatchTask(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.WatchTask(ctx, taskID, userID.(bson.ObjectID))
+  if err != nil {
+    h.handleError(c, err, "Failed to watch task")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgTaskWatched, gin.H{"task": response})
+}
+
+// UnwatchTask - DELETE /tasks/:id/watch
+func (h *TaskHandler) UnwatchTask(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.UnwatchTask(ctx, taskID, userID.(bson.ObjectID))
+  if err != nil {
+    h.handleError(c, err, "Failed to unwatch task")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgTaskUnwatched, gin.H{"task": response})
+}
+
+// handleError - map task service errors to responses
+func (h *TaskHandler) handleError(c *gin.Context, err error, msg string) {
+  var blocked *services.TaskBlockedError
+  var outdated *services.TaskVersionError
+  switch {
+  case errors.Is(err, services.ErrTaskNotFound):
+    utils.Fail(c, 404, types.MsgTaskNotFound, nil)
+  case errors.Is(err, services.ErrUserNotFound):
+    utils.Fail(c, 404, types.MsgUserNotFound, nil)
+  case errors.Is(err, services.ErrWorkspaceNotFound):
+    utils.Fail(c, 404, types.MsgWorkspaceNotFound, nil)
+  case errors.Is(err, services.ErrTaskOwnerRequired):
+    utils.Fail(c, 403, types.MsgTaskOwnerOnly, nil)
+  case errors.Is(err, services.ErrAssigneeNotMember):
+    utils.Fail(c, 422, types.MsgAssigneeNotMember, nil)
+  case errors.Is(err, services.ErrTaskShareNotFound):
+    utils.Fail(c, 404, types.MsgTaskShareNotFound, nil)
+  case errors.Is(err, services.ErrTaskPermissionDenied):
+    utils.Fail(c, 403, types.MsgTaskPermissionDenied, nil)
+  case errors.Is(err, services.ErrCannotShareWithSelf):
+    utils.Fail(c, 422, types.MsgCannotShareWithSelf, nil)
+  case errors.Is(err, services.ErrTaskCycle):
+    utils.Fail(c, 409, types.MsgTaskCycle, nil)
+  case errors.Is(err, services.ErrTaskDepthExceeded):
+    utils.Fail(c, 422, types.MsgTaskDepthExceeded, gin.H{"max_depth": services.MaxTaskDepth})
+  case errors.Is(err, services.ErrInvalidParentTask):
+    utils.Fail(c, 422, types.MsgInvalidParentTask, nil)
+  case errors.As(err, &blocked):
+    utils.Fail(c, 409, types.MsgTaskBlocked, gin.H{"blockers": blocked.Blockers})
+  case errors.As(err, &outdated):
+    setETag(c, outdated.Current)
+    utils.Fail(c, 412, types.MsgTaskVersionMismatch, gin.H{"task": outdated.Current})
+  case errors.Is(err, services.ErrDependencyCycle):
+    utils.Fail(c, 409, types.MsgDependencyCycle, nil)
+  case errors.Is(err, services.ErrInvalidDependency):
+    utils.Fail(c, 422, types.MsgInvalidDependency, nil)
+  case errors.Is(err, services.ErrInvalidRecurrence):
+    utils.Fail(c, 422, types.MsgInvalidRecurrence, gin.H{"error": err.Error()})
+  case errors.Is(err, services.ErrChecklistItemNotFound):
+    utils.Fail(c, 404, types.MsgChecklistItemNotFound, nil)
+  case errors.Is(err, services.ErrChecklistMismatch):
+    utils.Fail(c, 422, types.MsgChecklistMismatch, nil)
+  case errors.Is(err, services.ErrTaskNotTrashed):
+    utils.Fail(c, 409, types.MsgTaskNotTrashed, nil)
+  case errors.Is(err, services.ErrTaskArchived):
+    utils.Fail(c, 409, types.MsgTaskIsArchived, nil)
+  case errors.Is(err, services.ErrTaskNotArchived):
+    utils.Fail(c, 409, types.MsgTaskNotArchived, nil)
+  case errors.Is(err, services.ErrInvalidBulkOperation):
+    utils.Fail(c, 422, types.MsgInvalidBulkOperation, nil)
+  case errors.Is(err, services.ErrBulkTooLarge):
+    utils.Fail(c, 422, types.MsgBulkTooLarge, gin.H{"max_tasks": services.MaxBulkTasks})
+  case errors.Is(err, services.ErrChecklistFull):
+    utils.Fail(c, 422, types.MsgChecklistFull, gin.H{"max_items": services.MaxChecklistItems})
+  default:
+    log.Error().Err(err).Msg(msg)
+    utils.Error(c, 500, types.MsgInternalError, 0, nil)
+  }
+}
+
+// isTaskRuleError - errors of UpdateTask/DeleteTask with their own status, others answer not found
+func isTaskRuleError(err error) bool {
+  return errors.Is(err, services.ErrTaskPermissionDenied) ||
+    errors.Is(err, services.ErrTaskCycle) ||
+    errors.Is(err, services.ErrTaskDepthExceeded) ||
+    errors.Is(err, services.ErrInvalidParentTask) ||
+    errors.Is(err, services.ErrTaskBlocked) ||
+    errors.Is(err, services.ErrInvalidRecurrence) ||
+    errors.Is(err, services.ErrVersionMismatch)
+}
+
+// ifMatch - task versions listed in If-Match, nil without the header or for "*".
+// If-Match compares strongly, so weak tags and tags that are not a version match nothing.
+func ifMatch(c *gin.Context) []int64 {
+  header := strings.TrimSpace(strings.Join(c.Request.Header.Values("If-Match"), ","))
+  if header == "" || header == "*" {
+    return nil
+  }
+
+  versions := []int64{}
+  for _, tag := range strings.Split(header, ",") {
+    tag = strings.TrimSpace(tag)
+    if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+      continue
+    }
+    if version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64); err == nil {
+      versions = append(versions, version)
+    }
+  }
+  return versions
+}
+
+// setETag - ETag of a task, its version; clients send it back as If-Match
+func setETag(c *gin.Context, task *types.TaskResponse) {
+  c.Header("ETag", fmt.Sprintf(`"%d"`, task.Version))
+}
+
+// parseTaskID - read :id path param, responds 400 when malformed
+func parseTaskID(c *gin.Context) (bson.ObjectID, bool) {
+  taskID, err := bson.ObjectIDFromHex(c.Param("id"))
+  if err != nil {
+    utils.Fail(c, 400, "Invalid task ID", gin.H{"error": "Invalid ID format"})
+    return bson.ObjectID{}, false
+  }
+  return taskID, true
 }
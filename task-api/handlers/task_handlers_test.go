@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
 
+	"task-api/services"
 	"task-api/types"
 )
 
@@ -46,19 +48,246 @@ func (m *MockTaskService) GetTask(ctx context.Context, taskID bson.ObjectID, use
   return args.Get(0).(*types.TaskResponse), args.Error(1)
 }
 
-func (m *MockTaskService) UpdateTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.UpdateTaskInput) (*types.TaskResponse, error) {
-  args := m.Called(ctx, taskID, userID, input)
+func (m *MockTaskService) UpdateTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.UpdateTaskInput, versions []int64) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID, input, versions)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) DeleteTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, versions []int64) error {
+  args := m.Called(ctx, taskID, userID, versions)
+  return args.Error(0)
+}
+
+func (m *MockTaskService) PurgeTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, versions []int64) error {
+  args := m.Called(ctx, taskID, userID, versions)
+  return args.Error(0)
+}
+
+func (m *MockTaskService) GetTrash(ctx context.Context, userID bson.ObjectID, query types.TrashQueryParams) (*types.TaskListResponse, error) {
+  args := m.Called(ctx, userID, query)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskListResponse), args.Error(1)
+}
+
+func (m *MockTaskService) RestoreTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) BulkTasks(ctx context.Context, userID bson.ObjectID, input types.BulkTaskInput) (*types.BulkTaskResponse, error) {
+  args := m.Called(ctx, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.BulkTaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) ArchiveTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) UnarchiveTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) AssignTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID, assigneeID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) UnassignTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID, assigneeID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) WatchTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) UnwatchTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID)
   if args.Get(0) == nil {
     return nil, args.Error(1)
   }
   return args.Get(0).(*types.TaskResponse), args.Error(1)
 }
 
-func (m *MockTaskService) DeleteTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) error {
+func (m *MockTaskService) ShareTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.ShareTaskInput) (*types.TaskShareResponse, error) {
+  args := m.Called(ctx, taskID, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskShareResponse), args.Error(1)
+}
+
+func (m *MockTaskService) GetTaskShares(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) ([]types.TaskShareResponse, error) {
   args := m.Called(ctx, taskID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]types.TaskShareResponse), args.Error(1)
+}
+
+func (m *MockTaskService) RevokeTaskShare(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, sharedWithID bson.ObjectID) error {
+  args := m.Called(ctx, taskID, userID, sharedWithID)
   return args.Error(0)
 }
 
+func (m *MockTaskService) GetSharedTasks(ctx context.Context, userID bson.ObjectID, query types.SharedTaskQueryParams) (*types.SharedTaskListResponse, error) {
+  args := m.Called(ctx, userID, query)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.SharedTaskListResponse), args.Error(1)
+}
+
+func (m *MockTaskService) GetSubtasks(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) ([]types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) CreateSubtask(ctx context.Context, parentID bson.ObjectID, userID bson.ObjectID, input types.CreateTaskInput) (*types.TaskResponse, error) {
+  args := m.Called(ctx, parentID, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) AddBlocker(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, blockerID bson.ObjectID) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID, blockerID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) RemoveBlocker(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, blockerID bson.ObjectID) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID, blockerID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) GetTaskDependencies(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskDependencyGraph, error) {
+  args := m.Called(ctx, taskID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskDependencyGraph), args.Error(1)
+}
+
+func (m *MockTaskService) AddChecklistItem(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.AddChecklistItemInput) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) UpdateChecklistItem(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, itemID bson.ObjectID, input types.UpdateChecklistItemInput) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID, itemID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) RemoveChecklistItem(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, itemID bson.ObjectID) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID, itemID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) ReorderChecklist(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, itemIDs []bson.ObjectID) (*types.TaskResponse, error) {
+  args := m.Called(ctx, taskID, userID, itemIDs)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskResponse), args.Error(1)
+}
+
+func (m *MockTaskService) GetTaskHistory(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, query types.TaskHistoryQueryParams) (*types.TaskHistoryResponse, error) {
+  args := m.Called(ctx, taskID, userID, query)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.TaskHistoryResponse), args.Error(1)
+}
+
+// setupTaskRouter - task routes as registered by routes.SetupTaskRoutes, authenticated as userID
+func setupTaskRouter(mockService *MockTaskService, userID bson.ObjectID) *gin.Engine {
+  handler := NewTaskHandler(mockService)
+  router := setupRouter()
+  router.Use(func(c *gin.Context) {
+    c.Set("userID", userID)
+    c.Next()
+  })
+
+  tasks := router.Group("/tasks")
+  tasks.POST("", handler.CreateTask)
+  tasks.GET("", handler.GetTasks)
+  tasks.GET("/shared", handler.GetSharedTasks)
+  tasks.GET("/trash", handler.GetTrash)
+  tasks.POST("/bulk", handler.BulkTasks)
+  tasks.GET("/:id", handler.GetTask)
+  tasks.PUT("/:id", handler.UpdateTask)
+  tasks.DELETE("/:id", handler.DeleteTask)
+  tasks.POST("/:id/restore", handler.RestoreTask)
+  tasks.POST("/:id/archive", handler.ArchiveTask)
+  tasks.POST("/:id/unarchive", handler.UnarchiveTask)
+  tasks.GET("/:id/history", handler.GetTaskHistory)
+  tasks.GET("/:id/subtasks", handler.GetSubtasks)
+  tasks.POST("/:id/subtasks", handler.CreateSubtask)
+  tasks.GET("/:id/dependencies", handler.GetTaskDependencies)
+  tasks.POST("/:id/blockers", handler.AddBlocker)
+  tasks.DELETE("/:id/blockers/:bid", handler.RemoveBlocker)
+  tasks.POST("/:id/checklist", handler.AddChecklistItem)
+  tasks.PUT("/:id/checklist/order", handler.ReorderChecklist)
+  tasks.PATCH("/:id/checklist/:item", handler.UpdateChecklistItem)
+  tasks.DELETE("/:id/checklist/:item", handler.RemoveChecklistItem)
+  tasks.POST("/:id/assignees", handler.AssignTask)
+  tasks.DELETE("/:id/assignees/:uid", handler.UnassignTask)
+  tasks.POST("/:id/watch", handler.WatchTask)
+  tasks.DELETE("/:id/watch", handler.UnwatchTask)
+  tasks.POST("/:id/shares", handler.ShareTask)
+  tasks.GET("/:id/shares", handler.GetTaskShares)
+  tasks.DELETE("/:id/shares/:uid", handler.RevokeTaskShare)
+  return router
+}
+
 func TestTaskHandler_CreateTask(t *testing.T) {
   t.Run("should create task successfully", func(t *testing.T) {
     mockService := new(MockTaskService)
@@ -124,6 +353,29 @@ func TestTaskHandler_CreateTask(t *testing.T) {
     assert.Equal(t, http.StatusBadRequest, w.Code)
   })
 
+  t.Run("should return 422 for invalid recurrence", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    handler := NewTaskHandler(mockService)
+    router := setupRouter()
+    
+    router.Use(func(c *gin.Context) {
+      c.Set("userID", bson.NewObjectID())
+      c.Next()
+    })
+    router.POST("/tasks", handler.CreateTask)
+
+    mockService.On("CreateTask", mock.Anything, mock.Anything, mock.Anything).
+      Return(nil, fmt.Errorf("%w: unsupported FREQ HOURLY", services.ErrInvalidRecurrence))
+
+    req, _ := http.NewRequest("POST", "/tasks", bytes.NewBufferString(`{"title":"Vehicle check","recurrence":"FREQ=HOURLY"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+    assert.Contains(t, w.Body.String(), "unsupported FREQ HOURLY")
+  })
+
   t.Run("should fail without authentication", func(t *testing.T) {
     mockService := new(MockTaskService)
     handler := NewTaskHandler(mockService)
@@ -429,7 +681,7 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
       Status: "completed",
     }
 
-    mockService.On("UpdateTask", mock.Anything, taskID, userID, mock.Anything).
+    mockService.On("UpdateTask", mock.Anything, taskID, userID, mock.Anything, ([]int64)(nil)).
       Return(expectedResponse, nil)
 
     body := map[string]string{
@@ -484,7 +736,7 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
     })
     router.PUT("/tasks/:id", handler.UpdateTask)
 
-    mockService.On("UpdateTask", mock.Anything, taskID, userID, mock.Anything).
+    mockService.On("UpdateTask", mock.Anything, taskID, userID, mock.Anything, ([]int64)(nil)).
       Return(nil, errors.New("task not found"))
 
     body := map[string]string{"title": "Updated"}
@@ -516,7 +768,7 @@ func TestTaskHandler_DeleteTask(t *testing.T) {
     })
     router.DELETE("/tasks/:id", handler.DeleteTask)
 
-    mockService.On("DeleteTask", mock.Anything, taskID, userID).Return(nil)
+    mockService.On("DeleteTask", mock.Anything, taskID, userID, ([]int64)(nil)).Return(nil)
 
     req, _ := http.NewRequest("DELETE", "/tasks/"+taskID.Hex(), nil)
     w := httptest.NewRecorder()
@@ -559,7 +811,7 @@ func TestTaskHandler_DeleteTask(t *testing.T) {
     })
     router.DELETE("/tasks/:id", handler.DeleteTask)
 
-    mockService.On("DeleteTask", mock.Anything, taskID, userID).
+    mockService.On("DeleteTask", mock.Anything, taskID, userID, ([]int64)(nil)).
       Return(errors.New("task not found"))
 
     req, _ := http.NewRequest("DELETE", "/tasks/"+taskID.Hex(), nil)
@@ -571,3 +823,151 @@ func TestTaskHandler_DeleteTask(t *testing.T) {
     mockService.AssertExpectations(t)
   })
 }
+
+func TestTaskHandler_WorkspaceTasks(t *testing.T) {
+  newRouter := func(mockService *MockTaskService, userID bson.ObjectID) *gin.Engine {
+    handler := NewTaskHandler(mockService)
+    router := setupRouter()
+    router.Use(func(c *gin.Context) {
+      c.Set("userID", userID)
+      c.Next()
+    })
+    router.POST("/workspaces/:wid/tasks", handler.CreateTask)
+    router.GET("/workspaces/:wid/tasks", handler.GetTasks)
+    return router
+  }
+
+  t.Run("should create task in workspace from path", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+    router := newRouter(mockService, userID)
+
+    mockService.On("CreateTask", mock.Anything, userID, mock.MatchedBy(func(input types.CreateTaskInput) bool {
+      return input.WorkspaceID == workspaceID.Hex()
+    })).Return(&types.TaskResponse{Title: "Shared", WorkspaceID: workspaceID.Hex()}, nil)
+
+    req, _ := http.NewRequest("POST", "/workspaces/"+workspaceID.Hex()+"/tasks", bytes.NewBufferString(`{"title":"Shared"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusCreated, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should list only tasks of workspace", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+    router := newRouter(mockService, userID)
+
+    mockService.On("GetTasks", mock.Anything, userID, types.TaskQueryParams{WorkspaceID: workspaceID.Hex()}).
+      Return(&types.TaskListResponse{Tasks: []types.TaskResponse{}}, nil)
+
+    req, _ := http.NewRequest("GET", "/workspaces/"+workspaceID.Hex()+"/tasks", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 404 for workspace of non-member", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := newRouter(mockService, bson.NewObjectID())
+
+    mockService.On("GetTasks", mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrWorkspaceNotFound)
+
+    req, _ := http.NewRequest("GET", "/workspaces/"+bson.NewObjectID().Hex()+"/tasks", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusNotFound, w.Code)
+  })
+}
+
+func TestTaskHandler_Assignees(t *testing.T) {
+  newRouter := func(mockService *MockTaskService, userID bson.ObjectID) *gin.Engine {
+    handler := NewTaskHandler(mockService)
+    router := setupRouter()
+    router.Use(func(c *gin.Context) {
+      c.Set("userID", userID)
+      c.Next()
+    })
+    router.POST("/tasks/:id/assignees", handler.AssignTask)
+    router.DELETE("/tasks/:id/assignees/:uid", handler.UnassignTask)
+    router.POST("/tasks/:id/watch", handler.WatchTask)
+    return router
+  }
+
+  t.Run("should assign user", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID, assigneeID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+    router := newRouter(mockService, userID)
+
+    mockService.On("AssignTask", mock.Anything, taskID, userID, assigneeID).
+      Return(&types.TaskResponse{ID: taskID.Hex(), AssigneeIDs: []string{assigneeID.Hex()}}, nil)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/assignees", bytes.NewBufferString(`{"user_id":"`+assigneeID.Hex()+`"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), assigneeID.Hex())
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should reject invalid user id", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := newRouter(mockService, bson.NewObjectID())
+
+    req, _ := http.NewRequest("POST", "/tasks/"+bson.NewObjectID().Hex()+"/assignees", bytes.NewBufferString(`{"user_id":"nope"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "AssignTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should map service errors", func(t *testing.T) {
+    tests := []struct {
+      err  error
+      code int
+    }{
+      {services.ErrTaskNotFound, http.StatusNotFound},
+      {services.ErrUserNotFound, http.StatusNotFound},
+      {services.ErrTaskOwnerRequired, http.StatusForbidden},
+      {services.ErrAssigneeNotMember, http.StatusUnprocessableEntity},
+    }
+
+    for _, tt := range tests {
+      mockService := new(MockTaskService)
+      router := newRouter(mockService, bson.NewObjectID())
+
+      mockService.On("UnassignTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.err)
+
+      req, _ := http.NewRequest("DELETE", "/tasks/"+bson.NewObjectID().Hex()+"/assignees/"+bson.NewObjectID().Hex(), nil)
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, req)
+
+      assert.Equal(t, tt.code, w.Code, tt.err.Error())
+    }
+  })
+
+  t.Run("should watch task", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := newRouter(mockService, userID)
+
+    mockService.On("WatchTask", mock.Anything, taskID, userID).
+      Return(&types.TaskResponse{ID: taskID.Hex(), WatcherIDs: []string{userID.Hex()}}, nil)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/watch", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/types"
+	"task-api/utils"
+)
+
+// GetTaskHistory - GET /tasks/:id/history
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  var query types.TaskHistoryQueryParams
+  if err := c.ShouldBindQuery(&query); err != nil {
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+  
+  history, err := h.taskService.GetTaskHistory(ctx, taskID, userID.(bson.ObjectID), query)
+  if err != nil {
+    h.handleError(c, err, "Failed to get task history")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgTaskHistoryRetrieved, history)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+func TestTaskHandler_GetTaskHistory(t *testing.T) {
+  t.Run("should return a page of events", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+    cursor := bson.NewObjectID().Hex()
+
+    history := &types.TaskHistoryResponse{
+      Events: []types.TaskEventResponse{{
+        TaskID:  taskID.Hex(),
+        Action:  types.TaskEventUpdated,
+        Changes: []types.FieldChangeResponse{{Field: "status", Old: "pending", New: "completed"}},
+      }},
+      NextCursor: bson.NewObjectID().Hex(),
+    }
+    mockService.On("GetTaskHistory", mock.Anything, taskID, userID, types.TaskHistoryQueryParams{Cursor: cursor, Limit: 1}).Return(history, nil)
+
+    req, _ := http.NewRequest("GET", "/tasks/"+taskID.Hex()+"/history?limit=1&cursor="+cursor, nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    var body struct {
+      Data types.TaskHistoryResponse `json:"data"`
+    }
+    json.Unmarshal(w.Body.Bytes(), &body)
+    assert.Equal(t, "completed", body.Data.Events[0].Changes[0].New)
+    assert.Equal(t, history.NextCursor, body.Data.NextCursor)
+  })
+
+  t.Run("should reject invalid cursor", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    req, _ := http.NewRequest("GET", "/tasks/"+bson.NewObjectID().Hex()+"/history?cursor=nope", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "GetTaskHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should hide history of invisible task", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("GetTaskHistory", mock.Anything, taskID, userID, mock.Anything).Return(nil, services.ErrTaskNotFound)
+
+    req, _ := http.NewRequest("GET", "/tasks/"+taskID.Hex()+"/history", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusNotFound, w.Code)
+  })
+}
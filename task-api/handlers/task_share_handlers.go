@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/types"
+	"task-api/utils"
+)
+
+// ShareTask - POST /tasks/:id/shares
+func (h *TaskHandler) ShareTask(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  var input types.ShareTaskInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, 400, "Request body required", gin.H{"error": "Please provide email and permission"})
+      return
+    }
+    
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.ShareTask(ctx, taskID, userID.(bson.ObjectID), input)
+  if err != nil {
+    h.handleError(c, err, "Failed to share task")
+    return
+  }
+  
+  log.Info().
+    Str("task_id", taskID.Hex()).
+    Str("shared_with", response.UserID).
+    Str("permission", response.Permission).
+    Msg("Task shared")
+  
+  utils.Success(c, 201, types.MsgTaskShared, gin.H{"share": response})
+}
+
+// GetTaskShares - GET /tasks/:id/shares
+func (h *TaskHandler) GetTaskShares(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+  
+  shares, err := h.taskService.GetTaskShares(ctx, taskID, userID.(bson.ObjectID))
+  if err != nil {
+    h.handleError(c, err, "Failed to get task shares")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgTaskSharesRetrieved, gin.H{"shares": shares})
+}
+
+// RevokeTaskShare - DELETE /tasks/:id/shares/:uid
+func (h *TaskHandler) RevokeTaskShare(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  sharedWithID, err := bson.ObjectIDFromHex(c.Param("uid"))
+  if err != nil {
+    utils.Fail(c, 400, "Invalid user ID", gin.H{"error": "Invalid ID format"})
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  if err := h.taskService.RevokeTaskShare(ctx, taskID, userID.(bson.ObjectID), sharedWithID); err != nil {
+    h.handleError(c, err, "Failed to revoke task share")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgTaskShareRevoked, gin.H{"revoked_id": sharedWithID.Hex()})
+}
+
+// GetSharedTasks - GET /tasks/shared
+func (h *TaskHandler) GetSharedTasks(c *gin.Context) {
+  var query types.SharedTaskQueryParams
+  
+  if err := c.ShouldBindQuery(&query); err != nil {
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.GetSharedTasks(ctx, userID.(bson.ObjectID), query)
+  if err != nil {
+    h.handleError(c, err, "Failed to get shared tasks")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgSharedTasksRetrieved, response)
+}
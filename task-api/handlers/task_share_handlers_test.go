@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+func TestTaskHandler_ShareTask(t *testing.T) {
+  t.Run("should share task", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    input := types.ShareTaskInput{Email: "colleague@test.com", Permission: types.SharePermissionEdit}
+    mockService.On("ShareTask", mock.Anything, taskID, userID, input).
+      Return(&types.TaskShareResponse{UserID: bson.NewObjectID().Hex(), Email: input.Email, Permission: input.Permission}, nil)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/shares", bytes.NewBufferString(`{"email":"colleague@test.com","permission":"edit"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusCreated, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should reject unknown permission", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    req, _ := http.NewRequest("POST", "/tasks/"+bson.NewObjectID().Hex()+"/shares", bytes.NewBufferString(`{"email":"colleague@test.com","permission":"admin"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "ShareTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should map service errors", func(t *testing.T) {
+    tests := []struct {
+      err  error
+      code int
+    }{
+      {services.ErrTaskNotFound, http.StatusNotFound},
+      {services.ErrUserNotFound, http.StatusNotFound},
+      {services.ErrTaskOwnerRequired, http.StatusForbidden},
+      {services.ErrTaskPermissionDenied, http.StatusForbidden},
+      {services.ErrCannotShareWithSelf, http.StatusUnprocessableEntity},
+    }
+
+    for _, tt := range tests {
+      mockService := new(MockTaskService)
+      router := setupTaskRouter(mockService, bson.NewObjectID())
+
+      mockService.On("ShareTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.err)
+
+      req, _ := http.NewRequest("POST", "/tasks/"+bson.NewObjectID().Hex()+"/shares", bytes.NewBufferString(`{"email":"colleague@test.com","permission":"view"}`))
+      req.Header.Set("Content-Type", "application/json")
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, req)
+
+      assert.Equal(t, tt.code, w.Code, tt.err.Error())
+    }
+  })
+}
+
+func TestTaskHandler_RevokeTaskShare(t *testing.T) {
+  t.Run("should return 404 for missing share", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    mockService.On("RevokeTaskShare", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(services.ErrTaskShareNotFound)
+
+    req, _ := http.NewRequest("DELETE", "/tasks/"+bson.NewObjectID().Hex()+"/shares/"+bson.NewObjectID().Hex(), nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusNotFound, w.Code)
+  })
+}
+
+func TestTaskHandler_GetSharedTasks(t *testing.T) {
+  t.Run("should route /tasks/shared before /tasks/:id", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID := bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("GetSharedTasks", mock.Anything, userID, types.SharedTaskQueryParams{Page: 2}).
+      Return(&types.SharedTaskListResponse{Tasks: []types.SharedTaskResponse{}}, nil)
+
+    req, _ := http.NewRequest("GET", "/tasks/shared?page=2", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+}
+
+func TestTaskHandler_SharedPermissions(t *testing.T) {
+  t.Run("should return 403 when share does not allow update", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    mockService.On("UpdateTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrTaskPermissionDenied)
+
+    req, _ := http.NewRequest("PUT", "/tasks/"+bson.NewObjectID().Hex(), bytes.NewBufferString(`{"title":"Changed"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusForbidden, w.Code)
+  })
+
+  t.Run("should return 403 when share does not allow delete", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    router := setupTaskRouter(mockService, bson.NewObjectID())
+
+    mockService.On("DeleteTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(services.ErrTaskPermissionDenied)
+
+    req, _ := http.NewRequest("DELETE", "/tasks/"+bson.NewObjectID().Hex(), nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusForbidden, w.Code)
+  })
+}
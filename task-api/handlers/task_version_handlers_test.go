@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+// atVersions - matches the versions argument of the task service
+func atVersions(want ...int64) any {
+  return mock.MatchedBy(func(versions []int64) bool {
+    return versions != nil && slices.Equal(versions, want)
+  })
+}
+
+func TestTaskHandler_ETag(t *testing.T) {
+  t.Run("should send the version as ETag", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("GetTask", mock.Anything, taskID, userID).Return(&types.TaskResponse{ID: taskID.Hex(), Version: 7}, nil)
+
+    req, _ := http.NewRequest("GET", "/tasks/"+taskID.Hex(), nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Equal(t, `"7"`, w.Header().Get("ETag"))
+  })
+
+  t.Run("should update at the If-Match versions", func(t *testing.T) {
+    for header, versions := range map[string][]int64{`"7"`: {7}, `"3", "7"`: {3, 7}, `"3",W/"5" , "7"`: {3, 7}} {
+      mockService := new(MockTaskService)
+      userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+      router := setupTaskRouter(mockService, userID)
+
+      mockService.On("UpdateTask", mock.Anything, taskID, userID, mock.Anything, atVersions(versions...)).
+        Return(&types.TaskResponse{ID: taskID.Hex(), Version: 8}, nil)
+
+      req, _ := http.NewRequest("PUT", "/tasks/"+taskID.Hex(), bytes.NewBufferString(`{"title":"Mine"}`))
+      req.Header.Set("Content-Type", "application/json")
+      req.Header.Set("If-Match", header)
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, req)
+
+      assert.Equal(t, http.StatusOK, w.Code, header)
+      assert.Equal(t, `"8"`, w.Header().Get("ETag"))
+      mockService.AssertExpectations(t)
+    }
+  })
+
+  t.Run("should return the current task when the version is stale", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    current := &types.TaskResponse{ID: taskID.Hex(), Title: "Theirs", Version: 9}
+    mockService.On("UpdateTask", mock.Anything, taskID, userID, mock.Anything, atVersions(7)).
+      Return(nil, &services.TaskVersionError{Current: current})
+
+    req, _ := http.NewRequest("PUT", "/tasks/"+taskID.Hex(), bytes.NewBufferString(`{"title":"Mine"}`))
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("If-Match", `"7"`)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+    assert.Equal(t, `"9"`, w.Header().Get("ETag"))
+
+    var body struct {
+      Data struct {
+        Task types.TaskResponse `json:"task"`
+      } `json:"data"`
+    }
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+    assert.Equal(t, "Theirs", body.Data.Task.Title)
+    assert.Equal(t, int64(9), body.Data.Task.Version)
+  })
+
+  t.Run("should never match weak tags or tags that are not a version", func(t *testing.T) {
+    for _, header := range []string{`W/"2"`, `"abc"`, `2`} {
+      mockService := new(MockTaskService)
+      userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+      router := setupTaskRouter(mockService, userID)
+
+      mockService.On("DeleteTask", mock.Anything, taskID, userID, atVersions()).
+        Return(&services.TaskVersionError{Current: &types.TaskResponse{ID: taskID.Hex(), Version: 2}})
+
+      req, _ := http.NewRequest("DELETE", "/tasks/"+taskID.Hex(), nil)
+      req.Header.Set("If-Match", header)
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, req)
+
+      assert.Equal(t, http.StatusPreconditionFailed, w.Code, header)
+      mockService.AssertExpectations(t)
+    }
+  })
+
+  t.Run("should delete unconditionally with If-Match *", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("DeleteTask", mock.Anything, taskID, userID, ([]int64)(nil)).Return(nil)
+
+    req, _ := http.NewRequest("DELETE", "/tasks/"+taskID.Hex(), nil)
+    req.Header.Set("If-Match", "*")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+  })
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+	"task-api/utils"
+)
+
+// TokenHandler - struct for personal access token handlers
+type TokenHandler struct {
+  tokenService services.PersonalAccessTokenService
+}
+
+// NewTokenHandler - constructor
+func NewTokenHandler(tokenService services.PersonalAccessTokenService) *TokenHandler {
+  return &TokenHandler{
+    tokenService: tokenService,
+  }
+}
+
+// ListTokens - GET /auth/tokens
+func (h *TokenHandler) ListTokens(c *gin.Context) {
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  tokens, err := h.tokenService.List(ctx, userID.(bson.ObjectID))
+  if err != nil {
+    log.Error().Err(err).Msg("Failed to list personal access tokens")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgTokensRetrieved, gin.H{"tokens": tokens})
+}
+
+// CreateToken - POST /auth/tokens
+func (h *TokenHandler) CreateToken(c *gin.Context) {
+  var input types.CreatePersonalAccessTokenInput
+
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, http.StatusBadRequest, "Request body required", gin.H{
+        "error": "Please provide name and scopes",
+      })
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{
+      "error": err.Error(),
+    })
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.tokenService.Create(ctx, userID.(bson.ObjectID), input)
+  if err != nil {
+    if errors.Is(err, services.ErrTokenLimitReached) {
+      utils.Fail(c, http.StatusConflict, types.MsgTokenLimitReached, nil)
+      return
+    }
+
+    log.Error().Err(err).Msg("Failed to create personal access token")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  utils.Success(c, http.StatusCreated, types.MsgTokenCreated, gin.H{"token": response})
+}
+
+// RevokeToken - DELETE /auth/tokens/:id
+func (h *TokenHandler) RevokeToken(c *gin.Context) {
+  tokenID, err := bson.ObjectIDFromHex(c.Param("id"))
+  if err != nil {
+    utils.Fail(c, http.StatusBadRequest, "Invalid token ID", gin.H{"error": "Invalid ID format"})
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  if err := h.tokenService.Revoke(ctx, userID.(bson.ObjectID), tokenID); err != nil {
+    if errors.Is(err, services.ErrPersonalAccessTokenNotFound) {
+      utils.Fail(c, http.StatusNotFound, types.MsgTokenNotFound, nil)
+      return
+    }
+
+    log.Error().Err(err).Msg("Failed to revoke personal access token")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgTokenRevoked, gin.H{"revoked_id": tokenID.Hex()})
+}
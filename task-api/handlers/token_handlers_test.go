@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/services"
+	"task-api/types"
+)
+
+// MockPersonalAccessTokenService mocks the PersonalAccessTokenService interface
+type MockPersonalAccessTokenService struct {
+  mock.Mock
+}
+
+func (m *MockPersonalAccessTokenService) Create(ctx context.Context, userID bson.ObjectID, input types.CreatePersonalAccessTokenInput) (*types.CreatePersonalAccessTokenResponse, error) {
+  args := m.Called(ctx, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.CreatePersonalAccessTokenResponse), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenService) List(ctx context.Context, userID bson.ObjectID) ([]types.PersonalAccessTokenResponse, error) {
+  args := m.Called(ctx, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]types.PersonalAccessTokenResponse), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenService) Revoke(ctx context.Context, userID, tokenID bson.ObjectID) error {
+  args := m.Called(ctx, userID, tokenID)
+  return args.Error(0)
+}
+
+func (m *MockPersonalAccessTokenService) Authenticate(ctx context.Context, token string) (*models.PersonalAccessToken, *models.User, error) {
+  args := m.Called(ctx, token)
+  if args.Get(0) == nil {
+    return nil, nil, args.Error(2)
+  }
+  return args.Get(0).(*models.PersonalAccessToken), args.Get(1).(*models.User), args.Error(2)
+}
+
+func TestTokenHandler_CreateToken(t *testing.T) {
+  t.Run("should return 201 with token", func(t *testing.T) {
+    mockService := new(MockPersonalAccessTokenService)
+    handler := NewTokenHandler(mockService)
+    router := setupRouter()
+
+    userID := bson.NewObjectID()
+    router.POST("/auth/tokens", withSession(userID, "jti", time.Now()), handler.CreateToken)
+
+    input := types.CreatePersonalAccessTokenInput{Name: "CI", Scopes: []string{types.ScopeTasksRead}}
+    mockService.On("Create", mock.Anything, userID, input).Return(&types.CreatePersonalAccessTokenResponse{
+      PersonalAccessTokenResponse: types.PersonalAccessTokenResponse{Name: "CI", Scopes: input.Scopes},
+      Token:                       "mtp_secret",
+    }, nil)
+
+    jsonBody, _ := json.Marshal(input)
+    req, _ := http.NewRequest("POST", "/auth/tokens", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusCreated, w.Code)
+    assert.Contains(t, w.Body.String(), "mtp_secret")
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 400 on unknown scope", func(t *testing.T) {
+    mockService := new(MockPersonalAccessTokenService)
+    handler := NewTokenHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/tokens", withSession(bson.NewObjectID(), "jti", time.Now()), handler.CreateToken)
+
+    jsonBody, _ := json.Marshal(map[string]interface{}{"name": "CI", "scopes": []string{"admin"}})
+    req, _ := http.NewRequest("POST", "/auth/tokens", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should return 409 at token limit", func(t *testing.T) {
+    mockService := new(MockPersonalAccessTokenService)
+    handler := NewTokenHandler(mockService)
+    router := setupRouter()
+    router.POST("/auth/tokens", withSession(bson.NewObjectID(), "jti", time.Now()), handler.CreateToken)
+
+    mockService.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrTokenLimitReached)
+
+    jsonBody, _ := json.Marshal(map[string]interface{}{"name": "CI", "scopes": []string{types.ScopeTasksWrite}})
+    req, _ := http.NewRequest("POST", "/auth/tokens", bytes.NewBuffer(jsonBody))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusConflict, w.Code)
+  })
+}
+
+func TestTokenHandler_ListTokens(t *testing.T) {
+  t.Run("should list tokens without secrets", func(t *testing.T) {
+    mockService := new(MockPersonalAccessTokenService)
+    handler := NewTokenHandler(mockService)
+    router := setupRouter()
+
+    userID := bson.NewObjectID()
+    router.GET("/auth/tokens", withSession(userID, "jti", time.Now()), handler.ListTokens)
+
+    mockService.On("List", mock.Anything, userID).
+      Return([]types.PersonalAccessTokenResponse{{Name: "CI", Prefix: "mtp_abcdef"}}, nil)
+
+    req, _ := http.NewRequest("GET", "/auth/tokens", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "mtp_abcdef")
+    assert.NotContains(t, w.Body.String(), `"token"`)
+  })
+}
+
+func TestTokenHandler_RevokeToken(t *testing.T) {
+  t.Run("should revoke token", func(t *testing.T) {
+    mockService := new(MockPersonalAccessTokenService)
+    handler := NewTokenHandler(mockService)
+    router := setupRouter()
+
+    userID := bson.NewObjectID()
+    tokenID := bson.NewObjectID()
+    router.DELETE("/auth/tokens/:id", withSession(userID, "jti", time.Now()), handler.RevokeToken)
+
+    mockService.On("Revoke", mock.Anything, userID, tokenID).Return(nil)
+
+    req, _ := http.NewRequest("DELETE", "/auth/tokens/"+tokenID.Hex(), nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 404 for unknown token", func(t *testing.T) {
+    mockService := new(MockPersonalAccessTokenService)
+    handler := NewTokenHandler(mockService)
+    router := setupRouter()
+    router.DELETE("/auth/tokens/:id", withSession(bson.NewObjectID(), "jti", time.Now()), handler.RevokeToken)
+
+    mockService.On("Revoke", mock.Anything, mock.Anything, mock.Anything).Return(services.ErrPersonalAccessTokenNotFound)
+
+    req, _ := http.NewRequest("DELETE", "/auth/tokens/"+bson.NewObjectID().Hex(), nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusNotFound, w.Code)
+  })
+
+  t.Run("should return 400 on invalid id", func(t *testing.T) {
+    mockService := new(MockPersonalAccessTokenService)
+    handler := NewTokenHandler(mockService)
+    router := setupRouter()
+    router.DELETE("/auth/tokens/:id", withSession(bson.NewObjectID(), "jti", time.Now()), handler.RevokeToken)
+
+    req, _ := http.NewRequest("DELETE", "/auth/tokens/not-an-id", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+}
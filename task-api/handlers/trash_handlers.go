@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/types"
+	"task-api/utils"
+)
+
+// GetTrash - GET /tasks/trash
+func (h *TaskHandler) GetTrash(c *gin.Context) {
+  var query types.TrashQueryParams
+  if err := c.ShouldBindQuery(&query); err != nil {
+    utils.Fail(c, 400, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+  
+  result, err := h.taskService.GetTrash(ctx, userID.(bson.ObjectID), query)
+  if err != nil {
+    h.handleError(c, err, "Failed to get trash")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgTrashRetrieved, result)
+}
+
+// RestoreTask - POST /tasks/:id/restore
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+  taskID, ok := parseTaskID(c)
+  if !ok {
+    return
+  }
+  
+  userID, _ := c.Get("userID")
+  
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+  
+  response, err := h.taskService.RestoreTask(ctx, taskID, userID.(bson.ObjectID))
+  if err != nil {
+    h.handleError(c, err, "Failed to restore task")
+    return
+  }
+  
+  utils.Success(c, 200, types.MsgTaskRestored, gin.H{"task": response})
+}
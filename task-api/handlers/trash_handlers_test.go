@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+func TestTaskHandler_DeleteTaskPermanent(t *testing.T) {
+  t.Run("should purge with permanent flag", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("PurgeTask", mock.Anything, taskID, userID, ([]int64)(nil)).Return(nil)
+
+    req, _ := http.NewRequest("DELETE", "/tasks/"+taskID.Hex()+"?permanent=true", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgTaskPurged)
+    mockService.AssertNotCalled(t, "DeleteTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+}
+
+func TestTaskHandler_GetTrash(t *testing.T) {
+  t.Run("should list trashed tasks", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID := bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    result := &types.TaskListResponse{Tasks: []types.TaskResponse{{Title: "Old"}}, Meta: types.PaginationMeta{Page: 1, Limit: 5, Total: 1, TotalPages: 1}}
+    mockService.On("GetTrash", mock.Anything, userID, types.TrashQueryParams{Page: 1, Limit: 5}).Return(result, nil)
+
+    req, _ := http.NewRequest("GET", "/tasks/trash?page=1&limit=5", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "Old")
+  })
+}
+
+func TestTaskHandler_RestoreTask(t *testing.T) {
+  t.Run("should restore trashed task", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("RestoreTask", mock.Anything, taskID, userID).Return(&types.TaskResponse{ID: taskID.Hex()}, nil)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/restore", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), taskID.Hex())
+  })
+
+  t.Run("should return conflict when task is not in the trash", func(t *testing.T) {
+    mockService := new(MockTaskService)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupTaskRouter(mockService, userID)
+
+    mockService.On("RestoreTask", mock.Anything, taskID, userID).Return(nil, services.ErrTaskNotTrashed)
+
+    req, _ := http.NewRequest("POST", "/tasks/"+taskID.Hex()+"/restore", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusConflict, w.Code)
+  })
+}
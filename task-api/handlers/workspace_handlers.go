@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+	"task-api/utils"
+)
+
+// WorkspaceHandler - struct for workspace handlers
+type WorkspaceHandler struct {
+  workspaceService services.WorkspaceService
+}
+
+// NewWorkspaceHandler - constructor
+func NewWorkspaceHandler(workspaceService services.WorkspaceService) *WorkspaceHandler {
+  return &WorkspaceHandler{
+    workspaceService: workspaceService,
+  }
+}
+
+// CreateWorkspace - POST /workspaces
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+  var input types.CreateWorkspaceInput
+
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, http.StatusBadRequest, "Request body required", gin.H{
+        "error": "Please provide workspace name",
+      })
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.workspaceService.CreateWorkspace(ctx, userID.(bson.ObjectID), input)
+  if err != nil {
+    log.Error().Err(err).Msg("Failed to create workspace")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  utils.Success(c, http.StatusCreated, types.MsgWorkspaceCreated, gin.H{"workspace": response})
+}
+
+// GetWorkspaces - GET /workspaces
+func (h *WorkspaceHandler) GetWorkspaces(c *gin.Context) {
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  workspaces, err := h.workspaceService.GetWorkspaces(ctx, userID.(bson.ObjectID))
+  if err != nil {
+    log.Error().Err(err).Msg("Failed to get workspaces")
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgWorkspacesRetrieved, gin.H{"workspaces": workspaces})
+}
+
+// GetWorkspace - GET /workspaces/:wid
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+  workspaceID, ok := parseWorkspaceID(c)
+  if !ok {
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  response, err := h.workspaceService.GetWorkspace(ctx, workspaceID, userID.(bson.ObjectID))
+  if err != nil {
+    h.handleError(c, err, "Failed to get workspace")
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgWorkspaceRetrieved, gin.H{"workspace": response})
+}
+
+// GetMembers - GET /workspaces/:wid/members
+func (h *WorkspaceHandler) GetMembers(c *gin.Context) {
+  workspaceID, ok := parseWorkspaceID(c)
+  if !ok {
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+  defer cancel()
+
+  members, err := h.workspaceService.GetMembers(ctx, workspaceID, userID.(bson.ObjectID))
+  if err != nil {
+    h.handleError(c, err, "Failed to get workspace members")
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgMembersRetrieved, gin.H{"members": members})
+}
+
+// AddMember - POST /workspaces/:wid/members
+func (h *WorkspaceHandler) AddMember(c *gin.Context) {
+  workspaceID, ok := parseWorkspaceID(c)
+  if !ok {
+    return
+  }
+
+  var input types.AddWorkspaceMemberInput
+  if err := c.ShouldBindJSON(&input); err != nil {
+    if err == io.EOF {
+      utils.Fail(c, http.StatusBadRequest, "Request body required", gin.H{
+        "error": "Please provide email",
+      })
+      return
+    }
+
+    utils.Fail(c, http.StatusBadRequest, types.MsgValidationFailed, gin.H{"error": err.Error()})
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  member, err := h.workspaceService.AddMember(ctx, workspaceID, userID.(bson.ObjectID), input)
+  if err != nil {
+    h.handleError(c, err, "Failed to add workspace member")
+    return
+  }
+
+  utils.Success(c, http.StatusCreated, types.MsgMemberAdded, gin.H{"member": member})
+}
+
+// RemoveMember - DELETE /workspaces/:wid/members/:uid
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+  workspaceID, ok := parseWorkspaceID(c)
+  if !ok {
+    return
+  }
+
+  memberID, err := bson.ObjectIDFromHex(c.Param("uid"))
+  if err != nil {
+    utils.Fail(c, http.StatusBadRequest, "Invalid user ID", gin.H{"error": "Invalid ID format"})
+    return
+  }
+
+  userID, _ := c.Get("userID")
+
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+  defer cancel()
+
+  if err := h.workspaceService.RemoveMember(ctx, workspaceID, userID.(bson.ObjectID), memberID); err != nil {
+    h.handleError(c, err, "Failed to remove workspace member")
+    return
+  }
+
+  utils.Success(c, http.StatusOK, types.MsgMemberRemoved, gin.H{"removed_id": memberID.Hex()})
+}
+
+// handleError - map workspace service errors to responses
+func (h *WorkspaceHandler) handleError(c *gin.Context, err error, msg string) {
+  switch {
+  case errors.Is(err, services.ErrWorkspaceNotFound):
+    utils.Fail(c, http.StatusNotFound, types.MsgWorkspaceNotFound, nil)
+  case errors.Is(err, services.ErrWorkspaceMemberNotFound):
+    utils.Fail(c, http.StatusNotFound, types.MsgMemberNotFound, nil)
+  case errors.Is(err, services.ErrUserNotFound):
+    utils.Fail(c, http.StatusNotFound, types.MsgUserNotFound, nil)
+  case errors.Is(err, services.ErrWorkspaceOwnerRequired):
+    utils.Fail(c, http.StatusForbidden, types.MsgWorkspaceOwnerOnly, nil)
+  case errors.Is(err, services.ErrAlreadyWorkspaceMember):
+    utils.Fail(c, http.StatusConflict, types.MsgAlreadyMember, nil)
+  case errors.Is(err, services.ErrLastWorkspaceOwner):
+    utils.Fail(c, http.StatusConflict, types.MsgLastWorkspaceOwner, nil)
+  default:
+    log.Error().Err(err).Msg(msg)
+    utils.Error(c, http.StatusInternalServerError, types.MsgInternalError, 0, nil)
+  }
+}
+
+// parseWorkspaceID - read :wid path param, responds 400 when malformed
+func parseWorkspaceID(c *gin.Context) (bson.ObjectID, bool) {
+  workspaceID, err := bson.ObjectIDFromHex(c.Param("wid"))
+  if err != nil {
+    utils.Fail(c, http.StatusBadRequest, "Invalid workspace ID", gin.H{"error": "Invalid ID format"})
+    return bson.ObjectID{}, false
+  }
+  return workspaceID, true
+}
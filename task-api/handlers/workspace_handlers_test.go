@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/services"
+	"task-api/types"
+)
+
+// MockWorkspaceService mocks the WorkspaceService interface
+type MockWorkspaceService struct {
+  mock.Mock
+}
+
+func (m *MockWorkspaceService) CreateWorkspace(ctx context.Context, userID bson.ObjectID, input types.CreateWorkspaceInput) (*types.WorkspaceResponse, error) {
+  args := m.Called(ctx, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.WorkspaceResponse), args.Error(1)
+}
+
+func (m *MockWorkspaceService) GetWorkspaces(ctx context.Context, userID bson.ObjectID) ([]types.WorkspaceResponse, error) {
+  args := m.Called(ctx, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]types.WorkspaceResponse), args.Error(1)
+}
+
+func (m *MockWorkspaceService) GetWorkspace(ctx context.Context, workspaceID, userID bson.ObjectID) (*types.WorkspaceResponse, error) {
+  args := m.Called(ctx, workspaceID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.WorkspaceResponse), args.Error(1)
+}
+
+func (m *MockWorkspaceService) GetMembers(ctx context.Context, workspaceID, userID bson.ObjectID) ([]types.WorkspaceMemberResponse, error) {
+  args := m.Called(ctx, workspaceID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]types.WorkspaceMemberResponse), args.Error(1)
+}
+
+func (m *MockWorkspaceService) AddMember(ctx context.Context, workspaceID, userID bson.ObjectID, input types.AddWorkspaceMemberInput) (*types.WorkspaceMemberResponse, error) {
+  args := m.Called(ctx, workspaceID, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.WorkspaceMemberResponse), args.Error(1)
+}
+
+func (m *MockWorkspaceService) RemoveMember(ctx context.Context, workspaceID, userID, memberID bson.ObjectID) error {
+  args := m.Called(ctx, workspaceID, userID, memberID)
+  return args.Error(0)
+}
+
+// setupWorkspaceRouter - workspace routes for an authenticated user
+func setupWorkspaceRouter(handler *WorkspaceHandler, userID bson.ObjectID) *gin.Engine {
+  router := setupRouter()
+  router.Use(func(c *gin.Context) {
+    c.Set("userID", userID)
+    c.Next()
+  })
+  router.POST("/workspaces", handler.CreateWorkspace)
+  router.GET("/workspaces/:wid", handler.GetWorkspace)
+  router.POST("/workspaces/:wid/members", handler.AddMember)
+  router.DELETE("/workspaces/:wid/members/:uid", handler.RemoveMember)
+  return router
+}
+
+func TestWorkspaceHandler_CreateWorkspace(t *testing.T) {
+  t.Run("should return 201 with workspace", func(t *testing.T) {
+    mockService := new(MockWorkspaceService)
+    userID := bson.NewObjectID()
+    router := setupWorkspaceRouter(NewWorkspaceHandler(mockService), userID)
+
+    mockService.On("CreateWorkspace", mock.Anything, userID, types.CreateWorkspaceInput{Name: "Team"}).
+      Return(&types.WorkspaceResponse{Name: "Team", Role: types.WorkspaceRoleOwner}, nil)
+
+    req, _ := http.NewRequest("POST", "/workspaces", bytes.NewBufferString(`{"name":"Team"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusCreated, w.Code)
+    assert.Contains(t, w.Body.String(), `"role":"owner"`)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should return 400 without name", func(t *testing.T) {
+    router := setupWorkspaceRouter(NewWorkspaceHandler(new(MockWorkspaceService)), bson.NewObjectID())
+
+    req, _ := http.NewRequest("POST", "/workspaces", bytes.NewBufferString(`{}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+}
+
+func TestWorkspaceHandler_GetWorkspace(t *testing.T) {
+  t.Run("should return 404 for non-member", func(t *testing.T) {
+    mockService := new(MockWorkspaceService)
+    router := setupWorkspaceRouter(NewWorkspaceHandler(mockService), bson.NewObjectID())
+
+    mockService.On("GetWorkspace", mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrWorkspaceNotFound)
+
+    req, _ := http.NewRequest("GET", "/workspaces/"+bson.NewObjectID().Hex(), nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusNotFound, w.Code)
+  })
+
+  t.Run("should return 400 for invalid ID", func(t *testing.T) {
+    router := setupWorkspaceRouter(NewWorkspaceHandler(new(MockWorkspaceService)), bson.NewObjectID())
+
+    req, _ := http.NewRequest("GET", "/workspaces/invalid", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+  })
+}
+
+func TestWorkspaceHandler_AddMember(t *testing.T) {
+  t.Run("should return 201 with member", func(t *testing.T) {
+    mockService := new(MockWorkspaceService)
+    userID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+    router := setupWorkspaceRouter(NewWorkspaceHandler(mockService), userID)
+
+    input := types.AddWorkspaceMemberInput{Email: "teammate@test.com"}
+    mockService.On("AddMember", mock.Anything, workspaceID, userID, input).
+      Return(&types.WorkspaceMemberResponse{Email: "teammate@test.com", Role: types.WorkspaceRoleMember}, nil)
+
+    req, _ := http.NewRequest("POST", "/workspaces/"+workspaceID.Hex()+"/members", bytes.NewBufferString(`{"email":"teammate@test.com"}`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusCreated, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should map service errors", func(t *testing.T) {
+    cases := []struct {
+      err    error
+      status int
+    }{
+      {services.ErrWorkspaceNotFound, http.StatusNotFound},
+      {services.ErrUserNotFound, http.StatusNotFound},
+      {services.ErrWorkspaceOwnerRequired, http.StatusForbidden},
+      {services.ErrAlreadyWorkspaceMember, http.StatusConflict},
+    }
+
+    for _, tc := range cases {
+      mockService := new(MockWorkspaceService)
+      router := setupWorkspaceRouter(NewWorkspaceHandler(mockService), bson.NewObjectID())
+      mockService.On("AddMember", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, tc.err)
+
+      req, _ := http.NewRequest("POST", "/workspaces/"+bson.NewObjectID().Hex()+"/members", bytes.NewBufferString(`{"email":"teammate@test.com"}`))
+      req.Header.Set("Content-Type", "application/json")
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, req)
+
+      assert.Equal(t, tc.status, w.Code, tc.err.Error())
+    }
+  })
+}
+
+func TestWorkspaceHandler_RemoveMember(t *testing.T) {
+  t.Run("should remove member", func(t *testing.T) {
+    mockService := new(MockWorkspaceService)
+    userID, workspaceID, memberID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+    router := setupWorkspaceRouter(NewWorkspaceHandler(mockService), userID)
+
+    mockService.On("RemoveMember", mock.Anything, workspaceID, userID, memberID).Return(nil)
+
+    req, _ := http.NewRequest("DELETE", "/workspaces/"+workspaceID.Hex()+"/members/"+memberID.Hex(), nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+  })
+
+  t.Run("should refuse removing the last owner", func(t *testing.T) {
+    mockService := new(MockWorkspaceService)
+    router := setupWorkspaceRouter(NewWorkspaceHandler(mockService), bson.NewObjectID())
+
+    mockService.On("RemoveMember", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(services.ErrLastWorkspaceOwner)
+
+    req, _ := http.NewRequest("DELETE", "/workspaces/"+bson.NewObjectID().Hex()+"/members/"+bson.NewObjectID().Hex(), nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusConflict, w.Code)
+  })
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// logMailer - stand-in Mailer for local dev and tests, appends every message to a file
+type logMailer struct {
+  mu   sync.Mutex
+  path string
+}
+
+// NewLogMailer - constructor
+func NewLogMailer(path string) Mailer {
+  return &logMailer{path: path}
+}
+
+// Send - append message to file and log its recipient and subject
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+  m.mu.Lock()
+  defer m.mu.Unlock()
+
+  if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+    return err
+  }
+
+  file, err := os.OpenFile(m.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+  if err != nil {
+    return err
+  }
+  defer file.Close()
+
+  _, err = fmt.Fprintf(file, "----- %s -----\nTo: %s\nSubject: %s\n\n%s\n\n",
+    time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+  if err != nil {
+    return err
+  }
+
+  log.Info().
+    Str("to", msg.To).
+    Str("subject", msg.Subject).
+    Str("file", m.path).
+    Msg("Email written to mail log")
+
+  return nil
+}
@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"task-api/configs"
+)
+
+// Message - plain text email
+type Message struct {
+  To      string
+  Subject string
+  Body    string
+}
+
+// Mailer - interface for sending emails
+type Mailer interface {
+  Send(ctx context.Context, msg Message) error
+}
+
+// NewMailerFromEnv - build Mailer from MAILER env ("smtp" or "log", default "log")
+func NewMailerFromEnv() Mailer {
+  switch strings.ToLower(configs.GetEnv("MAILER", "log")) {
+  case "smtp":
+    log.Info().Msg("Using SMTP mailer")
+    return NewSMTPMailer(SMTPConfig{
+      Host:     configs.GetEnv("SMTP_HOST", "localhost"),
+      Port:     configs.GetEnv("SMTP_PORT", "587"),
+      Username: configs.GetEnv("SMTP_USERNAME", ""),
+      Password: configs.GetEnv("SMTP_PASSWORD", ""),
+      From:     configs.GetEnv("SMTP_FROM", "no-reply@localhost"),
+    })
+  default:
+    path := configs.GetEnv("MAILER_LOG_FILE", "./logs/mail.log")
+    log.Info().Str("file", path).Msg("Using log mailer, emails are written to file")
+    return NewLogMailer(path)
+  }
+}
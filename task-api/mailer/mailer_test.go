@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogMailer_Send(t *testing.T) {
+  t.Run("should append messages to file", func(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "mail", "mail.log")
+    m := NewLogMailer(path)
+
+    err := m.Send(context.Background(), Message{To: "a@test.com", Subject: "First", Body: "hello"})
+    assert.NoError(t, err)
+
+    err = m.Send(context.Background(), Message{To: "b@test.com", Subject: "Second", Body: "world"})
+    assert.NoError(t, err)
+
+    content, err := os.ReadFile(path)
+    assert.NoError(t, err)
+    assert.Contains(t, string(content), "To: a@test.com")
+    assert.Contains(t, string(content), "Subject: Second")
+    assert.Contains(t, string(content), "world")
+  })
+}
+
+func TestBuildMessage(t *testing.T) {
+  t.Run("should build message with headers", func(t *testing.T) {
+    date := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+    raw := string(buildMessage("no-reply@test.com", Message{
+      To:      "user@test.com",
+      Subject: "Reset",
+      Body:    "line1\nline2",
+    }, date))
+
+    assert.Contains(t, raw, "From: no-reply@test.com\r\n")
+    assert.Contains(t, raw, "To: user@test.com\r\n")
+    assert.Contains(t, raw, "Subject: Reset\r\n")
+    assert.Contains(t, raw, "\r\n\r\nline1\r\nline2")
+  })
+
+  t.Run("should strip header injection", func(t *testing.T) {
+    raw := string(buildMessage("no-reply@test.com", Message{
+      To:      "user@test.com\r\nBcc: evil@test.com",
+      Subject: "Reset",
+    }, time.Now()))
+
+    assert.NotContains(t, raw, "\r\nBcc:")
+  })
+}
@@ -0,0 +1,61 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig - SMTP server settings
+type SMTPConfig struct {
+  Host     string
+  Port     string
+  Username string
+  Password string
+  From     string
+}
+
+// smtpMailer - implement Mailer over SMTP (STARTTLS when the server offers it)
+type smtpMailer struct {
+  config SMTPConfig
+}
+
+// NewSMTPMailer - constructor
+func NewSMTPMailer(config SMTPConfig) Mailer {
+  return &smtpMailer{config: config}
+}
+
+// Send - send message, fails fast if ctx is already done
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+  if err := ctx.Err(); err != nil {
+    return err
+  }
+
+  var auth smtp.Auth
+  if m.config.Username != "" {
+    auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+  }
+
+  addr := net.JoinHostPort(m.config.Host, m.config.Port)
+  return smtp.SendMail(addr, auth, m.config.From, []string{msg.To}, buildMessage(m.config.From, msg, time.Now()))
+}
+
+// buildMessage - RFC 5322 message with headers, CR/LF stripped from header values
+func buildMessage(from string, msg Message, date time.Time) []byte {
+  clean := strings.NewReplacer("\r", "", "\n", "")
+
+  var b strings.Builder
+  fmt.Fprintf(&b, "From: %s\r\n", clean.Replace(from))
+  fmt.Fprintf(&b, "To: %s\r\n", clean.Replace(msg.To))
+  fmt.Fprintf(&b, "Subject: %s\r\n", clean.Replace(msg.Subject))
+  fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+  b.WriteString("MIME-Version: 1.0\r\n")
+  b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+  b.WriteString("\r\n")
+  b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+  return []byte(b.String())
+}
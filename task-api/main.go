@@ -1,6 +1,11 @@
 package main
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 
@@ -19,6 +24,14 @@ func main() {
   // load env
   configs.LoadEnv()
 
+  // load JWT signing keys, fail fast on a broken key directory
+  if err := utils.InitKeys(); err != nil {
+    log.Fatal().Err(err).Msg("Failed to load JWT keys")
+  }
+
+  // reload keys on SIGHUP after adding or retiring key files
+  go reloadKeysOnSignal()
+
   // connect to database
   mongoURI := configs.GetEnv("MONGO_URI", "mongodb://localhost:27017")
   db.ConnectDB(mongoURI)
@@ -26,6 +39,12 @@ func main() {
   // Initialize container
   container := app.NewContainer(db.DB)
 
+  // delete tasks that have been in the trash longer than TRASH_RETENTION
+  go container.TaskPurger.Run(context.Background())
+
+  // archive tasks completed more than AUTO_ARCHIVE_DAYS ago, returns at once when it is not set
+  go container.TaskArchiver.Run(context.Background())
+
   // Setup Gin
   r := gin.New()
 
@@ -48,3 +67,15 @@ func main() {
   log.Printf("Server: http://localhost:%s", port)
   r.Run(":" + port)
 }
+
+// reloadKeysOnSignal - re-read JWT keys on every SIGHUP, keeping the old set on error
+func reloadKeysOnSignal() {
+  signals := make(chan os.Signal, 1)
+  signal.Notify(signals, syscall.SIGHUP)
+
+  for range signals {
+    if err := utils.InitKeys(); err != nil {
+      log.Error().Err(err).Msg("Failed to reload JWT keys, keeping current keys")
+    }
+  }
+}
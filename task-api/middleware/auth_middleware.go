@@ -1,14 +1,29 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 
+	"task-api/repositories"
+	"task-api/services"
+	"task-api/types"
 	"task-api/utils"
 )
 
-// AuthMiddleware - JWT authentication
-func AuthMiddleware() gin.HandlerFunc {
+// Values of the "authMethod" context key
+const (
+  AuthMethodSession = "session" // JWT access token from a login
+  AuthMethodPAT     = "pat"     // Personal access token
+)
+
+// AuthMiddleware - JWT or personal access token authentication,
+// rejects JWTs found in the revocation store
+func AuthMiddleware(revocations repositories.RevocationStore, tokens services.PersonalAccessTokenService) gin.HandlerFunc {
   return func(c *gin.Context) {
     // Get token from header
     authHeader := c.GetHeader("Authorization")
@@ -26,19 +41,91 @@ func AuthMiddleware() gin.HandlerFunc {
       token = authHeader[7:]
     }
     
+    // Personal access tokens are opaque, look them up instead of parsing
+    if strings.HasPrefix(token, services.PersonalAccessTokenPrefix) {
+      authenticatePAT(c, tokens, token)
+      return
+    }
+
     // Validate token
     claims, err := utils.ValidateToken(token)
+    if err == nil && claims.Purpose != "" {
+      // Pending 2FA tokens are only good for POST /auth/2fa/login
+      err = errors.New("not an access token")
+    }
     if err != nil {
       log.Warn().Err(err).Str("ip", c.ClientIP()).Msg("Invalid token")
       utils.Fail(c, 401, "Unauthorized", gin.H{"error": "Invalid or expired token"})
       c.Abort()
       return
     }
+
+    // Check revocation (logout / logout everywhere)
+    ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+    defer cancel()
+
+    revoked, err := revocations.IsRevoked(ctx, claims.ID, claims.UserID, issuedAt(claims))
+    if err != nil {
+      // Fail closed: a revoked token must never pass because the store is down
+      log.Error().Err(err).Msg("Failed to check token revocation")
+      utils.Error(c, 503, "Service unavailable", 0, nil)
+      c.Abort()
+      return
+    }
+    if revoked {
+      log.Warn().Str("ip", c.ClientIP()).Str("jti", claims.ID).Msg("Revoked token")
+      utils.Fail(c, 401, "Unauthorized", gin.H{"error": "Invalid or expired token"})
+      c.Abort()
+      return
+    }
     
     // Set user info in context
     c.Set("userID", claims.UserID)
     c.Set("userEmail", claims.Email)
+    c.Set("userRole", types.EffectiveRole(claims.Role))
+    c.Set("authMethod", AuthMethodSession)
+    c.Set("tokenID", claims.ID)
+    if claims.ExpiresAt != nil {
+      c.Set("tokenExpiresAt", claims.ExpiresAt.Time)
+    }
     
     c.Next()
   }
 }
+
+// authenticatePAT - resolve personal access token and set its owner and scopes in context
+func authenticatePAT(c *gin.Context, tokens services.PersonalAccessTokenService, token string) {
+  ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+  defer cancel()
+
+  pat, user, err := tokens.Authenticate(ctx, token)
+  if err != nil {
+    if errors.Is(err, services.ErrInvalidPersonalAccessToken) {
+      log.Warn().Str("ip", c.ClientIP()).Msg("Invalid personal access token")
+      utils.Fail(c, 401, "Unauthorized", gin.H{"error": "Invalid or expired token"})
+      c.Abort()
+      return
+    }
+
+    log.Error().Err(err).Msg("Failed to check personal access token")
+    utils.Error(c, 503, "Service unavailable", 0, nil)
+    c.Abort()
+    return
+  }
+
+  c.Set("userID", user.ID)
+  c.Set("userEmail", user.Email)
+  c.Set("userRole", types.EffectiveRole(user.Role))
+  c.Set("authMethod", AuthMethodPAT)
+  c.Set("tokenScopes", pat.Scopes)
+
+  c.Next()
+}
+
+// issuedAt - token issue time, zero time when iat is missing
+func issuedAt(claims *types.JWTClaims) time.Time {
+  if claims.IssuedAt == nil {
+    return time.Time{}
+  }
+  return claims.IssuedAt.Time
+}
@@ -1,18 +1,75 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
 
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/services"
+	"task-api/types"
 	"task-api/utils"
 )
 
+// failingRevocationStore - RevocationStore whose backend is unavailable
+type failingRevocationStore struct{}
+
+func (failingRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+  return errors.New("store unavailable")
+}
+
+func (failingRevocationStore) RevokeAllForUser(ctx context.Context, userID bson.ObjectID, issuedBefore time.Time, expiresAt time.Time) error {
+  return errors.New("store unavailable")
+}
+
+func (failingRevocationStore) IsRevoked(ctx context.Context, tokenID string, userID bson.ObjectID, issuedAt time.Time) (bool, error) {
+  return false, errors.New("store unavailable")
+}
+
+// MockPersonalAccessTokenService mocks the PersonalAccessTokenService interface
+type MockPersonalAccessTokenService struct {
+  mock.Mock
+}
+
+func (m *MockPersonalAccessTokenService) Create(ctx context.Context, userID bson.ObjectID, input types.CreatePersonalAccessTokenInput) (*types.CreatePersonalAccessTokenResponse, error) {
+  args := m.Called(ctx, userID, input)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*types.CreatePersonalAccessTokenResponse), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenService) List(ctx context.Context, userID bson.ObjectID) ([]types.PersonalAccessTokenResponse, error) {
+  args := m.Called(ctx, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]types.PersonalAccessTokenResponse), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenService) Revoke(ctx context.Context, userID, tokenID bson.ObjectID) error {
+  args := m.Called(ctx, userID, tokenID)
+  return args.Error(0)
+}
+
+func (m *MockPersonalAccessTokenService) Authenticate(ctx context.Context, token string) (*models.PersonalAccessToken, *models.User, error) {
+  args := m.Called(ctx, token)
+  if args.Get(0) == nil {
+    return nil, nil, args.Error(2)
+  }
+  return args.Get(0).(*models.PersonalAccessToken), args.Get(1).(*models.User), args.Error(2)
+}
+
 // TestMain sets up environment for middleware tests
 func TestMain(m *testing.M) {
   os.Setenv("GO_ENV", "test")
@@ -32,7 +89,7 @@ func TestAuthMiddleware(t *testing.T) {
   t.Run("should pass with valid token", func(t *testing.T) {
     // Setup
     router := gin.New()
-    router.Use(AuthMiddleware())
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), new(MockPersonalAccessTokenService)))
     router.GET("/protected", func(c *gin.Context) {
       userID := c.GetString("userID")
       userEmail := c.GetString("userEmail")
@@ -46,7 +103,7 @@ func TestAuthMiddleware(t *testing.T) {
     // Generate valid token
     userID := bson.NewObjectID()
     email := "test@test.com"
-    token, _ := utils.GenerateToken(userID, email)
+    token, _ := utils.GenerateToken(userID, email, "member")
 
     // Execute
     req := httptest.NewRequest("GET", "/protected", nil)
@@ -62,7 +119,7 @@ func TestAuthMiddleware(t *testing.T) {
 
   t.Run("should fail with missing authorization header", func(t *testing.T) {
     router := gin.New()
-    router.Use(AuthMiddleware())
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), new(MockPersonalAccessTokenService)))
     router.GET("/protected", func(c *gin.Context) {
       c.JSON(200, gin.H{"message": "success"})
     })
@@ -78,7 +135,7 @@ func TestAuthMiddleware(t *testing.T) {
 
   t.Run("should fail with invalid token format", func(t *testing.T) {
     router := gin.New()
-    router.Use(AuthMiddleware())
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), new(MockPersonalAccessTokenService)))
     router.GET("/protected", func(c *gin.Context) {
       c.JSON(200, gin.H{"message": "success"})
     })
@@ -94,7 +151,7 @@ func TestAuthMiddleware(t *testing.T) {
 
   t.Run("should fail with malformed token", func(t *testing.T) {
     router := gin.New()
-    router.Use(AuthMiddleware())
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), new(MockPersonalAccessTokenService)))
     router.GET("/protected", func(c *gin.Context) {
       c.JSON(200, gin.H{"message": "success"})
     })
@@ -109,14 +166,14 @@ func TestAuthMiddleware(t *testing.T) {
 
   t.Run("should handle token without Bearer prefix", func(t *testing.T) {
     router := gin.New()
-    router.Use(AuthMiddleware())
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), new(MockPersonalAccessTokenService)))
     router.GET("/protected", func(c *gin.Context) {
       c.JSON(200, gin.H{"message": "success"})
     })
 
     // Generate valid token
     userID := bson.NewObjectID()
-    token, _ := utils.GenerateToken(userID, "test@test.com")
+    token, _ := utils.GenerateToken(userID, "test@test.com", "member")
 
     req := httptest.NewRequest("GET", "/protected", nil)
     req.Header.Set("Authorization", token) // Without "Bearer "
@@ -128,7 +185,7 @@ func TestAuthMiddleware(t *testing.T) {
 
   t.Run("should fail with empty token", func(t *testing.T) {
     router := gin.New()
-    router.Use(AuthMiddleware())
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), new(MockPersonalAccessTokenService)))
     router.GET("/protected", func(c *gin.Context) {
       c.JSON(200, gin.H{"message": "success"})
     })
@@ -143,7 +200,7 @@ func TestAuthMiddleware(t *testing.T) {
 
   t.Run("should set user context correctly", func(t *testing.T) {
     router := gin.New()
-    router.Use(AuthMiddleware())
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), new(MockPersonalAccessTokenService)))
     router.GET("/protected", func(c *gin.Context) {
       // Check if context is set correctly
       userID, exists := c.Get("userID")
@@ -158,13 +215,225 @@ func TestAuthMiddleware(t *testing.T) {
     })
 
     userID := bson.NewObjectID()
-    token, _ := utils.GenerateToken(userID, "test@test.com")
+    token, _ := utils.GenerateToken(userID, "test@test.com", "member")
+
+    req := httptest.NewRequest("GET", "/protected", nil)
+    req.Header.Set("Authorization", "Bearer "+token)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+  })
+
+  t.Run("should reject revoked token", func(t *testing.T) {
+    store := repositories.NewMemoryRevocationStore()
+    router := gin.New()
+    router.Use(AuthMiddleware(store, new(MockPersonalAccessTokenService)))
+    router.GET("/protected", func(c *gin.Context) {
+      c.JSON(200, gin.H{"message": "success"})
+    })
+
+    userID := bson.NewObjectID()
+    token, _ := utils.GenerateToken(userID, "test@test.com", "member")
+    claims, _ := utils.ValidateToken(token)
+    store.RevokeToken(context.Background(), claims.ID, claims.ExpiresAt.Time)
+
+    req := httptest.NewRequest("GET", "/protected", nil)
+    req.Header.Set("Authorization", "Bearer "+token)
+    w := httptest.NewRecorder()
+
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnauthorized, w.Code)
+  })
+
+  t.Run("should reject mfa pending token", func(t *testing.T) {
+    router := gin.New()
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), new(MockPersonalAccessTokenService)))
+    router.GET("/protected", func(c *gin.Context) {
+      c.JSON(200, gin.H{"message": "success"})
+    })
+
+    token, _ := utils.GenerateMFAToken(bson.NewObjectID(), "test@test.com")
+
+    req := httptest.NewRequest("GET", "/protected", nil)
+    req.Header.Set("Authorization", "Bearer "+token)
+    w := httptest.NewRecorder()
+
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnauthorized, w.Code)
+  })
+
+  t.Run("should reject token issued before logout everywhere", func(t *testing.T) {
+    store := repositories.NewMemoryRevocationStore()
+    router := gin.New()
+    router.Use(AuthMiddleware(store, new(MockPersonalAccessTokenService)))
+    router.GET("/protected", func(c *gin.Context) {
+      c.JSON(200, gin.H{"message": "success"})
+    })
+
+    userID := bson.NewObjectID()
+    token, _ := utils.GenerateToken(userID, "test@test.com", "member")
+    store.RevokeAllForUser(context.Background(), userID, time.Now().Add(2*time.Second), time.Now().Add(time.Hour))
+
+    req := httptest.NewRequest("GET", "/protected", nil)
+    req.Header.Set("Authorization", "Bearer "+token)
+    w := httptest.NewRecorder()
+
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnauthorized, w.Code)
+  })
+
+  t.Run("should set token id for logout", func(t *testing.T) {
+    router := gin.New()
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), new(MockPersonalAccessTokenService)))
+    router.GET("/protected", func(c *gin.Context) {
+      assert.NotEmpty(t, c.GetString("tokenID"))
+      assert.False(t, c.GetTime("tokenExpiresAt").IsZero())
+      c.JSON(200, gin.H{"message": "success"})
+    })
+
+    token, _ := utils.GenerateToken(bson.NewObjectID(), "test@test.com", "member")
+
+    req := httptest.NewRequest("GET", "/protected", nil)
+    req.Header.Set("Authorization", "Bearer "+token)
+    w := httptest.NewRecorder()
+
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+  })
+
+  t.Run("should fail closed when revocation store is unavailable", func(t *testing.T) {
+    router := gin.New()
+    router.Use(AuthMiddleware(failingRevocationStore{}, new(MockPersonalAccessTokenService)))
+    router.GET("/protected", func(c *gin.Context) {
+      c.JSON(200, gin.H{"message": "success"})
+    })
+
+    token, _ := utils.GenerateToken(bson.NewObjectID(), "test@test.com", "member")
 
     req := httptest.NewRequest("GET", "/protected", nil)
     req.Header.Set("Authorization", "Bearer "+token)
     w := httptest.NewRecorder()
+
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+  })
+
+  t.Run("should accept personal access token", func(t *testing.T) {
+    tokens := new(MockPersonalAccessTokenService)
+    router := gin.New()
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), tokens))
+    router.GET("/protected", func(c *gin.Context) {
+      assert.Equal(t, AuthMethodPAT, c.GetString("authMethod"))
+      assert.Equal(t, []string{types.ScopeTasksRead}, c.GetStringSlice("tokenScopes"))
+      c.JSON(200, gin.H{"userEmail": c.GetString("userEmail")})
+    })
+
+    user := &models.User{ID: bson.NewObjectID(), Email: "ci@test.com"}
+    pat := &models.PersonalAccessToken{UserID: user.ID, Scopes: []string{types.ScopeTasksRead}}
+    tokens.On("Authenticate", mock.Anything, "mtp_valid").Return(pat, user, nil)
+
+    req := httptest.NewRequest("GET", "/protected", nil)
+    req.Header.Set("Authorization", "Bearer mtp_valid")
+    w := httptest.NewRecorder()
+
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), "ci@test.com")
+  })
+
+  t.Run("should reject invalid personal access token", func(t *testing.T) {
+    tokens := new(MockPersonalAccessTokenService)
+    router := gin.New()
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), tokens))
+    router.GET("/protected", func(c *gin.Context) {
+      c.JSON(200, gin.H{"message": "success"})
+    })
+
+    tokens.On("Authenticate", mock.Anything, "mtp_revoked").Return(nil, nil, services.ErrInvalidPersonalAccessToken)
+
+    req := httptest.NewRequest("GET", "/protected", nil)
+    req.Header.Set("Authorization", "Bearer mtp_revoked")
+    w := httptest.NewRecorder()
+
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusUnauthorized, w.Code)
+  })
+
+  t.Run("should fail closed when token store is unavailable", func(t *testing.T) {
+    tokens := new(MockPersonalAccessTokenService)
+    router := gin.New()
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), tokens))
+    router.GET("/protected", func(c *gin.Context) {
+      c.JSON(200, gin.H{"message": "success"})
+    })
+
+    tokens.On("Authenticate", mock.Anything, mock.Anything).Return(nil, nil, errors.New("store unavailable"))
+
+    req := httptest.NewRequest("GET", "/protected", nil)
+    req.Header.Set("Authorization", "Bearer mtp_any")
+    w := httptest.NewRecorder()
+
     router.ServeHTTP(w, req)
 
+    assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+  })
+}
+
+func TestAuthMiddleware_Roles(t *testing.T) {
+  gin.SetMode(gin.TestMode)
+
+  newRouter := func() *gin.Engine {
+    router := gin.New()
+    router.Use(AuthMiddleware(repositories.NewMemoryRevocationStore(), new(MockPersonalAccessTokenService)))
+    router.GET("/admin", RequireRole(types.RoleAdmin), func(c *gin.Context) {
+      c.JSON(200, gin.H{"role": c.GetString("userRole")})
+    })
+    router.GET("/tasks", func(c *gin.Context) {
+      c.JSON(200, gin.H{"role": c.GetString("userRole")})
+    })
+    return router
+  }
+
+  t.Run("should take role from token and authorize admin", func(t *testing.T) {
+    token, _ := utils.GenerateToken(bson.NewObjectID(), "admin@test.com", types.RoleAdmin)
+
+    req := httptest.NewRequest("GET", "/admin", nil)
+    req.Header.Set("Authorization", "Bearer "+token)
+    w := httptest.NewRecorder()
+    newRouter().ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), `"role":"admin"`)
+  })
+
+  t.Run("should forbid member on admin route", func(t *testing.T) {
+    token, _ := utils.GenerateToken(bson.NewObjectID(), "test@test.com", types.RoleMember)
+
+    req := httptest.NewRequest("GET", "/admin", nil)
+    req.Header.Set("Authorization", "Bearer "+token)
+    w := httptest.NewRecorder()
+    newRouter().ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusForbidden, w.Code)
+  })
+
+  t.Run("should treat tokens without role as member", func(t *testing.T) {
+    token, _ := utils.GenerateToken(bson.NewObjectID(), "test@test.com", "")
+
+    req := httptest.NewRequest("GET", "/tasks", nil)
+    req.Header.Set("Authorization", "Bearer "+token)
+    w := httptest.NewRecorder()
+    newRouter().ServeHTTP(w, req)
+
     assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), `"role":"member"`)
   })
 }
@@ -43,7 +43,8 @@ func CORSMiddleware() gin.HandlerFunc {
         }
 
         c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
-        c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
+        c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, If-Match")
+        c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
         c.Writer.Header().Set("Access-Control-Max-Age", "86400")
 
         if c.Request.Method == "OPTIONS" {
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"task-api/types"
+	"task-api/utils"
+)
+
+// RequireRole - allow only users whose role (set by AuthMiddleware) is one of roles
+func RequireRole(roles ...string) gin.HandlerFunc {
+  return func(c *gin.Context) {
+    role := c.GetString("userRole")
+
+    for _, allowed := range roles {
+      if role == allowed {
+        c.Next()
+        return
+      }
+    }
+
+    log.Warn().
+      Str("ip", c.ClientIP()).
+      Str("role", role).
+      Strs("required_roles", roles).
+      Msg("Role not allowed")
+    utils.Fail(c, 403, types.MsgForbidden, gin.H{"required_roles": roles})
+    c.Abort()
+  }
+}
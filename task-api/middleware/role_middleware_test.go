@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/types"
+)
+
+// withRole - fake AuthMiddleware result, empty role means no authentication ran
+func withRole(role string) gin.HandlerFunc {
+  return func(c *gin.Context) {
+    if role != "" {
+      c.Set("userRole", role)
+    }
+    c.Next()
+  }
+}
+
+func TestRequireRole(t *testing.T) {
+  gin.SetMode(gin.TestMode)
+
+  cases := []struct {
+    name    string
+    role    string
+    allowed []string
+    want    int
+  }{
+    {"admin reaches admin route", types.RoleAdmin, []string{types.RoleAdmin}, http.StatusOK},
+    {"member is forbidden on admin route", types.RoleMember, []string{types.RoleAdmin}, http.StatusForbidden},
+    {"viewer is forbidden on admin route", types.RoleViewer, []string{types.RoleAdmin}, http.StatusForbidden},
+    {"member may write", types.RoleMember, []string{types.RoleAdmin, types.RoleMember}, http.StatusOK},
+    {"viewer may not write", types.RoleViewer, []string{types.RoleAdmin, types.RoleMember}, http.StatusForbidden},
+    {"missing role is forbidden", "", []string{types.RoleAdmin, types.RoleMember, types.RoleViewer}, http.StatusForbidden},
+    {"unknown role is forbidden", "superuser", []string{types.RoleAdmin}, http.StatusForbidden},
+  }
+
+  for _, tc := range cases {
+    t.Run(tc.name, func(t *testing.T) {
+      router := gin.New()
+      router.GET("/protected", withRole(tc.role), RequireRole(tc.allowed...), func(c *gin.Context) {
+        c.JSON(200, gin.H{"message": "success"})
+      })
+
+      req := httptest.NewRequest("GET", "/protected", nil)
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, req)
+
+      assert.Equal(t, tc.want, w.Code)
+      if tc.want == http.StatusForbidden {
+        assert.Contains(t, w.Body.String(), types.MsgForbidden)
+      }
+    })
+  }
+}
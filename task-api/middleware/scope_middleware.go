@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"task-api/types"
+	"task-api/utils"
+)
+
+// RequireScope - personal access tokens must carry scope, login sessions have full access
+func RequireScope(scope string) gin.HandlerFunc {
+  return func(c *gin.Context) {
+    if c.GetString("authMethod") != AuthMethodPAT {
+      c.Next()
+      return
+    }
+
+    for _, granted := range c.GetStringSlice("tokenScopes") {
+      if granted == scope {
+        c.Next()
+        return
+      }
+    }
+
+    log.Warn().Str("ip", c.ClientIP()).Str("scope", scope).Msg("Personal access token missing scope")
+    utils.Fail(c, 403, types.MsgInsufficientScope, gin.H{"required_scope": scope})
+    c.Abort()
+  }
+}
+
+// SessionOnly - refuse personal access tokens, e.g. for account and token management
+func SessionOnly() gin.HandlerFunc {
+  return func(c *gin.Context) {
+    if c.GetString("authMethod") == AuthMethodPAT {
+      utils.Fail(c, 403, types.MsgSessionRequired, nil)
+      c.Abort()
+      return
+    }
+
+    c.Next()
+  }
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/types"
+)
+
+// withAuth - fake AuthMiddleware result
+func withAuth(method string, scopes []string) gin.HandlerFunc {
+  return func(c *gin.Context) {
+    c.Set("authMethod", method)
+    if scopes != nil {
+      c.Set("tokenScopes", scopes)
+    }
+    c.Next()
+  }
+}
+
+func TestRequireScope(t *testing.T) {
+  gin.SetMode(gin.TestMode)
+
+  cases := []struct {
+    name   string
+    method string
+    scopes []string
+    want   int
+  }{
+    {"session has full access", AuthMethodSession, nil, http.StatusOK},
+    {"token with scope passes", AuthMethodPAT, []string{types.ScopeTasksRead, types.ScopeTasksWrite}, http.StatusOK},
+    {"token without scope is forbidden", AuthMethodPAT, []string{types.ScopeTasksRead}, http.StatusForbidden},
+    {"token without scopes is forbidden", AuthMethodPAT, []string{}, http.StatusForbidden},
+  }
+
+  for _, tc := range cases {
+    t.Run(tc.name, func(t *testing.T) {
+      router := gin.New()
+      router.POST("/tasks", withAuth(tc.method, tc.scopes), RequireScope(types.ScopeTasksWrite), func(c *gin.Context) {
+        c.JSON(200, gin.H{"message": "success"})
+      })
+
+      req := httptest.NewRequest("POST", "/tasks", nil)
+      w := httptest.NewRecorder()
+      router.ServeHTTP(w, req)
+
+      assert.Equal(t, tc.want, w.Code)
+      if tc.want == http.StatusForbidden {
+        assert.Contains(t, w.Body.String(), types.ScopeTasksWrite)
+      }
+    })
+  }
+}
+
+func TestSessionOnly(t *testing.T) {
+  gin.SetMode(gin.TestMode)
+
+  t.Run("should allow login session", func(t *testing.T) {
+    router := gin.New()
+    router.POST("/auth/tokens", withAuth(AuthMethodSession, nil), SessionOnly(), func(c *gin.Context) {
+      c.JSON(200, gin.H{"message": "success"})
+    })
+
+    req := httptest.NewRequest("POST", "/auth/tokens", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+  })
+
+  t.Run("should refuse personal access token", func(t *testing.T) {
+    router := gin.New()
+    router.POST("/auth/tokens", withAuth(AuthMethodPAT, []string{types.ScopeTasksWrite}), SessionOnly(), func(c *gin.Context) {
+      c.JSON(200, gin.H{"message": "success"})
+    })
+
+    req := httptest.NewRequest("POST", "/auth/tokens", nil)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusForbidden, w.Code)
+    assert.Contains(t, w.Body.String(), types.MsgSessionRequired)
+  })
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Comment - message in the discussion thread of a task
+type Comment struct {
+  ID        bson.ObjectID   `bson:"_id,omitempty"`
+  TaskID    bson.ObjectID   `bson:"task_id"`
+  AuthorID  bson.ObjectID   `bson:"author_id"`
+  Body      string          `bson:"body"`
+  Mentions  []bson.ObjectID `bson:"mentions,omitempty"` // users mentioned by @email
+  CreatedAt time.Time       `bson:"created_at"`
+  UpdatedAt time.Time       `bson:"updated_at"`
+}
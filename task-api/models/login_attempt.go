@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginAttempt - failed login counter, ID is "email:<address>" or "ip:<address>"
+type LoginAttempt struct {
+  ID            string     `bson:"_id"`
+  Failures      int        `bson:"failures"`
+  LastFailureAt time.Time  `bson:"last_failure_at"`
+  LockedUntil   *time.Time `bson:"locked_until,omitempty"`
+  ExpiresAt     time.Time  `bson:"expires_at"` // TTL index removes stale counters
+}
+
+// IsLocked - check whether the key is locked at the given time
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+  return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
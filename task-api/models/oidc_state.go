@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// OIDCState - pending SSO login between /auth/oidc/login and the callback,
+// keyed by the hash of the state parameter and consumed once
+type OIDCState struct {
+  ID           string    `bson:"_id"`           // SHA-256 of the state value
+  Nonce        string    `bson:"nonce"`         // Must come back in the ID token
+  CodeVerifier string    `bson:"code_verifier"` // PKCE verifier, never leaves the server
+  ExpiresAt    time.Time `bson:"expires_at"`    // TTL index removes abandoned logins
+  CreatedAt    time.Time `bson:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PasswordReset - database model for single-use reset tokens, only the hash is stored
+type PasswordReset struct {
+  ID        bson.ObjectID  `bson:"_id,omitempty"`
+  UserID    bson.ObjectID  `bson:"user_id"`
+  TokenHash string         `bson:"token_hash"`
+  ExpiresAt time.Time      `bson:"expires_at"`  // TTL index removes expired tokens
+  CreatedAt time.Time      `bson:"created_at"`
+  UsedAt    *time.Time     `bson:"used_at,omitempty"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PersonalAccessToken - database model for long-lived API tokens, only the hash is stored
+type PersonalAccessToken struct {
+  ID         bson.ObjectID  `bson:"_id,omitempty"`
+  UserID     bson.ObjectID  `bson:"user_id"`
+  Name       string         `bson:"name"`
+  TokenHash  string         `bson:"token_hash"`
+  Prefix     string         `bson:"prefix"` // First characters of the token, to tell tokens apart in listings
+  Scopes     []string       `bson:"scopes"`
+  ExpiresAt  *time.Time     `bson:"expires_at,omitempty"` // nil never expires, TTL index removes expired tokens
+  LastUsedAt *time.Time     `bson:"last_used_at,omitempty"`
+  CreatedAt  time.Time      `bson:"created_at"`
+}
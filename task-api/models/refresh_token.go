@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// RefreshToken - database model, only the SHA-256 hash of the token is stored
+type RefreshToken struct {
+  ID        bson.ObjectID  `bson:"_id,omitempty"`
+  UserID    bson.ObjectID  `bson:"user_id"`
+  FamilyID  bson.ObjectID  `bson:"family_id"`   // shared by every token rotated from the same login
+  TokenHash string         `bson:"token_hash"`
+  ExpiresAt time.Time      `bson:"expires_at"`  // TTL index removes expired tokens
+  CreatedAt time.Time      `bson:"created_at"`
+  UsedAt    *time.Time     `bson:"used_at,omitempty"`
+  RevokedAt *time.Time     `bson:"revoked_at,omitempty"`
+}
@@ -8,15 +8,61 @@ import (
 
 // Task - database model
 type Task struct {
-  ID          bson.ObjectID  `bson:"_id,omitempty"`
-  UserID      bson.ObjectID  `bson:"user_id"`
-  Title       string         `bson:"title"`
-  Description string         `bson:"description"`
-  Status      string         `bson:"status"`      // pending, in_progress, completed
-  Priority    string         `bson:"priority"`    // low, medium, high
-  DueDate     *time.Time     `bson:"due_date,omitempty"`
-  Tags        []string       `bson:"tags"`
-  CreatedAt   time.Time      `bson:"created_at"`
-  UpdatedAt   time.Time      `bson:"updated_at"`
-  CompletedAt *time.Time     `bson:"completed_at,omitempty"`
+  ID           bson.ObjectID   `bson:"_id,omitempty"`
+  UserID       bson.ObjectID   `bson:"user_id"`
+  WorkspaceID  *bson.ObjectID  `bson:"workspace_id,omitempty"` // nil for personal tasks
+  ParentID     *bson.ObjectID  `bson:"parent_id,omitempty"`    // nil for top-level tasks
+  Title        string          `bson:"title"`
+  Description  string          `bson:"description"`
+  Status       string          `bson:"status"`                 // pending, in_progress, completed
+  Priority     string          `bson:"priority"`               // low, medium, high
+  DueDate      *time.Time      `bson:"due_date,omitempty"`
+  Tags         []string        `bson:"tags"`
+  AssigneeIDs  []bson.ObjectID `bson:"assignee_ids,omitempty"`
+  WatcherIDs   []bson.ObjectID `bson:"watcher_ids,omitempty"`
+  BlockedBy    []bson.ObjectID `bson:"blocked_by,omitempty"` // tasks that must be completed first
+  Recurrence   string          `bson:"recurrence,omitempty"` // iCalendar RRULE, empty for one-off tasks
+  Series       *TaskSeries     `bson:"series,omitempty"`     // set once a task recurs
+  Checklist    []ChecklistItem `bson:"checklist,omitempty"`
+  Attachments  []Attachment    `bson:"attachments,omitempty"` // metadata, contents live in the blob store
+  CreatedAt    time.Time       `bson:"created_at"`
+  UpdatedAt    time.Time       `bson:"updated_at"`
+  CompletedAt  *time.Time      `bson:"completed_at,omitempty"`
+  ArchivedAt   *time.Time      `bson:"archived_at,omitempty"`   // set while the task is archived
+  UnarchivedAt *time.Time      `bson:"unarchived_at,omitempty"` // last unarchived, auto-archive waits a full period from it
+  DeletedAt    *time.Time      `bson:"deleted_at,omitempty"`    // set while the task is in the trash
+  DeletedWith  *bson.ObjectID  `bson:"deleted_with,omitempty"`  // trashed task this subtask went to the trash with
+  Version      int64           `bson:"version"`                 // moves on with every write, clients send it back as If-Match
+}
+
+// ChecklistItem - simple step inside a task
+type ChecklistItem struct {
+  ID     bson.ObjectID `bson:"id"`
+  Text   string        `bson:"text"`
+  Done   bool          `bson:"done"`
+  Order  int           `bson:"order"` // items are listed by order, lowest first
+  DoneAt *time.Time    `bson:"done_at,omitempty"`
+}
+
+// Attachment - file attached to a task, its contents are stored under ID.Hex() in the blob store
+type Attachment struct {
+  ID          bson.ObjectID `bson:"id"`
+  Name        string        `bson:"name"`         // original file name
+  ContentType string        `bson:"content_type"` // sniffed from the contents
+  Size        int64         `bson:"size"`
+  UploadedBy  bson.ObjectID `bson:"uploaded_by"`
+  UploadedAt  time.Time     `bson:"uploaded_at"`
+}
+
+// TaskSeries - place of a recurring task in its series and the values the next occurrence starts from
+type TaskSeries struct {
+  ID          bson.ObjectID `bson:"id"`         // first task of the series
+  Occurrence  int           `bson:"occurrence"` // 1 for the first task
+  Timezone    string        `bson:"timezone"`   // IANA zone the rule is expanded in
+  Start       time.Time     `bson:"start"`      // DTSTART of the rule
+  Scheduled   time.Time     `bson:"scheduled"`  // due date the rule gave this occurrence
+  Title       string        `bson:"title"`
+  Description string        `bson:"description"`
+  Priority    string        `bson:"priority"`
+  Tags        []string      `bson:"tags"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TaskEvent - entry in the history of a task
+type TaskEvent struct {
+  ID        bson.ObjectID `bson:"_id,omitempty"`
+  TaskID    bson.ObjectID `bson:"task_id"`
+  ActorID   bson.ObjectID `bson:"actor_id"`          // user who made the change
+  Action    string        `bson:"action"`            // created, updated, deleted
+  Changes   []FieldChange `bson:"changes,omitempty"` // empty for deletes
+  CreatedAt time.Time     `bson:"created_at"`
+}
+
+// FieldChange - value of a task field before and after an event, nil when unset
+type FieldChange struct {
+  Field string `bson:"field"`
+  Old   any    `bson:"old"`
+  New   any    `bson:"new"`
+}
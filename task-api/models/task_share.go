@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TaskShare - access to a single task granted to another user, unique per task and user
+type TaskShare struct {
+  ID         bson.ObjectID  `bson:"_id,omitempty"`
+  TaskID     bson.ObjectID  `bson:"task_id"`
+  UserID     bson.ObjectID  `bson:"user_id"`
+  Permission string         `bson:"permission"` // view, edit, owner
+  SharedBy   bson.ObjectID  `bson:"shared_by"`
+  CreatedAt  time.Time      `bson:"created_at"`
+  UpdatedAt  time.Time      `bson:"updated_at"`
+}
@@ -12,6 +12,16 @@ type User struct {
     Email     string             `bson:"email" json:"email" binding:"required,email"`
     Password  string             `bson:"password" json:"-"`
     Name      string             `bson:"name" json:"name" binding:"required,min=2,max=100"`
+    Timezone  string             `bson:"timezone,omitempty" json:"timezone,omitempty"` // IANA name, e.g. Asia/Jakarta
+    Locale    string             `bson:"locale,omitempty" json:"locale,omitempty"`     // BCP 47 tag, e.g. id-ID
+    MFAEnabled    bool           `bson:"mfa_enabled" json:"mfa_enabled"`
+    TOTPSecret    string         `bson:"totp_secret,omitempty" json:"-"`    // Set on setup, active once MFAEnabled
+    TOTPLastStep  int64          `bson:"totp_last_step,omitempty" json:"-"` // Last accepted time step, blocks code replay
+    RecoveryCodes []string       `bson:"recovery_codes,omitempty" json:"-"` // SHA-256 hashes of unused recovery codes
+    OIDCIssuer    string         `bson:"oidc_issuer,omitempty" json:"-"`    // Linked SSO identity, unique with OIDCSubject
+    OIDCSubject   string         `bson:"oidc_subject,omitempty" json:"-"`
+    Role          string         `bson:"role,omitempty" json:"role"`                 // admin, member or viewer, empty means member
+    DisabledAt    *time.Time     `bson:"disabled_at,omitempty" json:"disabled_at"` // Disabled accounts cannot log in
     CreatedAt time.Time          `bson:"created_at" json:"created_at"`
     UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
 }
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Workspace - database model for a team sharing tasks
+type Workspace struct {
+  ID        bson.ObjectID  `bson:"_id,omitempty"`
+  Name      string         `bson:"name"`
+  CreatedBy bson.ObjectID  `bson:"created_by"`
+  CreatedAt time.Time      `bson:"created_at"`
+  UpdatedAt time.Time      `bson:"updated_at"`
+}
+
+// WorkspaceMember - membership of a user in a workspace, unique per workspace and user
+type WorkspaceMember struct {
+  ID          bson.ObjectID  `bson:"_id,omitempty"`
+  WorkspaceID bson.ObjectID  `bson:"workspace_id"`
+  UserID      bson.ObjectID  `bson:"user_id"`
+  Role        string         `bson:"role"` // owner, member
+  CreatedAt   time.Time      `bson:"created_at"`
+}
@@ -0,0 +1,174 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// minKeyRefresh - unknown kids refetch the JWKS at most this often
+const minKeyRefresh = 30 * time.Second
+
+// jsonWebKey - public key as published in the IdP's JWKS (RFC 7517)
+type jsonWebKey struct {
+  Kty string `json:"kty"`
+  Kid string `json:"kid"`
+  Use string `json:"use"`
+  Crv string `json:"crv"`
+  N   string `json:"n"`
+  E   string `json:"e"`
+  X   string `json:"x"`
+  Y   string `json:"y"`
+}
+
+// keyCache - IdP signing keys by kid, refreshed when a token names an unknown kid
+// so the IdP can rotate keys without a restart
+type keyCache struct {
+  uri   string
+  fetch func(ctx context.Context, url string, v interface{}) error
+
+  mu        sync.Mutex
+  keys      map[string]crypto.PublicKey
+  fetchedAt time.Time
+}
+
+// lookup - key for kid, an empty kid is accepted when the set has a single key
+func (c *keyCache) lookup(ctx context.Context, kid string) (crypto.PublicKey, error) {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  if key := c.find(kid); key != nil {
+    return key, nil
+  }
+
+  if c.keys != nil && time.Since(c.fetchedAt) < minKeyRefresh {
+    return nil, fmt.Errorf("unknown key id %q", kid)
+  }
+  if err := c.refresh(ctx); err != nil {
+    return nil, err
+  }
+
+  if key := c.find(kid); key != nil {
+    return key, nil
+  }
+  return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// find - cached key for kid, caller holds mu
+func (c *keyCache) find(kid string) crypto.PublicKey {
+  if kid == "" && len(c.keys) == 1 {
+    for _, key := range c.keys {
+      return key
+    }
+  }
+  return c.keys[kid]
+}
+
+// refresh - replace cached keys with the current JWKS, caller holds mu
+func (c *keyCache) refresh(ctx context.Context) error {
+  var set struct {
+    Keys []jsonWebKey `json:"keys"`
+  }
+  if err := c.fetch(ctx, c.uri, &set); err != nil {
+    return fmt.Errorf("fetch jwks: %w", err)
+  }
+
+  keys := make(map[string]crypto.PublicKey, len(set.Keys))
+  for _, jwk := range set.Keys {
+    if jwk.Use != "" && jwk.Use != "sig" {
+      continue
+    }
+    // Keys of unsupported types are skipped, other keys stay usable
+    if key, err := jwk.publicKey(); err == nil {
+      keys[jwk.Kid] = key
+    }
+  }
+
+  c.keys = keys
+  c.fetchedAt = time.Now()
+  return nil
+}
+
+// publicKey - decode RSA, EC (P-256/384/521) or Ed25519 public key
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+  switch k.Kty {
+  case "RSA":
+    n, err := decodeBigInt(k.N)
+    if err != nil {
+      return nil, err
+    }
+    e, err := decodeBigInt(k.E)
+    if err != nil {
+      return nil, err
+    }
+    if !e.IsInt64() || e.Int64() > 1<<31-1 {
+      return nil, errors.New("invalid RSA exponent")
+    }
+    return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+  case "EC":
+    return k.ecdsaKey()
+
+  case "OKP":
+    if k.Crv != "Ed25519" {
+      return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+    }
+    x, err := base64.RawURLEncoding.DecodeString(k.X)
+    if err != nil || len(x) != ed25519.PublicKeySize {
+      return nil, errors.New("invalid Ed25519 key")
+    }
+    return ed25519.PublicKey(x), nil
+
+  default:
+    return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+  }
+}
+
+// ecdsaKey - decode EC key, the point is validated to lie on the curve
+func (k jsonWebKey) ecdsaKey() (crypto.PublicKey, error) {
+  var curve elliptic.Curve
+  var validator ecdh.Curve
+  switch k.Crv {
+  case "P-256":
+    curve, validator = elliptic.P256(), ecdh.P256()
+  case "P-384":
+    curve, validator = elliptic.P384(), ecdh.P384()
+  case "P-521":
+    curve, validator = elliptic.P521(), ecdh.P521()
+  default:
+    return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+  }
+
+  x, errX := base64.RawURLEncoding.DecodeString(k.X)
+  y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+  size := (curve.Params().BitSize + 7) / 8
+  if errX != nil || errY != nil || len(x) != size || len(y) != size {
+    return nil, errors.New("invalid EC key")
+  }
+
+  point := append(append([]byte{4}, x...), y...)
+  if _, err := validator.NewPublicKey(point); err != nil {
+    return nil, errors.New("invalid EC key")
+  }
+
+  return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
+
+// decodeBigInt - base64url encoded unsigned big-endian integer
+func decodeBigInt(value string) (*big.Int, error) {
+  data, err := base64.RawURLEncoding.DecodeString(value)
+  if err != nil || len(data) == 0 {
+    return nil, errors.New("invalid integer")
+  }
+  return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,273 @@
+// Package oidctest provides an in-process OpenID Connect provider for tests.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Identity - user signed in by the mock IdP
+type Identity struct {
+  Subject       string
+  Email         string
+  EmailVerified bool
+  Name          string
+}
+
+// authRequest - pending authorization code
+type authRequest struct {
+  clientID      string
+  redirectURI   string
+  nonce         string
+  codeChallenge string
+  identity      Identity
+}
+
+// IdP - mock OpenID provider serving discovery, JWKS, authorize and token endpoints.
+// Issuer() is the server URL; the authorize endpoint signs in the current user
+// (see SetUser) without showing a login page.
+type IdP struct {
+  Server       *httptest.Server
+  ClientID     string
+  ClientSecret string // Empty accepts public clients
+
+  mu    sync.Mutex
+  user  Identity
+  key   *rsa.PrivateKey
+  kid   string
+  codes map[string]authRequest
+}
+
+// NewIdP - start mock IdP, call Close when done
+func NewIdP(clientID, clientSecret string) *IdP {
+  idp := &IdP{
+    ClientID:     clientID,
+    ClientSecret: clientSecret,
+    user:         Identity{Subject: "user-1", Email: "user@example.com", EmailVerified: true, Name: "Test User"},
+    codes:        make(map[string]authRequest),
+  }
+  idp.RotateKey()
+
+  mux := http.NewServeMux()
+  mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+  mux.HandleFunc("/jwks", idp.jwks)
+  mux.HandleFunc("/authorize", idp.authorize)
+  mux.HandleFunc("/token", idp.token)
+  idp.Server = httptest.NewServer(mux)
+
+  return idp
+}
+
+// Close - stop server
+func (i *IdP) Close() {
+  i.Server.Close()
+}
+
+// Issuer - issuer URL, also the discovery base URL
+func (i *IdP) Issuer() string {
+  return i.Server.URL
+}
+
+// SetUser - identity returned for the next authorization
+func (i *IdP) SetUser(user Identity) {
+  i.mu.Lock()
+  defer i.mu.Unlock()
+  i.user = user
+}
+
+// RotateKey - replace signing key with a new one under a new kid
+func (i *IdP) RotateKey() {
+  key, err := rsa.GenerateKey(rand.Reader, 2048)
+  if err != nil {
+    panic(err)
+  }
+
+  i.mu.Lock()
+  defer i.mu.Unlock()
+  i.key = key
+  i.kid = "key-" + randomString()
+}
+
+// SignIDToken - sign arbitrary claims with the current key, for crafting invalid tokens
+func (i *IdP) SignIDToken(claims jwt.MapClaims) string {
+  i.mu.Lock()
+  defer i.mu.Unlock()
+
+  token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+  token.Header["kid"] = i.kid
+  signed, err := token.SignedString(i.key)
+  if err != nil {
+    panic(err)
+  }
+  return signed
+}
+
+// Authorize - follow an authorization URL like a browser whose user approves the
+// login, returns code and state from the redirect back to the client
+func (i *IdP) Authorize(authURL string) (code, state string, err error) {
+  client := &http.Client{
+    CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+  }
+
+  resp, err := client.Get(authURL)
+  if err != nil {
+    return "", "", err
+  }
+  defer resp.Body.Close()
+
+  if resp.StatusCode != http.StatusFound {
+    return "", "", errors.New("authorize: unexpected status " + resp.Status)
+  }
+
+  location, err := url.Parse(resp.Header.Get("Location"))
+  if err != nil {
+    return "", "", err
+  }
+  query := location.Query()
+  if query.Get("error") != "" {
+    return "", "", errors.New("authorize: " + query.Get("error"))
+  }
+
+  return query.Get("code"), query.Get("state"), nil
+}
+
+// discovery - serve /.well-known/openid-configuration
+func (i *IdP) discovery(w http.ResponseWriter, r *http.Request) {
+  writeJSON(w, http.StatusOK, map[string]interface{}{
+    "issuer":                                i.Issuer(),
+    "authorization_endpoint":                i.Issuer() + "/authorize",
+    "token_endpoint":                        i.Issuer() + "/token",
+    "jwks_uri":                              i.Issuer() + "/jwks",
+    "response_types_supported":              []string{"code"},
+    "subject_types_supported":               []string{"public"},
+    "id_token_signing_alg_values_supported": []string{"RS256"},
+    "code_challenge_methods_supported":      []string{"S256"},
+  })
+}
+
+// jwks - serve current public key
+func (i *IdP) jwks(w http.ResponseWriter, r *http.Request) {
+  i.mu.Lock()
+  pub := i.key.PublicKey
+  kid := i.kid
+  i.mu.Unlock()
+
+  writeJSON(w, http.StatusOK, map[string]interface{}{
+    "keys": []map[string]string{{
+      "kty": "RSA",
+      "kid": kid,
+      "use": "sig",
+      "alg": "RS256",
+      "n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+      "e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+    }},
+  })
+}
+
+// authorize - issue a code for the current user and redirect back to the client
+func (i *IdP) authorize(w http.ResponseWriter, r *http.Request) {
+  query := r.URL.Query()
+  if query.Get("client_id") != i.ClientID || query.Get("response_type") != "code" ||
+    query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+    http.Error(w, "invalid_request", http.StatusBadRequest)
+    return
+  }
+
+  code := randomString()
+
+  i.mu.Lock()
+  i.codes[code] = authRequest{
+    clientID:      query.Get("client_id"),
+    redirectURI:   query.Get("redirect_uri"),
+    nonce:         query.Get("nonce"),
+    codeChallenge: query.Get("code_challenge"),
+    identity:      i.user,
+  }
+  i.mu.Unlock()
+
+  redirect, _ := url.Parse(query.Get("redirect_uri"))
+  values := redirect.Query()
+  values.Set("code", code)
+  values.Set("state", query.Get("state"))
+  redirect.RawQuery = values.Encode()
+
+  http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token - exchange a code once, checking client, redirect URI and PKCE verifier
+func (i *IdP) token(w http.ResponseWriter, r *http.Request) {
+  if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+    writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+    return
+  }
+
+  clientID, secret, ok := r.BasicAuth()
+  if ok {
+    clientID, _ = url.QueryUnescape(clientID)
+    secret, _ = url.QueryUnescape(secret)
+  } else {
+    clientID = r.PostForm.Get("client_id")
+  }
+  if clientID != i.ClientID || secret != i.ClientSecret {
+    writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+    return
+  }
+
+  i.mu.Lock()
+  req, found := i.codes[r.PostForm.Get("code")]
+  delete(i.codes, r.PostForm.Get("code"))
+  i.mu.Unlock()
+
+  sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+  challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+  if !found || req.redirectURI != r.PostForm.Get("redirect_uri") || req.codeChallenge != challenge {
+    writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+    return
+  }
+
+  now := time.Now()
+  idToken := i.SignIDToken(jwt.MapClaims{
+    "iss":            i.Issuer(),
+    "sub":            req.identity.Subject,
+    "aud":            req.clientID,
+    "iat":            now.Unix(),
+    "exp":            now.Add(5 * time.Minute).Unix(),
+    "nonce":          req.nonce,
+    "email":          req.identity.Email,
+    "email_verified": req.identity.EmailVerified,
+    "name":           req.identity.Name,
+  })
+
+  writeJSON(w, http.StatusOK, map[string]interface{}{
+    "access_token": randomString(),
+    "token_type":   "Bearer",
+    "expires_in":   300,
+    "id_token":     idToken,
+  })
+}
+
+// writeJSON - write v as JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+  w.Header().Set("Content-Type", "application/json")
+  w.WriteHeader(status)
+  json.NewEncoder(w).Encode(v)
+}
+
+// randomString - random URL safe value for codes and kids
+func randomString() string {
+  b := make([]byte, 16)
+  rand.Read(b)
+  return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,317 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"task-api/configs"
+)
+
+// maxResponseSize - cap on discovery, JWKS and token responses read from the IdP
+const maxResponseSize = 1 << 20
+
+// clockSkew - tolerated difference between our clock and the IdP's
+const clockSkew = time.Minute
+
+// Errors returned by Provider, wrapped with details from the IdP
+var (
+  ErrDiscovery      = errors.New("oidc discovery failed")
+  ErrTokenExchange  = errors.New("oidc token exchange failed")
+  ErrInvalidIDToken = errors.New("invalid id token")
+)
+
+// signingMethods - ID token algorithms accepted, never "none" or HMAC
+var signingMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Config - OpenID Connect client settings
+type Config struct {
+  Issuer       string
+  ClientID     string
+  ClientSecret string // Empty for public clients, PKCE still protects the code
+  RedirectURL  string
+  Scopes       []string
+}
+
+// Tokens - token endpoint response
+type Tokens struct {
+  AccessToken string `json:"access_token"`
+  TokenType   string `json:"token_type"`
+  IDToken     string `json:"id_token"`
+  ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Claims - identity claims of a validated ID token
+type Claims struct {
+  jwt.RegisteredClaims
+  Nonce           string `json:"nonce"`
+  AuthorizedParty string `json:"azp,omitempty"`
+  Email           string `json:"email"`
+  EmailVerified   bool   `json:"email_verified"`
+  Name            string `json:"name"`
+}
+
+// Provider - interface for an OpenID Connect identity provider
+type Provider interface {
+  Issuer() string
+  AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+  Exchange(ctx context.Context, code, codeVerifier string) (*Tokens, error)
+  VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*Claims, error)
+}
+
+// discoveryDocument - fields used from /.well-known/openid-configuration
+type discoveryDocument struct {
+  Issuer                string `json:"issuer"`
+  AuthorizationEndpoint string `json:"authorization_endpoint"`
+  TokenEndpoint         string `json:"token_endpoint"`
+  JWKSURI               string `json:"jwks_uri"`
+}
+
+// provider - implement Provider, discovery and keys are fetched lazily and cached
+type provider struct {
+  config Config
+  client *http.Client
+
+  mu        sync.Mutex
+  discovery *discoveryDocument
+  keys      *keyCache
+}
+
+// NewProvider - constructor, client defaults to an http.Client with timeout
+func NewProvider(config Config, client *http.Client) Provider {
+  if client == nil {
+    client = &http.Client{Timeout: 10 * time.Second}
+  }
+  if len(config.Scopes) == 0 {
+    config.Scopes = []string{"openid", "email", "profile"}
+  }
+  return &provider{config: config, client: client}
+}
+
+// NewProviderFromEnv - build Provider from OIDC_* env, nil when OIDC_ISSUER is not set
+func NewProviderFromEnv() Provider {
+  issuer := configs.GetEnv("OIDC_ISSUER", "")
+  if issuer == "" {
+    return nil
+  }
+
+  return NewProvider(Config{
+    Issuer:       issuer,
+    ClientID:     configs.GetEnv("OIDC_CLIENT_ID", ""),
+    ClientSecret: configs.GetEnv("OIDC_CLIENT_SECRET", ""),
+    RedirectURL:  configs.GetEnv("OIDC_REDIRECT_URL", "http://localhost:8080/auth/oidc/callback"),
+    Scopes:       strings.Fields(configs.GetEnv("OIDC_SCOPES", "openid email profile")),
+  }, nil)
+}
+
+// Issuer - configured issuer URL
+func (p *provider) Issuer() string {
+  return p.config.Issuer
+}
+
+// AuthCodeURL - authorization endpoint URL for the code flow with S256 PKCE
+func (p *provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+  doc, err := p.discover(ctx)
+  if err != nil {
+    return "", err
+  }
+
+  endpoint, err := url.Parse(doc.AuthorizationEndpoint)
+  if err != nil {
+    return "", fmt.Errorf("%w: invalid authorization endpoint", ErrDiscovery)
+  }
+
+  query := endpoint.Query()
+  query.Set("response_type", "code")
+  query.Set("client_id", p.config.ClientID)
+  query.Set("redirect_uri", p.config.RedirectURL)
+  query.Set("scope", strings.Join(p.config.Scopes, " "))
+  query.Set("state", state)
+  query.Set("nonce", nonce)
+  query.Set("code_challenge", codeChallenge)
+  query.Set("code_challenge_method", "S256")
+  endpoint.RawQuery = query.Encode()
+
+  return endpoint.String(), nil
+}
+
+// Exchange - trade authorization code and PKCE verifier for tokens
+func (p *provider) Exchange(ctx context.Context, code, codeVerifier string) (*Tokens, error) {
+  doc, err := p.discover(ctx)
+  if err != nil {
+    return nil, err
+  }
+
+  form := url.Values{
+    "grant_type":    {"authorization_code"},
+    "code":          {code},
+    "redirect_uri":  {p.config.RedirectURL},
+    "code_verifier": {codeVerifier},
+    "client_id":     {p.config.ClientID},
+  }
+
+  req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+  if err != nil {
+    return nil, err
+  }
+  req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+  req.Header.Set("Accept", "application/json")
+  if p.config.ClientSecret != "" {
+    // client_secret_basic, credentials are form-encoded first (RFC 6749 section 2.3.1)
+    req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+  }
+
+  resp, err := p.client.Do(req)
+  if err != nil {
+    return nil, fmt.Errorf("%w: %v", ErrTokenExchange, err)
+  }
+  defer resp.Body.Close()
+
+  body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+  if err != nil {
+    return nil, fmt.Errorf("%w: %v", ErrTokenExchange, err)
+  }
+
+  if resp.StatusCode != http.StatusOK {
+    var oauthErr struct {
+      Error       string `json:"error"`
+      Description string `json:"error_description"`
+    }
+    json.Unmarshal(body, &oauthErr)
+    return nil, fmt.Errorf("%w: status %d %s %s", ErrTokenExchange, resp.StatusCode, oauthErr.Error, oauthErr.Description)
+  }
+
+  var tokens Tokens
+  if err := json.Unmarshal(body, &tokens); err != nil {
+    return nil, fmt.Errorf("%w: %v", ErrTokenExchange, err)
+  }
+  if tokens.IDToken == "" {
+    return nil, fmt.Errorf("%w: response has no id_token", ErrTokenExchange)
+  }
+
+  return &tokens, nil
+}
+
+// VerifyIDToken - check signature, issuer, audience, expiry and nonce of an ID token
+func (p *provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+  keys, err := p.keyCache(ctx)
+  if err != nil {
+    return nil, err
+  }
+
+  parser := jwt.NewParser(
+    jwt.WithValidMethods(signingMethods),
+    jwt.WithIssuer(p.config.Issuer),
+    jwt.WithAudience(p.config.ClientID),
+    jwt.WithExpirationRequired(),
+    jwt.WithIssuedAt(),
+    jwt.WithLeeway(clockSkew),
+  )
+
+  claims := &Claims{}
+  _, err = parser.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+    kid, _ := token.Header["kid"].(string)
+    return keys.lookup(ctx, kid)
+  })
+  if err != nil {
+    return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+  }
+
+  // azp must name us when present, and is required once other audiences are listed
+  if claims.AuthorizedParty != "" && claims.AuthorizedParty != p.config.ClientID {
+    return nil, fmt.Errorf("%w: unexpected azp", ErrInvalidIDToken)
+  }
+  if len(claims.Audience) > 1 && claims.AuthorizedParty == "" {
+    return nil, fmt.Errorf("%w: azp required with multiple audiences", ErrInvalidIDToken)
+  }
+
+  if nonce == "" || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+    return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+  }
+  if claims.Subject == "" {
+    return nil, fmt.Errorf("%w: missing sub", ErrInvalidIDToken)
+  }
+
+  return claims, nil
+}
+
+// discover - fetch and cache the discovery document, failures are retried on next call
+func (p *provider) discover(ctx context.Context) (*discoveryDocument, error) {
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  if p.discovery != nil {
+    return p.discovery, nil
+  }
+
+  var doc discoveryDocument
+  if err := p.getJSON(ctx, strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", &doc); err != nil {
+    return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+  }
+
+  // The document must be about the issuer we trust (OpenID Connect Discovery section 4.3)
+  if doc.Issuer != p.config.Issuer {
+    return nil, fmt.Errorf("%w: issuer mismatch %q", ErrDiscovery, doc.Issuer)
+  }
+  if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+    return nil, fmt.Errorf("%w: missing endpoints", ErrDiscovery)
+  }
+
+  p.discovery = &doc
+  return p.discovery, nil
+}
+
+// keyCache - JWKS cache of the provider, created after discovery
+func (p *provider) keyCache(ctx context.Context) (*keyCache, error) {
+  doc, err := p.discover(ctx)
+  if err != nil {
+    return nil, err
+  }
+
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  if p.keys == nil {
+    p.keys = &keyCache{uri: doc.JWKSURI, fetch: p.getJSON}
+  }
+  return p.keys, nil
+}
+
+// getJSON - GET url and decode JSON body into v
+func (p *provider) getJSON(ctx context.Context, url string, v interface{}) error {
+  req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+  if err != nil {
+    return err
+  }
+  req.Header.Set("Accept", "application/json")
+
+  resp, err := p.client.Do(req)
+  if err != nil {
+    return err
+  }
+  defer resp.Body.Close()
+
+  if resp.StatusCode != http.StatusOK {
+    return fmt.Errorf("GET %s: status %d", url, resp.StatusCode)
+  }
+
+  return json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v)
+}
+
+// CodeChallenge - S256 PKCE challenge of a code verifier (RFC 7636)
+func CodeChallenge(verifier string) string {
+  sum := sha256.Sum256([]byte(verifier))
+  return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,143 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/oidc/oidctest"
+)
+
+// newTestProvider - provider configured for the mock IdP
+func newTestProvider(idp *oidctest.IdP) Provider {
+  return NewProvider(Config{
+    Issuer:       idp.Issuer(),
+    ClientID:     idp.ClientID,
+    ClientSecret: idp.ClientSecret,
+    RedirectURL:  "http://localhost:8080/auth/oidc/callback",
+  }, nil)
+}
+
+// validClaims - claims the provider accepts, tests change single fields
+func validClaims(idp *oidctest.IdP, nonce string) map[string]interface{} {
+  now := time.Now()
+  return map[string]interface{}{
+    "iss":   idp.Issuer(),
+    "sub":   "user-1",
+    "aud":   idp.ClientID,
+    "iat":   now.Unix(),
+    "exp":   now.Add(5 * time.Minute).Unix(),
+    "nonce": nonce,
+  }
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+  idp := oidctest.NewIdP("task-api", "client-secret")
+  defer idp.Close()
+  client := newTestProvider(idp)
+  ctx := context.Background()
+
+  t.Run("should complete code flow with PKCE", func(t *testing.T) {
+    verifier := "verifier-0123456789-0123456789-0123456789"
+
+    authURL, err := client.AuthCodeURL(ctx, "state-1", "nonce-1", CodeChallenge(verifier))
+    assert.NoError(t, err)
+
+    parsed, _ := url.Parse(authURL)
+    assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+    assert.Equal(t, "openid email profile", parsed.Query().Get("scope"))
+
+    code, state, err := idp.Authorize(authURL)
+    assert.NoError(t, err)
+    assert.Equal(t, "state-1", state)
+
+    tokens, err := client.Exchange(ctx, code, verifier)
+    assert.NoError(t, err)
+
+    claims, err := client.VerifyIDToken(ctx, tokens.IDToken, "nonce-1")
+    assert.NoError(t, err)
+    assert.Equal(t, "user-1", claims.Subject)
+    assert.Equal(t, "user@example.com", claims.Email)
+    assert.True(t, claims.EmailVerified)
+  })
+
+  t.Run("should reject wrong code verifier", func(t *testing.T) {
+    authURL, _ := client.AuthCodeURL(ctx, "state-1", "nonce-1", CodeChallenge("right-verifier"))
+    code, _, err := idp.Authorize(authURL)
+    assert.NoError(t, err)
+
+    _, err = client.Exchange(ctx, code, "wrong-verifier")
+    assert.ErrorIs(t, err, ErrTokenExchange)
+  })
+}
+
+func TestProvider_VerifyIDToken(t *testing.T) {
+  idp := oidctest.NewIdP("task-api", "")
+  defer idp.Close()
+  client := newTestProvider(idp)
+  ctx := context.Background()
+
+  t.Run("should reject invalid claims", func(t *testing.T) {
+    cases := map[string]func(claims map[string]interface{}){
+      "wrong nonce":    func(c map[string]interface{}) { c["nonce"] = "other" },
+      "wrong audience": func(c map[string]interface{}) { c["aud"] = "other-client" },
+      "wrong issuer":   func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" },
+      "expired":        func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+      "missing sub":    func(c map[string]interface{}) { delete(c, "sub") },
+      "foreign azp":    func(c map[string]interface{}) { c["azp"] = "other-client" },
+      "multi aud":      func(c map[string]interface{}) { c["aud"] = []string{idp.ClientID, "other-client"} },
+    }
+
+    for name, modify := range cases {
+      claims := validClaims(idp, "nonce-1")
+      modify(claims)
+
+      _, err := client.VerifyIDToken(ctx, idp.SignIDToken(claims), "nonce-1")
+      assert.ErrorIs(t, err, ErrInvalidIDToken, name)
+    }
+  })
+
+  t.Run("should reject unsigned and HMAC tokens", func(t *testing.T) {
+    claims := jwt.MapClaims(validClaims(idp, "nonce-1"))
+
+    none, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+    _, err := client.VerifyIDToken(ctx, none, "nonce-1")
+    assert.ErrorIs(t, err, ErrInvalidIDToken)
+
+    hmac, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(idp.ClientSecret))
+    _, err = client.VerifyIDToken(ctx, hmac, "nonce-1")
+    assert.ErrorIs(t, err, ErrInvalidIDToken)
+  })
+
+  t.Run("should pick up rotated keys", func(t *testing.T) {
+    _, err := client.VerifyIDToken(ctx, idp.SignIDToken(validClaims(idp, "nonce-1")), "nonce-1")
+    assert.NoError(t, err)
+
+    idp.RotateKey()
+    client.(*provider).keys.fetchedAt = time.Time{} // Skip refresh rate limit
+
+    _, err = client.VerifyIDToken(ctx, idp.SignIDToken(validClaims(idp, "nonce-1")), "nonce-1")
+    assert.NoError(t, err)
+  })
+}
+
+func TestProvider_Discovery(t *testing.T) {
+  t.Run("should refuse document of another issuer", func(t *testing.T) {
+    idp := oidctest.NewIdP("task-api", "")
+    defer idp.Close()
+
+    client := NewProvider(Config{Issuer: idp.Issuer() + "/", ClientID: "task-api"}, nil)
+
+    _, err := client.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+    assert.ErrorIs(t, err, ErrDiscovery)
+  })
+}
+
+func TestCodeChallenge(t *testing.T) {
+  // RFC 7636 appendix B
+  assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
@@ -0,0 +1,322 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRule - RRULE could not be parsed or uses unsupported parts
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// Frequencies supported in FREQ
+const (
+  Daily   = "DAILY"
+  Weekly  = "WEEKLY"
+  Monthly = "MONTHLY"
+  Yearly  = "YEARLY"
+)
+
+// maxPeriods - periods searched for the next occurrence before giving up
+const maxPeriods = 1000
+
+// weekdays - BYDAY / WKST codes
+var weekdays = map[string]time.Weekday{
+  "MO": time.Monday,
+  "TU": time.Tuesday,
+  "WE": time.Wednesday,
+  "TH": time.Thursday,
+  "FR": time.Friday,
+  "SA": time.Saturday,
+  "SU": time.Sunday,
+}
+
+// Rule - parsed iCalendar RRULE (RFC 5545), limited to FREQ, INTERVAL, COUNT,
+// UNTIL, BYDAY (DAILY and WEEKLY), BYMONTHDAY (MONTHLY) and WKST
+type Rule struct {
+  Freq       string
+  Interval   int            // every Interval periods, at least 1
+  Count      int            // occurrences in total, 0 for no limit; tracked by callers
+  Until      *time.Time     // last allowed occurrence, inclusive
+  ByDay      []time.Weekday // days of the week, empty for the weekday of the start
+  ByMonthDay []int          // days of the month, negative count from the end
+  WeekStart  time.Weekday   // first day of the week for weekly intervals
+
+  untilDate bool // UNTIL was a date, compared in the expansion timezone
+}
+
+// Parse - parse an RRULE value, with or without the "RRULE:" prefix
+func Parse(value string) (*Rule, error) {
+  value = strings.TrimSpace(value)
+  if len(value) >= 6 && strings.EqualFold(value[:6], "RRULE:") {
+    value = value[6:]
+  }
+  if value == "" {
+    return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+  }
+
+  rule := &Rule{Interval: 1, WeekStart: time.Monday}
+  seen := map[string]bool{}
+
+  for _, part := range strings.Split(value, ";") {
+    key, val, ok := strings.Cut(part, "=")
+    key = strings.ToUpper(strings.TrimSpace(key))
+    val = strings.ToUpper(strings.TrimSpace(val))
+    if !ok || key == "" || val == "" {
+      return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+    }
+    if seen[key] {
+      return nil, fmt.Errorf("%w: %s given twice", ErrInvalidRule, key)
+    }
+    seen[key] = true
+
+    var err error
+    switch key {
+    case "FREQ":
+      if val != Daily && val != Weekly && val != Monthly && val != Yearly {
+        return nil, fmt.Errorf("%w: unsupported FREQ %s", ErrInvalidRule, val)
+      }
+      rule.Freq = val
+    case "INTERVAL":
+      rule.Interval, err = positive(key, val)
+    case "COUNT":
+      rule.Count, err = positive(key, val)
+    case "UNTIL":
+      err = rule.parseUntil(val)
+    case "BYDAY":
+      rule.ByDay, err = parseWeekdays(val)
+    case "BYMONTHDAY":
+      rule.ByMonthDay, err = parseMonthDays(val)
+    case "WKST":
+      day, known := weekdays[val]
+      if !known {
+        err = fmt.Errorf("%w: unknown WKST %s", ErrInvalidRule, val)
+      }
+      rule.WeekStart = day
+    default:
+      err = fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+    }
+    if err != nil {
+      return nil, err
+    }
+  }
+
+  switch {
+  case rule.Freq == "":
+    return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+  case rule.Count > 0 && rule.Until != nil:
+    return nil, fmt.Errorf("%w: COUNT and UNTIL cannot be combined", ErrInvalidRule)
+  case len(rule.ByDay) > 0 && rule.Freq != Daily && rule.Freq != Weekly:
+    return nil, fmt.Errorf("%w: BYDAY needs FREQ=DAILY or WEEKLY", ErrInvalidRule)
+  case len(rule.ByMonthDay) > 0 && rule.Freq != Monthly:
+    return nil, fmt.Errorf("%w: BYMONTHDAY needs FREQ=MONTHLY", ErrInvalidRule)
+  }
+
+  return rule, nil
+}
+
+// Next - first occurrence strictly after `after` of the series starting at start (DTSTART).
+// Occurrences are expanded in loc and keep the local time of day of start across DST changes.
+// Returns false when UNTIL has passed; COUNT is left to callers, who know the occurrence number.
+func (r *Rule) Next(start, after time.Time, loc *time.Location) (time.Time, bool) {
+  start, after = start.In(loc), after.In(loc)
+  if after.Before(start) {
+    after = start.Add(-time.Nanosecond)
+  }
+
+  hour, minute, sec := start.Clock()
+  at := func(day time.Time) time.Time {
+    return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, sec, 0, loc)
+  }
+
+  // Skip periods that end before `after`, one period of slack for partial weeks and months
+  var first int
+  switch r.Freq {
+  case Daily:
+    first = daysBetween(start, after) / r.Interval
+  case Weekly:
+    first = daysBetween(r.weekOf(start), after) / 7 / r.Interval
+  case Monthly:
+    first = ((after.Year()-start.Year())*12 + int(after.Month()-start.Month())) / r.Interval
+  case Yearly:
+    first = (after.Year() - start.Year()) / r.Interval
+  }
+  first = max(first-1, 0)
+
+  for period := first; period < first+maxPeriods; period++ {
+    for _, day := range r.days(start, period*r.Interval) {
+      occurrence := at(day)
+      if occurrence.Before(start) || !occurrence.After(after) {
+        continue
+      }
+      if r.ended(occurrence, loc) {
+        return time.Time{}, false
+      }
+      return occurrence, true
+    }
+  }
+
+  return time.Time{}, false
+}
+
+// days - candidate dates of the period `offset` periods after the start, in order
+func (r *Rule) days(start time.Time, offset int) []time.Time {
+  base := civil(start)
+
+  switch r.Freq {
+  case Daily:
+    day := base.AddDate(0, 0, offset)
+    if len(r.ByDay) > 0 && !containsWeekday(r.ByDay, day.Weekday()) {
+      return nil
+    }
+    return []time.Time{day}
+
+  case Weekly:
+    week := r.weekOf(start).AddDate(0, 0, 7*offset)
+    byDay := r.ByDay
+    if len(byDay) == 0 {
+      byDay = []time.Weekday{start.Weekday()}
+    }
+    days := make([]time.Time, 0, len(byDay))
+    for _, weekday := range byDay {
+      days = append(days, week.AddDate(0, 0, (int(weekday)-int(r.WeekStart)+7)%7))
+    }
+    sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+    return days
+
+  case Monthly:
+    month := time.Date(base.Year(), base.Month()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+    length := month.AddDate(0, 1, -1).Day()
+    byMonthDay := r.ByMonthDay
+    if len(byMonthDay) == 0 {
+      byMonthDay = []int{start.Day()}
+    }
+    numbers := []int{}
+    for _, n := range byMonthDay {
+      if n < 0 {
+        n = length + 1 + n
+      }
+      // Days the month does not have are skipped, as in RFC 5545
+      if n >= 1 && n <= length && !containsInt(numbers, n) {
+        numbers = append(numbers, n)
+      }
+    }
+    sort.Ints(numbers)
+    days := make([]time.Time, len(numbers))
+    for i, n := range numbers {
+      days[i] = month.AddDate(0, 0, n-1)
+    }
+    return days
+
+  case Yearly:
+    day := time.Date(base.Year()+offset, base.Month(), base.Day(), 0, 0, 0, 0, time.UTC)
+    if day.Day() != base.Day() {
+      return nil // February 29 outside leap years
+    }
+    return []time.Time{day}
+  }
+
+  return nil
+}
+
+// ended - whether occurrence is past UNTIL
+func (r *Rule) ended(occurrence time.Time, loc *time.Location) bool {
+  if r.Until == nil {
+    return false
+  }
+  if r.untilDate {
+    return civil(occurrence.In(loc)).After(*r.Until)
+  }
+  return occurrence.After(*r.Until)
+}
+
+// weekOf - first day of the week containing t, as a civil date
+func (r *Rule) weekOf(t time.Time) time.Time {
+  return civil(t).AddDate(0, 0, -((int(t.Weekday()) - int(r.WeekStart) + 7) % 7))
+}
+
+// parseUntil - UNTIL as UTC date-time, floating date-time or date
+func (r *Rule) parseUntil(value string) error {
+  for _, layout := range []string{"20060102T150405Z", "20060102T150405"} {
+    if until, err := time.Parse(layout, value); err == nil {
+      r.Until = &until
+      return nil
+    }
+  }
+
+  until, err := time.Parse("20060102", value)
+  if err != nil {
+    return fmt.Errorf("%w: malformed UNTIL %s", ErrInvalidRule, value)
+  }
+  r.Until = &until
+  r.untilDate = true
+  return nil
+}
+
+// positive - parse a positive integer part
+func positive(key, value string) (int, error) {
+  n, err := strconv.Atoi(value)
+  if err != nil || n < 1 {
+    return 0, fmt.Errorf("%w: %s must be a positive number", ErrInvalidRule, key)
+  }
+  return n, nil
+}
+
+// parseWeekdays - BYDAY list, ordinal prefixes like 1MO are not supported
+func parseWeekdays(value string) ([]time.Weekday, error) {
+  days := []time.Weekday{}
+  for _, code := range strings.Split(value, ",") {
+    day, ok := weekdays[code]
+    if !ok {
+      return nil, fmt.Errorf("%w: unsupported BYDAY %s", ErrInvalidRule, code)
+    }
+    if !containsWeekday(days, day) {
+      days = append(days, day)
+    }
+  }
+  return days, nil
+}
+
+// parseMonthDays - BYMONTHDAY list, 1 to 31 or -31 to -1
+func parseMonthDays(value string) ([]int, error) {
+  days := []int{}
+  for _, part := range strings.Split(value, ",") {
+    n, err := strconv.Atoi(part)
+    if err != nil || n == 0 || n < -31 || n > 31 {
+      return nil, fmt.Errorf("%w: unsupported BYMONTHDAY %s", ErrInvalidRule, part)
+    }
+    days = append(days, n)
+  }
+  return days, nil
+}
+
+// civil - calendar date of t, as midnight UTC so date arithmetic ignores DST
+func civil(t time.Time) time.Time {
+  return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween - calendar days from a to b
+func daysBetween(a, b time.Time) int {
+  return int(civil(b).Sub(civil(a)).Hours() / 24)
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+  for _, d := range days {
+    if d == day {
+      return true
+    }
+  }
+  return false
+}
+
+func containsInt(numbers []int, n int) bool {
+  for _, m := range numbers {
+    if m == n {
+      return true
+    }
+  }
+  return false
+}
@@ -0,0 +1,150 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+  t.Run("should parse supported parts", func(t *testing.T) {
+    rule, err := Parse("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10")
+
+    assert.NoError(t, err)
+    assert.Equal(t, Weekly, rule.Freq)
+    assert.Equal(t, 2, rule.Interval)
+    assert.Equal(t, 10, rule.Count)
+    assert.Equal(t, []time.Weekday{time.Monday, time.Friday}, rule.ByDay)
+  })
+
+  t.Run("should reject invalid rules", func(t *testing.T) {
+    for _, value := range []string{
+      "",
+      "INTERVAL=2",
+      "FREQ=HOURLY",
+      "FREQ=DAILY;INTERVAL=0",
+      "FREQ=DAILY;COUNT=3;UNTIL=20250101",
+      "FREQ=MONTHLY;BYDAY=1MO",
+      "FREQ=WEEKLY;BYMONTHDAY=1",
+      "FREQ=DAILY;BYSETPOS=1",
+      "FREQ=DAILY;FREQ=WEEKLY",
+      "FREQ=DAILY;UNTIL=tomorrow",
+    } {
+      _, err := Parse(value)
+      assert.ErrorIs(t, err, ErrInvalidRule, value)
+    }
+  })
+}
+
+func TestRule_Next(t *testing.T) {
+  utc := time.UTC
+  jakarta, _ := time.LoadLocation("Asia/Jakarta")
+  newYork, _ := time.LoadLocation("America/New_York")
+
+  tests := []struct {
+    name  string
+    rule  string
+    start time.Time
+    after time.Time
+    loc   *time.Location
+    want  time.Time
+  }{
+    {
+      name:  "daily with interval",
+      rule:  "FREQ=DAILY;INTERVAL=3",
+      start: time.Date(2025, 1, 1, 8, 0, 0, 0, utc),
+      after: time.Date(2025, 1, 4, 8, 0, 0, 0, utc),
+      loc:   utc,
+      want:  time.Date(2025, 1, 7, 8, 0, 0, 0, utc),
+    },
+    {
+      name:  "weekdays only",
+      rule:  "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+      start: time.Date(2025, 1, 3, 7, 0, 0, 0, jakarta), // Friday
+      after: time.Date(2025, 1, 3, 7, 0, 0, 0, jakarta),
+      loc:   jakarta,
+      want:  time.Date(2025, 1, 6, 7, 0, 0, 0, jakarta),
+    },
+    {
+      name:  "every other week on two days",
+      rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+      start: time.Date(2025, 1, 6, 9, 0, 0, 0, utc), // Monday
+      after: time.Date(2025, 1, 9, 9, 0, 0, 0, utc),
+      loc:   utc,
+      want:  time.Date(2025, 1, 20, 9, 0, 0, 0, utc),
+    },
+    {
+      name:  "monthly skips months without the day",
+      rule:  "FREQ=MONTHLY",
+      start: time.Date(2025, 1, 31, 10, 0, 0, 0, utc),
+      after: time.Date(2025, 1, 31, 10, 0, 0, 0, utc),
+      loc:   utc,
+      want:  time.Date(2025, 3, 31, 10, 0, 0, 0, utc),
+    },
+    {
+      name:  "last day of the month",
+      rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+      start: time.Date(2025, 1, 31, 10, 0, 0, 0, utc),
+      after: time.Date(2025, 1, 31, 10, 0, 0, 0, utc),
+      loc:   utc,
+      want:  time.Date(2025, 2, 28, 10, 0, 0, 0, utc),
+    },
+    {
+      name:  "yearly on leap day",
+      rule:  "FREQ=YEARLY",
+      start: time.Date(2024, 2, 29, 12, 0, 0, 0, utc),
+      after: time.Date(2024, 2, 29, 12, 0, 0, 0, utc),
+      loc:   utc,
+      want:  time.Date(2028, 2, 29, 12, 0, 0, 0, utc),
+    },
+    {
+      name:  "local time kept across daylight saving",
+      rule:  "FREQ=DAILY",
+      start: time.Date(2025, 3, 8, 9, 0, 0, 0, newYork),
+      after: time.Date(2025, 3, 8, 9, 0, 0, 0, newYork),
+      loc:   newYork,
+      want:  time.Date(2025, 3, 9, 9, 0, 0, 0, newYork),
+    },
+    {
+      name:  "expanded in the given timezone",
+      rule:  "FREQ=WEEKLY;BYDAY=MO",
+      start: time.Date(2025, 1, 5, 17, 30, 0, 0, utc), // Monday 00:30 in Jakarta
+      after: time.Date(2025, 1, 5, 17, 30, 0, 0, utc),
+      loc:   jakarta,
+      want:  time.Date(2025, 1, 13, 0, 30, 0, 0, jakarta),
+    },
+    {
+      name:  "first occurrence after a long pause",
+      rule:  "FREQ=WEEKLY;BYDAY=WE",
+      start: time.Date(2020, 1, 1, 9, 0, 0, 0, utc),
+      after: time.Date(2025, 6, 1, 0, 0, 0, 0, utc),
+      loc:   utc,
+      want:  time.Date(2025, 6, 4, 9, 0, 0, 0, utc),
+    },
+  }
+
+  for _, tt := range tests {
+    t.Run(tt.name, func(t *testing.T) {
+      rule, err := Parse(tt.rule)
+      assert.NoError(t, err)
+
+      next, ok := rule.Next(tt.start, tt.after, tt.loc)
+
+      assert.True(t, ok)
+      assert.True(t, tt.want.Equal(next), "want %s, got %s", tt.want, next)
+    })
+  }
+
+  t.Run("should stop after UNTIL", func(t *testing.T) {
+    rule, _ := Parse("FREQ=DAILY;UNTIL=20250102")
+    start := time.Date(2025, 1, 1, 23, 0, 0, 0, jakarta)
+
+    next, ok := rule.Next(start, start, jakarta)
+    assert.True(t, ok)
+    assert.Equal(t, 2, next.Day())
+
+    _, ok = rule.Next(start, next, jakarta)
+    assert.False(t, ok)
+  })
+}
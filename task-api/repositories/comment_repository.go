@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"task-api/models"
+)
+
+var ErrCommentNotFound = errors.New("comment not found")
+
+// CommentRepository - interface for task comments, callers authorize access to the task
+type CommentRepository interface {
+  Create(ctx context.Context, comment *models.Comment) error
+  FindByID(ctx context.Context, taskID, id bson.ObjectID) (*models.Comment, error)
+  FindByTask(ctx context.Context, taskID bson.ObjectID, after *bson.ObjectID, limit int) ([]models.Comment, error)
+  Update(ctx context.Context, taskID, id bson.ObjectID, body string, mentions []bson.ObjectID) (*models.Comment, error)
+  Delete(ctx context.Context, taskID, id bson.ObjectID) error
+  DeleteByTasks(ctx context.Context, taskIDs []bson.ObjectID) error
+}
+
+// commentRepository - implement CommentRepository
+type commentRepository struct {
+  collection *mongo.Collection
+}
+
+// NewCommentRepository - constructor
+func NewCommentRepository(db *mongo.Database) CommentRepository {
+  return &commentRepository{
+    collection: db.Collection("comments"),
+  }
+}
+
+// Create - insert comment
+func (r *commentRepository) Create(ctx context.Context, comment *models.Comment) error {
+  now := time.Now()
+  if comment.ID.IsZero() {
+    comment.ID = bson.NewObjectID()
+  }
+  comment.CreatedAt = now
+  comment.UpdatedAt = now
+
+  _, err := r.collection.InsertOne(ctx, comment)
+  return err
+}
+
+// FindByID - comment of task
+func (r *commentRepository) FindByID(ctx context.Context, taskID, id bson.ObjectID) (*models.Comment, error) {
+  var comment models.Comment
+
+  err := r.collection.FindOne(ctx, bson.M{"_id": id, "task_id": taskID}).Decode(&comment)
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrCommentNotFound
+    }
+    return nil, err
+  }
+
+  return &comment, nil
+}
+
+// FindByTask - comments of task oldest first, starting after the given comment ID (the cursor)
+func (r *commentRepository) FindByTask(ctx context.Context, taskID bson.ObjectID, after *bson.ObjectID, limit int) ([]models.Comment, error) {
+  filter := bson.M{"task_id": taskID}
+  if after != nil {
+    filter["_id"] = bson.M{"$gt": *after}
+  }
+
+  // ObjectIDs grow with creation time, so _id gives a stable order for the cursor
+  opts := options.Find().
+    SetSort(bson.D{{Key: "_id", Value: 1}}).
+    SetLimit(int64(limit))
+
+  cursor, err := r.collection.Find(ctx, filter, opts)
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  comments := []models.Comment{}
+  if err = cursor.All(ctx, &comments); err != nil {
+    return nil, err
+  }
+
+  return comments, nil
+}
+
+// Update - replace body and mentions, returns the updated comment
+func (r *commentRepository) Update(ctx context.Context, taskID, id bson.ObjectID, body string, mentions []bson.ObjectID) (*models.Comment, error) {
+  update := bson.M{
+    "$set": bson.M{
+      "body":       body,
+      "mentions":   mentions,
+      "updated_at": time.Now(),
+    },
+  }
+  opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+  var comment models.Comment
+  err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "task_id": taskID}, update, opts).Decode(&comment)
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrCommentNotFound
+    }
+    return nil, err
+  }
+
+  return &comment, nil
+}
+
+// Delete - remove comment of task
+func (r *commentRepository) Delete(ctx context.Context, taskID, id bson.ObjectID) error {
+  result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "task_id": taskID})
+  if err != nil {
+    return err
+  }
+
+  if result.DeletedCount == 0 {
+    return ErrCommentNotFound
+  }
+
+  return nil
+}
+
+// DeleteByTasks - remove every comment of the tasks (purged tasks)
+func (r *commentRepository) DeleteByTasks(ctx context.Context, taskIDs []bson.ObjectID) error {
+  _, err := r.collection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}})
+  return err
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+)
+
+func TestCommentRepository(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should page, edit and delete comments", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewCommentRepository(db)
+    ctx := context.Background()
+
+    taskID, authorID := bson.NewObjectID(), bson.NewObjectID()
+    comments := make([]*models.Comment, 3)
+    for i := range comments {
+      comments[i] = &models.Comment{TaskID: taskID, AuthorID: authorID, Body: "Comment"}
+      assert.NoError(t, repo.Create(ctx, comments[i]))
+    }
+    assert.NoError(t, repo.Create(ctx, &models.Comment{TaskID: bson.NewObjectID(), AuthorID: authorID, Body: "Elsewhere"}))
+
+    page, err := repo.FindByTask(ctx, taskID, nil, 2)
+    assert.NoError(t, err)
+    assert.Len(t, page, 2)
+    assert.Equal(t, comments[0].ID, page[0].ID)
+
+    page, err = repo.FindByTask(ctx, taskID, &page[1].ID, 2)
+    assert.NoError(t, err)
+    assert.Len(t, page, 1)
+    assert.Equal(t, comments[2].ID, page[0].ID)
+
+    mentioned := bson.NewObjectID()
+    updated, err := repo.Update(ctx, taskID, comments[0].ID, "Edited", []bson.ObjectID{mentioned})
+    assert.NoError(t, err)
+    assert.Equal(t, "Edited", updated.Body)
+    assert.Equal(t, []bson.ObjectID{mentioned}, updated.Mentions)
+
+    // Comments are addressed through their task
+    _, err = repo.FindByID(ctx, bson.NewObjectID(), comments[1].ID)
+    assert.ErrorIs(t, err, ErrCommentNotFound)
+
+    assert.NoError(t, repo.Delete(ctx, taskID, comments[1].ID))
+    assert.ErrorIs(t, repo.Delete(ctx, taskID, comments[1].ID), ErrCommentNotFound)
+  })
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"task-api/models"
+)
+
+// LoginAttemptStore - backend for failed login counters used by brute-force protection
+type LoginAttemptStore interface {
+  // Get - current counter for key, nil when there is none or it has expired
+  Get(ctx context.Context, key string) (*models.LoginAttempt, error)
+  // RecordFailure - count a failed attempt, starting over when the previous window has expired
+  RecordFailure(ctx context.Context, key string, window time.Duration) (*models.LoginAttempt, error)
+  // Lock - refuse logins for key until the given time
+  Lock(ctx context.Context, key string, until time.Time) error
+  // Reset - forget failures and lock for key
+  Reset(ctx context.Context, key string) error
+}
+
+// mongoLoginAttemptStore - implement LoginAttemptStore on a TTL collection
+type mongoLoginAttemptStore struct {
+  collection *mongo.Collection
+}
+
+// NewMongoLoginAttemptStore - constructor
+func NewMongoLoginAttemptStore(db *mongo.Database) LoginAttemptStore {
+  return &mongoLoginAttemptStore{
+    collection: db.Collection("login_attempts"),
+  }
+}
+
+// Get - find counter by key, ignoring entries the TTL monitor has not removed yet
+func (s *mongoLoginAttemptStore) Get(ctx context.Context, key string) (*models.LoginAttempt, error) {
+  var attempt models.LoginAttempt
+
+  filter := bson.M{
+    "_id":        key,
+    "expires_at": bson.M{"$gt": time.Now()},
+  }
+
+  err := s.collection.FindOne(ctx, filter).Decode(&attempt)
+  if err != nil {
+    if errors.Is(err, mongo.ErrNoDocuments) {
+      return nil, nil
+    }
+    return nil, err
+  }
+
+  return &attempt, nil
+}
+
+// RecordFailure - atomic increment with an update pipeline so the
+// window reset and the increment happen in one round trip
+func (s *mongoLoginAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (*models.LoginAttempt, error) {
+  now := time.Now()
+
+  update := mongo.Pipeline{
+    {{Key: "$set", Value: bson.M{
+      "failures": bson.M{"$cond": bson.A{
+        bson.M{"$gt": bson.A{"$expires_at", now}},
+        bson.M{"$add": bson.A{"$failures", 1}},
+        1,
+      }},
+      "last_failure_at": now,
+      // Never expire the counter while a lock is still running
+      "expires_at": bson.M{"$max": bson.A{now.Add(window), "$locked_until"}},
+    }}},
+  }
+
+  opts := options.FindOneAndUpdate().
+    SetUpsert(true).
+    SetReturnDocument(options.After)
+
+  var attempt models.LoginAttempt
+  if err := s.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&attempt); err != nil {
+    return nil, err
+  }
+
+  return &attempt, nil
+}
+
+// Lock - set lock and keep the counter alive at least until it ends
+func (s *mongoLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+  filter := bson.M{"_id": key}
+  update := bson.M{
+    "$set": bson.M{"locked_until": until},
+    "$max": bson.M{"expires_at": until},
+  }
+
+  _, err := s.collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+  return err
+}
+
+// Reset - delete counter
+func (s *mongoLoginAttemptStore) Reset(ctx context.Context, key string) error {
+  _, err := s.collection.DeleteOne(ctx, bson.M{"_id": key})
+  return err
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMongoLoginAttemptStore(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should increment, lock and reset counter", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    store := NewMongoLoginAttemptStore(db)
+    ctx := context.Background()
+
+    attempt, err := store.RecordFailure(ctx, "email:test@example.com", time.Minute)
+    assert.NoError(t, err)
+    assert.Equal(t, 1, attempt.Failures)
+
+    attempt, err = store.RecordFailure(ctx, "email:test@example.com", time.Minute)
+    assert.NoError(t, err)
+    assert.Equal(t, 2, attempt.Failures)
+
+    until := time.Now().Add(time.Hour)
+    err = store.Lock(ctx, "email:test@example.com", until)
+    assert.NoError(t, err)
+
+    attempt, err = store.Get(ctx, "email:test@example.com")
+    assert.NoError(t, err)
+    assert.True(t, attempt.IsLocked(time.Now()))
+
+    err = store.Reset(ctx, "email:test@example.com")
+    assert.NoError(t, err)
+
+    attempt, err = store.Get(ctx, "email:test@example.com")
+    assert.NoError(t, err)
+    assert.Nil(t, attempt)
+  })
+
+  t.Run("should start over after window expires", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    store := NewMongoLoginAttemptStore(db)
+    ctx := context.Background()
+
+    store.RecordFailure(ctx, "ip:1.2.3.4", time.Millisecond)
+    time.Sleep(5 * time.Millisecond)
+
+    attempt, err := store.RecordFailure(ctx, "ip:1.2.3.4", time.Minute)
+    assert.NoError(t, err)
+    assert.Equal(t, 1, attempt.Failures)
+  })
+}
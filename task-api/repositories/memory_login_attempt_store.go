@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"task-api/models"
+)
+
+// memoryLoginAttemptStore - in-process LoginAttemptStore for tests and single-instance setups
+type memoryLoginAttemptStore struct {
+  mu       sync.Mutex
+  attempts map[string]models.LoginAttempt
+}
+
+// NewMemoryLoginAttemptStore - constructor
+func NewMemoryLoginAttemptStore() LoginAttemptStore {
+  return &memoryLoginAttemptStore{
+    attempts: make(map[string]models.LoginAttempt),
+  }
+}
+
+// Get - return a copy of the counter, dropping it once expired
+func (s *memoryLoginAttemptStore) Get(ctx context.Context, key string) (*models.LoginAttempt, error) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  attempt, ok := s.current(key, time.Now())
+  if !ok {
+    return nil, nil
+  }
+  return &attempt, nil
+}
+
+// RecordFailure - increment counter, starting over after the window
+func (s *memoryLoginAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (*models.LoginAttempt, error) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  now := time.Now()
+  attempt, ok := s.current(key, now)
+  if !ok {
+    attempt = models.LoginAttempt{ID: key}
+  }
+
+  attempt.Failures++
+  attempt.LastFailureAt = now
+  attempt.ExpiresAt = now.Add(window)
+  if attempt.LockedUntil != nil && attempt.LockedUntil.After(attempt.ExpiresAt) {
+    attempt.ExpiresAt = *attempt.LockedUntil
+  }
+
+  s.attempts[key] = attempt
+  return &attempt, nil
+}
+
+// Lock - set lock and keep the counter alive at least until it ends
+func (s *memoryLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  attempt, ok := s.current(key, time.Now())
+  if !ok {
+    attempt = models.LoginAttempt{ID: key}
+  }
+
+  attempt.LockedUntil = &until
+  if until.After(attempt.ExpiresAt) {
+    attempt.ExpiresAt = until
+  }
+
+  s.attempts[key] = attempt
+  return nil
+}
+
+// Reset - delete counter
+func (s *memoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  delete(s.attempts, key)
+  return nil
+}
+
+// current - live counter for key, caller must hold mu
+func (s *memoryLoginAttemptStore) current(key string, now time.Time) (models.LoginAttempt, bool) {
+  attempt, ok := s.attempts[key]
+  if !ok {
+    return models.LoginAttempt{}, false
+  }
+  if !now.Before(attempt.ExpiresAt) {
+    delete(s.attempts, key)
+    return models.LoginAttempt{}, false
+  }
+  return attempt, true
+}
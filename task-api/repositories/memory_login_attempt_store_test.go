@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLoginAttemptStore(t *testing.T) {
+  ctx := context.Background()
+
+  t.Run("should count failures within window", func(t *testing.T) {
+    store := NewMemoryLoginAttemptStore()
+
+    for i := 1; i <= 3; i++ {
+      attempt, err := store.RecordFailure(ctx, "email:test@example.com", time.Minute)
+      assert.NoError(t, err)
+      assert.Equal(t, i, attempt.Failures)
+    }
+
+    attempt, err := store.Get(ctx, "email:test@example.com")
+    assert.NoError(t, err)
+    assert.Equal(t, 3, attempt.Failures)
+
+    attempt, _ = store.Get(ctx, "email:other@example.com")
+    assert.Nil(t, attempt)
+  })
+
+  t.Run("should start over after window expires", func(t *testing.T) {
+    store := NewMemoryLoginAttemptStore()
+
+    store.RecordFailure(ctx, "ip:1.2.3.4", time.Millisecond)
+    time.Sleep(5 * time.Millisecond)
+
+    attempt, _ := store.Get(ctx, "ip:1.2.3.4")
+    assert.Nil(t, attempt)
+
+    attempt, _ = store.RecordFailure(ctx, "ip:1.2.3.4", time.Minute)
+    assert.Equal(t, 1, attempt.Failures)
+  })
+
+  t.Run("should lock and reset", func(t *testing.T) {
+    store := NewMemoryLoginAttemptStore()
+    until := time.Now().Add(time.Hour)
+
+    err := store.Lock(ctx, "email:test@example.com", until)
+    assert.NoError(t, err)
+
+    attempt, _ := store.Get(ctx, "email:test@example.com")
+    assert.True(t, attempt.IsLocked(time.Now()))
+    assert.False(t, attempt.IsLocked(until.Add(time.Second)))
+
+    // Counter outlives the window while locked
+    attempt, _ = store.RecordFailure(ctx, "email:test@example.com", time.Millisecond)
+    assert.Equal(t, until, attempt.ExpiresAt)
+
+    err = store.Reset(ctx, "email:test@example.com")
+    assert.NoError(t, err)
+
+    attempt, _ = store.Get(ctx, "email:test@example.com")
+    assert.Nil(t, attempt)
+  })
+}
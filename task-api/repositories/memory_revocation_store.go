@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// memoryRevocationStore - in-process RevocationStore for tests and single-instance setups
+type memoryRevocationStore struct {
+  mu      sync.Mutex
+  entries map[string]revocationEntry
+}
+
+// NewMemoryRevocationStore - constructor
+func NewMemoryRevocationStore() RevocationStore {
+  return &memoryRevocationStore{
+    entries: make(map[string]revocationEntry),
+  }
+}
+
+// RevokeToken - revoke single token
+func (s *memoryRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  s.entries[tokenID] = revocationEntry{ID: tokenID, ExpiresAt: expiresAt}
+  return nil
+}
+
+// RevokeAllForUser - store user-wide cutoff, never moves an existing cutoff backwards
+func (s *memoryRevocationStore) RevokeAllForUser(ctx context.Context, userID bson.ObjectID, issuedBefore time.Time, expiresAt time.Time) error {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  key := userRevocationKey(userID)
+  if existing, ok := s.entries[key]; ok {
+    if existing.RevokedBefore != nil && existing.RevokedBefore.After(issuedBefore) {
+      issuedBefore = *existing.RevokedBefore
+    }
+    if existing.ExpiresAt.After(expiresAt) {
+      expiresAt = existing.ExpiresAt
+    }
+  }
+
+  s.entries[key] = revocationEntry{ID: key, RevokedBefore: &issuedBefore, ExpiresAt: expiresAt}
+  return nil
+}
+
+// IsRevoked - check jti entry and user cutoff, dropping expired entries on the way
+func (s *memoryRevocationStore) IsRevoked(ctx context.Context, tokenID string, userID bson.ObjectID, issuedAt time.Time) (bool, error) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  now := time.Now()
+  for _, key := range []string{tokenID, userRevocationKey(userID)} {
+    entry, ok := s.entries[key]
+    if !ok || key == "" {
+      continue
+    }
+    if now.After(entry.ExpiresAt) {
+      delete(s.entries, key)
+      continue
+    }
+    if isRevokedBy(entry, tokenID, issuedAt) {
+      return true, nil
+    }
+  }
+
+  return false, nil
+}
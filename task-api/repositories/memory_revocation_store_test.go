@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMemoryRevocationStore(t *testing.T) {
+  ctx := context.Background()
+
+  t.Run("should revoke single token by jti", func(t *testing.T) {
+    store := NewMemoryRevocationStore()
+    userID := bson.NewObjectID()
+    issuedAt := time.Now().Add(-time.Minute)
+
+    err := store.RevokeToken(ctx, "jti-1", time.Now().Add(time.Hour))
+    assert.NoError(t, err)
+
+    revoked, err := store.IsRevoked(ctx, "jti-1", userID, issuedAt)
+    assert.NoError(t, err)
+    assert.True(t, revoked)
+
+    revoked, _ = store.IsRevoked(ctx, "jti-2", userID, issuedAt)
+    assert.False(t, revoked)
+  })
+
+  t.Run("should revoke tokens issued before user cutoff", func(t *testing.T) {
+    store := NewMemoryRevocationStore()
+    userID := bson.NewObjectID()
+    now := time.Now()
+
+    err := store.RevokeAllForUser(ctx, userID, now, now.Add(time.Hour))
+    assert.NoError(t, err)
+
+    revoked, _ := store.IsRevoked(ctx, "old", userID, now.Add(-2*time.Second))
+    assert.True(t, revoked)
+
+    revoked, _ = store.IsRevoked(ctx, "new", userID, now.Add(2*time.Second))
+    assert.False(t, revoked)
+
+    revoked, _ = store.IsRevoked(ctx, "other-user", bson.NewObjectID(), now.Add(-2*time.Second))
+    assert.False(t, revoked)
+  })
+
+  t.Run("should not move user cutoff backwards", func(t *testing.T) {
+    store := NewMemoryRevocationStore()
+    userID := bson.NewObjectID()
+    now := time.Now()
+
+    store.RevokeAllForUser(ctx, userID, now, now.Add(time.Hour))
+    store.RevokeAllForUser(ctx, userID, now.Add(-time.Hour), now.Add(time.Minute))
+
+    revoked, _ := store.IsRevoked(ctx, "", userID, now.Add(-2*time.Second))
+    assert.True(t, revoked)
+  })
+
+  t.Run("should forget expired entries", func(t *testing.T) {
+    store := NewMemoryRevocationStore()
+    userID := bson.NewObjectID()
+
+    store.RevokeToken(ctx, "jti-1", time.Now().Add(-time.Second))
+
+    revoked, _ := store.IsRevoked(ctx, "jti-1", userID, time.Now().Add(-time.Minute))
+    assert.False(t, revoked)
+  })
+}
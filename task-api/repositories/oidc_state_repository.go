@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"task-api/models"
+)
+
+// ErrOIDCStateInvalid - state unknown, expired or already used
+var ErrOIDCStateInvalid = errors.New("oidc state invalid")
+
+// OIDCStateRepository - interface for pending SSO logins
+type OIDCStateRepository interface {
+  Create(ctx context.Context, state *models.OIDCState) error
+  Consume(ctx context.Context, stateHash string) (*models.OIDCState, error)
+}
+
+// oidcStateRepository - implement OIDCStateRepository
+type oidcStateRepository struct {
+  collection *mongo.Collection
+}
+
+// NewOIDCStateRepository - constructor
+func NewOIDCStateRepository(db *mongo.Database) OIDCStateRepository {
+  return &oidcStateRepository{
+    collection: db.Collection("oidc_states"),
+  }
+}
+
+// Create - store pending login
+func (r *oidcStateRepository) Create(ctx context.Context, state *models.OIDCState) error {
+  _, err := r.collection.InsertOne(ctx, state)
+  return err
+}
+
+// Consume - atomically remove a valid state and return it, so a callback can't be replayed
+func (r *oidcStateRepository) Consume(ctx context.Context, stateHash string) (*models.OIDCState, error) {
+  filter := bson.M{
+    "_id":        stateHash,
+    "expires_at": bson.M{"$gt": time.Now()},
+  }
+
+  var state models.OIDCState
+  err := r.collection.FindOneAndDelete(ctx, filter).Decode(&state)
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrOIDCStateInvalid
+    }
+    return nil, err
+  }
+
+  return &state, nil
+}
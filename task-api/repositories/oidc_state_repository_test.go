@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/models"
+)
+
+func TestOIDCStateRepository_Consume(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should consume state only once", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewOIDCStateRepository(db)
+    ctx := context.Background()
+
+    repo.Create(ctx, &models.OIDCState{ID: "hash-1", Nonce: "nonce", ExpiresAt: time.Now().Add(time.Minute)})
+
+    state, err := repo.Consume(ctx, "hash-1")
+    assert.NoError(t, err)
+    assert.Equal(t, "nonce", state.Nonce)
+
+    _, err = repo.Consume(ctx, "hash-1")
+    assert.ErrorIs(t, err, ErrOIDCStateInvalid)
+  })
+
+  t.Run("should reject expired state", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewOIDCStateRepository(db)
+    ctx := context.Background()
+
+    repo.Create(ctx, &models.OIDCState{ID: "hash-1", ExpiresAt: time.Now().Add(-time.Minute)})
+
+    _, err := repo.Consume(ctx, "hash-1")
+    assert.ErrorIs(t, err, ErrOIDCStateInvalid)
+  })
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"task-api/models"
+)
+
+// ErrResetTokenInvalid - token unknown, expired or already used
+var ErrResetTokenInvalid = errors.New("reset token invalid")
+
+// PasswordResetRepository - interface for password reset repository
+type PasswordResetRepository interface {
+  Create(ctx context.Context, reset *models.PasswordReset) error
+  Consume(ctx context.Context, tokenHash string) (*models.PasswordReset, error)
+  InvalidateForUser(ctx context.Context, userID bson.ObjectID) error
+}
+
+// passwordResetRepository - implement PasswordResetRepository
+type passwordResetRepository struct {
+  collection *mongo.Collection
+}
+
+// NewPasswordResetRepository - constructor
+func NewPasswordResetRepository(db *mongo.Database) PasswordResetRepository {
+  return &passwordResetRepository{
+    collection: db.Collection("password_resets"),
+  }
+}
+
+// Create - store new reset token
+func (r *passwordResetRepository) Create(ctx context.Context, reset *models.PasswordReset) error {
+  _, err := r.collection.InsertOne(ctx, reset)
+  return err
+}
+
+// Consume - atomically mark a valid token as used and return it
+func (r *passwordResetRepository) Consume(ctx context.Context, tokenHash string) (*models.PasswordReset, error) {
+  now := time.Now()
+
+  filter := bson.M{
+    "token_hash": tokenHash,
+    "used_at":    nil,
+    "expires_at": bson.M{"$gt": now},
+  }
+
+  update := bson.M{"$set": bson.M{"used_at": now}}
+
+  var reset models.PasswordReset
+  err := r.collection.FindOneAndUpdate(ctx, filter, update).Decode(&reset)
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrResetTokenInvalid
+    }
+    return nil, err
+  }
+
+  reset.UsedAt = &now
+  return &reset, nil
+}
+
+// InvalidateForUser - mark every unused token of user as used
+func (r *passwordResetRepository) InvalidateForUser(ctx context.Context, userID bson.ObjectID) error {
+  filter := bson.M{
+    "user_id": userID,
+    "used_at": nil,
+  }
+
+  update := bson.M{"$set": bson.M{"used_at": time.Now()}}
+
+  _, err := r.collection.UpdateMany(ctx, filter, update)
+  return err
+}
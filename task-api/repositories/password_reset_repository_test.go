@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+)
+
+func TestPasswordResetRepository_Consume(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  newReset := func(userID bson.ObjectID, hash string, expiresAt time.Time) *models.PasswordReset {
+    return &models.PasswordReset{
+      ID:        bson.NewObjectID(),
+      UserID:    userID,
+      TokenHash: hash,
+      ExpiresAt: expiresAt,
+      CreatedAt: time.Now(),
+    }
+  }
+
+  t.Run("should consume token only once", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewPasswordResetRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    repo.Create(ctx, newReset(userID, "hash-1", time.Now().Add(time.Hour)))
+
+    reset, err := repo.Consume(ctx, "hash-1")
+    assert.NoError(t, err)
+    assert.Equal(t, userID, reset.UserID)
+    assert.NotNil(t, reset.UsedAt)
+
+    _, err = repo.Consume(ctx, "hash-1")
+    assert.ErrorIs(t, err, ErrResetTokenInvalid)
+  })
+
+  t.Run("should reject expired token", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewPasswordResetRepository(db)
+    ctx := context.Background()
+
+    repo.Create(ctx, newReset(bson.NewObjectID(), "hash-1", time.Now().Add(-time.Minute)))
+
+    _, err := repo.Consume(ctx, "hash-1")
+    assert.ErrorIs(t, err, ErrResetTokenInvalid)
+  })
+
+  t.Run("should reject invalidated token", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewPasswordResetRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    repo.Create(ctx, newReset(userID, "hash-1", time.Now().Add(time.Hour)))
+
+    assert.NoError(t, repo.InvalidateForUser(ctx, userID))
+
+    _, err := repo.Consume(ctx, "hash-1")
+    assert.ErrorIs(t, err, ErrResetTokenInvalid)
+  })
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"task-api/models"
+)
+
+// ErrPersonalAccessTokenNotFound - no token matches the query
+var ErrPersonalAccessTokenNotFound = errors.New("personal access token not found")
+
+// PersonalAccessTokenRepository - interface for personal access token repository
+type PersonalAccessTokenRepository interface {
+  Create(ctx context.Context, token *models.PersonalAccessToken) error
+  FindByHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error)
+  FindByUserID(ctx context.Context, userID bson.ObjectID) ([]models.PersonalAccessToken, error)
+  CountByUserID(ctx context.Context, userID bson.ObjectID) (int64, error)
+  Delete(ctx context.Context, id, userID bson.ObjectID) error
+  TouchLastUsed(ctx context.Context, id bson.ObjectID, usedAt time.Time) error
+}
+
+// personalAccessTokenRepository - implement PersonalAccessTokenRepository
+type personalAccessTokenRepository struct {
+  collection *mongo.Collection
+}
+
+// NewPersonalAccessTokenRepository - constructor
+func NewPersonalAccessTokenRepository(db *mongo.Database) PersonalAccessTokenRepository {
+  return &personalAccessTokenRepository{
+    collection: db.Collection("personal_access_tokens"),
+  }
+}
+
+// Create - store new token
+func (r *personalAccessTokenRepository) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+  _, err := r.collection.InsertOne(ctx, token)
+  return err
+}
+
+// FindByHash - find token by its hash
+func (r *personalAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error) {
+  var token models.PersonalAccessToken
+
+  filter := bson.M{"token_hash": tokenHash}
+  err := r.collection.FindOne(ctx, filter).Decode(&token)
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrPersonalAccessTokenNotFound
+    }
+    return nil, err
+  }
+
+  return &token, nil
+}
+
+// FindByUserID - list tokens of user, newest first
+func (r *personalAccessTokenRepository) FindByUserID(ctx context.Context, userID bson.ObjectID) ([]models.PersonalAccessToken, error) {
+  filter := bson.M{"user_id": userID}
+  opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+  cursor, err := r.collection.Find(ctx, filter, opts)
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  tokens := []models.PersonalAccessToken{}
+  if err = cursor.All(ctx, &tokens); err != nil {
+    return nil, err
+  }
+
+  return tokens, nil
+}
+
+// CountByUserID - number of tokens of user
+func (r *personalAccessTokenRepository) CountByUserID(ctx context.Context, userID bson.ObjectID) (int64, error) {
+  return r.collection.CountDocuments(ctx, bson.M{"user_id": userID})
+}
+
+// Delete - revoke token, scoped to its owner
+func (r *personalAccessTokenRepository) Delete(ctx context.Context, id, userID bson.ObjectID) error {
+  filter := bson.M{"_id": id, "user_id": userID}
+
+  result, err := r.collection.DeleteOne(ctx, filter)
+  if err != nil {
+    return err
+  }
+
+  if result.DeletedCount == 0 {
+    return ErrPersonalAccessTokenNotFound
+  }
+
+  return nil
+}
+
+// TouchLastUsed - record token usage
+func (r *personalAccessTokenRepository) TouchLastUsed(ctx context.Context, id bson.ObjectID, usedAt time.Time) error {
+  filter := bson.M{"_id": id}
+  update := bson.M{"$set": bson.M{"last_used_at": usedAt}}
+
+  _, err := r.collection.UpdateOne(ctx, filter, update)
+  return err
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+)
+
+func TestPersonalAccessTokenRepository(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should create, find, list and delete tokens scoped to owner", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewPersonalAccessTokenRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    token := &models.PersonalAccessToken{
+      ID:        bson.NewObjectID(),
+      UserID:    userID,
+      Name:      "CI",
+      TokenHash: "hash-1",
+      Scopes:    []string{"tasks:read"},
+      CreatedAt: time.Now(),
+    }
+    assert.NoError(t, repo.Create(ctx, token))
+
+    found, err := repo.FindByHash(ctx, "hash-1")
+    assert.NoError(t, err)
+    assert.Equal(t, "CI", found.Name)
+
+    list, err := repo.FindByUserID(ctx, userID)
+    assert.NoError(t, err)
+    assert.Len(t, list, 1)
+
+    count, _ := repo.CountByUserID(ctx, userID)
+    assert.Equal(t, int64(1), count)
+
+    // Other users cannot delete it
+    err = repo.Delete(ctx, token.ID, bson.NewObjectID())
+    assert.ErrorIs(t, err, ErrPersonalAccessTokenNotFound)
+
+    assert.NoError(t, repo.Delete(ctx, token.ID, userID))
+
+    _, err = repo.FindByHash(ctx, "hash-1")
+    assert.ErrorIs(t, err, ErrPersonalAccessTokenNotFound)
+  })
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"task-api/models"
+)
+
+// ErrRefreshTokenNotFound - no refresh token matches the given hash
+var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+// RefreshTokenRepository - interface for refresh token repository
+type RefreshTokenRepository interface {
+  Create(ctx context.Context, token *models.RefreshToken) error
+  FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+  MarkUsed(ctx context.Context, id bson.ObjectID) (bool, error)
+  RevokeFamily(ctx context.Context, familyID bson.ObjectID) error
+  RevokeAllForUser(ctx context.Context, userID bson.ObjectID) error
+}
+
+// refreshTokenRepository - implement RefreshTokenRepository
+type refreshTokenRepository struct {
+  collection *mongo.Collection
+}
+
+// NewRefreshTokenRepository - constructor
+func NewRefreshTokenRepository(db *mongo.Database) RefreshTokenRepository {
+  return &refreshTokenRepository{
+    collection: db.Collection("refresh_tokens"),
+  }
+}
+
+// Create - store new refresh token
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+  _, err := r.collection.InsertOne(ctx, token)
+  return err
+}
+
+// FindByHash - find refresh token by its hash
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+  var token models.RefreshToken
+
+  filter := bson.M{"token_hash": tokenHash}
+  err := r.collection.FindOne(ctx, filter).Decode(&token)
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrRefreshTokenNotFound
+    }
+    return nil, err
+  }
+
+  return &token, nil
+}
+
+// MarkUsed - atomically mark token as used, returns false if it was already used or revoked
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, id bson.ObjectID) (bool, error) {
+  filter := bson.M{
+    "_id":        id,
+    "used_at":    nil,
+    "revoked_at": nil,
+  }
+
+  update := bson.M{"$set": bson.M{"used_at": time.Now()}}
+
+  result, err := r.collection.UpdateOne(ctx, filter, update)
+  if err != nil {
+    return false, err
+  }
+
+  return result.ModifiedCount == 1, nil
+}
+
+// RevokeFamily - revoke every token rotated from the same login
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID bson.ObjectID) error {
+  filter := bson.M{
+    "family_id":  familyID,
+    "revoked_at": nil,
+  }
+
+  update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+  _, err := r.collection.UpdateMany(ctx, filter, update)
+  return err
+}
+
+// RevokeAllForUser - revoke every refresh token of user
+func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID bson.ObjectID) error {
+  filter := bson.M{
+    "user_id":    userID,
+    "revoked_at": nil,
+  }
+
+  update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+  _, err := r.collection.UpdateMany(ctx, filter, update)
+  return err
+}
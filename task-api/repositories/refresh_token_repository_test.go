@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+)
+
+func newTestRefreshToken(userID, familyID bson.ObjectID, hash string) *models.RefreshToken {
+  return &models.RefreshToken{
+    ID:        bson.NewObjectID(),
+    UserID:    userID,
+    FamilyID:  familyID,
+    TokenHash: hash,
+    ExpiresAt: time.Now().Add(time.Hour),
+    CreatedAt: time.Now(),
+  }
+}
+
+func TestRefreshTokenRepository_FindByHash(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should find token by hash", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewRefreshTokenRepository(db)
+    ctx := context.Background()
+
+    token := newTestRefreshToken(bson.NewObjectID(), bson.NewObjectID(), "hash-1")
+    assert.NoError(t, repo.Create(ctx, token))
+
+    result, err := repo.FindByHash(ctx, "hash-1")
+
+    assert.NoError(t, err)
+    assert.Equal(t, token.ID, result.ID)
+    assert.Equal(t, token.FamilyID, result.FamilyID)
+  })
+
+  t.Run("should return ErrRefreshTokenNotFound", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewRefreshTokenRepository(db)
+
+    result, err := repo.FindByHash(context.Background(), "missing")
+
+    assert.ErrorIs(t, err, ErrRefreshTokenNotFound)
+    assert.Nil(t, result)
+  })
+}
+
+func TestRefreshTokenRepository_MarkUsed(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should only succeed once", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewRefreshTokenRepository(db)
+    ctx := context.Background()
+
+    token := newTestRefreshToken(bson.NewObjectID(), bson.NewObjectID(), "hash-1")
+    repo.Create(ctx, token)
+
+    first, err := repo.MarkUsed(ctx, token.ID)
+    assert.NoError(t, err)
+    assert.True(t, first)
+
+    second, err := repo.MarkUsed(ctx, token.ID)
+    assert.NoError(t, err)
+    assert.False(t, second)
+  })
+}
+
+func TestRefreshTokenRepository_RevokeFamily(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should revoke only tokens of the family", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewRefreshTokenRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    familyID := bson.NewObjectID()
+    repo.Create(ctx, newTestRefreshToken(userID, familyID, "hash-1"))
+    repo.Create(ctx, newTestRefreshToken(userID, familyID, "hash-2"))
+    repo.Create(ctx, newTestRefreshToken(userID, bson.NewObjectID(), "hash-3"))
+
+    err := repo.RevokeFamily(ctx, familyID)
+    assert.NoError(t, err)
+
+    revoked, _ := repo.FindByHash(ctx, "hash-2")
+    assert.NotNil(t, revoked.RevokedAt)
+
+    other, _ := repo.FindByHash(ctx, "hash-3")
+    assert.Nil(t, other.RevokedAt)
+  })
+}
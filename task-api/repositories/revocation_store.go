@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// RevocationStore - backend for revoked access tokens, consulted by AuthMiddleware
+type RevocationStore interface {
+  // RevokeToken - revoke a single access token by jti until it would have expired anyway
+  RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+  // RevokeAllForUser - revoke every access token of user issued before issuedBefore
+  RevokeAllForUser(ctx context.Context, userID bson.ObjectID, issuedBefore time.Time, expiresAt time.Time) error
+  // IsRevoked - check token by jti and by user-wide cutoff
+  IsRevoked(ctx context.Context, tokenID string, userID bson.ObjectID, issuedAt time.Time) (bool, error)
+}
+
+// revocationEntry - document in revoked_tokens collection,
+// _id is either the token jti or "user:<user id>" for user-wide cutoffs
+type revocationEntry struct {
+  ID            string     `bson:"_id"`
+  RevokedBefore *time.Time `bson:"revoked_before,omitempty"`
+  ExpiresAt     time.Time  `bson:"expires_at"` // TTL index removes entries once tokens are expired
+}
+
+// mongoRevocationStore - implement RevocationStore on a TTL collection
+type mongoRevocationStore struct {
+  collection *mongo.Collection
+}
+
+// NewMongoRevocationStore - constructor
+func NewMongoRevocationStore(db *mongo.Database) RevocationStore {
+  return &mongoRevocationStore{
+    collection: db.Collection("revoked_tokens"),
+  }
+}
+
+// userRevocationKey - _id of user-wide cutoff entry
+func userRevocationKey(userID bson.ObjectID) string {
+  return "user:" + userID.Hex()
+}
+
+// RevokeToken - revoke single token
+func (s *mongoRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+  filter := bson.M{"_id": tokenID}
+  update := bson.M{"$set": bson.M{"expires_at": expiresAt}}
+
+  _, err := s.collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+  return err
+}
+
+// RevokeAllForUser - store user-wide cutoff, never moves an existing cutoff backwards
+func (s *mongoRevocationStore) RevokeAllForUser(ctx context.Context, userID bson.ObjectID, issuedBefore time.Time, expiresAt time.Time) error {
+  filter := bson.M{"_id": userRevocationKey(userID)}
+  update := bson.M{
+    "$max": bson.M{
+      "revoked_before": issuedBefore,
+      "expires_at":     expiresAt,
+    },
+  }
+
+  _, err := s.collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+  return err
+}
+
+// IsRevoked - single query for both the jti entry and the user cutoff
+func (s *mongoRevocationStore) IsRevoked(ctx context.Context, tokenID string, userID bson.ObjectID, issuedAt time.Time) (bool, error) {
+  ids := []string{userRevocationKey(userID)}
+  if tokenID != "" {
+    ids = append(ids, tokenID)
+  }
+
+  cursor, err := s.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+  if err != nil {
+    return false, err
+  }
+  defer cursor.Close(ctx)
+
+  var entries []revocationEntry
+  if err = cursor.All(ctx, &entries); err != nil {
+    return false, err
+  }
+
+  for _, entry := range entries {
+    if isRevokedBy(entry, tokenID, issuedAt) {
+      return true, nil
+    }
+  }
+
+  return false, nil
+}
+
+// isRevokedBy - decide whether entry revokes the token.
+// JWT iat has second precision, so a cutoff only applies to tokens issued in an
+// earlier second; callers revoke the current jti explicitly to close that gap.
+func isRevokedBy(entry revocationEntry, tokenID string, issuedAt time.Time) bool {
+  if entry.RevokedBefore == nil {
+    return tokenID != "" && entry.ID == tokenID
+  }
+  return issuedAt.Before(entry.RevokedBefore.Truncate(time.Second))
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMongoRevocationStore(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should revoke single token by jti", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    store := NewMongoRevocationStore(db)
+    ctx := context.Background()
+    userID := bson.NewObjectID()
+
+    err := store.RevokeToken(ctx, "jti-1", time.Now().Add(time.Hour))
+    assert.NoError(t, err)
+
+    revoked, err := store.IsRevoked(ctx, "jti-1", userID, time.Now().Add(-time.Minute))
+    assert.NoError(t, err)
+    assert.True(t, revoked)
+
+    revoked, _ = store.IsRevoked(ctx, "jti-2", userID, time.Now().Add(-time.Minute))
+    assert.False(t, revoked)
+  })
+
+  t.Run("should revoke tokens issued before user cutoff", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    store := NewMongoRevocationStore(db)
+    ctx := context.Background()
+    userID := bson.NewObjectID()
+    now := time.Now()
+
+    err := store.RevokeAllForUser(ctx, userID, now, now.Add(time.Hour))
+    assert.NoError(t, err)
+
+    revoked, _ := store.IsRevoked(ctx, "old", userID, now.Add(-2*time.Second))
+    assert.True(t, revoked)
+
+    revoked, _ = store.IsRevoked(ctx, "new", userID, now.Add(2*time.Second))
+    assert.False(t, revoked)
+  })
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"task-api/models"
+)
+
+// TaskEventRepository - interface for the history of tasks, callers authorize access to the task
+type TaskEventRepository interface {
+  Create(ctx context.Context, event *models.TaskEvent) error
+  FindByTask(ctx context.Context, taskID bson.ObjectID, before *bson.ObjectID, limit int) ([]models.TaskEvent, error)
+  DeleteByTasks(ctx context.Context, taskIDs []bson.ObjectID) error
+}
+
+// taskEventRepository - implement TaskEventRepository
+type taskEventRepository struct {
+  collection *mongo.Collection
+}
+
+// NewTaskEventRepository - constructor
+func NewTaskEventRepository(db *mongo.Database) TaskEventRepository {
+  return &taskEventRepository{
+    collection: db.Collection("task_events"),
+  }
+}
+
+// Create - insert event, events are never changed afterwards
+func (r *taskEventRepository) Create(ctx context.Context, event *models.TaskEvent) error {
+  if event.ID.IsZero() {
+    event.ID = bson.NewObjectID()
+  }
+  if event.CreatedAt.IsZero() {
+    event.CreatedAt = time.Now()
+  }
+
+  _, err := r.collection.InsertOne(ctx, event)
+  return err
+}
+
+// FindByTask - events of task newest first, starting before the given event ID (the cursor)
+func (r *taskEventRepository) FindByTask(ctx context.Context, taskID bson.ObjectID, before *bson.ObjectID, limit int) ([]models.TaskEvent, error) {
+  filter := bson.M{"task_id": taskID}
+  if before != nil {
+    filter["_id"] = bson.M{"$lt": *before}
+  }
+
+  opts := options.Find().
+    SetSort(bson.D{{Key: "_id", Value: -1}}).
+    SetLimit(int64(limit))
+
+  cursor, err := r.collection.Find(ctx, filter, opts)
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  events := []models.TaskEvent{}
+  if err = cursor.All(ctx, &events); err != nil {
+    return nil, err
+  }
+
+  return events, nil
+}
+
+// DeleteByTasks - remove the history of the tasks (purged tasks)
+func (r *taskEventRepository) DeleteByTasks(ctx context.Context, taskIDs []bson.ObjectID) error {
+  _, err := r.collection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}})
+  return err
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+)
+
+func TestTaskEventRepository(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should page events newest first", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskEventRepository(db)
+    ctx := context.Background()
+
+    taskID, actorID := bson.NewObjectID(), bson.NewObjectID()
+    due := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+    events := []*models.TaskEvent{
+      {TaskID: taskID, ActorID: actorID, Action: "created"},
+      {TaskID: taskID, ActorID: actorID, Action: "updated", Changes: []models.FieldChange{{Field: "due_date", Old: nil, New: due}}},
+      {TaskID: taskID, ActorID: actorID, Action: "updated", Changes: []models.FieldChange{{Field: "tags", Old: []string{"a"}, New: []string{"a", "b"}}}},
+    }
+    for _, event := range events {
+      assert.NoError(t, repo.Create(ctx, event))
+    }
+    assert.NoError(t, repo.Create(ctx, &models.TaskEvent{TaskID: bson.NewObjectID(), ActorID: actorID, Action: "created"}))
+
+    page, err := repo.FindByTask(ctx, taskID, nil, 2)
+    assert.NoError(t, err)
+    assert.Len(t, page, 2)
+    assert.Equal(t, events[2].ID, page[0].ID)
+    assert.Equal(t, bson.A{"a", "b"}, page[0].Changes[0].New)
+    assert.Equal(t, bson.NewDateTimeFromTime(due), page[1].Changes[0].New)
+
+    page, err = repo.FindByTask(ctx, taskID, &page[1].ID, 2)
+    assert.NoError(t, err)
+    assert.Len(t, page, 1)
+    assert.Equal(t, events[0].ID, page[0].ID)
+  })
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -13,46 +14,107 @@ import (
 	"task-api/types"
 )
 
+var ErrTaskNotFound = errors.New("task not found")
+
+// notTrashed - condition on deleted_at matching tasks outside the trash
+var notTrashed = bson.M{"$exists": false}
+
+// incVersion - every write moves the version on, conditional writes compare it
+var incVersion = bson.M{"version": 1}
+
+// Errors for parts of a task, the task itself exists
+var (
+  ErrChecklistItemNotFound = errors.New("checklist item not found")
+  ErrChecklistMismatch     = errors.New("checklist changed")
+  ErrChecklistFull         = errors.New("checklist is full")
+  ErrAttachmentNotFound    = errors.New("attachment not found")
+  ErrTooManyAttachments    = errors.New("too many attachments")
+  ErrVersionConflict       = errors.New("task version changed")
+)
+
+// TaskWrite - fields to set on one task of a bulk write, at the version it was read
+type TaskWrite struct {
+  ID      bson.ObjectID
+  Version int64
+  Set     bson.M
+}
+
 // TaskRepository - interface
 type TaskRepository interface {
   Create(ctx context.Context, task *models.Task) error
   FindByID(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) (*models.Task, error)
   FindByUserID(ctx context.Context, userID bson.ObjectID, query types.TaskQueryParams) ([]models.Task, int64, error)
   Update(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, updates bson.M) error
-  Delete(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) error
+  AddAssignee(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) error
+  RemoveAssignee(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) error
+  AddWatcher(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) error
+  RemoveWatcher(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) error
+  FindByIDs(ctx context.Context, ids []bson.ObjectID) ([]models.Task, error)
+  UpdateByID(ctx context.Context, id bson.ObjectID, updates bson.M) error
+  FindChildren(ctx context.Context, parentID bson.ObjectID) ([]models.Task, error)
+  FindChildIDs(ctx context.Context, parentIDs []bson.ObjectID) ([]bson.ObjectID, error)
+  ChildProgress(ctx context.Context, parentIDs []bson.ObjectID) (map[bson.ObjectID]types.TaskProgress, error)
+  DeleteByIDs(ctx context.Context, ids []bson.ObjectID) error
+  AddBlocker(ctx context.Context, id bson.ObjectID, blockerID bson.ObjectID) error
+  RemoveBlocker(ctx context.Context, id bson.ObjectID, blockerID bson.ObjectID) error
+  FindBlockedBy(ctx context.Context, blockerIDs []bson.ObjectID) ([]models.Task, error)
+  PullBlockers(ctx context.Context, blockerIDs []bson.ObjectID) error
+  PullWorkspaceMember(ctx context.Context, workspaceID bson.ObjectID, userID bson.ObjectID) error
+  AddChecklistItem(ctx context.Context, id bson.ObjectID, item models.ChecklistItem, maxItems int) error
+  UpdateChecklistItem(ctx context.Context, id bson.ObjectID, itemID bson.ObjectID, updates bson.M) error
+  RemoveChecklistItem(ctx context.Context, id bson.ObjectID, itemID bson.ObjectID) error
+  ReorderChecklist(ctx context.Context, id bson.ObjectID, itemIDs []bson.ObjectID) error
+  AddAttachment(ctx context.Context, id bson.ObjectID, attachment models.Attachment, maxAttachments int) error
+  RemoveAttachment(ctx context.Context, id bson.ObjectID, attachmentID bson.ObjectID) error
+  Trash(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, deletedAt time.Time) error
+  TrashByIDs(ctx context.Context, ids []bson.ObjectID, deletedAt time.Time, deletedWith *bson.ObjectID) error
+  FindOwned(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) (*models.Task, error)
+  FindTrash(ctx context.Context, userID bson.ObjectID, page int, limit int) ([]models.Task, int64, error)
+  FindTrashedWith(ctx context.Context, id bson.ObjectID) ([]models.Task, error)
+  FindExpired(ctx context.Context, before time.Time, limit int) ([]models.Task, error)
+  Restore(ctx context.Context, id bson.ObjectID) error
+  ArchiveCompleted(ctx context.Context, completedBefore time.Time, archivedAt time.Time) (int64, error)
+  BulkUpdate(ctx context.Context, writes []TaskWrite) ([]error, error)
+  SetIfVersion(ctx context.Context, id bson.ObjectID, version int64, set bson.M) error
+  WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 // taskRepository - implementation
 type taskRepository struct {
   collection *mongo.Collection
+  members    *mongo.Collection
 }
 
 // NewTaskRepository - constructor
 func NewTaskRepository(db *mongo.Database) TaskRepository {
   return &taskRepository{
     collection: db.Collection("tasks"),
+    members:    db.Collection("workspace_members"),
   }
 }
 
-// Create - create new task
+// Create - create new task, at version 1
 func (r *taskRepository) Create(ctx context.Context, task *models.Task) error {
+  if task.Version == 0 {
+    task.Version = 1
+  }
   _, err := r.collection.InsertOne(ctx, task)
   return err
 }
 
-// FindByID - find task by ID (with user ownership check)
+// FindByID - find task by ID (with access check)
 func (r *taskRepository) FindByID(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) (*models.Task, error) {
   var task models.Task
   
-  filter := bson.M{
-    "_id":     id,
-    "user_id": userID, // Ensure user owns this task
+  filter, err := r.visibleFilter(ctx, userID, bson.M{"_id": id})
+  if err != nil {
+    return nil, err
   }
   
-  err := r.collection.FindOne(ctx, filter).Decode(&task)
+  err = r.collection.FindOne(ctx, filter).Decode(&task)
   if err != nil {
     if err == mongo.ErrNoDocuments {
-      return nil, errors.New("task not found")
+      return nil, ErrTaskNotFound
     }
     return nil, err
   }
@@ -60,10 +122,22 @@ func (r *taskRepository) FindByID(ctx context.Context, id bson.ObjectID, userID
   return &task, nil
 }
 
-// FindByUserID - find tasks by user ID with filters
+// FindByUserID - find tasks the user can access with filters
 func (r *taskRepository) FindByUserID(ctx context.Context, userID bson.ObjectID, query types.TaskQueryParams) ([]models.Task, int64, error) {
   // Build filter
-  filter := bson.M{"user_id": userID}
+  filter := bson.M{}
+  
+  if query.WorkspaceID != "" {
+    workspaceID, err := bson.ObjectIDFromHex(query.WorkspaceID)
+    if err != nil {
+      return nil, 0, err
+    }
+    filter["workspace_id"] = workspaceID
+  }
+  
+  if query.AssignedTo == types.AssignedToMe {
+    filter["assignee_ids"] = userID
+  }
   
   if query.Status != "" {
     filter["status"] = query.Status
@@ -73,6 +147,23 @@ func (r *taskRepository) FindByUserID(ctx context.Context, userID bson.ObjectID,
     filter["priority"] = query.Priority
   }
   
+  switch query.Checklist {
+  case types.ChecklistOpen:
+    filter["checklist"] = bson.M{"$elemMatch": bson.M{"done": false}}
+  case types.ChecklistDone:
+    filter["checklist.0"] = bson.M{"$exists": true}
+    filter["checklist.done"] = bson.M{"$ne": false}
+  }
+  
+  // Archived tasks are left out unless asked for, null counts as not archived
+  switch query.Archived {
+  case types.ArchivedOnly:
+    filter["archived_at"] = bson.M{"$ne": nil}
+  case types.ArchivedAll:
+  default:
+    filter["archived_at"] = nil
+  }
+  
   if query.Search != "" {
     filter["$or"] = []bson.M{
       {"title": bson.M{"$regex": query.Search, "$options": "i"}},
@@ -80,6 +171,11 @@ func (r *taskRepository) FindByUserID(ctx context.Context, userID bson.ObjectID,
     }
   }
   
+  filter, err := r.visibleFilter(ctx, userID, filter)
+  if err != nil {
+    return nil, 0, err
+  }
+  
   // Count total
   total, err := r.collection.CountDocuments(ctx, filter)
   if err != nil {
@@ -134,14 +230,14 @@ func (r *taskRepository) FindByUserID(ctx context.Context, userID bson.ObjectID,
 
 // Update - update task
 func (r *taskRepository) Update(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, updates bson.M) error {
-  filter := bson.M{
-    "_id":     id,
-    "user_id": userID,
+  filter, err := r.visibleFilter(ctx, userID, bson.M{"_id": id})
+  if err != nil {
+    return err
   }
   
   updates["updated_at"] = time.Now()
   
-  update := bson.M{"$set": updates}
+  update := bson.M{"$set": updates, "$inc": incVersion}
   
   result, err := r.collection.UpdateOne(ctx, filter, update)
   if err != nil {
@@ -149,27 +245,647 @@ func (r *taskRepository) Update(ctx context.Context, id bson.ObjectID, userID bs
   }
   
   if result.MatchedCount == 0 {
-    return errors.New("task not found")
+    return ErrTaskNotFound
   }
   
   return nil
 }
 
-// Delete - delete task
-func (r *taskRepository) Delete(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) error {
+// FindByIDs - find tasks by IDs without access check, callers authorize (task shares), trashed tasks are skipped
+func (r *taskRepository) FindByIDs(ctx context.Context, ids []bson.ObjectID) ([]models.Task, error) {
+  cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": notTrashed})
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  tasks := []models.Task{}
+  if err = cursor.All(ctx, &tasks); err != nil {
+    return nil, err
+  }
+
+  return tasks, nil
+}
+
+// UpdateByID - update task without access check, callers authorize
+func (r *taskRepository) UpdateByID(ctx context.Context, id bson.ObjectID, updates bson.M) error {
+  updates["updated_at"] = time.Now()
+
+  result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": updates, "$inc": incVersion})
+  if err != nil {
+    return err
+  }
+
+  if result.MatchedCount == 0 {
+    return ErrTaskNotFound
+  }
+
+  return nil
+}
+
+// FindChildren - direct subtasks of a task, oldest first, callers authorize through the parent
+func (r *taskRepository) FindChildren(ctx context.Context, parentID bson.ObjectID) ([]models.Task, error) {
+  opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+  cursor, err := r.collection.Find(ctx, bson.M{"parent_id": parentID, "deleted_at": notTrashed}, opts)
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  tasks := []models.Task{}
+  if err = cursor.All(ctx, &tasks); err != nil {
+    return nil, err
+  }
+
+  return tasks, nil
+}
+
+// FindChildIDs - IDs of direct subtasks of any of the parents, trashed subtasks are skipped
+func (r *taskRepository) FindChildIDs(ctx context.Context, parentIDs []bson.ObjectID) ([]bson.ObjectID, error) {
+  opts := options.Find().SetProjection(bson.M{"_id": 1})
+
+  cursor, err := r.collection.Find(ctx, bson.M{"parent_id": bson.M{"$in": parentIDs}, "deleted_at": notTrashed}, opts)
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  var children []models.Task
+  if err = cursor.All(ctx, &children); err != nil {
+    return nil, err
+  }
+
+  ids := make([]bson.ObjectID, len(children))
+  for i, child := range children {
+    ids[i] = child.ID
+  }
+  return ids, nil
+}
+
+// ChildProgress - completion of direct subtasks per parent, parents without subtasks are absent
+func (r *taskRepository) ChildProgress(ctx context.Context, parentIDs []bson.ObjectID) (map[bson.ObjectID]types.TaskProgress, error) {
+  pipeline := mongo.Pipeline{
+    {{Key: "$match", Value: bson.M{"parent_id": bson.M{"$in": parentIDs}, "deleted_at": notTrashed}}},
+    {{Key: "$group", Value: bson.M{
+      "_id":   "$parent_id",
+      "total": bson.M{"$sum": 1},
+      "completed": bson.M{"$sum": bson.M{
+        "$cond": bson.A{bson.M{"$eq": bson.A{"$status", types.TaskStatusCompleted}}, 1, 0},
+      }},
+    }}},
+  }
+
+  cursor, err := r.collection.Aggregate(ctx, pipeline)
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  var groups []struct {
+    ParentID  bson.ObjectID `bson:"_id"`
+    Total     int           `bson:"total"`
+    Completed int           `bson:"completed"`
+  }
+  if err = cursor.All(ctx, &groups); err != nil {
+    return nil, err
+  }
+
+  progress := make(map[bson.ObjectID]types.TaskProgress, len(groups))
+  for _, group := range groups {
+    progress[group.ParentID] = types.NewTaskProgress(group.Total, group.Completed)
+  }
+  return progress, nil
+}
+
+// DeleteByIDs - delete tasks for good without access check, callers authorize (purge)
+func (r *taskRepository) DeleteByIDs(ctx context.Context, ids []bson.ObjectID) error {
+  _, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+  return err
+}
+
+// AddAssignee - add user to assignees
+func (r *taskRepository) AddAssignee(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) error {
+  return r.updateSet(ctx, id, userID, "$addToSet", "assignee_ids", assigneeID)
+}
+
+// RemoveAssignee - remove user from assignees
+func (r *taskRepository) RemoveAssignee(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) error {
+  return r.updateSet(ctx, id, userID, "$pull", "assignee_ids", assigneeID)
+}
+
+// AddWatcher - add user to watchers
+func (r *taskRepository) AddWatcher(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) error {
+  return r.updateSet(ctx, id, userID, "$addToSet", "watcher_ids", userID)
+}
+
+// RemoveWatcher - remove user from watchers
+func (r *taskRepository) RemoveWatcher(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) error {
+  return r.updateSet(ctx, id, userID, "$pull", "watcher_ids", userID)
+}
+
+// AddBlocker - task cannot start before blocker is completed, callers authorize
+func (r *taskRepository) AddBlocker(ctx context.Context, id bson.ObjectID, blockerID bson.ObjectID) error {
+  return r.applySet(ctx, bson.M{"_id": id}, "$addToSet", "blocked_by", blockerID)
+}
+
+// RemoveBlocker - drop blocker from task, callers authorize
+func (r *taskRepository) RemoveBlocker(ctx context.Context, id bson.ObjectID, blockerID bson.ObjectID) error {
+  return r.applySet(ctx, bson.M{"_id": id}, "$pull", "blocked_by", blockerID)
+}
+
+// FindBlockedBy - tasks blocked by any of the blockers, callers authorize
+func (r *taskRepository) FindBlockedBy(ctx context.Context, blockerIDs []bson.ObjectID) ([]models.Task, error) {
+  cursor, err := r.collection.Find(ctx, bson.M{"blocked_by": bson.M{"$in": blockerIDs}, "deleted_at": notTrashed})
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  tasks := []models.Task{}
+  if err = cursor.All(ctx, &tasks); err != nil {
+    return nil, err
+  }
+
+  return tasks, nil
+}
+
+// PullBlockers - remove deleted tasks from every blocked_by list
+func (r *taskRepository) PullBlockers(ctx context.Context, blockerIDs []bson.ObjectID) error {
+  _, err := r.collection.UpdateMany(ctx,
+    bson.M{"blocked_by": bson.M{"$in": blockerIDs}},
+    bson.M{"$pull": bson.M{"blocked_by": bson.M{"$in": blockerIDs}}, "$inc": incVersion},
+  )
+  return err
+}
+
+// PullWorkspaceMember - remove a former member from assignees and watchers of every task of the workspace
+func (r *taskRepository) PullWorkspaceMember(ctx context.Context, workspaceID bson.ObjectID, userID bson.ObjectID) error {
+  _, err := r.collection.UpdateMany(ctx,
+    bson.M{"workspace_id": workspaceID, "$or": bson.A{bson.M{"assignee_ids": userID}, bson.M{"watcher_ids": userID}}},
+    bson.M{"$pull": bson.M{"assignee_ids": userID, "watcher_ids": userID}, "$inc": incVersion},
+  )
+  return err
+}
+
+// AddChecklistItem - append item to a checklist with fewer than maxItems items, callers authorize.
+// The limit is part of the filter so concurrent adds cannot exceed it.
+func (r *taskRepository) AddChecklistItem(ctx context.Context, id bson.ObjectID, item models.ChecklistItem, maxItems int) error {
+  filter := bson.M{"_id": id, fmt.Sprintf("checklist.%d", maxItems-1): bson.M{"$exists": false}}
+  err := r.applySet(ctx, filter, "$push", "checklist", item)
+  if errors.Is(err, ErrTaskNotFound) {
+    return ErrChecklistFull
+  }
+  return err
+}
+
+// UpdateChecklistItem - set fields of one checklist item (keys relative to the item), callers authorize
+func (r *taskRepository) UpdateChecklistItem(ctx context.Context, id bson.ObjectID, itemID bson.ObjectID, updates bson.M) error {
+  set := bson.M{"updated_at": time.Now()}
+  for field, value := range updates {
+    set["checklist.$."+field] = value
+  }
+
+  result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "checklist.id": itemID}, bson.M{"$set": set, "$inc": incVersion})
+  if err != nil {
+    return err
+  }
+
+  if result.MatchedCount == 0 {
+    return ErrChecklistItemNotFound
+  }
+
+  return nil
+}
+
+// RemoveChecklistItem - remove one checklist item, callers authorize
+func (r *taskRepository) RemoveChecklistItem(ctx context.Context, id bson.ObjectID, itemID bson.ObjectID) error {
+  err := r.applySet(ctx, bson.M{"_id": id, "checklist.id": itemID}, "$pull", "checklist", bson.M{"id": itemID})
+  if errors.Is(err, ErrTaskNotFound) {
+    return ErrChecklistItemNotFound
+  }
+  return err
+}
+
+// ReorderChecklist - number items in the order of itemIDs, which must list every item once.
+// One update with array filters, it fails with ErrChecklistMismatch when the items changed meanwhile.
+func (r *taskRepository) ReorderChecklist(ctx context.Context, id bson.ObjectID, itemIDs []bson.ObjectID) error {
   filter := bson.M{
-    "_id":     id,
-    "user_id": userID,
+    "_id":          id,
+    "checklist":    bson.M{"$size": len(itemIDs)},
+    "checklist.id": bson.M{"$all": itemIDs},
   }
-  
-  result, err := r.collection.DeleteOne(ctx, filter)
+
+  set := bson.M{"updated_at": time.Now()}
+  arrayFilters := make([]any, len(itemIDs))
+  for i, itemID := range itemIDs {
+    name := fmt.Sprintf("i%d", i)
+    set["checklist.$["+name+"].order"] = i
+    arrayFilters[i] = bson.M{name + ".id": itemID}
+  }
+
+  opts := options.UpdateOne().SetArrayFilters(arrayFilters)
+  result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": set, "$inc": incVersion}, opts)
   if err != nil {
     return err
   }
-  
-  if result.DeletedCount == 0 {
-    return errors.New("task not found")
+
+  if result.MatchedCount == 0 {
+    return ErrChecklistMismatch
   }
-  
+
+  return nil
+}
+
+// AddAttachment - append attachment metadata to a task with fewer than maxAttachments, callers authorize.
+// Like AddChecklistItem the limit is part of the filter.
+func (r *taskRepository) AddAttachment(ctx context.Context, id bson.ObjectID, attachment models.Attachment, maxAttachments int) error {
+  filter := bson.M{"_id": id, fmt.Sprintf("attachments.%d", maxAttachments-1): bson.M{"$exists": false}}
+  err := r.applySet(ctx, filter, "$push", "attachments", attachment)
+  if errors.Is(err, ErrTaskNotFound) {
+    return ErrTooManyAttachments
+  }
+  return err
+}
+
+// RemoveAttachment - remove attachment metadata, callers authorize and delete the contents
+func (r *taskRepository) RemoveAttachment(ctx context.Context, id bson.ObjectID, attachmentID bson.ObjectID) error {
+  err := r.applySet(ctx, bson.M{"_id": id, "attachments.id": attachmentID}, "$pull", "attachments", bson.M{"id": attachmentID})
+  if errors.Is(err, ErrTaskNotFound) {
+    return ErrAttachmentNotFound
+  }
+  return err
+}
+
+// Trash - move task to the trash (assignees cannot delete)
+func (r *taskRepository) Trash(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, deletedAt time.Time) error {
+  filter, err := r.accessFilter(ctx, userID, bson.M{"_id": id, "deleted_at": notTrashed})
+  if err != nil {
+    return err
+  }
+
+  result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"deleted_at": deletedAt}, "$inc": incVersion})
+  if err != nil {
+    return err
+  }
+
+  if result.MatchedCount == 0 {
+    return ErrTaskNotFound
+  }
+
+  return nil
+}
+
+// TrashByIDs - move tasks to the trash without access check, callers authorize,
+// deletedWith marks subtasks trashed together with their ancestor
+func (r *taskRepository) TrashByIDs(ctx context.Context, ids []bson.ObjectID, deletedAt time.Time, deletedWith *bson.ObjectID) error {
+  set := bson.M{"deleted_at": deletedAt}
+  if deletedWith != nil {
+    set["deleted_with"] = *deletedWith
+  }
+
+  result, err := r.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": notTrashed}, bson.M{"$set": set, "$inc": incVersion})
+  if err != nil {
+    return err
+  }
+
+  if result.MatchedCount == 0 {
+    return ErrTaskNotFound
+  }
+
   return nil
 }
+
+// FindOwned - task the user may delete, in the trash or not
+func (r *taskRepository) FindOwned(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) (*models.Task, error) {
+  filter, err := r.accessFilter(ctx, userID, bson.M{"_id": id})
+  if err != nil {
+    return nil, err
+  }
+
+  var task models.Task
+  err = r.collection.FindOne(ctx, filter).Decode(&task)
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrTaskNotFound
+    }
+    return nil, err
+  }
+
+  return &task, nil
+}
+
+// FindTrash - trashed tasks the user owns, most recently deleted first;
+// subtasks trashed with their ancestor are listed through it
+func (r *taskRepository) FindTrash(ctx context.Context, userID bson.ObjectID, page int, limit int) ([]models.Task, int64, error) {
+  filter, err := r.accessFilter(ctx, userID, bson.M{
+    "deleted_at":   bson.M{"$exists": true},
+    "deleted_with": bson.M{"$exists": false},
+  })
+  if err != nil {
+    return nil, 0, err
+  }
+
+  total, err := r.collection.CountDocuments(ctx, filter)
+  if err != nil {
+    return nil, 0, err
+  }
+
+  opts := options.Find().
+    SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}}).
+    SetSkip(int64((page - 1) * limit)).
+    SetLimit(int64(limit))
+
+  cursor, err := r.collection.Find(ctx, filter, opts)
+  if err != nil {
+    return nil, 0, err
+  }
+  defer cursor.Close(ctx)
+
+  tasks := []models.Task{}
+  if err = cursor.All(ctx, &tasks); err != nil {
+    return nil, 0, err
+  }
+
+  return tasks, total, nil
+}
+
+// FindTrashedWith - subtasks that went to the trash together with task id
+func (r *taskRepository) FindTrashedWith(ctx context.Context, id bson.ObjectID) ([]models.Task, error) {
+  cursor, err := r.collection.Find(ctx, bson.M{"deleted_with": id})
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  tasks := []models.Task{}
+  if err = cursor.All(ctx, &tasks); err != nil {
+    return nil, err
+  }
+
+  return tasks, nil
+}
+
+// FindExpired - up to limit tasks trashed before the given time, for the purger
+func (r *taskRepository) FindExpired(ctx context.Context, before time.Time, limit int) ([]models.Task, error) {
+  opts := options.Find().
+    SetSort(bson.D{{Key: "deleted_at", Value: 1}}).
+    SetLimit(int64(limit))
+
+  cursor, err := r.collection.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": before}}, opts)
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  tasks := []models.Task{}
+  if err = cursor.All(ctx, &tasks); err != nil {
+    return nil, err
+  }
+
+  return tasks, nil
+}
+
+// Restore - take a trashed task and the subtasks trashed with it out of the trash, callers authorize
+func (r *taskRepository) Restore(ctx context.Context, id bson.ObjectID) error {
+  filter := bson.M{
+    "$or": []bson.M{
+      {"_id": id, "deleted_at": bson.M{"$exists": true}},
+      {"deleted_with": id},
+    },
+  }
+  update := bson.M{
+    "$unset": bson.M{"deleted_at": "", "deleted_with": ""},
+    "$set":   bson.M{"updated_at": time.Now()},
+    "$inc":   incVersion,
+  }
+
+  result, err := r.collection.UpdateMany(ctx, filter, update)
+  if err != nil {
+    return err
+  }
+
+  if result.MatchedCount == 0 {
+    return ErrTaskNotFound
+  }
+
+  return nil
+}
+
+// ArchiveCompleted - archive tasks completed and not unarchived since completedBefore,
+// without access check (auto-archive), returns how many
+func (r *taskRepository) ArchiveCompleted(ctx context.Context, completedBefore time.Time, archivedAt time.Time) (int64, error) {
+  filter := bson.M{
+    "status":        types.TaskStatusCompleted,
+    "completed_at":  bson.M{"$lt": completedBefore},
+    "unarchived_at": bson.M{"$not": bson.M{"$gte": completedBefore}},
+    "archived_at":   nil,
+    "deleted_at":    notTrashed,
+  }
+
+  result, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"archived_at": archivedAt}, "$inc": incVersion})
+  if err != nil {
+    return 0, err
+  }
+
+  return result.ModifiedCount, nil
+}
+
+// BulkUpdate - apply writes in one unordered bulk write without access check,
+// callers authorize; returns the error of each write, nil when it went through
+// and ErrVersionConflict for tasks changed or trashed since they were read.
+// Later writes to a task already written in the same call fail with ErrVersionConflict.
+func (r *taskRepository) BulkUpdate(ctx context.Context, writes []TaskWrite) ([]error, error) {
+  errs := make([]error, len(writes))
+  if len(writes) == 0 {
+    return errs, nil
+  }
+
+  // updated_at tells this call's writes apart from others that moved a task to the same version
+  now := time.Now().Truncate(time.Millisecond)
+  written := map[bson.ObjectID]bool{}
+  writeModels := []mongo.WriteModel{}
+  indexes := []int{} // write of each model
+  for i, write := range writes {
+    // Planned at the same version, at most one of them could match
+    if written[write.ID] {
+      errs[i] = ErrVersionConflict
+      continue
+    }
+    written[write.ID] = true
+
+    set := bson.M{"updated_at": now}
+    for field, value := range write.Set {
+      set[field] = value
+    }
+    writeModels = append(writeModels, mongo.NewUpdateOneModel().
+      SetFilter(bson.M{"_id": write.ID, "deleted_at": notTrashed, "version": versionFilter(write.Version)}).
+      SetUpdate(bson.M{"$set": set, "$inc": incVersion}))
+    indexes = append(indexes, i)
+  }
+
+  result, err := r.collection.BulkWrite(ctx, writeModels, options.BulkWrite().SetOrdered(false))
+
+  // Failed writes are reported per item, the others are applied
+  var bulkErr mongo.BulkWriteException
+  if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+    for _, writeErr := range bulkErr.WriteErrors {
+      errs[indexes[writeErr.Index]] = writeErr
+    }
+  } else if err != nil {
+    return errs, err
+  }
+
+  // Writes that matched nothing fail nowhere, find them by what they would have left
+  if result == nil || result.MatchedCount < int64(len(writeModels)-len(bulkErr.WriteErrors)) {
+    if err := r.markConflicts(ctx, writes, errs, now); err != nil {
+      return nil, err
+    }
+  }
+  return errs, nil
+}
+
+// markConflicts - ErrVersionConflict for the writes without error whose task was not
+// written at updatedAt or is not one version past theirs
+func (r *taskRepository) markConflicts(ctx context.Context, writes []TaskWrite, errs []error, updatedAt time.Time) error {
+  ids := make([]bson.ObjectID, len(writes))
+  for i, write := range writes {
+    ids[i] = write.ID
+  }
+
+  opts := options.Find().SetProjection(bson.M{"version": 1, "updated_at": 1})
+  cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": notTrashed}, opts)
+  if err != nil {
+    return err
+  }
+  defer cursor.Close(ctx)
+
+  tasks := []models.Task{}
+  if err = cursor.All(ctx, &tasks); err != nil {
+    return err
+  }
+
+  byID := make(map[bson.ObjectID]*models.Task, len(tasks))
+  for i := range tasks {
+    byID[tasks[i].ID] = &tasks[i]
+  }
+  for i, write := range writes {
+    if errs[i] != nil {
+      continue
+    }
+    if task, ok := byID[write.ID]; !ok || task.Version != write.Version+1 || !task.UpdatedAt.Equal(updatedAt) {
+      errs[i] = ErrVersionConflict
+    }
+  }
+  return nil
+}
+
+// SetIfVersion - set fields of a task still at version without access check,
+// callers authorize; ErrVersionConflict when it was changed or trashed meanwhile
+func (r *taskRepository) SetIfVersion(ctx context.Context, id bson.ObjectID, version int64, set bson.M) error {
+  filter := bson.M{"_id": id, "deleted_at": notTrashed, "version": versionFilter(version)}
+
+  fields := bson.M{"updated_at": time.Now()}
+  for field, value := range set {
+    fields[field] = value
+  }
+
+  result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": fields, "$inc": incVersion})
+  if err != nil {
+    return err
+  }
+
+  if result.MatchedCount == 0 {
+    return ErrVersionConflict
+  }
+
+  return nil
+}
+
+// WithTransaction - run fn in a transaction, repository calls made with its ctx
+// are committed together or not at all (needs a replica set)
+func (r *taskRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+  session, err := r.collection.Database().Client().StartSession()
+  if err != nil {
+    return err
+  }
+  defer session.EndSession(ctx)
+
+  _, err = session.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+    return nil, fn(ctx)
+  })
+  return err
+}
+
+// versionFilter - match a version, tasks written before versioning have none and count as version 0
+func versionFilter(version int64) any {
+  if version == 0 {
+    return bson.M{"$in": bson.A{0, nil}}
+  }
+  return version
+}
+
+// updateSet - apply $addToSet / $pull on an ID array of a task visible to the user
+func (r *taskRepository) updateSet(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, op string, field string, value bson.ObjectID) error {
+  filter, err := r.visibleFilter(ctx, userID, bson.M{"_id": id})
+  if err != nil {
+    return err
+  }
+
+  return r.applySet(ctx, filter, op, field, value)
+}
+
+// applySet - apply an array operator ($addToSet, $push, $pull) to the task matching filter
+func (r *taskRepository) applySet(ctx context.Context, filter bson.M, op string, field string, value any) error {
+  update := bson.M{
+    op:     bson.M{field: value},
+    "$set": bson.M{"updated_at": time.Now()},
+    "$inc": incVersion,
+  }
+
+  result, err := r.collection.UpdateOne(ctx, filter, update)
+  if err != nil {
+    return err
+  }
+
+  if result.MatchedCount == 0 {
+    return ErrTaskNotFound
+  }
+
+  return nil
+}
+
+// visibleFilter - like accessFilter, additionally matching tasks assigned to the user, without trashed tasks
+func (r *taskRepository) visibleFilter(ctx context.Context, userID bson.ObjectID, filter bson.M) (bson.M, error) {
+  access, err := r.accessClauses(ctx, userID)
+  if err != nil {
+    return nil, err
+  }
+
+  access = append(access, bson.M{"assignee_ids": userID})
+  return bson.M{"$and": []bson.M{filter, {"$or": access}, {"deleted_at": notTrashed}}}, nil
+}
+
+// accessFilter - restrict filter to the user's personal tasks and the tasks
+// of workspaces the user is a member of
+func (r *taskRepository) accessFilter(ctx context.Context, userID bson.ObjectID, filter bson.M) (bson.M, error) {
+  access, err := r.accessClauses(ctx, userID)
+  if err != nil {
+    return nil, err
+  }
+
+  return bson.M{"$and": []bson.M{filter, {"$or": access}}}, nil
+}
+
+// accessClauses - tasks the user owns: personal tasks and tasks of the user's workspaces
+func (r *taskRepository) accessClauses(ctx context.Context, userID bson.ObjectID) ([]bson.M, error) {
+  workspaceIDs, err := memberWorkspaceIDs(ctx, r.members, userID)
+  if err != nil {
+    return nil, err
+  }
+
+  return []bson.M{
+    {"user_id": userID, "workspace_id": bson.M{"$exists": false}},
+    {"workspace_id": bson.M{"$in": workspaceIDs}},
+  }, nil
+}
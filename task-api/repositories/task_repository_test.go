@@ -153,6 +153,103 @@ func TestTaskRepository_FindByID(t *testing.T) {
   })
 }
 
+func TestTaskRepository_WorkspaceAccess(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should grant access to workspace tasks by membership", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    authorID, memberID, outsiderID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+    workspaceID := bson.NewObjectID()
+    db.Collection("workspace_members").InsertMany(ctx, []interface{}{
+      models.WorkspaceMember{ID: bson.NewObjectID(), WorkspaceID: workspaceID, UserID: authorID, Role: "owner"},
+      models.WorkspaceMember{ID: bson.NewObjectID(), WorkspaceID: workspaceID, UserID: memberID, Role: "member"},
+    })
+
+    shared := models.Task{ID: bson.NewObjectID(), UserID: authorID, WorkspaceID: &workspaceID, Title: "Shared"}
+    personal := models.Task{ID: bson.NewObjectID(), UserID: authorID, Title: "Personal"}
+    db.Collection("tasks").InsertMany(ctx, []interface{}{shared, personal})
+
+    result, err := repo.FindByID(ctx, shared.ID, memberID)
+    assert.NoError(t, err)
+    assert.Equal(t, "Shared", result.Title)
+
+    _, err = repo.FindByID(ctx, shared.ID, outsiderID)
+    assert.Error(t, err)
+
+    // Personal tasks stay private to their author
+    _, err = repo.FindByID(ctx, personal.ID, memberID)
+    assert.Error(t, err)
+
+    assert.NoError(t, repo.Update(ctx, shared.ID, memberID, bson.M{"title": "Updated"}))
+    assert.Error(t, repo.Trash(ctx, shared.ID, outsiderID, time.Now()))
+
+    tasks, total, err := repo.FindByUserID(ctx, memberID, types.TaskQueryParams{})
+    assert.NoError(t, err)
+    assert.Equal(t, int64(1), total)
+    assert.Equal(t, "Updated", tasks[0].Title)
+
+    _, total, _ = repo.FindByUserID(ctx, authorID, types.TaskQueryParams{WorkspaceID: workspaceID.Hex()})
+    assert.Equal(t, int64(1), total)
+  })
+}
+
+func TestTaskRepository_Assignees(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should let assignees read and update but not delete", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    ownerID, assigneeID := bson.NewObjectID(), bson.NewObjectID()
+    task := models.Task{ID: bson.NewObjectID(), UserID: ownerID, Title: "Assigned"}
+    other := models.Task{ID: bson.NewObjectID(), UserID: ownerID, Title: "Other"}
+    db.Collection("tasks").InsertMany(ctx, []interface{}{task, other})
+
+    _, err := repo.FindByID(ctx, task.ID, assigneeID)
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+
+    assert.NoError(t, repo.AddAssignee(ctx, task.ID, ownerID, assigneeID))
+    assert.NoError(t, repo.AddAssignee(ctx, task.ID, ownerID, assigneeID))
+
+    result, err := repo.FindByID(ctx, task.ID, assigneeID)
+    assert.NoError(t, err)
+    assert.Equal(t, []bson.ObjectID{assigneeID}, result.AssigneeIDs)
+
+    assert.NoError(t, repo.Update(ctx, task.ID, assigneeID, bson.M{"status": "in_progress"}))
+    assert.ErrorIs(t, repo.Trash(ctx, task.ID, assigneeID, time.Now()), ErrTaskNotFound)
+
+    tasks, total, err := repo.FindByUserID(ctx, ownerID, types.TaskQueryParams{AssignedTo: types.AssignedToMe})
+    assert.NoError(t, err)
+    assert.Equal(t, int64(0), total)
+    assert.Empty(t, tasks)
+
+    _, total, _ = repo.FindByUserID(ctx, assigneeID, types.TaskQueryParams{AssignedTo: types.AssignedToMe})
+    assert.Equal(t, int64(1), total)
+
+    assert.NoError(t, repo.AddWatcher(ctx, task.ID, assigneeID))
+    assert.NoError(t, repo.RemoveAssignee(ctx, task.ID, ownerID, assigneeID))
+
+    // Watching alone grants no access
+    _, err = repo.FindByID(ctx, task.ID, assigneeID)
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+    assert.ErrorIs(t, repo.RemoveWatcher(ctx, task.ID, assigneeID), ErrTaskNotFound)
+  })
+}
+
 func TestTaskRepository_FindByUserID(t *testing.T) {
   if testing.Short() {
     t.Skip("Skipping integration test")
@@ -468,7 +565,7 @@ func TestTaskRepository_Delete(t *testing.T) {
     t.Skip("Skipping integration test")
   }
 
-  t.Run("should delete task", func(t *testing.T) {
+  t.Run("should move task to the trash", func(t *testing.T) {
     db := setupTestDB(t)
     if db == nil {
       return
@@ -484,14 +581,18 @@ func TestTaskRepository_Delete(t *testing.T) {
     }
     db.Collection("tasks").InsertOne(ctx, task)
 
-    err := repo.Delete(ctx, task.ID, userID)
+    err := repo.Trash(ctx, task.ID, userID, time.Now())
 
     assert.NoError(t, err)
 
-    // Verify deleted
+    // Verify hidden but kept in the trash
+    _, err = repo.FindByID(ctx, task.ID, userID)
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+
     var result models.Task
     err = db.Collection("tasks").FindOne(ctx, bson.M{"_id": task.ID}).Decode(&result)
-    assert.Error(t, err) // Should not exist
+    assert.NoError(t, err)
+    assert.NotNil(t, result.DeletedAt)
   })
 
   t.Run("should return error when task not found", func(t *testing.T) {
@@ -502,7 +603,7 @@ func TestTaskRepository_Delete(t *testing.T) {
     repo := NewTaskRepository(db)
     ctx := context.Background()
 
-    err := repo.Delete(ctx, bson.NewObjectID(), bson.NewObjectID())
+    err := repo.Trash(ctx, bson.NewObjectID(), bson.NewObjectID(), time.Now())
 
     assert.Error(t, err)
     assert.Equal(t, "task not found", err.Error())
@@ -527,7 +628,7 @@ func TestTaskRepository_Delete(t *testing.T) {
     db.Collection("tasks").InsertOne(ctx, task)
 
     // Try delete as different user
-    err := repo.Delete(ctx, task.ID, otherUserID)
+    err := repo.Trash(ctx, task.ID, otherUserID, time.Now())
 
     assert.Error(t, err)
     assert.Equal(t, "task not found", err.Error())
@@ -537,5 +638,514 @@ func TestTaskRepository_Delete(t *testing.T) {
     err = db.Collection("tasks").FindOne(ctx, bson.M{"_id": task.ID}).Decode(&result)
     assert.NoError(t, err)
     assert.Equal(t, "Protected Task", result.Title)
+    assert.Nil(t, result.DeletedAt)
+  })
+}
+
+func TestTaskRepository_Subtasks(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should find children and roll up their progress", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    parent := models.Task{ID: bson.NewObjectID(), UserID: userID, Title: "Parent"}
+    done := models.Task{ID: bson.NewObjectID(), UserID: userID, ParentID: &parent.ID, Status: types.TaskStatusCompleted, CreatedAt: time.Now()}
+    open := models.Task{ID: bson.NewObjectID(), UserID: userID, ParentID: &parent.ID, Status: types.TaskStatusPending, CreatedAt: time.Now().Add(time.Second)}
+    grandchild := models.Task{ID: bson.NewObjectID(), UserID: userID, ParentID: &open.ID, Status: types.TaskStatusPending}
+    db.Collection("tasks").InsertMany(ctx, []any{parent, done, open, grandchild})
+
+    children, err := repo.FindChildren(ctx, parent.ID)
+    assert.NoError(t, err)
+    assert.Len(t, children, 2)
+    assert.Equal(t, done.ID, children[0].ID)
+
+    ids, err := repo.FindChildIDs(ctx, []bson.ObjectID{parent.ID, open.ID})
+    assert.NoError(t, err)
+    assert.ElementsMatch(t, []bson.ObjectID{done.ID, open.ID, grandchild.ID}, ids)
+
+    progress, err := repo.ChildProgress(ctx, []bson.ObjectID{parent.ID, done.ID})
+    assert.NoError(t, err)
+    assert.Equal(t, types.NewTaskProgress(2, 1), progress[parent.ID])
+    _, ok := progress[done.ID]
+    assert.False(t, ok)
+  })
+
+  t.Run("should delete tasks by IDs", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    keep := models.Task{ID: bson.NewObjectID(), UserID: bson.NewObjectID()}
+    drop := models.Task{ID: bson.NewObjectID(), UserID: bson.NewObjectID()}
+    db.Collection("tasks").InsertMany(ctx, []any{keep, drop})
+
+    err := repo.DeleteByIDs(ctx, []bson.ObjectID{drop.ID})
+    assert.NoError(t, err)
+
+    count, _ := db.Collection("tasks").CountDocuments(ctx, bson.M{})
+    assert.Equal(t, int64(1), count)
+  })
+}
+
+func TestTaskRepository_Blockers(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should link, find and pull blockers", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    blocker := models.Task{ID: bson.NewObjectID(), UserID: userID}
+    task := models.Task{ID: bson.NewObjectID(), UserID: userID}
+    db.Collection("tasks").InsertMany(ctx, []any{blocker, task})
+
+    err := repo.AddBlocker(ctx, task.ID, blocker.ID)
+    assert.NoError(t, err)
+
+    blocked, err := repo.FindBlockedBy(ctx, []bson.ObjectID{blocker.ID})
+    assert.NoError(t, err)
+    assert.Len(t, blocked, 1)
+    assert.Equal(t, task.ID, blocked[0].ID)
+
+    err = repo.PullBlockers(ctx, []bson.ObjectID{blocker.ID})
+    assert.NoError(t, err)
+
+    var result models.Task
+    db.Collection("tasks").FindOne(ctx, bson.M{"_id": task.ID}).Decode(&result)
+    assert.Empty(t, result.BlockedBy)
+
+    err = repo.RemoveBlocker(ctx, bson.NewObjectID(), blocker.ID)
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+  })
+}
+
+func TestTaskRepository_PullWorkspaceMember(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should drop the member from tasks of that workspace only", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    ownerID, memberID := bson.NewObjectID(), bson.NewObjectID()
+    workspaceID, otherID := bson.NewObjectID(), bson.NewObjectID()
+    task := models.Task{ID: bson.NewObjectID(), UserID: ownerID, WorkspaceID: &workspaceID, AssigneeIDs: []bson.ObjectID{memberID}, WatcherIDs: []bson.ObjectID{memberID, ownerID}}
+    other := models.Task{ID: bson.NewObjectID(), UserID: ownerID, WorkspaceID: &otherID, AssigneeIDs: []bson.ObjectID{memberID}}
+    db.Collection("tasks").InsertMany(ctx, []any{task, other})
+
+    err := repo.PullWorkspaceMember(ctx, workspaceID, memberID)
+    assert.NoError(t, err)
+
+    var result models.Task
+    db.Collection("tasks").FindOne(ctx, bson.M{"_id": task.ID}).Decode(&result)
+    assert.Empty(t, result.AssigneeIDs)
+    assert.Equal(t, []bson.ObjectID{ownerID}, result.WatcherIDs)
+    assert.Equal(t, int64(1), result.Version)
+
+    db.Collection("tasks").FindOne(ctx, bson.M{"_id": other.ID}).Decode(&result)
+    assert.Equal(t, []bson.ObjectID{memberID}, result.AssigneeIDs)
+  })
+}
+
+func TestTaskRepository_Checklist(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should add, update, reorder and remove items", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    task := models.Task{ID: bson.NewObjectID(), UserID: bson.NewObjectID()}
+    db.Collection("tasks").InsertOne(ctx, task)
+
+    first := models.ChecklistItem{ID: bson.NewObjectID(), Text: "First", Order: 0}
+    second := models.ChecklistItem{ID: bson.NewObjectID(), Text: "Second", Order: 1}
+    assert.NoError(t, repo.AddChecklistItem(ctx, task.ID, first, 2))
+    assert.NoError(t, repo.AddChecklistItem(ctx, task.ID, second, 2))
+
+    // A third item does not fit in a checklist of two
+    err := repo.AddChecklistItem(ctx, task.ID, models.ChecklistItem{ID: bson.NewObjectID(), Text: "Third"}, 2)
+    assert.ErrorIs(t, err, ErrChecklistFull)
+
+    err = repo.UpdateChecklistItem(ctx, task.ID, second.ID, bson.M{"done": true})
+    assert.NoError(t, err)
+
+    err = repo.ReorderChecklist(ctx, task.ID, []bson.ObjectID{second.ID, first.ID})
+    assert.NoError(t, err)
+
+    var result models.Task
+    db.Collection("tasks").FindOne(ctx, bson.M{"_id": task.ID}).Decode(&result)
+    assert.Len(t, result.Checklist, 2)
+    assert.Equal(t, 1, result.Checklist[0].Order)
+    assert.Equal(t, 0, result.Checklist[1].Order)
+    assert.True(t, result.Checklist[1].Done)
+
+    // Reorder must list every item
+    err = repo.ReorderChecklist(ctx, task.ID, []bson.ObjectID{first.ID})
+    assert.ErrorIs(t, err, ErrChecklistMismatch)
+
+    err = repo.RemoveChecklistItem(ctx, task.ID, first.ID)
+    assert.NoError(t, err)
+
+    err = repo.RemoveChecklistItem(ctx, task.ID, first.ID)
+    assert.ErrorIs(t, err, ErrChecklistItemNotFound)
+
+    err = repo.UpdateChecklistItem(ctx, task.ID, first.ID, bson.M{"done": true})
+    assert.ErrorIs(t, err, ErrChecklistItemNotFound)
+  })
+
+  t.Run("should filter tasks by checklist state", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    open := models.Task{ID: bson.NewObjectID(), UserID: userID, Checklist: []models.ChecklistItem{{ID: bson.NewObjectID(), Done: true}, {ID: bson.NewObjectID()}}}
+    done := models.Task{ID: bson.NewObjectID(), UserID: userID, Checklist: []models.ChecklistItem{{ID: bson.NewObjectID(), Done: true}}}
+    plain := models.Task{ID: bson.NewObjectID(), UserID: userID}
+    db.Collection("tasks").InsertMany(ctx, []any{open, done, plain})
+
+    tasks, total, err := repo.FindByUserID(ctx, userID, types.TaskQueryParams{Page: 1, Limit: 10, Checklist: types.ChecklistOpen})
+    assert.NoError(t, err)
+    assert.Equal(t, int64(1), total)
+    assert.Equal(t, open.ID, tasks[0].ID)
+
+    tasks, total, err = repo.FindByUserID(ctx, userID, types.TaskQueryParams{Page: 1, Limit: 10, Checklist: types.ChecklistDone})
+    assert.NoError(t, err)
+    assert.Equal(t, int64(1), total)
+    assert.Equal(t, done.ID, tasks[0].ID)
+  })
+}
+
+func TestTaskRepository_Attachments(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should add and remove attachment metadata", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    task := models.Task{ID: bson.NewObjectID(), UserID: bson.NewObjectID()}
+    db.Collection("tasks").InsertOne(ctx, task)
+
+    attachment := models.Attachment{ID: bson.NewObjectID(), Name: "photo.png", ContentType: "image/png", Size: 42}
+    assert.NoError(t, repo.AddAttachment(ctx, task.ID, attachment, 1))
+
+    // Full at one attachment
+    err := repo.AddAttachment(ctx, task.ID, models.Attachment{ID: bson.NewObjectID(), Name: "other.png"}, 1)
+    assert.ErrorIs(t, err, ErrTooManyAttachments)
+
+    var result models.Task
+    db.Collection("tasks").FindOne(ctx, bson.M{"_id": task.ID}).Decode(&result)
+    assert.Len(t, result.Attachments, 1)
+    assert.Equal(t, "photo.png", result.Attachments[0].Name)
+
+    assert.NoError(t, repo.RemoveAttachment(ctx, task.ID, attachment.ID))
+    assert.ErrorIs(t, repo.RemoveAttachment(ctx, task.ID, attachment.ID), ErrAttachmentNotFound)
+  })
+}
+
+func TestTaskRepository_Trash(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should hide trashed tasks until restored", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    parent := models.Task{ID: bson.NewObjectID(), UserID: userID, Title: "Parent"}
+    child := models.Task{ID: bson.NewObjectID(), UserID: userID, ParentID: &parent.ID, Title: "Child"}
+    db.Collection("tasks").InsertMany(ctx, []interface{}{parent, child})
+
+    now := time.Now()
+    assert.NoError(t, repo.Trash(ctx, parent.ID, userID, now))
+    assert.NoError(t, repo.TrashByIDs(ctx, []bson.ObjectID{child.ID}, now, &parent.ID))
+    assert.ErrorIs(t, repo.Trash(ctx, parent.ID, userID, now), ErrTaskNotFound)
+
+    _, err := repo.FindByID(ctx, parent.ID, userID)
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+    _, total, _ := repo.FindByUserID(ctx, userID, types.TaskQueryParams{})
+    assert.Equal(t, int64(0), total)
+    found, _ := repo.FindByIDs(ctx, []bson.ObjectID{parent.ID, child.ID})
+    assert.Empty(t, found)
+
+    // Only the deleted task is listed, its subtask comes with it
+    trash, total, err := repo.FindTrash(ctx, userID, 1, 10)
+    assert.NoError(t, err)
+    assert.Equal(t, int64(1), total)
+    assert.Equal(t, parent.ID, trash[0].ID)
+    with, _ := repo.FindTrashedWith(ctx, parent.ID)
+    assert.Len(t, with, 1)
+
+    expired, _ := repo.FindExpired(ctx, now.Add(time.Second), 10)
+    assert.Len(t, expired, 2)
+    expired, _ = repo.FindExpired(ctx, now.Add(-time.Second), 10)
+    assert.Empty(t, expired)
+
+    assert.NoError(t, repo.Restore(ctx, parent.ID))
+    result, err := repo.FindByID(ctx, child.ID, userID)
+    assert.NoError(t, err)
+    assert.Nil(t, result.DeletedAt)
+    assert.Nil(t, result.DeletedWith)
+    assert.ErrorIs(t, repo.Restore(ctx, parent.ID), ErrTaskNotFound)
+  })
+}
+
+func TestTaskRepository_Archive(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should leave archived tasks out of the list by default", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    now := time.Now()
+    live := models.Task{ID: bson.NewObjectID(), UserID: userID, Title: "Live"}
+    archived := models.Task{ID: bson.NewObjectID(), UserID: userID, Title: "Archived", ArchivedAt: &now}
+    db.Collection("tasks").InsertMany(ctx, []interface{}{live, archived})
+
+    tasks, _, _ := repo.FindByUserID(ctx, userID, types.TaskQueryParams{})
+    assert.Len(t, tasks, 1)
+    assert.Equal(t, live.ID, tasks[0].ID)
+
+    tasks, _, _ = repo.FindByUserID(ctx, userID, types.TaskQueryParams{Archived: types.ArchivedOnly})
+    assert.Len(t, tasks, 1)
+    assert.Equal(t, archived.ID, tasks[0].ID)
+
+    _, total, _ := repo.FindByUserID(ctx, userID, types.TaskQueryParams{Archived: types.ArchivedAll})
+    assert.Equal(t, int64(2), total)
+
+    // Unarchived tasks hold null, which counts as not archived
+    assert.NoError(t, repo.UpdateByID(ctx, archived.ID, bson.M{"archived_at": nil}))
+    _, total, _ = repo.FindByUserID(ctx, userID, types.TaskQueryParams{})
+    assert.Equal(t, int64(2), total)
+  })
+
+  t.Run("should archive tasks completed before the cutoff", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    now := time.Now()
+    old := now.Add(-40 * 24 * time.Hour)
+    recent := now.Add(-time.Hour)
+    expired := models.Task{ID: bson.NewObjectID(), UserID: userID, Status: types.TaskStatusCompleted, CompletedAt: &old, UpdatedAt: old}
+    fresh := models.Task{ID: bson.NewObjectID(), UserID: userID, Status: types.TaskStatusCompleted, CompletedAt: &recent, UpdatedAt: recent}
+    touched := models.Task{ID: bson.NewObjectID(), UserID: userID, Status: types.TaskStatusCompleted, CompletedAt: &old, UpdatedAt: recent}
+    unarchived := models.Task{ID: bson.NewObjectID(), UserID: userID, Status: types.TaskStatusCompleted, CompletedAt: &old, UnarchivedAt: &recent}
+    pending := models.Task{ID: bson.NewObjectID(), UserID: userID, Status: types.TaskStatusPending, UpdatedAt: old}
+    db.Collection("tasks").InsertMany(ctx, []interface{}{expired, fresh, touched, unarchived, pending})
+
+    archived, err := repo.ArchiveCompleted(ctx, now.Add(-30*24*time.Hour), now)
+    assert.NoError(t, err)
+    assert.Equal(t, int64(2), archived)
+
+    // Later writes do not keep a completed task in the list
+    result, _ := repo.FindByID(ctx, expired.ID, userID)
+    assert.NotNil(t, result.ArchivedAt)
+    result, _ = repo.FindByID(ctx, touched.ID, userID)
+    assert.NotNil(t, result.ArchivedAt)
+    result, _ = repo.FindByID(ctx, unarchived.ID, userID)
+    assert.Nil(t, result.ArchivedAt)
+
+    archived, _ = repo.ArchiveCompleted(ctx, now.Add(-30*24*time.Hour), now)
+    assert.Zero(t, archived)
+  })
+}
+
+func TestTaskRepository_BulkUpdate(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should apply writes to live tasks", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    now := time.Now()
+    live := models.Task{ID: bson.NewObjectID(), UserID: userID, Title: "Live", Priority: types.TaskPriorityLow}
+    trashed := models.Task{ID: bson.NewObjectID(), UserID: userID, Title: "Trashed", Priority: types.TaskPriorityLow, DeletedAt: &now}
+    db.Collection("tasks").InsertMany(ctx, []interface{}{live, trashed})
+
+    errs, err := repo.BulkUpdate(ctx, []TaskWrite{
+      {ID: live.ID, Set: bson.M{"priority": types.TaskPriorityHigh}},
+      {ID: trashed.ID, Set: bson.M{"priority": types.TaskPriorityHigh}},
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, []error{nil, ErrVersionConflict}, errs)
+
+    result, _ := repo.FindByID(ctx, live.ID, userID)
+    assert.Equal(t, types.TaskPriorityHigh, result.Priority)
+    assert.False(t, result.UpdatedAt.IsZero())
+
+    // Tasks in the trash are left alone
+    result, _ = repo.FindOwned(ctx, trashed.ID, userID)
+    assert.Equal(t, types.TaskPriorityLow, result.Priority)
+
+    errs, err = repo.BulkUpdate(ctx, nil)
+    assert.NoError(t, err)
+    assert.Empty(t, errs)
+  })
+
+  t.Run("should report tasks changed or trashed since they were read", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    current := &models.Task{ID: bson.NewObjectID(), UserID: userID, Priority: types.TaskPriorityLow}
+    changed := &models.Task{ID: bson.NewObjectID(), UserID: userID, Priority: types.TaskPriorityLow}
+    trashed := &models.Task{ID: bson.NewObjectID(), UserID: userID, Priority: types.TaskPriorityLow}
+    for _, task := range []*models.Task{current, changed, trashed} {
+      assert.NoError(t, repo.Create(ctx, task))
+    }
+    // Another writer moved it to version 2, where the bulk write would have left it
+    assert.NoError(t, repo.Update(ctx, changed.ID, userID, bson.M{"title": "Elsewhere"}))
+    assert.NoError(t, repo.TrashByIDs(ctx, []bson.ObjectID{trashed.ID}, time.Now(), nil))
+
+    set := bson.M{"priority": types.TaskPriorityHigh}
+    errs, err := repo.BulkUpdate(ctx, []TaskWrite{
+      {ID: current.ID, Version: 1, Set: set},
+      {ID: changed.ID, Version: 1, Set: set},
+      {ID: trashed.ID, Version: 1, Set: set},
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, []error{nil, ErrVersionConflict, ErrVersionConflict}, errs)
+
+    result, _ := repo.FindByID(ctx, changed.ID, userID)
+    assert.Equal(t, types.TaskPriorityLow, result.Priority)
+  })
+
+  t.Run("should write a task once per call", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Priority: types.TaskPriorityLow}
+    assert.NoError(t, repo.Create(ctx, task))
+
+    errs, err := repo.BulkUpdate(ctx, []TaskWrite{
+      {ID: task.ID, Version: 1, Set: bson.M{"priority": types.TaskPriorityHigh}},
+      {ID: task.ID, Version: 1, Set: bson.M{"title": "Twice"}},
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, []error{nil, ErrVersionConflict}, errs)
+
+    result, _ := repo.FindByID(ctx, task.ID, userID)
+    assert.Equal(t, types.TaskPriorityHigh, result.Priority)
+    assert.NotEqual(t, "Twice", result.Title)
+    assert.Equal(t, int64(2), result.Version)
+  })
+}
+
+func TestTaskRepository_SetIfVersion(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should write only at the expected version", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Title: "First"}
+    assert.NoError(t, repo.Create(ctx, task))
+    assert.Equal(t, int64(1), task.Version)
+
+    // Every write moves the version on
+    assert.NoError(t, repo.Update(ctx, task.ID, userID, bson.M{"title": "Second"}))
+    assert.NoError(t, repo.AddWatcher(ctx, task.ID, userID))
+
+    err := repo.SetIfVersion(ctx, task.ID, 1, bson.M{"title": "Stale"})
+    assert.ErrorIs(t, err, ErrVersionConflict)
+
+    assert.NoError(t, repo.SetIfVersion(ctx, task.ID, 3, bson.M{"title": "Third"}))
+    result, _ := repo.FindByID(ctx, task.ID, userID)
+    assert.Equal(t, "Third", result.Title)
+    assert.Equal(t, int64(4), result.Version)
+  })
+
+  t.Run("should treat tasks without a version as version 0", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskRepository(db)
+    ctx := context.Background()
+
+    userID := bson.NewObjectID()
+    taskID := bson.NewObjectID()
+    db.Collection("tasks").InsertOne(ctx, bson.M{"_id": taskID, "user_id": userID, "title": "Old"})
+
+    assert.NoError(t, repo.SetIfVersion(ctx, taskID, 0, bson.M{"title": "New"}))
+    result, _ := repo.FindByID(ctx, taskID, userID)
+    assert.Equal(t, int64(1), result.Version)
   })
 }
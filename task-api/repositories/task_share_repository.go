@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"task-api/models"
+)
+
+var ErrTaskShareNotFound = errors.New("task share not found")
+
+// TaskShareRepository - interface for per-task shares
+type TaskShareRepository interface {
+  Upsert(ctx context.Context, share *models.TaskShare) error
+  FindByTaskAndUser(ctx context.Context, taskID, userID bson.ObjectID) (*models.TaskShare, error)
+  FindByTask(ctx context.Context, taskID bson.ObjectID) ([]models.TaskShare, error)
+  FindByUser(ctx context.Context, userID bson.ObjectID, page, limit int) ([]models.TaskShare, int64, error)
+  Delete(ctx context.Context, taskID, userID bson.ObjectID) error
+  DeleteByTask(ctx context.Context, taskID bson.ObjectID) error
+}
+
+// taskShareRepository - implement TaskShareRepository
+type taskShareRepository struct {
+  collection *mongo.Collection
+}
+
+// NewTaskShareRepository - constructor
+func NewTaskShareRepository(db *mongo.Database) TaskShareRepository {
+  return &taskShareRepository{
+    collection: db.Collection("task_shares"),
+  }
+}
+
+// Upsert - grant share, sharing again with the same user changes the permission
+func (r *taskShareRepository) Upsert(ctx context.Context, share *models.TaskShare) error {
+  now := time.Now()
+  filter := bson.M{"task_id": share.TaskID, "user_id": share.UserID}
+  update := bson.M{
+    "$set": bson.M{
+      "permission": share.Permission,
+      "shared_by":  share.SharedBy,
+      "updated_at": now,
+    },
+    "$setOnInsert": bson.M{
+      "_id":        bson.NewObjectID(),
+      "created_at": now,
+    },
+  }
+  opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+  return r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(share)
+}
+
+// FindByTaskAndUser - share of task with user
+func (r *taskShareRepository) FindByTaskAndUser(ctx context.Context, taskID, userID bson.ObjectID) (*models.TaskShare, error) {
+  var share models.TaskShare
+
+  err := r.collection.FindOne(ctx, bson.M{"task_id": taskID, "user_id": userID}).Decode(&share)
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrTaskShareNotFound
+    }
+    return nil, err
+  }
+
+  return &share, nil
+}
+
+// FindByTask - shares of task, oldest first
+func (r *taskShareRepository) FindByTask(ctx context.Context, taskID bson.ObjectID) ([]models.TaskShare, error) {
+  opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+  cursor, err := r.collection.Find(ctx, bson.M{"task_id": taskID}, opts)
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  shares := []models.TaskShare{}
+  if err = cursor.All(ctx, &shares); err != nil {
+    return nil, err
+  }
+
+  return shares, nil
+}
+
+// FindByUser - shares granted to user, newest first
+func (r *taskShareRepository) FindByUser(ctx context.Context, userID bson.ObjectID, page, limit int) ([]models.TaskShare, int64, error) {
+  filter := bson.M{"user_id": userID}
+
+  total, err := r.collection.CountDocuments(ctx, filter)
+  if err != nil {
+    return nil, 0, err
+  }
+
+  if page < 1 {
+    page = 1
+  }
+  if limit < 1 {
+    limit = 10
+  }
+
+  opts := options.Find().
+    SetSort(bson.D{{Key: "created_at", Value: -1}}).
+    SetSkip(int64((page - 1) * limit)).
+    SetLimit(int64(limit))
+
+  cursor, err := r.collection.Find(ctx, filter, opts)
+  if err != nil {
+    return nil, 0, err
+  }
+  defer cursor.Close(ctx)
+
+  shares := []models.TaskShare{}
+  if err = cursor.All(ctx, &shares); err != nil {
+    return nil, 0, err
+  }
+
+  return shares, total, nil
+}
+
+// Delete - revoke share of task with user
+func (r *taskShareRepository) Delete(ctx context.Context, taskID, userID bson.ObjectID) error {
+  result, err := r.collection.DeleteOne(ctx, bson.M{"task_id": taskID, "user_id": userID})
+  if err != nil {
+    return err
+  }
+
+  if result.DeletedCount == 0 {
+    return ErrTaskShareNotFound
+  }
+
+  return nil
+}
+
+// DeleteByTask - remove every share of a deleted task
+func (r *taskShareRepository) DeleteByTask(ctx context.Context, taskID bson.ObjectID) error {
+  _, err := r.collection.DeleteMany(ctx, bson.M{"task_id": taskID})
+  return err
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/types"
+)
+
+func TestTaskShareRepository(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should upsert, list and revoke shares", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewTaskShareRepository(db)
+    ctx := context.Background()
+
+    ownerID, userID, taskID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+
+    share := &models.TaskShare{TaskID: taskID, UserID: userID, Permission: types.SharePermissionView, SharedBy: ownerID}
+    assert.NoError(t, repo.Upsert(ctx, share))
+    assert.False(t, share.ID.IsZero())
+    assert.False(t, share.CreatedAt.IsZero())
+
+    // Sharing again changes the permission of the same share
+    again := &models.TaskShare{TaskID: taskID, UserID: userID, Permission: types.SharePermissionEdit, SharedBy: ownerID}
+    assert.NoError(t, repo.Upsert(ctx, again))
+    assert.Equal(t, share.ID, again.ID)
+
+    found, err := repo.FindByTaskAndUser(ctx, taskID, userID)
+    assert.NoError(t, err)
+    assert.Equal(t, types.SharePermissionEdit, found.Permission)
+
+    shares, _ := repo.FindByTask(ctx, taskID)
+    assert.Len(t, shares, 1)
+
+    assert.NoError(t, repo.Upsert(ctx, &models.TaskShare{TaskID: bson.NewObjectID(), UserID: userID, Permission: types.SharePermissionView, SharedBy: ownerID}))
+    shares, total, err := repo.FindByUser(ctx, userID, 1, 1)
+    assert.NoError(t, err)
+    assert.Equal(t, int64(2), total)
+    assert.Len(t, shares, 1)
+
+    assert.NoError(t, repo.Delete(ctx, taskID, userID))
+    assert.ErrorIs(t, repo.Delete(ctx, taskID, userID), ErrTaskShareNotFound)
+
+    _, err = repo.FindByTaskAndUser(ctx, taskID, userID)
+    assert.ErrorIs(t, err, ErrTaskShareNotFound)
+  })
+}
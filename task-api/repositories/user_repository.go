@@ -3,16 +3,36 @@ package repositories
 import (
 	"context"
 	"errors"
+	"regexp"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 
 	"task-api/models"
+	"task-api/types"
 )
 
+// ErrUserNotFound - no user matches the query
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrEmailAlreadyExists - returned when the email_unique index rejects an insert
+var ErrEmailAlreadyExists = errors.New("email already exists")
+
 // UserRepository - interface for user repository
 type UserRepository interface {
   FindByEmail(ctx context.Context, email string) (*models.User, error)
+  FindByID(ctx context.Context, id bson.ObjectID) (*models.User, error)
+  FindByOIDCSubject(ctx context.Context, issuer, subject string) (*models.User, error)
+  ExistsByEmail(ctx context.Context, email string) (bool, error)
+  Create(ctx context.Context, user *models.User) error
+  Update(ctx context.Context, id bson.ObjectID, updates bson.M) error
+  UpdatePassword(ctx context.Context, id bson.ObjectID, hashedPassword string) error
+  UseTOTPStep(ctx context.Context, id bson.ObjectID, step int64) (bool, error)
+  ConsumeRecoveryCode(ctx context.Context, id bson.ObjectID, codeHash string) (bool, error)
+  LinkOIDCIdentity(ctx context.Context, id bson.ObjectID, issuer, subject string) (bool, error)
+  List(ctx context.Context, query types.UserQueryParams) ([]models.User, int64, error)
 }
 
 // userRepository - implement UserRepository
@@ -38,10 +58,226 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models
   
   if err != nil {
     if err == mongo.ErrNoDocuments {
-      return nil, errors.New("user not found")
+      return nil, ErrUserNotFound
     }
     return nil, err
   }
   
   return &user, nil
 }
+
+// FindByID - find user by ID
+func (r *userRepository) FindByID(ctx context.Context, id bson.ObjectID) (*models.User, error) {
+  var user models.User
+
+  filter := bson.M{"_id": id}
+  err := r.collection.FindOne(ctx, filter).Decode(&user)
+
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrUserNotFound
+    }
+    return nil, err
+  }
+
+  return &user, nil
+}
+
+// FindByOIDCSubject - find user linked to an SSO identity
+func (r *userRepository) FindByOIDCSubject(ctx context.Context, issuer, subject string) (*models.User, error) {
+  var user models.User
+
+  filter := bson.M{"oidc_issuer": issuer, "oidc_subject": subject}
+  err := r.collection.FindOne(ctx, filter).Decode(&user)
+
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrUserNotFound
+    }
+    return nil, err
+  }
+
+  return &user, nil
+}
+
+// ExistsByEmail - check whether an account already uses this email
+func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+  filter := bson.M{"email": email}
+
+  count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+  if err != nil {
+    return false, err
+  }
+
+  return count > 0, nil
+}
+
+// Create - insert new user
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+  _, err := r.collection.InsertOne(ctx, user)
+  if err != nil {
+    // email_unique index violation (e.g. two concurrent registrations)
+    if mongo.IsDuplicateKeyError(err) {
+      return ErrEmailAlreadyExists
+    }
+    return err
+  }
+
+  return nil
+}
+
+// Update - update user fields
+func (r *userRepository) Update(ctx context.Context, id bson.ObjectID, updates bson.M) error {
+  filter := bson.M{"_id": id}
+
+  updates["updated_at"] = time.Now()
+
+  update := bson.M{"$set": updates}
+
+  result, err := r.collection.UpdateOne(ctx, filter, update)
+  if err != nil {
+    return err
+  }
+
+  if result.MatchedCount == 0 {
+    return ErrUserNotFound
+  }
+
+  return nil
+}
+
+// UpdatePassword - replace password hash of user
+func (r *userRepository) UpdatePassword(ctx context.Context, id bson.ObjectID, hashedPassword string) error {
+  filter := bson.M{"_id": id}
+  update := bson.M{
+    "$set": bson.M{
+      "password":   hashedPassword,
+      "updated_at": time.Now(),
+    },
+  }
+
+  result, err := r.collection.UpdateOne(ctx, filter, update)
+  if err != nil {
+    return err
+  }
+
+  if result.MatchedCount == 0 {
+    return ErrUserNotFound
+  }
+
+  return nil
+}
+
+// UseTOTPStep - record step as used, false when it (or a later one) was already used
+func (r *userRepository) UseTOTPStep(ctx context.Context, id bson.ObjectID, step int64) (bool, error) {
+  filter := bson.M{
+    "_id": id,
+    "$or": bson.A{
+      bson.M{"totp_last_step": bson.M{"$lt": step}},
+      bson.M{"totp_last_step": bson.M{"$exists": false}},
+    },
+  }
+  update := bson.M{"$set": bson.M{"totp_last_step": step}}
+
+  result, err := r.collection.UpdateOne(ctx, filter, update)
+  if err != nil {
+    return false, err
+  }
+
+  return result.ModifiedCount > 0, nil
+}
+
+// ConsumeRecoveryCode - atomically remove code hash, false when it is not (or no longer) there
+func (r *userRepository) ConsumeRecoveryCode(ctx context.Context, id bson.ObjectID, codeHash string) (bool, error) {
+  filter := bson.M{"_id": id, "recovery_codes": codeHash}
+  update := bson.M{
+    "$pull": bson.M{"recovery_codes": codeHash},
+    "$set":  bson.M{"updated_at": time.Now()},
+  }
+
+  result, err := r.collection.UpdateOne(ctx, filter, update)
+  if err != nil {
+    return false, err
+  }
+
+  return result.ModifiedCount > 0, nil
+}
+
+// LinkOIDCIdentity - link SSO identity to user, false when the user is already linked
+func (r *userRepository) LinkOIDCIdentity(ctx context.Context, id bson.ObjectID, issuer, subject string) (bool, error) {
+  filter := bson.M{"_id": id, "oidc_subject": bson.M{"$exists": false}}
+  update := bson.M{
+    "$set": bson.M{
+      "oidc_issuer":  issuer,
+      "oidc_subject": subject,
+      "updated_at":   time.Now(),
+    },
+  }
+
+  result, err := r.collection.UpdateOne(ctx, filter, update)
+  if err != nil {
+    return false, err
+  }
+
+  return result.ModifiedCount > 0, nil
+}
+
+// List - find users with filters, newest first
+func (r *userRepository) List(ctx context.Context, query types.UserQueryParams) ([]models.User, int64, error) {
+  filter := bson.M{}
+
+  // Accounts created before roles existed have no role and count as members
+  if query.Role == types.RoleMember {
+    filter["role"] = bson.M{"$in": bson.A{types.RoleMember, "", nil}}
+  } else if query.Role != "" {
+    filter["role"] = query.Role
+  }
+
+  switch query.Status {
+  case "active":
+    filter["disabled_at"] = nil
+  case "disabled":
+    filter["disabled_at"] = bson.M{"$ne": nil}
+  }
+
+  if query.Search != "" {
+    pattern := regexp.QuoteMeta(query.Search)
+    filter["$or"] = []bson.M{
+      {"email": bson.M{"$regex": pattern, "$options": "i"}},
+      {"name": bson.M{"$regex": pattern, "$options": "i"}},
+    }
+  }
+
+  total, err := r.collection.CountDocuments(ctx, filter)
+  if err != nil {
+    return nil, 0, err
+  }
+
+  page := 1
+  if query.Page > 0 {
+    page = query.Page
+  }
+
+  limit := 10
+  if query.Limit > 0 {
+    limit = query.Limit
+  }
+
+  opts := options.Find().
+    SetSort(bson.D{{Key: "created_at", Value: -1}}).
+    SetSkip(int64((page - 1) * limit)).
+    SetLimit(int64(limit))
+
+  cursor, err := r.collection.Find(ctx, filter, opts)
+  if err != nil {
+    return nil, 0, err
+  }
+  defer cursor.Close(ctx)
+
+  var users []models.User
+  if err = cursor.All(ctx, &users); err != nil {
+    return nil, 0, err
+  }
+
+  return users, total, nil
+}
@@ -12,6 +12,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 
 	"task-api/models"
+	"task-api/types"
 )
 
 // setupTestDB creates an in-memory MongoDB instance for testing
@@ -117,3 +118,309 @@ func TestUserRepository_FindByEmail(t *testing.T) {
     assert.Nil(t, result)
   })
 }
+
+func TestUserRepository_FindByID(t *testing.T) {
+  t.Run("should find user by ID", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+    ctx := context.Background()
+
+    testUser := models.User{
+      ID:    bson.NewObjectID(),
+      Email: "id@test.com",
+      Name:  "By ID",
+    }
+    db.Collection("users").InsertOne(ctx, testUser)
+
+    result, err := repo.FindByID(ctx, testUser.ID)
+
+    assert.NoError(t, err)
+    assert.Equal(t, "id@test.com", result.Email)
+  })
+
+  t.Run("should return ErrUserNotFound", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+
+    result, err := repo.FindByID(context.Background(), bson.NewObjectID())
+
+    assert.ErrorIs(t, err, ErrUserNotFound)
+    assert.Nil(t, result)
+  })
+}
+
+func TestUserRepository_ExistsByEmail(t *testing.T) {
+  t.Run("should return true when email is taken", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+    ctx := context.Background()
+
+    db.Collection("users").InsertOne(ctx, models.User{
+      ID:    bson.NewObjectID(),
+      Email: "taken@test.com",
+      Name:  "Taken",
+    })
+
+    exists, err := repo.ExistsByEmail(ctx, "taken@test.com")
+
+    assert.NoError(t, err)
+    assert.True(t, exists)
+  })
+
+  t.Run("should return false when email is free", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+
+    exists, err := repo.ExistsByEmail(context.Background(), "free@test.com")
+
+    assert.NoError(t, err)
+    assert.False(t, exists)
+  })
+}
+
+func TestUserRepository_Create(t *testing.T) {
+  t.Run("should create user", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+    ctx := context.Background()
+
+    user := &models.User{
+      ID:        bson.NewObjectID(),
+      Email:     "new@test.com",
+      Name:      "New User",
+      Password:  "hashedpassword",
+      CreatedAt: time.Now(),
+      UpdatedAt: time.Now(),
+    }
+
+    err := repo.Create(ctx, user)
+    assert.NoError(t, err)
+
+    result, err := repo.FindByEmail(ctx, "new@test.com")
+    assert.NoError(t, err)
+    assert.Equal(t, "New User", result.Name)
+  })
+
+  t.Run("should map unique index violation to ErrEmailAlreadyExists", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+    ctx := context.Background()
+
+    _, err := db.Collection("users").Indexes().CreateOne(ctx, mongo.IndexModel{
+      Keys:    bson.D{{Key: "email", Value: 1}},
+      Options: options.Index().SetUnique(true).SetName("email_unique"),
+    })
+    assert.NoError(t, err)
+
+    first := &models.User{ID: bson.NewObjectID(), Email: "dup@test.com", Name: "First"}
+    second := &models.User{ID: bson.NewObjectID(), Email: "dup@test.com", Name: "Second"}
+
+    assert.NoError(t, repo.Create(ctx, first))
+    err = repo.Create(ctx, second)
+
+    assert.ErrorIs(t, err, ErrEmailAlreadyExists)
+  })
+}
+
+func TestUserRepository_Update(t *testing.T) {
+  t.Run("should update profile fields", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+    ctx := context.Background()
+
+    oldTime := time.Now().Add(-time.Hour)
+    testUser := models.User{ID: bson.NewObjectID(), Email: "me@test.com", Name: "Old", UpdatedAt: oldTime}
+    db.Collection("users").InsertOne(ctx, testUser)
+
+    err := repo.Update(ctx, testUser.ID, bson.M{"name": "New", "timezone": "Asia/Jakarta"})
+    assert.NoError(t, err)
+
+    result, _ := repo.FindByID(ctx, testUser.ID)
+    assert.Equal(t, "New", result.Name)
+    assert.Equal(t, "Asia/Jakarta", result.Timezone)
+    assert.True(t, result.UpdatedAt.After(oldTime))
+  })
+
+  t.Run("should return ErrUserNotFound", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+
+    err := repo.Update(context.Background(), bson.NewObjectID(), bson.M{"name": "New"})
+
+    assert.ErrorIs(t, err, ErrUserNotFound)
+  })
+}
+
+func TestUserRepository_UpdatePassword(t *testing.T) {
+  t.Run("should replace password hash", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+    ctx := context.Background()
+
+    testUser := models.User{ID: bson.NewObjectID(), Email: "pw@test.com", Password: "old-hash"}
+    db.Collection("users").InsertOne(ctx, testUser)
+
+    err := repo.UpdatePassword(ctx, testUser.ID, "new-hash")
+    assert.NoError(t, err)
+
+    result, _ := repo.FindByID(ctx, testUser.ID)
+    assert.Equal(t, "new-hash", result.Password)
+  })
+
+  t.Run("should return ErrUserNotFound", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+
+    err := repo.UpdatePassword(context.Background(), bson.NewObjectID(), "hash")
+
+    assert.ErrorIs(t, err, ErrUserNotFound)
+  })
+}
+
+func TestUserRepository_UseTOTPStep(t *testing.T) {
+  t.Run("should accept each step only once and never go backwards", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+    ctx := context.Background()
+
+    testUser := models.User{ID: bson.NewObjectID(), Email: "totp@test.com"}
+    db.Collection("users").InsertOne(ctx, testUser)
+
+    ok, err := repo.UseTOTPStep(ctx, testUser.ID, 100)
+    assert.NoError(t, err)
+    assert.True(t, ok)
+
+    ok, _ = repo.UseTOTPStep(ctx, testUser.ID, 100)
+    assert.False(t, ok)
+
+    ok, _ = repo.UseTOTPStep(ctx, testUser.ID, 99)
+    assert.False(t, ok)
+
+    ok, _ = repo.UseTOTPStep(ctx, testUser.ID, 101)
+    assert.True(t, ok)
+  })
+}
+
+func TestUserRepository_ConsumeRecoveryCode(t *testing.T) {
+  t.Run("should consume code once", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+    ctx := context.Background()
+
+    testUser := models.User{ID: bson.NewObjectID(), Email: "codes@test.com", RecoveryCodes: []string{"hash-1", "hash-2"}}
+    db.Collection("users").InsertOne(ctx, testUser)
+
+    ok, err := repo.ConsumeRecoveryCode(ctx, testUser.ID, "hash-1")
+    assert.NoError(t, err)
+    assert.True(t, ok)
+
+    ok, _ = repo.ConsumeRecoveryCode(ctx, testUser.ID, "hash-1")
+    assert.False(t, ok)
+
+    result, _ := repo.FindByID(ctx, testUser.ID)
+    assert.Equal(t, []string{"hash-2"}, result.RecoveryCodes)
+  })
+}
+
+func TestUserRepository_LinkOIDCIdentity(t *testing.T) {
+  t.Run("should link once and find user by identity", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+    ctx := context.Background()
+
+    testUser := models.User{ID: bson.NewObjectID(), Email: "sso@test.com"}
+    db.Collection("users").InsertOne(ctx, testUser)
+
+    ok, err := repo.LinkOIDCIdentity(ctx, testUser.ID, "https://idp.test", "sub-1")
+    assert.NoError(t, err)
+    assert.True(t, ok)
+
+    // Already linked identities are never replaced
+    ok, _ = repo.LinkOIDCIdentity(ctx, testUser.ID, "https://idp.test", "sub-2")
+    assert.False(t, ok)
+
+    result, err := repo.FindByOIDCSubject(ctx, "https://idp.test", "sub-1")
+    assert.NoError(t, err)
+    assert.Equal(t, testUser.ID, result.ID)
+
+    _, err = repo.FindByOIDCSubject(ctx, "https://idp.test", "sub-2")
+    assert.ErrorIs(t, err, ErrUserNotFound)
+  })
+}
+
+func TestUserRepository_List(t *testing.T) {
+  t.Run("should filter by role, status and search", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewUserRepository(db)
+    ctx := context.Background()
+
+    disabledAt := time.Now()
+    db.Collection("users").InsertMany(ctx, []interface{}{
+      models.User{ID: bson.NewObjectID(), Email: "admin@test.com", Name: "Admin", Role: types.RoleAdmin},
+      models.User{ID: bson.NewObjectID(), Email: "legacy@test.com", Name: "Legacy"},
+      models.User{ID: bson.NewObjectID(), Email: "viewer@test.com", Name: "Viewer", Role: types.RoleViewer, DisabledAt: &disabledAt},
+    })
+
+    users, total, err := repo.List(ctx, types.UserQueryParams{Role: types.RoleMember})
+    assert.NoError(t, err)
+    assert.Equal(t, int64(1), total)
+    assert.Equal(t, "legacy@test.com", users[0].Email)
+
+    users, total, _ = repo.List(ctx, types.UserQueryParams{Status: "disabled"})
+    assert.Equal(t, int64(1), total)
+    assert.Equal(t, "viewer@test.com", users[0].Email)
+
+    _, total, _ = repo.List(ctx, types.UserQueryParams{Status: "active"})
+    assert.Equal(t, int64(2), total)
+
+    // Search input is matched literally
+    users, total, _ = repo.List(ctx, types.UserQueryParams{Search: "ADMIN@"})
+    assert.Equal(t, int64(1), total)
+    assert.Equal(t, "admin@test.com", users[0].Email)
+
+    _, total, _ = repo.List(ctx, types.UserQueryParams{Search: ".*"})
+    assert.Equal(t, int64(0), total)
+  })
+}
@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"task-api/models"
+	"task-api/types"
+)
+
+var (
+  ErrWorkspaceNotFound       = errors.New("workspace not found")
+  ErrWorkspaceMemberNotFound = errors.New("workspace member not found")
+  ErrAlreadyWorkspaceMember  = errors.New("already a workspace member")
+  ErrLastWorkspaceOwner      = errors.New("workspace needs an owner")
+)
+
+// WorkspaceRepository - interface for workspaces and their memberships
+type WorkspaceRepository interface {
+  Create(ctx context.Context, workspace *models.Workspace) error
+  FindByID(ctx context.Context, id bson.ObjectID) (*models.Workspace, error)
+  FindByIDs(ctx context.Context, ids []bson.ObjectID) ([]models.Workspace, error)
+  AddMember(ctx context.Context, member *models.WorkspaceMember) error
+  FindMember(ctx context.Context, workspaceID, userID bson.ObjectID) (*models.WorkspaceMember, error)
+  FindMembers(ctx context.Context, workspaceID bson.ObjectID) ([]models.WorkspaceMember, error)
+  FindMembershipsByUser(ctx context.Context, userID bson.ObjectID) ([]models.WorkspaceMember, error)
+  CountOwners(ctx context.Context, workspaceID bson.ObjectID) (int64, error)
+  RemoveMember(ctx context.Context, workspaceID, userID bson.ObjectID) error
+  RemoveOwner(ctx context.Context, workspaceID, userID bson.ObjectID) error
+}
+
+// workspaceRepository - implement WorkspaceRepository
+type workspaceRepository struct {
+  collection *mongo.Collection
+  members    *mongo.Collection
+}
+
+// NewWorkspaceRepository - constructor
+func NewWorkspaceRepository(db *mongo.Database) WorkspaceRepository {
+  return &workspaceRepository{
+    collection: db.Collection("workspaces"),
+    members:    db.Collection("workspace_members"),
+  }
+}
+
+// Create - insert new workspace
+func (r *workspaceRepository) Create(ctx context.Context, workspace *models.Workspace) error {
+  _, err := r.collection.InsertOne(ctx, workspace)
+  return err
+}
+
+// FindByID - find workspace by ID
+func (r *workspaceRepository) FindByID(ctx context.Context, id bson.ObjectID) (*models.Workspace, error) {
+  var workspace models.Workspace
+
+  err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&workspace)
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrWorkspaceNotFound
+    }
+    return nil, err
+  }
+
+  return &workspace, nil
+}
+
+// FindByIDs - find workspaces by IDs, sorted by name
+func (r *workspaceRepository) FindByIDs(ctx context.Context, ids []bson.ObjectID) ([]models.Workspace, error) {
+  filter := bson.M{"_id": bson.M{"$in": ids}}
+  opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+  cursor, err := r.collection.Find(ctx, filter, opts)
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  workspaces := []models.Workspace{}
+  if err = cursor.All(ctx, &workspaces); err != nil {
+    return nil, err
+  }
+
+  return workspaces, nil
+}
+
+// AddMember - insert membership, the unique index rejects duplicates
+func (r *workspaceRepository) AddMember(ctx context.Context, member *models.WorkspaceMember) error {
+  _, err := r.members.InsertOne(ctx, member)
+  if err != nil {
+    if mongo.IsDuplicateKeyError(err) {
+      return ErrAlreadyWorkspaceMember
+    }
+    return err
+  }
+
+  return nil
+}
+
+// FindMember - membership of user in workspace
+func (r *workspaceRepository) FindMember(ctx context.Context, workspaceID, userID bson.ObjectID) (*models.WorkspaceMember, error) {
+  var member models.WorkspaceMember
+
+  filter := bson.M{"workspace_id": workspaceID, "user_id": userID}
+  err := r.members.FindOne(ctx, filter).Decode(&member)
+  if err != nil {
+    if err == mongo.ErrNoDocuments {
+      return nil, ErrWorkspaceMemberNotFound
+    }
+    return nil, err
+  }
+
+  return &member, nil
+}
+
+// FindMembers - members of workspace, oldest first
+func (r *workspaceRepository) FindMembers(ctx context.Context, workspaceID bson.ObjectID) ([]models.WorkspaceMember, error) {
+  return r.findMembers(ctx, bson.M{"workspace_id": workspaceID})
+}
+
+// FindMembershipsByUser - every workspace membership of user
+func (r *workspaceRepository) FindMembershipsByUser(ctx context.Context, userID bson.ObjectID) ([]models.WorkspaceMember, error) {
+  return r.findMembers(ctx, bson.M{"user_id": userID})
+}
+
+// CountOwners - number of owners of workspace
+func (r *workspaceRepository) CountOwners(ctx context.Context, workspaceID bson.ObjectID) (int64, error) {
+  filter := bson.M{"workspace_id": workspaceID, "role": types.WorkspaceRoleOwner}
+  return r.members.CountDocuments(ctx, filter)
+}
+
+// RemoveMember - delete membership
+func (r *workspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID bson.ObjectID) error {
+  filter := bson.M{"workspace_id": workspaceID, "user_id": userID}
+
+  result, err := r.members.DeleteOne(ctx, filter)
+  if err != nil {
+    return err
+  }
+
+  if result.DeletedCount == 0 {
+    return ErrWorkspaceMemberNotFound
+  }
+
+  return nil
+}
+
+// RemoveOwner - delete owner membership unless it is the last owner (needs a replica set)
+func (r *workspaceRepository) RemoveOwner(ctx context.Context, workspaceID, userID bson.ObjectID) error {
+  session, err := r.members.Database().Client().StartSession()
+  if err != nil {
+    return err
+  }
+  defer session.EndSession(ctx)
+
+  _, err = session.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+    // Concurrent removals write the same workspace and conflict, so the owner count below cannot go stale
+    update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+    if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": workspaceID}, update); err != nil {
+      return nil, err
+    }
+
+    filter := bson.M{"workspace_id": workspaceID, "user_id": userID, "role": types.WorkspaceRoleOwner}
+    result, err := r.members.DeleteOne(ctx, filter)
+    if err != nil {
+      return nil, err
+    }
+    if result.DeletedCount == 0 {
+      return nil, ErrWorkspaceMemberNotFound
+    }
+
+    owners, err := r.CountOwners(ctx, workspaceID)
+    if err != nil {
+      return nil, err
+    }
+    if owners == 0 {
+      return nil, ErrLastWorkspaceOwner
+    }
+    return nil, nil
+  })
+  return err
+}
+
+// findMembers - memberships matching filter
+func (r *workspaceRepository) findMembers(ctx context.Context, filter bson.M) ([]models.WorkspaceMember, error) {
+  opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+  cursor, err := r.members.Find(ctx, filter, opts)
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  members := []models.WorkspaceMember{}
+  if err = cursor.All(ctx, &members); err != nil {
+    return nil, err
+  }
+
+  return members, nil
+}
+
+// memberWorkspaceIDs - IDs of workspaces user belongs to
+func memberWorkspaceIDs(ctx context.Context, members *mongo.Collection, userID bson.ObjectID) ([]bson.ObjectID, error) {
+  opts := options.Find().SetProjection(bson.M{"workspace_id": 1})
+
+  cursor, err := members.Find(ctx, bson.M{"user_id": userID}, opts)
+  if err != nil {
+    return nil, err
+  }
+  defer cursor.Close(ctx)
+
+  var memberships []models.WorkspaceMember
+  if err = cursor.All(ctx, &memberships); err != nil {
+    return nil, err
+  }
+
+  ids := make([]bson.ObjectID, len(memberships))
+  for i, member := range memberships {
+    ids[i] = member.WorkspaceID
+  }
+  return ids, nil
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"task-api/models"
+	"task-api/types"
+)
+
+func TestWorkspaceRepository(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should manage memberships", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewWorkspaceRepository(db)
+    ctx := context.Background()
+
+    // Same unique index as db/indexes.js
+    db.Collection("workspace_members").Indexes().CreateOne(ctx, mongo.IndexModel{
+      Keys:    bson.D{{Key: "workspace_id", Value: 1}, {Key: "user_id", Value: 1}},
+      Options: options.Index().SetUnique(true),
+    })
+
+    ownerID, memberID := bson.NewObjectID(), bson.NewObjectID()
+    workspace := &models.Workspace{ID: bson.NewObjectID(), Name: "Team", CreatedBy: ownerID, CreatedAt: time.Now()}
+    assert.NoError(t, repo.Create(ctx, workspace))
+
+    assert.NoError(t, repo.AddMember(ctx, &models.WorkspaceMember{ID: bson.NewObjectID(), WorkspaceID: workspace.ID, UserID: ownerID, Role: types.WorkspaceRoleOwner}))
+    assert.NoError(t, repo.AddMember(ctx, &models.WorkspaceMember{ID: bson.NewObjectID(), WorkspaceID: workspace.ID, UserID: memberID, Role: types.WorkspaceRoleMember}))
+
+    err := repo.AddMember(ctx, &models.WorkspaceMember{ID: bson.NewObjectID(), WorkspaceID: workspace.ID, UserID: memberID})
+    assert.ErrorIs(t, err, ErrAlreadyWorkspaceMember)
+
+    member, err := repo.FindMember(ctx, workspace.ID, memberID)
+    assert.NoError(t, err)
+    assert.Equal(t, types.WorkspaceRoleMember, member.Role)
+
+    owners, _ := repo.CountOwners(ctx, workspace.ID)
+    assert.Equal(t, int64(1), owners)
+
+    memberships, _ := repo.FindMembershipsByUser(ctx, memberID)
+    assert.Len(t, memberships, 1)
+
+    assert.NoError(t, repo.RemoveMember(ctx, workspace.ID, memberID))
+    _, err = repo.FindMember(ctx, workspace.ID, memberID)
+    assert.ErrorIs(t, err, ErrWorkspaceMemberNotFound)
+  })
+
+  t.Run("should keep the last owner", func(t *testing.T) {
+    db := setupTestDB(t)
+    if db == nil {
+      return
+    }
+    repo := NewWorkspaceRepository(db)
+    ctx := context.Background()
+
+    firstID, secondID := bson.NewObjectID(), bson.NewObjectID()
+    workspace := &models.Workspace{ID: bson.NewObjectID(), Name: "Team", CreatedBy: firstID, CreatedAt: time.Now()}
+    assert.NoError(t, repo.Create(ctx, workspace))
+    assert.NoError(t, repo.AddMember(ctx, &models.WorkspaceMember{ID: bson.NewObjectID(), WorkspaceID: workspace.ID, UserID: firstID, Role: types.WorkspaceRoleOwner}))
+    assert.NoError(t, repo.AddMember(ctx, &models.WorkspaceMember{ID: bson.NewObjectID(), WorkspaceID: workspace.ID, UserID: secondID, Role: types.WorkspaceRoleOwner}))
+
+    // Both owners remove each other at the same time, one of them must stay
+    errs := make(chan error, 2)
+    go func() { errs <- repo.RemoveOwner(ctx, workspace.ID, firstID) }()
+    go func() { errs <- repo.RemoveOwner(ctx, workspace.ID, secondID) }()
+    first, second := <-errs, <-errs
+
+    assert.True(t, (first == nil) != (second == nil))
+    assert.ErrorIs(t, errors.Join(first, second), ErrLastWorkspaceOwner)
+    owners, _ := repo.CountOwners(ctx, workspace.ID)
+    assert.Equal(t, int64(1), owners)
+  })
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"task-api/handlers"
+	"task-api/middleware"
+	"task-api/types"
+)
+
+// SetupAdminRoutes - user administration, admins with a login session only
+func SetupAdminRoutes(r *gin.Engine, adminHandler *handlers.AdminHandler, authMiddleware gin.HandlerFunc) {
+  admin := r.Group("/admin")
+  admin.Use(authMiddleware, middleware.SessionOnly(), middleware.RequireRole(types.RoleAdmin))
+  {
+    admin.GET("/users", adminHandler.ListUsers)                 // List users (with filters)
+    admin.POST("/users/:id/disable", adminHandler.DisableUser)  // Disable account and end sessions
+    admin.POST("/users/:id/enable", adminHandler.EnableUser)    // Re-enable account
+    admin.PATCH("/users/:id/role", adminHandler.UpdateUserRole) // Change role
+    admin.GET("/users/:id/tasks", adminHandler.ListUserTasks)   // View any user's tasks
+  }
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"task-api/handlers"
+	"task-api/middleware"
+	"task-api/types"
+)
+
+// SetupAttachmentRoutes - files attached to a task, listed in the task itself
+func SetupAttachmentRoutes(r *gin.Engine, attachmentHandler *handlers.AttachmentHandler, authMiddleware gin.HandlerFunc) {
+  attachments := r.Group("/tasks/:id/attachments")
+  attachments.Use(authMiddleware)
+
+  read := middleware.RequireScope(types.ScopeTasksRead)
+  write := middleware.RequireScope(types.ScopeTasksWrite)
+  editor := middleware.RequireRole(types.RoleAdmin, types.RoleMember)
+  {
+    attachments.POST("", write, editor, attachmentHandler.UploadAttachment)        // Upload file (multipart)
+    attachments.GET("/:aid", read, attachmentHandler.DownloadAttachment)           // Download file
+    attachments.DELETE("/:aid", write, editor, attachmentHandler.DeleteAttachment) // Delete file
+  }
+}
@@ -4,12 +4,30 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"task-api/handlers"
+	"task-api/middleware"
 )
 
 // SetupAuthRoutes - setup auth routes
-func SetupAuthRoutes(r *gin.Engine, authHandler *handlers.AuthHandler) {
+func SetupAuthRoutes(r *gin.Engine, authHandler *handlers.AuthHandler, authMiddleware gin.HandlerFunc) {
   auth := r.Group("/auth")
   {
     auth.POST("/login", authHandler.Login)
+    auth.POST("/register", authHandler.Register)
+    auth.POST("/refresh", authHandler.Refresh)
+    auth.POST("/forgot-password", authHandler.ForgotPassword)
+    auth.POST("/reset-password", authHandler.ResetPassword)
+    auth.POST("/2fa/login", authHandler.LoginMFA) // Exchange mfa token + code for a session
+  }
+
+  session := auth.Group("")
+  session.Use(authMiddleware, middleware.SessionOnly()) // Protected routes, no personal access tokens
+  {
+    session.POST("/logout", authHandler.Logout)        // Log out current session
+    session.POST("/logout-all", authHandler.LogoutAll) // Log out everywhere
+    session.GET("/me", authHandler.GetMe)               // Current user profile
+    session.PATCH("/me", authHandler.UpdateMe)          // Update name, timezone, locale
+    session.POST("/change-password", authHandler.ChangePassword)
+    session.POST("/2fa/setup", authHandler.SetupMFA)   // Start TOTP enrollment
+    session.POST("/2fa/verify", authHandler.VerifyMFA) // Enable 2FA, returns recovery codes
   }
 }
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"task-api/handlers"
+	"task-api/middleware"
+	"task-api/types"
+)
+
+// SetupCommentRoutes - discussion thread of a task
+func SetupCommentRoutes(r *gin.Engine, commentHandler *handlers.CommentHandler, authMiddleware gin.HandlerFunc) {
+  comments := r.Group("/tasks/:id/comments")
+  comments.Use(authMiddleware)
+
+  read := middleware.RequireScope(types.ScopeTasksRead)
+  write := middleware.RequireScope(types.ScopeTasksWrite)
+  editor := middleware.RequireRole(types.RoleAdmin, types.RoleMember)
+  {
+    comments.GET("", read, commentHandler.GetComments)                    // List comments (cursor pagination)
+    comments.POST("", write, editor, commentHandler.CreateComment)        // Post comment
+    comments.PUT("/:cid", write, editor, commentHandler.UpdateComment)    // Edit own comment
+    comments.DELETE("/:cid", write, editor, commentHandler.DeleteComment) // Delete own comment
+  }
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"task-api/handlers"
+)
+
+// SetupOIDCRoutes - single sign-on with the OpenID Connect provider
+func SetupOIDCRoutes(r *gin.Engine, oidcHandler *handlers.OIDCHandler) {
+  oidc := r.Group("/auth/oidc")
+  {
+    oidc.GET("/login", oidcHandler.Login)       // Redirect to IdP
+    oidc.GET("/callback", oidcHandler.Callback) // IdP redirects back here
+  }
+}
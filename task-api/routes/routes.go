@@ -2,18 +2,30 @@ package routes
 
 import (
 	"task-api/app"
+	"task-api/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 // SetupRoutes - setup routes
 func SetupRoutes(r *gin.Engine, c *app.Container) {
+  authMiddleware := middleware.AuthMiddleware(c.RevocationStore, c.TokenService)
+
   // Setup test routes
   SetupTestRoutes(r)
 
+  // JWKS for services verifying our tokens
+  SetupWellKnownRoutes(r)
+
   // Auth routes
-  SetupAuthRoutes(r, c.AuthHandler)
+  SetupAuthRoutes(r, c.AuthHandler, authMiddleware)
+  SetupTokenRoutes(r, c.TokenHandler, authMiddleware)
+  SetupOIDCRoutes(r, c.OIDCHandler)
 
 
-  SetupTaskRoutes(r, c.TaskHandler)
+  SetupTaskRoutes(r, c.TaskHandler, authMiddleware)
+  SetupCommentRoutes(r, c.CommentHandler, authMiddleware)
+  SetupAttachmentRoutes(r, c.AttachmentHandler, authMiddleware)
+  SetupWorkspaceRoutes(r, c.WorkspaceHandler, c.TaskHandler, authMiddleware)
+  SetupAdminRoutes(r, c.AdminHandler, authMiddleware)
 }
@@ -5,16 +5,56 @@ import (
 
 	"task-api/handlers"
 	"task-api/middleware"
+	"task-api/types"
 )
 
-func SetupTaskRoutes(r *gin.Engine, taskHandler *handlers.TaskHandler) {
+func SetupTaskRoutes(r *gin.Engine, taskHandler *handlers.TaskHandler, authMiddleware gin.HandlerFunc) {
   tasks := r.Group("/tasks")
-  tasks.Use(middleware.AuthMiddleware()) // Protected routes
+  tasks.Use(authMiddleware) // Protected routes
+
+  // Scopes only restrict personal access tokens, login sessions pass through
+  read := middleware.RequireScope(types.ScopeTasksRead)
+  write := middleware.RequireScope(types.ScopeTasksWrite)
+
+  // Viewers are read-only
+  editor := middleware.RequireRole(types.RoleAdmin, types.RoleMember)
+
+  // Sharing is managed from a login session, like workspace membership
+  session := middleware.SessionOnly()
   {
-    tasks.POST("", taskHandler.CreateTask)      // Create task
-    tasks.GET("", taskHandler.GetTasks)         // Get all tasks (with filters)
-    tasks.GET("/:id", taskHandler.GetTask)      // Get single task
-    tasks.PUT("/:id", taskHandler.UpdateTask)   // Update task
-    tasks.DELETE("/:id", taskHandler.DeleteTask) // Delete task
+    tasks.POST("", write, editor, taskHandler.CreateTask)       // Create task
+    tasks.GET("", read, taskHandler.GetTasks)                   // Get all tasks (with filters)
+    tasks.GET("/shared", read, taskHandler.GetSharedTasks)      // Tasks shared with me
+    tasks.GET("/trash", read, taskHandler.GetTrash)             // Deleted tasks
+    tasks.POST("/bulk", write, editor, taskHandler.BulkTasks)   // Change many tasks at once
+    tasks.GET("/:id", read, taskHandler.GetTask)                // Get single task
+    tasks.PUT("/:id", write, editor, taskHandler.UpdateTask)    // Update task
+    tasks.DELETE("/:id", write, editor, taskHandler.DeleteTask) // Delete task (?permanent=true skips the trash)
+
+    tasks.POST("/:id/restore", write, editor, taskHandler.RestoreTask)     // Restore from the trash
+    tasks.POST("/:id/archive", write, editor, taskHandler.ArchiveTask)     // Archive task
+    tasks.POST("/:id/unarchive", write, editor, taskHandler.UnarchiveTask) // Back to the task list
+    tasks.GET("/:id/history", read, taskHandler.GetTaskHistory)            // Change history
+
+    tasks.GET("/:id/subtasks", read, taskHandler.GetSubtasks)             // List subtasks
+    tasks.POST("/:id/subtasks", write, editor, taskHandler.CreateSubtask) // Create subtask
+
+    tasks.GET("/:id/dependencies", read, taskHandler.GetTaskDependencies)        // Dependency graph
+    tasks.POST("/:id/blockers", write, editor, taskHandler.AddBlocker)           // Add blocker
+    tasks.DELETE("/:id/blockers/:bid", write, editor, taskHandler.RemoveBlocker) // Remove blocker
+
+    tasks.POST("/:id/checklist", write, editor, taskHandler.AddChecklistItem)            // Add checklist item
+    tasks.PUT("/:id/checklist/order", write, editor, taskHandler.ReorderChecklist)       // Reorder checklist
+    tasks.PATCH("/:id/checklist/:item", write, editor, taskHandler.UpdateChecklistItem)  // Edit / check item
+    tasks.DELETE("/:id/checklist/:item", write, editor, taskHandler.RemoveChecklistItem) // Remove item
+
+    tasks.POST("/:id/assignees", write, editor, taskHandler.AssignTask)          // Assign user
+    tasks.DELETE("/:id/assignees/:uid", write, editor, taskHandler.UnassignTask) // Unassign user
+    tasks.POST("/:id/watch", write, taskHandler.WatchTask)                       // Watch task
+    tasks.DELETE("/:id/watch", write, taskHandler.UnwatchTask)                   // Stop watching
+
+    tasks.POST("/:id/shares", session, editor, taskHandler.ShareTask)              // Share task by email
+    tasks.GET("/:id/shares", session, taskHandler.GetTaskShares)                   // List shares
+    tasks.DELETE("/:id/shares/:uid", session, editor, taskHandler.RevokeTaskShare) // Revoke share or leave
   }
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"task-api/handlers"
+	"task-api/middleware"
+)
+
+// SetupTokenRoutes - personal access token management, only from a login session
+func SetupTokenRoutes(r *gin.Engine, tokenHandler *handlers.TokenHandler, authMiddleware gin.HandlerFunc) {
+  tokens := r.Group("/auth/tokens")
+  tokens.Use(authMiddleware, middleware.SessionOnly())
+  {
+    tokens.GET("", tokenHandler.ListTokens)         // List tokens
+    tokens.POST("", tokenHandler.CreateToken)       // Mint token
+    tokens.DELETE("/:id", tokenHandler.RevokeToken) // Revoke token
+  }
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"task-api/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupWellKnownRoutes - public discovery endpoints
+func SetupWellKnownRoutes(r *gin.Engine) {
+  r.GET("/.well-known/jwks.json", handlers.JWKS)
+}
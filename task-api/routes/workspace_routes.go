@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"task-api/handlers"
+	"task-api/middleware"
+	"task-api/types"
+)
+
+// SetupWorkspaceRoutes - workspaces, their members and workspace tasks
+func SetupWorkspaceRoutes(r *gin.Engine, workspaceHandler *handlers.WorkspaceHandler, taskHandler *handlers.TaskHandler, authMiddleware gin.HandlerFunc) {
+  workspaces := r.Group("/workspaces")
+  workspaces.Use(authMiddleware)
+
+  // Membership is managed from a login session, tasks also accept scoped tokens
+  session := middleware.SessionOnly()
+  read := middleware.RequireScope(types.ScopeTasksRead)
+  write := middleware.RequireScope(types.ScopeTasksWrite)
+  editor := middleware.RequireRole(types.RoleAdmin, types.RoleMember)
+  {
+    workspaces.POST("", session, editor, workspaceHandler.CreateWorkspace)                  // Create workspace
+    workspaces.GET("", session, workspaceHandler.GetWorkspaces)                             // List my workspaces
+    workspaces.GET("/:wid", session, workspaceHandler.GetWorkspace)                         // Get workspace
+    workspaces.GET("/:wid/members", session, workspaceHandler.GetMembers)                   // List members
+    workspaces.POST("/:wid/members", session, editor, workspaceHandler.AddMember)           // Add member by email
+    workspaces.DELETE("/:wid/members/:uid", session, editor, workspaceHandler.RemoveMember) // Remove member or leave
+    workspaces.POST("/:wid/tasks", write, editor, taskHandler.CreateTask)                   // Create workspace task
+    workspaces.GET("/:wid/tasks", read, taskHandler.GetTasks)                               // List workspace tasks
+  }
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// ErrCannotModifySelf - admins may not disable or demote their own account
+var ErrCannotModifySelf = errors.New("cannot modify own account")
+
+// AdminService - interface for user administration
+type AdminService interface {
+  ListUsers(ctx context.Context, query types.UserQueryParams) (*types.UserListResponse, error)
+  DisableUser(ctx context.Context, actorID, userID bson.ObjectID) (*types.AdminUserResponse, error)
+  EnableUser(ctx context.Context, actorID, userID bson.ObjectID) (*types.AdminUserResponse, error)
+  UpdateUserRole(ctx context.Context, actorID, userID bson.ObjectID, role string) (*types.AdminUserResponse, error)
+  ListUserTasks(ctx context.Context, userID bson.ObjectID, query types.TaskQueryParams) (*types.TaskListResponse, error)
+}
+
+// adminService - implementation
+type adminService struct {
+  userRepo    repositories.UserRepository
+  authService AuthService
+  taskService TaskService
+}
+
+// NewAdminService - constructor
+func NewAdminService(userRepo repositories.UserRepository, authService AuthService, taskService TaskService) AdminService {
+  return &adminService{
+    userRepo:    userRepo,
+    authService: authService,
+    taskService: taskService,
+  }
+}
+
+// ListUsers - list users with filters
+func (s *adminService) ListUsers(ctx context.Context, query types.UserQueryParams) (*types.UserListResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  users, total, err := s.userRepo.List(ctx, query)
+  if err != nil {
+    return nil, err
+  }
+
+  page := 1
+  if query.Page > 0 {
+    page = query.Page
+  }
+
+  limit := 10
+  if query.Limit > 0 {
+    limit = query.Limit
+  }
+
+  totalPages := int(total) / limit
+  if int(total)%limit != 0 {
+    totalPages++
+  }
+
+  return &types.UserListResponse{
+    Users: types.ToAdminUserResponseList(users),
+    Meta: types.PaginationMeta{
+      Page:        page,
+      Limit:       limit,
+      Total:       total,
+      TotalPages:  totalPages,
+      HasNextPage: page < totalPages,
+      HasPrevPage: page > 1,
+    },
+  }, nil
+}
+
+// DisableUser - block the account and end all of its sessions
+func (s *adminService) DisableUser(ctx context.Context, actorID, userID bson.ObjectID) (*types.AdminUserResponse, error) {
+  if actorID == userID {
+    return nil, ErrCannotModifySelf
+  }
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if err := s.userRepo.Update(ctx, userID, bson.M{"disabled_at": time.Now()}); err != nil {
+    return nil, mapUserNotFound(err)
+  }
+
+  // Access tokens stay valid until expiry unless revoked here
+  if err := s.authService.LogoutAll(ctx, userID, "", time.Time{}); err != nil {
+    return nil, err
+  }
+
+  log.Info().Str("actor_id", actorID.Hex()).Str("user_id", userID.Hex()).Msg("User disabled")
+
+  return s.findUser(ctx, userID)
+}
+
+// EnableUser - allow a disabled account to log in again
+func (s *adminService) EnableUser(ctx context.Context, actorID, userID bson.ObjectID) (*types.AdminUserResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if err := s.userRepo.Update(ctx, userID, bson.M{"disabled_at": nil}); err != nil {
+    return nil, mapUserNotFound(err)
+  }
+
+  log.Info().Str("actor_id", actorID.Hex()).Str("user_id", userID.Hex()).Msg("User enabled")
+
+  return s.findUser(ctx, userID)
+}
+
+// UpdateUserRole - change role, sessions are revoked so new tokens carry it
+func (s *adminService) UpdateUserRole(ctx context.Context, actorID, userID bson.ObjectID, role string) (*types.AdminUserResponse, error) {
+  // Keeps at least the acting admin around
+  if actorID == userID {
+    return nil, ErrCannotModifySelf
+  }
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if err := s.userRepo.Update(ctx, userID, bson.M{"role": role}); err != nil {
+    return nil, mapUserNotFound(err)
+  }
+
+  if err := s.authService.LogoutAll(ctx, userID, "", time.Time{}); err != nil {
+    return nil, err
+  }
+
+  log.Info().Str("actor_id", actorID.Hex()).Str("user_id", userID.Hex()).Str("role", role).Msg("User role changed")
+
+  return s.findUser(ctx, userID)
+}
+
+// ListUserTasks - tasks of any user
+func (s *adminService) ListUserTasks(ctx context.Context, userID bson.ObjectID, query types.TaskQueryParams) (*types.TaskListResponse, error) {
+  if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+    return nil, mapUserNotFound(err)
+  }
+
+  return s.taskService.GetTasks(ctx, userID, query)
+}
+
+// findUser - reload user after a change
+func (s *adminService) findUser(ctx context.Context, userID bson.ObjectID) (*types.AdminUserResponse, error) {
+  user, err := s.userRepo.FindByID(ctx, userID)
+  if err != nil {
+    return nil, mapUserNotFound(err)
+  }
+
+  response := types.ToAdminUserResponse(user)
+  return &response, nil
+}
+
+// mapUserNotFound - translate repository not found error
+func mapUserNotFound(err error) error {
+  if errors.Is(err, repositories.ErrUserNotFound) {
+    return ErrUserNotFound
+  }
+  return err
+}
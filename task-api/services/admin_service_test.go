@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// newTestAdminService - admin service on top of the real auth and task services with mocked repositories
+func newTestAdminService() (AdminService, *authServiceMocks, *MockTaskRepository) {
+  authService, mocks := newTestAuthService()
+  taskRepo := new(MockTaskRepository)
+  return NewAdminService(mocks.userRepo, authService, NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))), mocks, taskRepo
+}
+
+func TestAdminService_ListUsers(t *testing.T) {
+  t.Run("should return users with pagination meta", func(t *testing.T) {
+    service, mocks, _ := newTestAdminService()
+
+    query := types.UserQueryParams{Role: types.RoleAdmin, Page: 2, Limit: 1}
+    mocks.userRepo.On("List", mock.Anything, query).
+      Return([]models.User{{ID: bson.NewObjectID(), Email: "admin@test.com", Role: types.RoleAdmin}}, int64(3), nil)
+
+    result, err := service.ListUsers(context.Background(), query)
+
+    assert.NoError(t, err)
+    assert.Len(t, result.Users, 1)
+    assert.Equal(t, types.RoleAdmin, result.Users[0].Role)
+    assert.Equal(t, 3, result.Meta.TotalPages)
+    assert.True(t, result.Meta.HasNextPage)
+    assert.True(t, result.Meta.HasPrevPage)
+  })
+}
+
+func TestAdminService_DisableUser(t *testing.T) {
+  t.Run("should disable user and revoke sessions", func(t *testing.T) {
+    service, mocks, _ := newTestAdminService()
+    adminID, userID := bson.NewObjectID(), bson.NewObjectID()
+    disabledAt := time.Now()
+
+    mocks.userRepo.On("Update", mock.Anything, userID, mock.MatchedBy(func(updates bson.M) bool {
+      _, ok := updates["disabled_at"].(time.Time)
+      return ok
+    })).Return(nil)
+    mocks.refreshRepo.On("RevokeAllForUser", mock.Anything, userID).Return(nil)
+    mocks.userRepo.On("FindByID", mock.Anything, userID).
+      Return(&models.User{ID: userID, Email: "user@test.com", DisabledAt: &disabledAt}, nil)
+
+    result, err := service.DisableUser(context.Background(), adminID, userID)
+
+    assert.NoError(t, err)
+    assert.True(t, result.Disabled)
+    mocks.refreshRepo.AssertExpectations(t)
+
+    // Access tokens issued before now are rejected
+    revoked, _ := mocks.revocations.IsRevoked(context.Background(), "jti-1", userID, time.Now().Add(-time.Minute))
+    assert.True(t, revoked)
+  })
+
+  t.Run("should refuse to disable own account", func(t *testing.T) {
+    service, mocks, _ := newTestAdminService()
+    adminID := bson.NewObjectID()
+
+    _, err := service.DisableUser(context.Background(), adminID, adminID)
+
+    assert.ErrorIs(t, err, ErrCannotModifySelf)
+    mocks.userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should return not found for unknown user", func(t *testing.T) {
+    service, mocks, _ := newTestAdminService()
+
+    mocks.userRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(repositories.ErrUserNotFound)
+
+    _, err := service.DisableUser(context.Background(), bson.NewObjectID(), bson.NewObjectID())
+
+    assert.ErrorIs(t, err, ErrUserNotFound)
+    mocks.refreshRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything, mock.Anything)
+  })
+}
+
+func TestAdminService_UpdateUserRole(t *testing.T) {
+  t.Run("should change role and revoke sessions", func(t *testing.T) {
+    service, mocks, _ := newTestAdminService()
+    userID := bson.NewObjectID()
+
+    mocks.userRepo.On("Update", mock.Anything, userID, bson.M{"role": types.RoleViewer}).Return(nil)
+    mocks.refreshRepo.On("RevokeAllForUser", mock.Anything, userID).Return(nil)
+    mocks.userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID, Role: types.RoleViewer}, nil)
+
+    result, err := service.UpdateUserRole(context.Background(), bson.NewObjectID(), userID, types.RoleViewer)
+
+    assert.NoError(t, err)
+    assert.Equal(t, types.RoleViewer, result.Role)
+    mocks.userRepo.AssertExpectations(t)
+    mocks.refreshRepo.AssertExpectations(t)
+  })
+
+  t.Run("should refuse to change own role", func(t *testing.T) {
+    service, _, _ := newTestAdminService()
+    adminID := bson.NewObjectID()
+
+    _, err := service.UpdateUserRole(context.Background(), adminID, adminID, types.RoleMember)
+
+    assert.ErrorIs(t, err, ErrCannotModifySelf)
+  })
+}
+
+func TestAdminService_ListUserTasks(t *testing.T) {
+  t.Run("should list tasks of another user", func(t *testing.T) {
+    service, mocks, taskRepo := newTestAdminService()
+    userID := bson.NewObjectID()
+
+    mocks.userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID}, nil)
+    taskRepo.On("FindByUserID", mock.Anything, userID, mock.Anything).
+      Return([]models.Task{{ID: bson.NewObjectID(), UserID: userID, Title: "Task"}}, int64(1), nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    result, err := service.ListUserTasks(context.Background(), userID, types.TaskQueryParams{})
+
+    assert.NoError(t, err)
+    assert.Len(t, result.Tasks, 1)
+  })
+
+  t.Run("should return not found for unknown user", func(t *testing.T) {
+    service, mocks, taskRepo := newTestAdminService()
+
+    mocks.userRepo.On("FindByID", mock.Anything, mock.Anything).Return(nil, repositories.ErrUserNotFound)
+
+    _, err := service.ListUserTasks(context.Background(), bson.NewObjectID(), types.TaskQueryParams{})
+
+    assert.ErrorIs(t, err, ErrUserNotFound)
+    taskRepo.AssertNotCalled(t, "FindByUserID", mock.Anything, mock.Anything, mock.Anything)
+  })
+}
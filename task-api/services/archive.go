@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/types"
+)
+
+// ArchiveTask - archive a task, it stays readable but leaves the task list
+func (s *taskService) ArchiveTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error) {
+  return s.setArchived(ctx, taskID, userID, true)
+}
+
+// UnarchiveTask - bring an archived task back to the task list
+func (s *taskService) UnarchiveTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error) {
+  return s.setArchived(ctx, taskID, userID, false)
+}
+
+// setArchived - archive or unarchive a task the user can edit
+func (s *taskService) setArchived(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, archive bool) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  task, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return nil, err
+  }
+
+  if archive && task.ArchivedAt != nil {
+    return nil, ErrTaskArchived
+  }
+  if !archive && task.ArchivedAt == nil {
+    return nil, ErrTaskNotArchived
+  }
+
+  after := *task
+  after.ArchivedAt = nil
+  if archive {
+    now := time.Now()
+    after.ArchivedAt = &now
+  }
+
+  updates := bson.M{"archived_at": after.ArchivedAt}
+  if !archive {
+    // Keeps auto-archive away for another full period
+    updates["unarchived_at"] = time.Now()
+  }
+  if err := s.taskRepo.UpdateByID(ctx, taskID, updates); err != nil {
+    return nil, mapTaskNotFound(err)
+  }
+  s.recordEvent(ctx, taskID, userID, types.TaskEventUpdated, taskChanges(task, &after))
+
+  return s.GetTask(ctx, taskID, userID)
+}
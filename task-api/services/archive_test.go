@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/types"
+)
+
+func TestTaskService_ArchiveTask(t *testing.T) {
+  t.Run("should archive task and record the change", func(t *testing.T) {
+    taskRepo, eventRepo := new(MockTaskRepository), new(MockTaskEventRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), eventRepo, new(MockTaskPurger))
+    events := recordedEvents(eventRepo)
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Status: types.TaskStatusCompleted}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("UpdateByID", mock.Anything, task.ID, mock.MatchedBy(func(updates bson.M) bool {
+      archivedAt, ok := updates["archived_at"].(*time.Time)
+      return ok && archivedAt != nil
+    })).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.ArchiveTask(context.Background(), task.ID, userID)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+    assert.Len(t, *events, 1)
+    assert.Equal(t, "archived_at", (*events)[0].Changes[0].Field)
+  })
+
+  t.Run("should refuse tasks that are already archived", func(t *testing.T) {
+    service, taskRepo, _ := newTestTrashService()
+    userID := bson.NewObjectID()
+    archivedAt := time.Now()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, ArchivedAt: &archivedAt}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+    _, err := service.ArchiveTask(context.Background(), task.ID, userID)
+
+    assert.ErrorIs(t, err, ErrTaskArchived)
+    taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+  })
+}
+
+func TestTaskService_UnarchiveTask(t *testing.T) {
+  t.Run("should clear archived_at and hold off auto-archive", func(t *testing.T) {
+    service, taskRepo, _ := newTestTrashService()
+    userID := bson.NewObjectID()
+    archivedAt := time.Now()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, ArchivedAt: &archivedAt}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("UpdateByID", mock.Anything, task.ID, mock.MatchedBy(func(updates bson.M) bool {
+      _, ok := updates["unarchived_at"].(time.Time)
+      return updates["archived_at"] == (*time.Time)(nil) && ok
+    })).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UnarchiveTask(context.Background(), task.ID, userID)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should refuse tasks that are not archived", func(t *testing.T) {
+    service, taskRepo, _ := newTestTrashService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+    _, err := service.UnarchiveTask(context.Background(), task.ID, userID)
+
+    assert.ErrorIs(t, err, ErrTaskNotArchived)
+  })
+}
+
+func TestTaskArchiver_ArchiveCompleted(t *testing.T) {
+  t.Run("should archive tasks completed before the policy cutoff", func(t *testing.T) {
+    taskRepo := new(MockTaskRepository)
+    archiver := &taskArchiver{taskRepo: taskRepo, after: 30 * 24 * time.Hour}
+    start := time.Now()
+
+    taskRepo.On("ArchiveCompleted", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+      cutoff := start.Add(-30 * 24 * time.Hour)
+      return !before.Before(cutoff) && before.Before(cutoff.Add(time.Minute))
+    }), mock.Anything).Return(int64(3), nil)
+
+    archived, err := archiver.ArchiveCompleted(context.Background())
+
+    assert.NoError(t, err)
+    assert.Equal(t, int64(3), archived)
+  })
+
+  t.Run("should do nothing when turned off", func(t *testing.T) {
+    taskRepo := new(MockTaskRepository)
+    archiver := &taskArchiver{taskRepo: taskRepo}
+
+    archived, err := archiver.ArchiveCompleted(context.Background())
+
+    assert.NoError(t, err)
+    assert.Zero(t, archived)
+    taskRepo.AssertNotCalled(t, "ArchiveCompleted", mock.Anything, mock.Anything, mock.Anything)
+  })
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/configs"
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/storage"
+	"task-api/types"
+)
+
+// Attachment errors that handlers map to specific HTTP statuses
+var (
+  ErrAttachmentNotFound = errors.New("attachment not found")
+  ErrAttachmentTooLarge = errors.New("attachment too large")
+  ErrAttachmentType     = errors.New("attachment type not allowed")
+  ErrTooManyAttachments = errors.New("too many attachments")
+)
+
+// MaxAttachments - files allowed on one task
+const MaxAttachments = 20
+
+// sniffLength - bytes http.DetectContentType looks at
+const sniffLength = 512
+
+// AllowedAttachmentTypes - content types accepted for upload, detected from the contents
+// rather than trusting the file name or the client's Content-Type
+var AllowedAttachmentTypes = map[string]bool{
+  "image/jpeg":      true,
+  "image/png":       true,
+  "image/gif":       true,
+  "image/webp":      true,
+  "application/pdf": true,
+}
+
+// AttachmentMaxSize - upload limit in bytes from ATTACHMENT_MAX_SIZE, default 10 MiB
+func AttachmentMaxSize() int64 {
+  return int64(configs.GetInt("ATTACHMENT_MAX_SIZE", 10<<20))
+}
+
+// AttachmentService - interface
+type AttachmentService interface {
+  UploadAttachment(ctx context.Context, taskID, userID bson.ObjectID, name string, content io.Reader) (*types.AttachmentResponse, error)
+  OpenAttachment(ctx context.Context, taskID, attachmentID, userID bson.ObjectID) (*types.AttachmentResponse, io.ReadCloser, error)
+  DeleteAttachment(ctx context.Context, taskID, attachmentID, userID bson.ObjectID) error
+}
+
+// attachmentService - implementation
+type attachmentService struct {
+  taskRepo  repositories.TaskRepository
+  shareRepo repositories.TaskShareRepository
+  eventRepo repositories.TaskEventRepository
+  blobs     storage.BlobStore
+  maxSize   int64
+}
+
+// NewAttachmentService - constructor
+func NewAttachmentService(taskRepo repositories.TaskRepository, shareRepo repositories.TaskShareRepository, eventRepo repositories.TaskEventRepository, blobs storage.BlobStore) AttachmentService {
+  return &attachmentService{
+    taskRepo:  taskRepo,
+    shareRepo: shareRepo,
+    eventRepo: eventRepo,
+    blobs:     blobs,
+    maxSize:   AttachmentMaxSize(),
+  }
+}
+
+// UploadAttachment - store content and attach it to the task, needs edit access
+func (s *attachmentService) UploadAttachment(ctx context.Context, taskID, userID bson.ObjectID, name string, content io.Reader) (*types.AttachmentResponse, error) {
+  // Longer than other writes, the upload is streamed to the blob store
+  ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+  defer cancel()
+
+  task, err := findAccessibleTask(ctx, s.taskRepo, s.shareRepo, taskID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return nil, err
+  }
+
+  if len(task.Attachments) >= MaxAttachments {
+    return nil, ErrTooManyAttachments
+  }
+
+  head := make([]byte, sniffLength)
+  n, err := io.ReadFull(content, head)
+  if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+    return nil, err
+  }
+  head = head[:n]
+
+  contentType := sniffContentType(head)
+  if !AllowedAttachmentTypes[contentType] {
+    return nil, ErrAttachmentType
+  }
+
+  attachment := models.Attachment{
+    ID:          bson.NewObjectID(),
+    Name:        cleanFileName(name),
+    ContentType: contentType,
+    UploadedBy:  userID,
+    UploadedAt:  time.Now(),
+  }
+  key := attachment.ID.Hex()
+
+  // One byte past the limit tells an oversized file apart
+  body := io.LimitReader(io.MultiReader(bytes.NewReader(head), content), s.maxSize+1)
+  size, err := s.blobs.Put(ctx, key, body)
+  if err != nil {
+    return nil, err
+  }
+  if size > s.maxSize {
+    deleteBlob(ctx, s.blobs, key)
+    return nil, ErrAttachmentTooLarge
+  }
+  attachment.Size = size
+
+  // Checked again in the write, uploads running side by side could pass the check above together
+  if err := s.taskRepo.AddAttachment(ctx, taskID, attachment, MaxAttachments); err != nil {
+    deleteBlob(ctx, s.blobs, key)
+    if errors.Is(err, repositories.ErrTooManyAttachments) {
+      return nil, ErrTooManyAttachments
+    }
+    return nil, mapTaskNotFound(err)
+  }
+
+  recordTaskEvent(ctx, s.eventRepo, taskID, userID, types.TaskEventUpdated, []models.FieldChange{
+    {Field: attachmentField(attachment.ID), New: attachment.Name},
+  })
+
+  response := types.ToAttachmentResponse(taskID, &attachment)
+  return &response, nil
+}
+
+// OpenAttachment - metadata and contents of an attachment, needs view access.
+// The reader is bound to ctx of the caller, who must close it.
+func (s *attachmentService) OpenAttachment(ctx context.Context, taskID, attachmentID, userID bson.ObjectID) (*types.AttachmentResponse, io.ReadCloser, error) {
+  checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  attachment, err := s.attachment(checkCtx, taskID, attachmentID, userID, types.SharePermissionView)
+  if err != nil {
+    return nil, nil, err
+  }
+
+  reader, err := s.blobs.Open(ctx, attachment.ID.Hex())
+  if err != nil {
+    if errors.Is(err, storage.ErrBlobNotFound) {
+      return nil, nil, ErrAttachmentNotFound
+    }
+    return nil, nil, err
+  }
+
+  response := types.ToAttachmentResponse(taskID, attachment)
+  return &response, reader, nil
+}
+
+// DeleteAttachment - detach and delete the contents, needs edit access
+func (s *attachmentService) DeleteAttachment(ctx context.Context, taskID, attachmentID, userID bson.ObjectID) error {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  attachment, err := s.attachment(ctx, taskID, attachmentID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return err
+  }
+
+  if err := s.taskRepo.RemoveAttachment(ctx, taskID, attachmentID); err != nil {
+    if errors.Is(err, repositories.ErrAttachmentNotFound) {
+      return ErrAttachmentNotFound
+    }
+    return err
+  }
+
+  recordTaskEvent(ctx, s.eventRepo, taskID, userID, types.TaskEventUpdated, []models.FieldChange{
+    {Field: attachmentField(attachmentID), Old: attachment.Name},
+  })
+
+  // The attachment is gone for users once detached, leftover contents are only logged
+  deleteBlob(ctx, s.blobs, attachmentID.Hex())
+  return nil
+}
+
+// attachment - metadata of an attachment of a task the user can access
+func (s *attachmentService) attachment(ctx context.Context, taskID, attachmentID, userID bson.ObjectID, required string) (*models.Attachment, error) {
+  task, err := findAccessibleTask(ctx, s.taskRepo, s.shareRepo, taskID, userID, required)
+  if err != nil {
+    return nil, err
+  }
+
+  for i := range task.Attachments {
+    if task.Attachments[i].ID == attachmentID {
+      return &task.Attachments[i], nil
+    }
+  }
+  return nil, ErrAttachmentNotFound
+}
+
+// attachmentField - history field of one attachment
+func attachmentField(attachmentID bson.ObjectID) string {
+  return "attachments." + attachmentID.Hex()
+}
+
+// deleteBlob - best-effort removal of stored contents
+func deleteBlob(ctx context.Context, blobs storage.BlobStore, key string) {
+  if err := blobs.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+    log.Warn().Err(err).Str("key", key).Msg("Failed to delete attachment contents")
+  }
+}
+
+// sniffContentType - media type detected from the first bytes, without parameters
+func sniffContentType(head []byte) string {
+  contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+  return strings.TrimSpace(contentType)
+}
+
+// cleanFileName - last path element of a client file name, at most 255 bytes
+func cleanFileName(name string) string {
+  if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+    name = name[i+1:]
+  }
+  name = strings.TrimSpace(strings.ToValidUTF8(name, ""))
+  if len(name) > 255 {
+    name = strings.ToValidUTF8(name[:255], "")
+  }
+  if name == "" || name == "." || name == ".." {
+    return "file"
+  }
+  return name
+}
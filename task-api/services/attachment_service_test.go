@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/storage"
+	"task-api/types"
+)
+
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+func newTestAttachmentService(t *testing.T, maxSize int64) (*attachmentService, *MockTaskRepository, *MockTaskShareRepository, storage.BlobStore) {
+  taskRepo, shareRepo := new(MockTaskRepository), new(MockTaskShareRepository)
+  blobs := storage.NewLocalStore(t.TempDir())
+  return &attachmentService{taskRepo: taskRepo, shareRepo: shareRepo, eventRepo: newMockTaskEventRepository(), blobs: blobs, maxSize: maxSize}, taskRepo, shareRepo, blobs
+}
+
+func TestAttachmentService_UploadAttachment(t *testing.T) {
+  t.Run("should store file with sniffed type", func(t *testing.T) {
+    service, taskRepo, _, blobs := newTestAttachmentService(t, 1024)
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("AddAttachment", mock.Anything, task.ID, mock.MatchedBy(func(a models.Attachment) bool {
+      return a.Name == "site.png" && a.ContentType == "image/png" && a.Size == 12 && a.UploadedBy == userID
+    }), MaxAttachments).Return(nil)
+
+    // Client paths are stripped from the name
+    response, err := service.UploadAttachment(context.Background(), task.ID, userID, `C:\fakepath\site.png`, strings.NewReader(pngHeader+"data"))
+
+    assert.NoError(t, err)
+    assert.Equal(t, "image/png", response.ContentType)
+    taskRepo.AssertExpectations(t)
+
+    reader, err := blobs.Open(context.Background(), response.ID)
+    assert.NoError(t, err)
+    content, _ := io.ReadAll(reader)
+    reader.Close()
+    assert.Equal(t, pngHeader+"data", string(content))
+  })
+
+  t.Run("should reject content that is not an allowed type", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestAttachmentService(t, 1024)
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+    // The name claims a PDF, the contents are HTML
+    _, err := service.UploadAttachment(context.Background(), task.ID, userID, "report.pdf", strings.NewReader("<html><script>alert(1)</script></html>"))
+
+    assert.ErrorIs(t, err, ErrAttachmentType)
+    taskRepo.AssertNotCalled(t, "AddAttachment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should reject and remove oversized file", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestAttachmentService(t, 16)
+    dir := t.TempDir()
+    service.blobs = storage.NewLocalStore(dir)
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+    _, err := service.UploadAttachment(context.Background(), task.ID, userID, "big.pdf", strings.NewReader("%PDF-1.7 "+strings.Repeat("x", 100)))
+
+    assert.ErrorIs(t, err, ErrAttachmentTooLarge)
+    taskRepo.AssertNotCalled(t, "AddAttachment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    entries, _ := os.ReadDir(dir)
+    assert.Empty(t, entries)
+  })
+
+  t.Run("should refuse full task", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestAttachmentService(t, 1024)
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Attachments: make([]models.Attachment, MaxAttachments)}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+    _, err := service.UploadAttachment(context.Background(), task.ID, userID, "a.png", strings.NewReader(pngHeader))
+
+    assert.ErrorIs(t, err, ErrTooManyAttachments)
+  })
+
+  t.Run("should remove the contents when concurrent uploads filled the task", func(t *testing.T) {
+    service, taskRepo, _, blobs := newTestAttachmentService(t, 1024)
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Attachments: make([]models.Attachment, MaxAttachments-1)}
+
+    var key string
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("AddAttachment", mock.Anything, task.ID, mock.Anything, MaxAttachments).
+      Run(func(args mock.Arguments) { key = args.Get(2).(models.Attachment).ID.Hex() }).
+      Return(repositories.ErrTooManyAttachments)
+
+    _, err := service.UploadAttachment(context.Background(), task.ID, userID, "a.png", strings.NewReader(pngHeader))
+
+    assert.ErrorIs(t, err, ErrTooManyAttachments)
+    _, err = blobs.Open(context.Background(), key)
+    assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+  })
+
+  t.Run("should refuse viewers of a shared task", func(t *testing.T) {
+    service, taskRepo, shareRepo, _ := newTestAttachmentService(t, 1024)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(taskShare(taskID, userID, types.SharePermissionView), nil)
+
+    _, err := service.UploadAttachment(context.Background(), taskID, userID, "a.png", strings.NewReader(pngHeader))
+
+    assert.ErrorIs(t, err, ErrTaskPermissionDenied)
+  })
+}
+
+func TestAttachmentService_DeleteAttachment(t *testing.T) {
+  t.Run("should detach and delete contents", func(t *testing.T) {
+    service, taskRepo, _, blobs := newTestAttachmentService(t, 1024)
+    userID := bson.NewObjectID()
+    attachment := models.Attachment{ID: bson.NewObjectID(), Name: "a.png"}
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Attachments: []models.Attachment{attachment}}
+    blobs.Put(context.Background(), attachment.ID.Hex(), strings.NewReader(pngHeader))
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("RemoveAttachment", mock.Anything, task.ID, attachment.ID).Return(nil)
+
+    err := service.DeleteAttachment(context.Background(), task.ID, attachment.ID, userID)
+
+    assert.NoError(t, err)
+    _, err = blobs.Open(context.Background(), attachment.ID.Hex())
+    assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+  })
+
+  t.Run("should record the removal in the history", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestAttachmentService(t, 1024)
+    eventRepo := new(MockTaskEventRepository)
+    service.eventRepo = eventRepo
+    events := recordedEvents(eventRepo)
+    userID := bson.NewObjectID()
+    attachment := models.Attachment{ID: bson.NewObjectID(), Name: "a.png"}
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Attachments: []models.Attachment{attachment}}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("RemoveAttachment", mock.Anything, task.ID, attachment.ID).Return(nil)
+
+    err := service.DeleteAttachment(context.Background(), task.ID, attachment.ID, userID)
+
+    assert.NoError(t, err)
+    assert.Len(t, *events, 1)
+    assert.Equal(t, types.TaskEventUpdated, (*events)[0].Action)
+    assert.Equal(t, []models.FieldChange{{Field: "attachments." + attachment.ID.Hex(), Old: "a.png"}}, (*events)[0].Changes)
+  })
+
+  t.Run("should return not found for unknown attachment", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestAttachmentService(t, 1024)
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+    err := service.DeleteAttachment(context.Background(), task.ID, bson.NewObjectID(), userID)
+
+    assert.ErrorIs(t, err, ErrAttachmentNotFound)
+  })
+}
+
+func TestAttachmentService_OpenAttachment(t *testing.T) {
+  t.Run("should open contents for viewers", func(t *testing.T) {
+    service, taskRepo, shareRepo, blobs := newTestAttachmentService(t, 1024)
+    userID := bson.NewObjectID()
+    attachment := models.Attachment{ID: bson.NewObjectID(), Name: "a.png", ContentType: "image/png", Size: 8}
+    task := models.Task{ID: bson.NewObjectID(), UserID: bson.NewObjectID(), Attachments: []models.Attachment{attachment}}
+    blobs.Put(context.Background(), attachment.ID.Hex(), strings.NewReader(pngHeader))
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, task.ID, userID).Return(taskShare(task.ID, userID, types.SharePermissionView), nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{task.ID}).Return([]models.Task{task}, nil)
+
+    response, reader, err := service.OpenAttachment(context.Background(), task.ID, attachment.ID, userID)
+
+    assert.NoError(t, err)
+    assert.Equal(t, "a.png", response.Name)
+    content, _ := io.ReadAll(reader)
+    reader.Close()
+    assert.Equal(t, pngHeader, string(content))
+  })
+}
@@ -3,8 +3,16 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/configs"
+	"task-api/mailer"
+	"task-api/models"
 	"task-api/repositories"
 	"task-api/types"
 	"task-api/utils"
@@ -12,20 +20,66 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// Auth errors that handlers map to specific HTTP statuses
+var (
+  ErrEmailAlreadyRegistered = errors.New("email already registered")
+  ErrWeakPassword           = errors.New("weak password")
+  ErrInvalidRefreshToken    = errors.New("invalid refresh token")
+  ErrRefreshTokenReused     = errors.New("refresh token reused")
+  ErrInvalidResetToken      = errors.New("invalid reset token")
+  ErrInvalidCurrentPassword = errors.New("invalid current password")
+  ErrInvalidTimezone        = errors.New("invalid timezone")
+  ErrUserNotFound           = errors.New("user not found")
+  ErrAccountDisabled        = errors.New("account disabled")
+)
+
 // AuthService - interface for auth service
 type AuthService interface {
   Login(ctx context.Context, input types.LoginInput) (*types.LoginResponse, error)
+  Register(ctx context.Context, input types.RegisterInput) (*types.LoginResponse, error)
+  Refresh(ctx context.Context, refreshToken string) (*types.LoginResponse, error)
+  Logout(ctx context.Context, userID bson.ObjectID, tokenID string, expiresAt time.Time, input types.LogoutInput) error
+  LogoutAll(ctx context.Context, userID bson.ObjectID, tokenID string, expiresAt time.Time) error
+  ForgotPassword(ctx context.Context, input types.ForgotPasswordInput) error
+  ResetPassword(ctx context.Context, input types.ResetPasswordInput) error
+  GetProfile(ctx context.Context, userID bson.ObjectID) (*types.UserResponse, error)
+  UpdateProfile(ctx context.Context, userID bson.ObjectID, input types.UpdateProfileInput) (*types.UserResponse, error)
+  ChangePassword(ctx context.Context, userID bson.ObjectID, tokenID string, expiresAt time.Time, input types.ChangePasswordInput) (*types.LoginResponse, error)
+  SetupMFA(ctx context.Context, userID bson.ObjectID) (*types.MFASetupResponse, error)
+  VerifyMFASetup(ctx context.Context, userID bson.ObjectID, input types.MFAVerifyInput) (*types.MFAVerifyResponse, error)
+  LoginMFA(ctx context.Context, input types.MFALoginInput) (*types.LoginResponse, error)
+  LoginExternal(ctx context.Context, user *models.User) (*types.LoginResponse, error)
 }
 
 // authService - implement AuthService
 type authService struct {
-  userRepo repositories.UserRepository
+  userRepo    repositories.UserRepository
+  refreshRepo repositories.RefreshTokenRepository
+  revocations repositories.RevocationStore
+  resetRepo   repositories.PasswordResetRepository
+  mailer      mailer.Mailer
+  throttle    *loginThrottle
 }
 
 // NewAuthService - constructor
-func NewAuthService(userRepo repositories.UserRepository) AuthService {
+func NewAuthService(
+  userRepo repositories.UserRepository,
+  refreshRepo repositories.RefreshTokenRepository,
+  revocations repositories.RevocationStore,
+  resetRepo repositories.PasswordResetRepository,
+  mailer mailer.Mailer,
+  attempts repositories.LoginAttemptStore,
+) AuthService {
   return &authService{
-    userRepo: userRepo,
+    userRepo:    userRepo,
+    refreshRepo: refreshRepo,
+    revocations: revocations,
+    resetRepo:   resetRepo,
+    mailer:      mailer,
+    throttle: &loginThrottle{
+      store:  attempts,
+      config: LoginThrottleConfigFromEnv(),
+    },
   }
 }
 
@@ -35,13 +89,20 @@ func (s *authService) Login(ctx context.Context, input types.LoginInput) (*types
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
 
+  email := normalizeEmail(input.Email)
+
+  // Refuse early while the account or client IP is locked or throttled
+  if err := s.throttle.check(ctx, email, input.ClientIP); err != nil {
+    return nil, err
+  }
+
   // Find user by email
-  user, err := s.userRepo.FindByEmail(ctx, input.Email)
+  user, err := s.userRepo.FindByEmail(ctx, email)
   if err != nil {
 		log.Debug().
       Str("email", input.Email).
       Msg("User not found in database")
-    return nil, errors.New("invalid credentials")
+    return nil, s.loginFailed(ctx, email, input.ClientIP)
   }
 
   // Verify password
@@ -49,11 +110,439 @@ func (s *authService) Login(ctx context.Context, input types.LoginInput) (*types
 		log.Debug().
       Str("email", input.Email).
       Msg("Invalid password")
-    return nil, errors.New("invalid credentials")
+    return nil, s.loginFailed(ctx, email, input.ClientIP)
+  }
+
+  // Only reported after the password check so it does not reveal accounts
+  if user.DisabledAt != nil {
+    return nil, ErrAccountDisabled
+  }
+
+  // Second factor pending: counters are only reset once the code is verified,
+  // otherwise repeating the password step would allow unlimited code guesses
+  if user.MFAEnabled {
+    return s.issueMFAChallenge(user)
+  }
+
+  if err := s.throttle.reset(ctx, email); err != nil {
+    return nil, err
+  }
+
+  // Every login starts a new refresh token family
+  return s.issueLoginResponse(ctx, user, bson.NewObjectID())
+}
+
+// LoginExternal - start a session for a user authenticated by an identity provider,
+// 2FA enabled accounts still get the second factor challenge
+func (s *authService) LoginExternal(ctx context.Context, user *models.User) (*types.LoginResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if user.DisabledAt != nil {
+    return nil, ErrAccountDisabled
+  }
+
+  if user.MFAEnabled {
+    return s.issueMFAChallenge(user)
+  }
+
+  return s.issueLoginResponse(ctx, user, bson.NewObjectID())
+}
+
+// loginFailed - record failed attempt, unknown emails count too so
+// lockouts do not reveal which accounts exist
+func (s *authService) loginFailed(ctx context.Context, email, ip string) error {
+  if err := s.throttle.recordFailure(ctx, email, ip); err != nil {
+    return err
+  }
+  return errors.New("invalid credentials")
+}
+
+// Register - create a new account and log it in
+func (s *authService) Register(ctx context.Context, input types.RegisterInput) (*types.LoginResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  email := normalizeEmail(input.Email)
+
+  // Check password strength before touching the database
+  if err := utils.ValidatePasswordStrength(input.Password); err != nil {
+    return nil, fmt.Errorf("%w: %v", ErrWeakPassword, err)
+  }
+
+  // Reject known duplicates early, the unique index still guards races
+  exists, err := s.userRepo.ExistsByEmail(ctx, email)
+  if err != nil {
+    return nil, err
+  }
+  if exists {
+    return nil, ErrEmailAlreadyRegistered
+  }
+
+  hashedPassword, err := utils.HashPassword(input.Password)
+  if err != nil {
+		log.Error().
+      Err(err).
+      Msg("Failed to hash password")
+    return nil, errors.New("failed to hash password")
+  }
+
+  now := time.Now()
+  user := &models.User{
+    ID:        bson.NewObjectID(),
+    Email:     email,
+    Password:  hashedPassword,
+    Name:      strings.TrimSpace(input.Name),
+    Role:      types.RoleMember,
+    CreatedAt: now,
+    UpdatedAt: now,
+  }
+
+  if err := s.userRepo.Create(ctx, user); err != nil {
+    if errors.Is(err, repositories.ErrEmailAlreadyExists) {
+      return nil, ErrEmailAlreadyRegistered
+    }
+    return nil, err
+  }
+
+  return s.issueLoginResponse(ctx, user, bson.NewObjectID())
+}
+
+// Refresh - rotate refresh token and issue a new access token
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*types.LoginResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  stored, err := s.refreshRepo.FindByHash(ctx, utils.HashToken(refreshToken))
+  if err != nil {
+    if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+      return nil, ErrInvalidRefreshToken
+    }
+    return nil, err
+  }
+
+  if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+    return nil, ErrInvalidRefreshToken
+  }
+
+  // A token that was already rotated is being replayed: assume it leaked
+  // and kill the whole family so neither party can keep using it
+  if stored.UsedAt != nil {
+    return nil, s.revokeReusedFamily(ctx, stored)
+  }
+
+  marked, err := s.refreshRepo.MarkUsed(ctx, stored.ID)
+  if err != nil {
+    return nil, err
+  }
+  if !marked {
+    // Lost the race against a concurrent refresh with the same token
+    return nil, s.revokeReusedFamily(ctx, stored)
+  }
+
+  user, err := s.userRepo.FindByID(ctx, stored.UserID)
+  if err != nil {
+    if errors.Is(err, repositories.ErrUserNotFound) {
+      return nil, ErrInvalidRefreshToken
+    }
+    return nil, err
+  }
+  if user.DisabledAt != nil {
+    return nil, ErrInvalidRefreshToken
+  }
+
+  return s.issueLoginResponse(ctx, user, stored.FamilyID)
+}
+
+// Logout - revoke current access token and, if given, its refresh token family
+func (s *authService) Logout(ctx context.Context, userID bson.ObjectID, tokenID string, expiresAt time.Time, input types.LogoutInput) error {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if err := s.revocations.RevokeToken(ctx, tokenID, expiresAt); err != nil {
+    return err
+  }
+
+  if input.RefreshToken == "" {
+    return nil
+  }
+
+  stored, err := s.refreshRepo.FindByHash(ctx, utils.HashToken(input.RefreshToken))
+  if err != nil {
+    if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+      return nil
+    }
+    return err
   }
 
+  // Never let one user revoke another user's session
+  if stored.UserID != userID {
+    return nil
+  }
+
+  return s.refreshRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+// LogoutAll - revoke every access and refresh token of user
+func (s *authService) LogoutAll(ctx context.Context, userID bson.ObjectID, tokenID string, expiresAt time.Time) error {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  return s.revokeAllSessions(ctx, userID, tokenID, expiresAt)
+}
+
+// ForgotPassword - email a single-use reset link. Returns nil for unknown
+// emails so the endpoint cannot be used to discover registered accounts.
+func (s *authService) ForgotPassword(ctx context.Context, input types.ForgotPasswordInput) error {
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  user, err := s.userRepo.FindByEmail(ctx, normalizeEmail(input.Email))
+  if err != nil {
+    if errors.Is(err, repositories.ErrUserNotFound) {
+		  log.Debug().
+        Str("email", input.Email).
+        Msg("Password reset requested for unknown email")
+      return nil
+    }
+    return err
+  }
+
+  // Only the newest link stays valid
+  if err := s.resetRepo.InvalidateForUser(ctx, user.ID); err != nil {
+    return err
+  }
+
+  token, err := utils.GenerateOpaqueToken()
+  if err != nil {
+    return err
+  }
+
+  now := time.Now()
+  ttl := configs.GetDuration("PASSWORD_RESET_TTL", time.Hour)
+  err = s.resetRepo.Create(ctx, &models.PasswordReset{
+    ID:        bson.NewObjectID(),
+    UserID:    user.ID,
+    TokenHash: utils.HashToken(token),
+    ExpiresAt: now.Add(ttl),
+    CreatedAt: now,
+  })
+  if err != nil {
+    return err
+  }
+
+  link := configs.GetEnv("PASSWORD_RESET_URL", "http://localhost:5173/reset-password") + "?token=" + url.QueryEscape(token)
+
+  err = s.mailer.Send(ctx, mailer.Message{
+    To:      user.Email,
+    Subject: "Reset your password",
+    Body: fmt.Sprintf(
+      "Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s\n\nThe link expires in %s and can only be used once. If you did not request this, you can ignore this email.\n",
+      user.Name, link, ttl,
+    ),
+  })
+  if err != nil {
+		log.Error().
+      Err(err).
+      Str("user_id", user.ID.Hex()).
+      Msg("Failed to send password reset email")
+    // Answered like an unknown email, an error here would tell that the account exists
+    return nil
+  }
+
+  return nil
+}
+
+// ResetPassword - set new password with a reset token and end every existing session
+func (s *authService) ResetPassword(ctx context.Context, input types.ResetPasswordInput) error {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if err := utils.ValidatePasswordStrength(input.Password); err != nil {
+    return fmt.Errorf("%w: %v", ErrWeakPassword, err)
+  }
+
+  reset, err := s.resetRepo.Consume(ctx, utils.HashToken(input.Token))
+  if err != nil {
+    if errors.Is(err, repositories.ErrResetTokenInvalid) {
+      return ErrInvalidResetToken
+    }
+    return err
+  }
+
+  hashedPassword, err := utils.HashPassword(input.Password)
+  if err != nil {
+    return errors.New("failed to hash password")
+  }
+
+  if err := s.userRepo.UpdatePassword(ctx, reset.UserID, hashedPassword); err != nil {
+    if errors.Is(err, repositories.ErrUserNotFound) {
+      return ErrInvalidResetToken
+    }
+    return err
+  }
+
+  // Whoever knew the old password must not stay logged in
+  if err := s.revokeAllSessions(ctx, reset.UserID, "", time.Time{}); err != nil {
+    return err
+  }
+
+  // Proving control of the mailbox unlocks an account locked by failed logins
+  user, err := s.userRepo.FindByID(ctx, reset.UserID)
+  if err != nil {
+    return err
+  }
+  if err := s.throttle.reset(ctx, user.Email); err != nil {
+    return err
+  }
+
+  log.Info().Str("user_id", reset.UserID.Hex()).Msg("Password reset completed")
+
+  return nil
+}
+
+// GetProfile - get current user
+func (s *authService) GetProfile(ctx context.Context, userID bson.ObjectID) (*types.UserResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  user, err := s.findUser(ctx, userID)
+  if err != nil {
+    return nil, err
+  }
+
+  response := types.ToUserResponse(user)
+  return &response, nil
+}
+
+// UpdateProfile - update name, timezone and locale of current user
+func (s *authService) UpdateProfile(ctx context.Context, userID bson.ObjectID, input types.UpdateProfileInput) (*types.UserResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  // Build update document
+  updates := bson.M{}
+
+  if input.Name != nil {
+    updates["name"] = strings.TrimSpace(*input.Name)
+  }
+
+  if input.Timezone != nil {
+    if _, err := time.LoadLocation(*input.Timezone); err != nil || *input.Timezone == "Local" {
+      return nil, ErrInvalidTimezone
+    }
+    updates["timezone"] = *input.Timezone
+  }
+
+  if input.Locale != nil {
+    updates["locale"] = *input.Locale
+  }
+
+  if len(updates) > 0 {
+    if err := s.userRepo.Update(ctx, userID, updates); err != nil {
+      if errors.Is(err, repositories.ErrUserNotFound) {
+        return nil, ErrUserNotFound
+      }
+      return nil, err
+    }
+  }
+
+  user, err := s.findUser(ctx, userID)
+  if err != nil {
+    return nil, err
+  }
+
+  response := types.ToUserResponse(user)
+  return &response, nil
+}
+
+// ChangePassword - verify current password, set new one, log out every other
+// session and return a fresh token pair for the caller
+func (s *authService) ChangePassword(ctx context.Context, userID bson.ObjectID, tokenID string, expiresAt time.Time, input types.ChangePasswordInput) (*types.LoginResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  user, err := s.findUser(ctx, userID)
+  if err != nil {
+    return nil, err
+  }
+
+  if !utils.CheckPassword(user.Password, input.CurrentPassword) {
+    log.Debug().
+      Str("user_id", userID.Hex()).
+      Msg("Invalid current password")
+    return nil, ErrInvalidCurrentPassword
+  }
+
+  if err := utils.ValidatePasswordStrength(input.NewPassword); err != nil {
+    return nil, fmt.Errorf("%w: %v", ErrWeakPassword, err)
+  }
+
+  hashedPassword, err := utils.HashPassword(input.NewPassword)
+  if err != nil {
+    return nil, errors.New("failed to hash password")
+  }
+
+  if err := s.userRepo.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+    return nil, err
+  }
+
+  if err := s.revokeAllSessions(ctx, userID, tokenID, expiresAt); err != nil {
+    return nil, err
+  }
+
+  log.Info().Str("user_id", userID.Hex()).Msg("Password changed")
+
+  return s.issueLoginResponse(ctx, user, bson.NewObjectID())
+}
+
+// findUser - find user by ID, mapping missing users to ErrUserNotFound
+func (s *authService) findUser(ctx context.Context, userID bson.ObjectID) (*models.User, error) {
+  user, err := s.userRepo.FindByID(ctx, userID)
+  if err != nil {
+    if errors.Is(err, repositories.ErrUserNotFound) {
+      return nil, ErrUserNotFound
+    }
+    return nil, err
+  }
+  return user, nil
+}
+
+// revokeAllSessions - revoke refresh tokens and access tokens issued until now.
+// The calling token is revoked explicitly because iat only has second precision.
+func (s *authService) revokeAllSessions(ctx context.Context, userID bson.ObjectID, tokenID string, expiresAt time.Time) error {
+  if err := s.refreshRepo.RevokeAllForUser(ctx, userID); err != nil {
+    return err
+  }
+
+  now := time.Now()
+  if err := s.revocations.RevokeAllForUser(ctx, userID, now, now.Add(utils.AccessTokenTTL())); err != nil {
+    return err
+  }
+
+  if tokenID != "" {
+    return s.revocations.RevokeToken(ctx, tokenID, expiresAt)
+  }
+  return nil
+}
+
+// revokeReusedFamily - revoke token family after reuse detection
+func (s *authService) revokeReusedFamily(ctx context.Context, stored *models.RefreshToken) error {
+	log.Warn().
+    Str("user_id", stored.UserID.Hex()).
+    Str("family_id", stored.FamilyID.Hex()).
+    Msg("Refresh token reuse detected, revoking token family")
+
+  if err := s.refreshRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+    return err
+  }
+  return ErrRefreshTokenReused
+}
+
+// issueLoginResponse - generate access and refresh tokens and build login response for user
+func (s *authService) issueLoginResponse(ctx context.Context, user *models.User, familyID bson.ObjectID) (*types.LoginResponse, error) {
   // Generate JWT token
-  token, err := utils.GenerateToken(user.ID, user.Email)
+  token, err := utils.GenerateToken(user.ID, user.Email, types.EffectiveRole(user.Role))
   if err != nil {
 		log.Error().
       Err(err).
@@ -62,11 +551,41 @@ func (s *authService) Login(ctx context.Context, input types.LoginInput) (*types
     return nil, errors.New("failed to generate token")
   }
 
+  // Generate opaque refresh token, only its hash is persisted
+  refreshToken, err := utils.GenerateOpaqueToken()
+  if err != nil {
+    return nil, errors.New("failed to generate token")
+  }
+
+  now := time.Now()
+  err = s.refreshRepo.Create(ctx, &models.RefreshToken{
+    ID:        bson.NewObjectID(),
+    UserID:    user.ID,
+    FamilyID:  familyID,
+    TokenHash: utils.HashToken(refreshToken),
+    ExpiresAt: now.Add(utils.RefreshTokenTTL()),
+    CreatedAt: now,
+  })
+  if err != nil {
+		log.Error().
+      Err(err).
+      Str("user_id", user.ID.Hex()).
+      Msg("Failed to store refresh token")
+    return nil, errors.New("failed to generate token")
+  }
+
   // Convert to response DTO
   userResponse := types.ToUserResponse(user)
 
   return &types.LoginResponse{
-    Token: token,
-    User:  userResponse,
+    Token:        token,
+    RefreshToken: refreshToken,
+    ExpiresIn:    int64(utils.AccessTokenTTL().Seconds()),
+    User:         userResponse,
   }, nil
 }
+
+// normalizeEmail - emails are stored lowercase and trimmed
+func normalizeEmail(email string) string {
+  return strings.ToLower(strings.TrimSpace(email))
+}
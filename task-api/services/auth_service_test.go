@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,7 +13,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
 
+	"task-api/mailer"
 	"task-api/models"
+	"task-api/repositories"
 	"task-api/types"
 	"task-api/utils"
 )
@@ -29,6 +33,154 @@ func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*mo
   return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) FindByID(ctx context.Context, id bson.ObjectID) (*models.User, error) {
+  args := m.Called(ctx, id)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByOIDCSubject(ctx context.Context, issuer, subject string) (*models.User, error) {
+  args := m.Called(ctx, issuer, subject)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+  args := m.Called(ctx, email)
+  return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user *models.User) error {
+  args := m.Called(ctx, user)
+  return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id bson.ObjectID, hashedPassword string) error {
+  args := m.Called(ctx, id, hashedPassword)
+  return args.Error(0)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, id bson.ObjectID, updates bson.M) error {
+  args := m.Called(ctx, id, updates)
+  return args.Error(0)
+}
+
+func (m *MockUserRepository) UseTOTPStep(ctx context.Context, id bson.ObjectID, step int64) (bool, error) {
+  args := m.Called(ctx, id, step)
+  return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) ConsumeRecoveryCode(ctx context.Context, id bson.ObjectID, codeHash string) (bool, error) {
+  args := m.Called(ctx, id, codeHash)
+  return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) LinkOIDCIdentity(ctx context.Context, id bson.ObjectID, issuer, subject string) (bool, error) {
+  args := m.Called(ctx, id, issuer, subject)
+  return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, query types.UserQueryParams) ([]models.User, int64, error) {
+  args := m.Called(ctx, query)
+  if args.Get(0) == nil {
+    return nil, args.Get(1).(int64), args.Error(2)
+  }
+  return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
+
+// MockRefreshTokenRepository
+type MockRefreshTokenRepository struct {
+  mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+  args := m.Called(ctx, token)
+  return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+  args := m.Called(ctx, tokenHash)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) MarkUsed(ctx context.Context, id bson.ObjectID) (bool, error) {
+  args := m.Called(ctx, id)
+  return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID bson.ObjectID) error {
+  args := m.Called(ctx, familyID)
+  return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID bson.ObjectID) error {
+  args := m.Called(ctx, userID)
+  return args.Error(0)
+}
+
+// MockPasswordResetRepository
+type MockPasswordResetRepository struct {
+  mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(ctx context.Context, reset *models.PasswordReset) error {
+  args := m.Called(ctx, reset)
+  return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) Consume(ctx context.Context, tokenHash string) (*models.PasswordReset, error) {
+  args := m.Called(ctx, tokenHash)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*models.PasswordReset), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) InvalidateForUser(ctx context.Context, userID bson.ObjectID) error {
+  args := m.Called(ctx, userID)
+  return args.Error(0)
+}
+
+// MockMailer
+type MockMailer struct {
+  mock.Mock
+}
+
+func (m *MockMailer) Send(ctx context.Context, msg mailer.Message) error {
+  args := m.Called(ctx, msg)
+  return args.Error(0)
+}
+
+// authServiceMocks - mocked dependencies of authService
+type authServiceMocks struct {
+  userRepo    *MockUserRepository
+  refreshRepo *MockRefreshTokenRepository
+  revocations repositories.RevocationStore
+  resetRepo   *MockPasswordResetRepository
+  mailer      *MockMailer
+  attempts    repositories.LoginAttemptStore
+}
+
+// newTestAuthService - build authService wired to fresh mocks
+func newTestAuthService() (AuthService, *authServiceMocks) {
+  mocks := &authServiceMocks{
+    userRepo:    new(MockUserRepository),
+    refreshRepo: new(MockRefreshTokenRepository),
+    revocations: repositories.NewMemoryRevocationStore(),
+    resetRepo:   new(MockPasswordResetRepository),
+    mailer:      new(MockMailer),
+    attempts:    repositories.NewMemoryLoginAttemptStore(),
+  }
+  return NewAuthService(mocks.userRepo, mocks.refreshRepo, mocks.revocations, mocks.resetRepo, mocks.mailer, mocks.attempts), mocks
+}
+
 // TestMain sets up environment for all service tests
 func TestMain(m *testing.M) {
   // Setup: Configure JWT_SECRET for token generation
@@ -47,8 +199,8 @@ func TestMain(m *testing.M) {
 
 func TestAuthService_Login(t *testing.T) {
   t.Run("should login successfully with correct credentials", func(t *testing.T) {
-    mockRepo := new(MockUserRepository)
-    service := NewAuthService(mockRepo)
+    service, mocks := newTestAuthService()
+    mockRepo := mocks.userRepo
 
     hashedPassword, _ := utils.HashPassword("password123")
     mockUser := &models.User{
@@ -61,6 +213,7 @@ func TestAuthService_Login(t *testing.T) {
     }
 
     mockRepo.On("FindByEmail", mock.Anything, "test@test.com").Return(mockUser, nil)
+    mocks.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
 
     input := types.LoginInput{
       Email:    "test@test.com",
@@ -72,15 +225,17 @@ func TestAuthService_Login(t *testing.T) {
     assert.NoError(t, err)
     assert.NotNil(t, result)
     assert.NotEmpty(t, result.Token)
+    assert.NotEmpty(t, result.RefreshToken)
     assert.Equal(t, "test@test.com", result.User.Email)
     assert.Equal(t, "Test User", result.User.Name)
     
     mockRepo.AssertExpectations(t)
+    mocks.refreshRepo.AssertExpectations(t)
   })
 
   t.Run("should fail with wrong password", func(t *testing.T) {
-    mockRepo := new(MockUserRepository)
-    service := NewAuthService(mockRepo)
+    service, mocks := newTestAuthService()
+    mockRepo := mocks.userRepo
 
     hashedPassword, _ := utils.HashPassword("correctpassword")
     mockUser := &models.User{
@@ -106,8 +261,8 @@ func TestAuthService_Login(t *testing.T) {
   })
 
   t.Run("should fail when user not found", func(t *testing.T) {
-    mockRepo := new(MockUserRepository)
-    service := NewAuthService(mockRepo)
+    service, mocks := newTestAuthService()
+    mockRepo := mocks.userRepo
 
     mockRepo.On("FindByEmail", mock.Anything, "notfound@test.com").
       Return(nil, errors.New("user not found"))
@@ -124,4 +279,711 @@ func TestAuthService_Login(t *testing.T) {
 
     mockRepo.AssertExpectations(t)
   })
+
+  t.Run("should refuse disabled account after password check", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    hashedPassword, _ := utils.HashPassword("password123")
+    disabledAt := time.Now()
+    mockUser := &models.User{
+      ID:         bson.NewObjectID(),
+      Email:      "test@test.com",
+      Password:   hashedPassword,
+      DisabledAt: &disabledAt,
+    }
+    mocks.userRepo.On("FindByEmail", mock.Anything, "test@test.com").Return(mockUser, nil)
+
+    result, err := service.Login(context.Background(), types.LoginInput{Email: "test@test.com", Password: "password123"})
+    assert.ErrorIs(t, err, ErrAccountDisabled)
+    assert.Nil(t, result)
+
+    // Wrong password still looks like any other failed login
+    _, err = service.Login(context.Background(), types.LoginInput{Email: "test@test.com", Password: "wrong"})
+    assert.NotErrorIs(t, err, ErrAccountDisabled)
+
+    mocks.refreshRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should put role into access token", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    hashedPassword, _ := utils.HashPassword("password123")
+    mockUser := &models.User{ID: bson.NewObjectID(), Email: "test@test.com", Password: hashedPassword, Role: types.RoleAdmin}
+    mocks.userRepo.On("FindByEmail", mock.Anything, "test@test.com").Return(mockUser, nil)
+    mocks.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+    result, err := service.Login(context.Background(), types.LoginInput{Email: "test@test.com", Password: "password123"})
+    assert.NoError(t, err)
+    assert.Equal(t, types.RoleAdmin, result.User.Role)
+
+    claims, err := utils.ValidateToken(result.Token)
+    assert.NoError(t, err)
+    assert.Equal(t, types.RoleAdmin, claims.Role)
+  })
+}
+
+func TestAuthService_LoginThrottle(t *testing.T) {
+  hashedPassword, _ := utils.HashPassword("password123")
+
+  // newThrottledService - auth service with small limits and no delays
+  newThrottledService := func() (AuthService, *authServiceMocks) {
+    service, mocks := newTestAuthService()
+    service.(*authService).throttle.config = LoginThrottleConfig{
+      Window:           time.Minute,
+      DelayAfter:       100,
+      BaseDelay:        time.Second,
+      MaxEmailFailures: 3,
+      MaxIPFailures:    5,
+      LockoutDuration:  time.Minute,
+    }
+    mocks.userRepo.On("FindByEmail", mock.Anything, "test@test.com").
+      Return(&models.User{ID: bson.NewObjectID(), Email: "test@test.com", Password: hashedPassword}, nil)
+    mocks.userRepo.On("FindByEmail", mock.Anything, mock.Anything).Return(nil, repositories.ErrUserNotFound)
+    return service, mocks
+  }
+
+  wrong := types.LoginInput{Email: "test@test.com", Password: "wrongpassword", ClientIP: "10.0.0.1"}
+
+  t.Run("should lock account after max failures", func(t *testing.T) {
+    service, _ := newThrottledService()
+
+    for i := 0; i < 2; i++ {
+      _, err := service.Login(context.Background(), wrong)
+      assert.EqualError(t, err, "invalid credentials")
+    }
+
+    _, err := service.Login(context.Background(), wrong)
+    assert.ErrorIs(t, err, ErrAccountLocked)
+
+    // Even the correct password is refused while locked
+    _, err = service.Login(context.Background(), types.LoginInput{Email: "test@test.com", Password: "password123", ClientIP: "10.0.0.2"})
+
+    var blocked *LoginBlockedError
+    assert.ErrorAs(t, err, &blocked)
+    assert.ErrorIs(t, err, ErrAccountLocked)
+    assert.InDelta(t, time.Minute.Seconds(), blocked.RetryAfter.Seconds(), 1)
+  })
+
+  t.Run("should lock client IP spraying many accounts", func(t *testing.T) {
+    service, _ := newThrottledService()
+
+    var err error
+    for i := 0; i < 5; i++ {
+      _, err = service.Login(context.Background(), types.LoginInput{
+        Email:    fmt.Sprintf("user%d@test.com", i),
+        Password: "password123",
+        ClientIP: "10.0.0.1",
+      })
+    }
+    assert.ErrorIs(t, err, ErrTooManyAttempts)
+
+    _, err = service.Login(context.Background(), types.LoginInput{Email: "test@test.com", Password: "password123", ClientIP: "10.0.0.1"})
+    assert.ErrorIs(t, err, ErrTooManyAttempts)
+  })
+
+  t.Run("should reset failures after successful login", func(t *testing.T) {
+    service, mocks := newThrottledService()
+    mocks.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+    service.Login(context.Background(), wrong)
+    service.Login(context.Background(), wrong)
+
+    _, err := service.Login(context.Background(), types.LoginInput{Email: "test@test.com", Password: "password123"})
+    assert.NoError(t, err)
+
+    attempt, _ := mocks.attempts.Get(context.Background(), "email:test@test.com")
+    assert.Nil(t, attempt)
+  })
+
+  t.Run("should enforce progressive delay", func(t *testing.T) {
+    service, _ := newThrottledService()
+    service.(*authService).throttle.config.DelayAfter = 1
+
+    _, err := service.Login(context.Background(), wrong)
+    assert.EqualError(t, err, "invalid credentials")
+
+    _, err = service.Login(context.Background(), wrong)
+    assert.ErrorIs(t, err, ErrTooManyAttempts)
+  })
+
+  t.Run("should refuse logins when the attempt store fails", func(t *testing.T) {
+    service, mocks := newThrottledService()
+    service.(*authService).throttle.store = failingAttemptStore{mocks.attempts}
+
+    _, err := service.Login(context.Background(), types.LoginInput{Email: "test@test.com", Password: "password123"})
+
+    assert.ErrorIs(t, err, ErrLoginUnavailable)
+    mocks.refreshRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+}
+
+// failingAttemptStore - attempt store whose reads fail like an unreachable database
+type failingAttemptStore struct {
+  repositories.LoginAttemptStore
+}
+
+func (failingAttemptStore) Get(ctx context.Context, key string) (*models.LoginAttempt, error) {
+  return nil, errors.New("connection refused")
+}
+
+func TestLoginThrottle_Delay(t *testing.T) {
+  throttle := &loginThrottle{config: LoginThrottleConfig{
+    DelayAfter:      3,
+    BaseDelay:       time.Second,
+    LockoutDuration: 10 * time.Second,
+  }}
+
+  assert.Equal(t, time.Duration(0), throttle.delay(2))
+  assert.Equal(t, time.Second, throttle.delay(3))
+  assert.Equal(t, 2*time.Second, throttle.delay(4))
+  assert.Equal(t, 8*time.Second, throttle.delay(6))
+  assert.Equal(t, 10*time.Second, throttle.delay(20))
+}
+
+func TestAuthService_Register(t *testing.T) {
+  t.Run("should register and return token", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    mockRepo := mocks.userRepo
+
+    var created *models.User
+    mockRepo.On("ExistsByEmail", mock.Anything, "new@test.com").Return(false, nil)
+    mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).
+      Run(func(args mock.Arguments) {
+        created = args.Get(1).(*models.User)
+      }).
+      Return(nil)
+    mocks.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+    input := types.RegisterInput{
+      Email:    "  New@Test.com ",
+      Password: "Password123",
+      Name:     "New User",
+    }
+
+    result, err := service.Register(context.Background(), input)
+
+    assert.NoError(t, err)
+    assert.NotNil(t, result)
+    assert.NotEmpty(t, result.Token)
+    assert.Equal(t, "new@test.com", result.User.Email)
+    assert.Equal(t, "New User", result.User.Name)
+
+    // Password must be stored hashed
+    assert.NotEqual(t, "Password123", created.Password)
+    assert.True(t, utils.CheckPassword(created.Password, "Password123"))
+
+    mockRepo.AssertExpectations(t)
+  })
+
+  t.Run("should reject weak password", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    mockRepo := mocks.userRepo
+
+    input := types.RegisterInput{
+      Email:    "new@test.com",
+      Password: "password",
+      Name:     "New User",
+    }
+
+    result, err := service.Register(context.Background(), input)
+
+    assert.ErrorIs(t, err, ErrWeakPassword)
+    assert.Nil(t, result)
+
+    mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should reject existing email", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    mockRepo := mocks.userRepo
+
+    mockRepo.On("ExistsByEmail", mock.Anything, "taken@test.com").Return(true, nil)
+
+    input := types.RegisterInput{
+      Email:    "taken@test.com",
+      Password: "Password123",
+      Name:     "Someone",
+    }
+
+    result, err := service.Register(context.Background(), input)
+
+    assert.ErrorIs(t, err, ErrEmailAlreadyRegistered)
+    assert.Nil(t, result)
+
+    mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should map duplicate key race to ErrEmailAlreadyRegistered", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    mockRepo := mocks.userRepo
+
+    mockRepo.On("ExistsByEmail", mock.Anything, "race@test.com").Return(false, nil)
+    mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).
+      Return(repositories.ErrEmailAlreadyExists)
+
+    input := types.RegisterInput{
+      Email:    "race@test.com",
+      Password: "Password123",
+      Name:     "Racer",
+    }
+
+    result, err := service.Register(context.Background(), input)
+
+    assert.ErrorIs(t, err, ErrEmailAlreadyRegistered)
+    assert.Nil(t, result)
+
+    mockRepo.AssertExpectations(t)
+  })
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+  newStoredToken := func(userID bson.ObjectID, raw string) *models.RefreshToken {
+    return &models.RefreshToken{
+      ID:        bson.NewObjectID(),
+      UserID:    userID,
+      FamilyID:  bson.NewObjectID(),
+      TokenHash: utils.HashToken(raw),
+      ExpiresAt: time.Now().Add(time.Hour),
+      CreatedAt: time.Now(),
+    }
+  }
+
+  t.Run("should rotate refresh token within the same family", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    user := &models.User{ID: bson.NewObjectID(), Email: "test@test.com", Name: "Test"}
+    stored := newStoredToken(user.ID, "old-token")
+
+    var rotated *models.RefreshToken
+    mocks.refreshRepo.On("FindByHash", mock.Anything, utils.HashToken("old-token")).Return(stored, nil)
+    mocks.refreshRepo.On("MarkUsed", mock.Anything, stored.ID).Return(true, nil)
+    mocks.userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+    mocks.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).
+      Run(func(args mock.Arguments) {
+        rotated = args.Get(1).(*models.RefreshToken)
+      }).
+      Return(nil)
+
+    result, err := service.Refresh(context.Background(), "old-token")
+
+    assert.NoError(t, err)
+    assert.NotEmpty(t, result.Token)
+    assert.NotEqual(t, "old-token", result.RefreshToken)
+    assert.Equal(t, stored.FamilyID, rotated.FamilyID)
+    assert.Equal(t, utils.HashToken(result.RefreshToken), rotated.TokenHash)
+
+    mocks.refreshRepo.AssertExpectations(t)
+    mocks.userRepo.AssertExpectations(t)
+  })
+
+  t.Run("should reject token of disabled user", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    disabledAt := time.Now()
+    user := &models.User{ID: bson.NewObjectID(), Email: "test@test.com", DisabledAt: &disabledAt}
+    stored := newStoredToken(user.ID, "old-token")
+
+    mocks.refreshRepo.On("FindByHash", mock.Anything, utils.HashToken("old-token")).Return(stored, nil)
+    mocks.refreshRepo.On("MarkUsed", mock.Anything, stored.ID).Return(true, nil)
+    mocks.userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+    _, err := service.Refresh(context.Background(), "old-token")
+
+    assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+    mocks.refreshRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should reject unknown token", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    mocks.refreshRepo.On("FindByHash", mock.Anything, mock.Anything).
+      Return(nil, repositories.ErrRefreshTokenNotFound)
+
+    result, err := service.Refresh(context.Background(), "unknown")
+
+    assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+    assert.Nil(t, result)
+  })
+
+  t.Run("should reject expired token", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    stored := newStoredToken(bson.NewObjectID(), "expired")
+    stored.ExpiresAt = time.Now().Add(-time.Minute)
+    mocks.refreshRepo.On("FindByHash", mock.Anything, mock.Anything).Return(stored, nil)
+
+    result, err := service.Refresh(context.Background(), "expired")
+
+    assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+    assert.Nil(t, result)
+    mocks.refreshRepo.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should revoke family when used token is replayed", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    stored := newStoredToken(bson.NewObjectID(), "replayed")
+    stored.UsedAt = timePtr(time.Now().Add(-time.Minute))
+    mocks.refreshRepo.On("FindByHash", mock.Anything, mock.Anything).Return(stored, nil)
+    mocks.refreshRepo.On("RevokeFamily", mock.Anything, stored.FamilyID).Return(nil)
+
+    result, err := service.Refresh(context.Background(), "replayed")
+
+    assert.ErrorIs(t, err, ErrRefreshTokenReused)
+    assert.Nil(t, result)
+    mocks.refreshRepo.AssertExpectations(t)
+  })
+
+  t.Run("should revoke family when concurrent refresh wins the race", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    stored := newStoredToken(bson.NewObjectID(), "raced")
+    mocks.refreshRepo.On("FindByHash", mock.Anything, mock.Anything).Return(stored, nil)
+    mocks.refreshRepo.On("MarkUsed", mock.Anything, stored.ID).Return(false, nil)
+    mocks.refreshRepo.On("RevokeFamily", mock.Anything, stored.FamilyID).Return(nil)
+
+    result, err := service.Refresh(context.Background(), "raced")
+
+    assert.ErrorIs(t, err, ErrRefreshTokenReused)
+    assert.Nil(t, result)
+    mocks.refreshRepo.AssertExpectations(t)
+  })
+
+  t.Run("should reject revoked token", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    stored := newStoredToken(bson.NewObjectID(), "revoked")
+    stored.RevokedAt = timePtr(time.Now())
+    mocks.refreshRepo.On("FindByHash", mock.Anything, mock.Anything).Return(stored, nil)
+
+    result, err := service.Refresh(context.Background(), "revoked")
+
+    assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+    assert.Nil(t, result)
+    mocks.refreshRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+  })
+}
+
+func TestAuthService_Logout(t *testing.T) {
+  t.Run("should revoke current access token", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    userID := bson.NewObjectID()
+
+    err := service.Logout(context.Background(), userID, "jti-1", time.Now().Add(time.Hour), types.LogoutInput{})
+
+    assert.NoError(t, err)
+    revoked, _ := mocks.revocations.IsRevoked(context.Background(), "jti-1", userID, time.Now())
+    assert.True(t, revoked)
+    mocks.refreshRepo.AssertNotCalled(t, "FindByHash", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should revoke refresh token family when provided", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    userID := bson.NewObjectID()
+
+    stored := &models.RefreshToken{ID: bson.NewObjectID(), UserID: userID, FamilyID: bson.NewObjectID()}
+    mocks.refreshRepo.On("FindByHash", mock.Anything, utils.HashToken("refresh")).Return(stored, nil)
+    mocks.refreshRepo.On("RevokeFamily", mock.Anything, stored.FamilyID).Return(nil)
+
+    err := service.Logout(context.Background(), userID, "jti-1", time.Now().Add(time.Hour), types.LogoutInput{RefreshToken: "refresh"})
+
+    assert.NoError(t, err)
+    mocks.refreshRepo.AssertExpectations(t)
+  })
+
+  t.Run("should not revoke refresh token of another user", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    stored := &models.RefreshToken{ID: bson.NewObjectID(), UserID: bson.NewObjectID(), FamilyID: bson.NewObjectID()}
+    mocks.refreshRepo.On("FindByHash", mock.Anything, mock.Anything).Return(stored, nil)
+
+    err := service.Logout(context.Background(), bson.NewObjectID(), "jti-1", time.Now().Add(time.Hour), types.LogoutInput{RefreshToken: "other"})
+
+    assert.NoError(t, err)
+    mocks.refreshRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+  })
+}
+
+func TestAuthService_LogoutAll(t *testing.T) {
+  t.Run("should revoke every session of user", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    userID := bson.NewObjectID()
+
+    mocks.refreshRepo.On("RevokeAllForUser", mock.Anything, userID).Return(nil)
+
+    err := service.LogoutAll(context.Background(), userID, "jti-current", time.Now().Add(time.Hour))
+
+    assert.NoError(t, err)
+    mocks.refreshRepo.AssertExpectations(t)
+
+    ctx := context.Background()
+    current, _ := mocks.revocations.IsRevoked(ctx, "jti-current", userID, time.Now())
+    assert.True(t, current)
+
+    older, _ := mocks.revocations.IsRevoked(ctx, "jti-other", userID, time.Now().Add(-time.Minute))
+    assert.True(t, older)
+  })
+
+  t.Run("should stop when refresh tokens cannot be revoked", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    userID := bson.NewObjectID()
+
+    mocks.refreshRepo.On("RevokeAllForUser", mock.Anything, userID).Return(errors.New("database error"))
+
+    err := service.LogoutAll(context.Background(), userID, "jti-current", time.Now().Add(time.Hour))
+
+    assert.Error(t, err)
+  })
+}
+
+func TestAuthService_ForgotPassword(t *testing.T) {
+  t.Run("should store hashed token and email reset link", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    user := &models.User{ID: bson.NewObjectID(), Email: "test@test.com", Name: "Test"}
+    mocks.userRepo.On("FindByEmail", mock.Anything, "test@test.com").Return(user, nil)
+    mocks.resetRepo.On("InvalidateForUser", mock.Anything, user.ID).Return(nil)
+
+    var stored *models.PasswordReset
+    mocks.resetRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.PasswordReset")).
+      Run(func(args mock.Arguments) {
+        stored = args.Get(1).(*models.PasswordReset)
+      }).
+      Return(nil)
+
+    var sent mailer.Message
+    mocks.mailer.On("Send", mock.Anything, mock.AnythingOfType("mailer.Message")).
+      Run(func(args mock.Arguments) {
+        sent = args.Get(1).(mailer.Message)
+      }).
+      Return(nil)
+
+    err := service.ForgotPassword(context.Background(), types.ForgotPasswordInput{Email: "test@test.com"})
+
+    assert.NoError(t, err)
+    assert.Equal(t, "test@test.com", sent.To)
+    assert.True(t, stored.ExpiresAt.After(time.Now()))
+
+    // The emailed token must hash to the stored hash, the raw token is never stored
+    idx := strings.Index(sent.Body, "token=")
+    assert.True(t, idx > 0)
+    token := strings.Fields(sent.Body[idx+len("token="):])[0]
+    assert.Equal(t, stored.TokenHash, utils.HashToken(token))
+    assert.NotContains(t, sent.Body, stored.TokenHash)
+
+    mocks.resetRepo.AssertExpectations(t)
+    mocks.mailer.AssertExpectations(t)
+  })
+
+  t.Run("should silently ignore unknown email", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    mocks.userRepo.On("FindByEmail", mock.Anything, "nobody@test.com").Return(nil, repositories.ErrUserNotFound)
+
+    err := service.ForgotPassword(context.Background(), types.ForgotPasswordInput{Email: "nobody@test.com"})
+
+    assert.NoError(t, err)
+    mocks.mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should answer mailer errors like unknown emails", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    user := &models.User{ID: bson.NewObjectID(), Email: "test@test.com"}
+    mocks.userRepo.On("FindByEmail", mock.Anything, "test@test.com").Return(user, nil)
+    mocks.resetRepo.On("InvalidateForUser", mock.Anything, user.ID).Return(nil)
+    mocks.resetRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+    mocks.mailer.On("Send", mock.Anything, mock.Anything).Return(errors.New("smtp down"))
+
+    err := service.ForgotPassword(context.Background(), types.ForgotPasswordInput{Email: "test@test.com"})
+
+    assert.NoError(t, err)
+    mocks.mailer.AssertExpectations(t)
+  })
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+  t.Run("should update password and revoke sessions", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    userID := bson.NewObjectID()
+    reset := &models.PasswordReset{ID: bson.NewObjectID(), UserID: userID}
+    mocks.resetRepo.On("Consume", mock.Anything, utils.HashToken("reset-token")).Return(reset, nil)
+
+    var newHash string
+    mocks.userRepo.On("UpdatePassword", mock.Anything, userID, mock.AnythingOfType("string")).
+      Run(func(args mock.Arguments) {
+        newHash = args.String(2)
+      }).
+      Return(nil)
+    mocks.refreshRepo.On("RevokeAllForUser", mock.Anything, userID).Return(nil)
+    mocks.userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID, Email: "test@test.com"}, nil)
+    mocks.attempts.Lock(context.Background(), "email:test@test.com", time.Now().Add(time.Hour))
+
+    err := service.ResetPassword(context.Background(), types.ResetPasswordInput{Token: "reset-token", Password: "NewPassword1"})
+
+    assert.NoError(t, err)
+    assert.True(t, utils.CheckPassword(newHash, "NewPassword1"))
+
+    // Reset unlocks the account
+    attempt, _ := mocks.attempts.Get(context.Background(), "email:test@test.com")
+    assert.Nil(t, attempt)
+
+    revoked, _ := mocks.revocations.IsRevoked(context.Background(), "old-jti", userID, time.Now().Add(-time.Minute))
+    assert.True(t, revoked)
+
+    mocks.userRepo.AssertExpectations(t)
+    mocks.refreshRepo.AssertExpectations(t)
+  })
+
+  t.Run("should reject invalid token", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    mocks.resetRepo.On("Consume", mock.Anything, mock.Anything).Return(nil, repositories.ErrResetTokenInvalid)
+
+    err := service.ResetPassword(context.Background(), types.ResetPasswordInput{Token: "used", Password: "NewPassword1"})
+
+    assert.ErrorIs(t, err, ErrInvalidResetToken)
+    mocks.userRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should reject weak password without consuming token", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    err := service.ResetPassword(context.Background(), types.ResetPasswordInput{Token: "reset-token", Password: "weakpassword"})
+
+    assert.ErrorIs(t, err, ErrWeakPassword)
+    mocks.resetRepo.AssertNotCalled(t, "Consume", mock.Anything, mock.Anything)
+  })
+}
+
+func TestAuthService_GetProfile(t *testing.T) {
+  t.Run("should return current user", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    userID := bson.NewObjectID()
+    mocks.userRepo.On("FindByID", mock.Anything, userID).
+      Return(&models.User{ID: userID, Email: "test@example.com", Name: "Test", Timezone: "Asia/Jakarta"}, nil)
+
+    result, err := service.GetProfile(context.Background(), userID)
+
+    assert.NoError(t, err)
+    assert.Equal(t, "test@example.com", result.Email)
+    assert.Equal(t, "Asia/Jakarta", result.Timezone)
+  })
+
+  t.Run("should return ErrUserNotFound", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    mocks.userRepo.On("FindByID", mock.Anything, mock.Anything).Return(nil, repositories.ErrUserNotFound)
+
+    result, err := service.GetProfile(context.Background(), bson.NewObjectID())
+
+    assert.Nil(t, result)
+    assert.ErrorIs(t, err, ErrUserNotFound)
+  })
+}
+
+func TestAuthService_UpdateProfile(t *testing.T) {
+  t.Run("should update provided fields only", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    userID := bson.NewObjectID()
+    name := "  New Name "
+    timezone := "Asia/Jakarta"
+    input := types.UpdateProfileInput{Name: &name, Timezone: &timezone}
+
+    mocks.userRepo.On("Update", mock.Anything, userID, bson.M{"name": "New Name", "timezone": "Asia/Jakarta"}).Return(nil)
+    mocks.userRepo.On("FindByID", mock.Anything, userID).
+      Return(&models.User{ID: userID, Email: "test@example.com", Name: "New Name", Timezone: "Asia/Jakarta"}, nil)
+
+    result, err := service.UpdateProfile(context.Background(), userID, input)
+
+    assert.NoError(t, err)
+    assert.Equal(t, "New Name", result.Name)
+    mocks.userRepo.AssertExpectations(t)
+  })
+
+  t.Run("should reject unknown timezone", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    timezone := "Mars/Olympus"
+    result, err := service.UpdateProfile(context.Background(), bson.NewObjectID(), types.UpdateProfileInput{Timezone: &timezone})
+
+    assert.Nil(t, result)
+    assert.ErrorIs(t, err, ErrInvalidTimezone)
+    mocks.userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+  })
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+  hashedPassword, _ := utils.HashPassword("OldPassword1")
+
+  t.Run("should change password, revoke other sessions and issue new tokens", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    userID := bson.NewObjectID()
+    user := &models.User{ID: userID, Email: "test@example.com", Password: hashedPassword}
+    expiresAt := time.Now().Add(time.Minute)
+
+    mocks.userRepo.On("FindByID", mock.Anything, userID).Return(user, nil)
+    var newHash string
+    mocks.userRepo.On("UpdatePassword", mock.Anything, userID, mock.AnythingOfType("string")).
+      Run(func(args mock.Arguments) {
+        newHash = args.String(2)
+      }).
+      Return(nil)
+    mocks.refreshRepo.On("RevokeAllForUser", mock.Anything, userID).Return(nil)
+    mocks.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+    result, err := service.ChangePassword(context.Background(), userID, "current-jti", expiresAt, types.ChangePasswordInput{
+      CurrentPassword: "OldPassword1",
+      NewPassword:     "NewPassword1",
+    })
+
+    assert.NoError(t, err)
+    assert.NotEmpty(t, result.Token)
+    assert.NotEmpty(t, result.RefreshToken)
+    assert.True(t, utils.CheckPassword(newHash, "NewPassword1"))
+
+    revoked, _ := mocks.revocations.IsRevoked(context.Background(), "current-jti", userID, time.Now())
+    assert.True(t, revoked)
+
+    mocks.userRepo.AssertExpectations(t)
+    mocks.refreshRepo.AssertExpectations(t)
+  })
+
+  t.Run("should reject wrong current password", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    userID := bson.NewObjectID()
+    mocks.userRepo.On("FindByID", mock.Anything, userID).
+      Return(&models.User{ID: userID, Password: hashedPassword}, nil)
+
+    result, err := service.ChangePassword(context.Background(), userID, "jti", time.Now(), types.ChangePasswordInput{
+      CurrentPassword: "WrongPassword1",
+      NewPassword:     "NewPassword1",
+    })
+
+    assert.Nil(t, result)
+    assert.ErrorIs(t, err, ErrInvalidCurrentPassword)
+    mocks.userRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should reject weak new password", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    userID := bson.NewObjectID()
+    mocks.userRepo.On("FindByID", mock.Anything, userID).
+      Return(&models.User{ID: userID, Password: hashedPassword}, nil)
+
+    result, err := service.ChangePassword(context.Background(), userID, "jti", time.Now(), types.ChangePasswordInput{
+      CurrentPassword: "OldPassword1",
+      NewPassword:     "weakpassword",
+    })
+
+    assert.Nil(t, result)
+    assert.ErrorIs(t, err, ErrWeakPassword)
+    mocks.userRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+  })
 }
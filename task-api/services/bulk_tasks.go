@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// MaxBulkTasks - tasks one bulk request may change, over all its operations
+const MaxBulkTasks = 100
+
+var (
+  ErrInvalidBulkOperation = errors.New("invalid bulk operation")
+  ErrBulkTooLarge         = errors.New("bulk operation matches too many tasks")
+  ErrBulkDuplicateTask    = errors.New("task is already changed by an earlier operation")
+  errBulkAborted          = errors.New("bulk operation aborted")
+)
+
+// bulkItemErrors - errors reported as they are for a single task, others are logged
+var bulkItemErrors = []error{
+  ErrTaskNotFound,
+  ErrTaskPermissionDenied,
+  ErrTaskOwnerRequired,
+  ErrTaskBlocked,
+  ErrTaskArchived,
+  ErrBulkDuplicateTask,
+  repositories.ErrVersionConflict,
+}
+
+// bulkItem - one task of a bulk operation and the change planned for it
+type bulkItem struct {
+  operation int
+  action    string
+  taskID    bson.ObjectID
+  task      *models.Task
+  after     models.Task // task as it will be, for the history
+  set       bson.M
+  err       error
+
+  // Results of applying the item
+  trashed []bson.ObjectID // subtasks that went to the trash with it
+  next    *models.Task    // next occurrence of a completed recurring task
+}
+
+// BulkTasks - apply operations to many tasks at once, with a result per task
+func (s *taskService) BulkTasks(ctx context.Context, userID bson.ObjectID, input types.BulkTaskInput) (*types.BulkTaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+  defer cancel()
+
+  // Malformed or oversized requests change nothing
+  targets := make([][]bson.ObjectID, len(input.Operations))
+  total := 0
+  for i := range input.Operations {
+    ids, err := s.bulkTargets(ctx, userID, &input.Operations[i])
+    if err != nil {
+      return nil, err
+    }
+    total += len(ids)
+    if total > MaxBulkTasks {
+      return nil, ErrBulkTooLarge
+    }
+    targets[i] = ids
+  }
+
+  // A task gets one change per request, later operations would be planned on the task as it was
+  now := time.Now()
+  items := []*bulkItem{}
+  planned := map[bson.ObjectID]bool{}
+  for i := range input.Operations {
+    for _, taskID := range targets[i] {
+      if planned[taskID] {
+        items = append(items, &bulkItem{operation: i, action: input.Operations[i].Action, taskID: taskID, err: ErrBulkDuplicateTask})
+        continue
+      }
+      planned[taskID] = true
+      items = append(items, s.planBulkItem(ctx, userID, i, &input.Operations[i], taskID, now))
+    }
+  }
+
+  var err error
+  switch {
+  case input.Atomic && bulkFailed(items):
+    // Nothing to write, a change already failed
+  case input.Atomic:
+    err = s.taskRepo.WithTransaction(ctx, func(ctx context.Context) error {
+      return s.applyBulk(ctx, items, true, now)
+    })
+  default:
+    err = s.applyBulk(ctx, items, false, now)
+  }
+  if err != nil && !errors.Is(err, errBulkAborted) {
+    return nil, err
+  }
+  aborted := input.Atomic && bulkFailed(items)
+
+  response := &types.BulkTaskResponse{Results: make([]types.BulkTaskResult, len(items))}
+  for i, item := range items {
+    result := types.BulkTaskResult{Operation: item.operation, TaskID: item.taskID.Hex(), Status: types.BulkResultApplied}
+    switch {
+    case item.err != nil:
+      result.Status, result.Error = types.BulkResultFailed, bulkItemError(item)
+      response.Failed++
+    case aborted:
+      result.Status = types.BulkResultSkipped
+    default:
+      s.recordBulkEvents(ctx, userID, item)
+      response.Applied++
+    }
+    response.Results[i] = result
+  }
+  return response, nil
+}
+
+// bulkTargets - IDs of the tasks an operation is about, by ids or by filter
+func (s *taskService) bulkTargets(ctx context.Context, userID bson.ObjectID, op *types.BulkTaskOperation) ([]bson.ObjectID, error) {
+  if (len(op.IDs) > 0) == (op.Filter != nil) {
+    return nil, ErrInvalidBulkOperation
+  }
+  if op.Action == types.BulkActionUpdate && op.Status == nil && op.Priority == nil && op.Tags == nil {
+    return nil, ErrInvalidBulkOperation
+  }
+
+  if op.Filter == nil {
+    seen := map[bson.ObjectID]bool{}
+    ids := make([]bson.ObjectID, 0, len(op.IDs))
+    for _, hex := range op.IDs {
+      id, err := bson.ObjectIDFromHex(hex)
+      if err != nil {
+        return nil, ErrInvalidBulkOperation
+      }
+      if !seen[id] {
+        seen[id] = true
+        ids = append(ids, id)
+      }
+    }
+    return ids, nil
+  }
+
+  // Same tasks as GET /tasks with this filter, all of them or none
+  query := *op.Filter
+  query.Page, query.Limit, query.Sort = 1, MaxBulkTasks, ""
+  tasks, total, err := s.taskRepo.FindByUserID(ctx, userID, query)
+  if err != nil {
+    return nil, err
+  }
+  if total > MaxBulkTasks {
+    return nil, ErrBulkTooLarge
+  }
+
+  ids := make([]bson.ObjectID, len(tasks))
+  for i := range tasks {
+    ids[i] = tasks[i].ID
+  }
+  return ids, nil
+}
+
+// planBulkItem - authorize and check the change of one task, nothing is written yet
+func (s *taskService) planBulkItem(ctx context.Context, userID bson.ObjectID, operation int, op *types.BulkTaskOperation, taskID bson.ObjectID, now time.Time) *bulkItem {
+  item := &bulkItem{operation: operation, action: op.Action, taskID: taskID, set: bson.M{}}
+
+  item.task, item.err = s.bulkTask(ctx, taskID, userID, op.Action)
+  if item.err != nil {
+    return item
+  }
+  item.after = *item.task
+
+  switch op.Action {
+  case types.BulkActionUpdate:
+    if op.Status != nil && *op.Status != item.task.Status {
+      // Work cannot start or finish while blockers are open
+      if *op.Status != types.TaskStatusPending {
+        if item.err = s.requireUnblocked(ctx, item.task, userID); item.err != nil {
+          return item
+        }
+      }
+
+      // completed_at only moves with the status, so repeated requests keep it
+      item.set["status"], item.after.Status = *op.Status, *op.Status
+      item.set["completed_at"], item.after.CompletedAt = nil, nil
+      if *op.Status == types.TaskStatusCompleted {
+        item.set["completed_at"], item.after.CompletedAt = now, &now
+      }
+    }
+    if op.Priority != nil {
+      item.set["priority"], item.after.Priority = *op.Priority, *op.Priority
+    }
+    if op.Tags != nil {
+      item.set["tags"], item.after.Tags = op.Tags, op.Tags
+    }
+
+  case types.BulkActionArchive:
+    if item.task.ArchivedAt != nil {
+      item.err = ErrTaskArchived
+      return item
+    }
+    item.set["archived_at"], item.after.ArchivedAt = now, &now
+
+  case types.BulkActionDelete:
+    item.set["deleted_at"] = now
+  }
+  return item
+}
+
+// bulkTask - task an operation may change, deleting needs the owner like DeleteTask
+func (s *taskService) bulkTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, action string) (*models.Task, error) {
+  task, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionEdit)
+  if err != nil || action != types.BulkActionDelete {
+    return task, err
+  }
+
+  if err := s.requireTaskOwner(ctx, task, userID); !errors.Is(err, ErrTaskOwnerRequired) {
+    return task, err
+  }
+  return task, s.requireShare(ctx, taskID, userID, types.SharePermissionOwner, ErrTaskOwnerRequired)
+}
+
+// applyBulk - write the planned changes in one bulk write, then the follow-ups
+// of deleted and completed tasks; atomic stops at the first failure
+func (s *taskService) applyBulk(ctx context.Context, items []*bulkItem, atomic bool, now time.Time) error {
+  // Transactions may run this more than once
+  pending := []*bulkItem{}
+  for _, item := range items {
+    item.trashed, item.next = nil, nil
+    if item.err == nil && len(item.set) > 0 {
+      pending = append(pending, item)
+    }
+  }
+
+  writes := make([]repositories.TaskWrite, len(pending))
+  for i, item := range pending {
+    writes[i] = repositories.TaskWrite{ID: item.taskID, Version: item.task.Version, Set: item.set}
+  }
+  errs, err := s.taskRepo.BulkUpdate(ctx, writes)
+  if err != nil {
+    return err
+  }
+
+  for i, item := range pending {
+    if errs[i] != nil {
+      item.err = errs[i]
+      continue
+    }
+
+    switch {
+    case item.action == types.BulkActionDelete:
+      // Subtasks go with their parent, unless they were deleted on their own
+      ids, err := s.descendantIDs(ctx, item.taskID)
+      if err == nil && len(ids) > 0 {
+        err = s.taskRepo.TrashByIDs(ctx, ids, now, &item.taskID)
+        if errors.Is(err, repositories.ErrTaskNotFound) {
+          err = nil
+        }
+      }
+      item.trashed, item.err = ids, err
+
+    case item.task.Recurrence != "" && item.after.Status == types.TaskStatusCompleted && item.task.Status != types.TaskStatusCompleted:
+      // Completing an occurrence of a recurring task creates the next one
+      item.next, item.err = s.spawnNext(ctx, item.taskID)
+    }
+  }
+
+  if atomic && bulkFailed(items) {
+    return errBulkAborted
+  }
+  return nil
+}
+
+// recordBulkEvents - history of an applied item
+func (s *taskService) recordBulkEvents(ctx context.Context, userID bson.ObjectID, item *bulkItem) {
+  if item.action == types.BulkActionDelete {
+    for _, id := range append([]bson.ObjectID{item.taskID}, item.trashed...) {
+      s.recordEvent(ctx, id, userID, types.TaskEventDeleted, nil)
+    }
+    return
+  }
+
+  s.recordEvent(ctx, item.taskID, userID, types.TaskEventUpdated, taskChanges(item.task, &item.after))
+  if item.next != nil {
+    s.recordEvent(ctx, item.next.ID, userID, types.TaskEventCreated, taskChanges(nil, item.next))
+  }
+}
+
+// bulkFailed - whether any item failed
+func bulkFailed(items []*bulkItem) bool {
+  for _, item := range items {
+    if item.err != nil {
+      return true
+    }
+  }
+  return false
+}
+
+// bulkItemError - message for a failed item, without internal details
+func bulkItemError(item *bulkItem) string {
+  for _, known := range bulkItemErrors {
+    if errors.Is(item.err, known) {
+      return known.Error()
+    }
+  }
+
+  log.Error().Err(item.err).Str("task_id", item.taskID.Hex()).Str("action", item.action).Msg("Failed to apply bulk change")
+  return "internal error"
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+func newTestBulkService() (TaskService, *MockTaskRepository, *MockTaskShareRepository) {
+  taskRepo, shareRepo := new(MockTaskRepository), new(MockTaskShareRepository)
+  return NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), shareRepo, newMockTaskEventRepository(), new(MockTaskPurger)), taskRepo, shareRepo
+}
+
+// bulkWrites - match a bulk write by task IDs, in order
+func bulkWrites(ids ...bson.ObjectID) interface{} {
+  return mock.MatchedBy(func(writes []repositories.TaskWrite) bool {
+    if len(writes) != len(ids) {
+      return false
+    }
+    for i := range writes {
+      if writes[i].ID != ids[i] {
+        return false
+      }
+    }
+    return true
+  })
+}
+
+func TestTaskService_BulkTasks(t *testing.T) {
+  t.Run("should update tasks by ids with a result per task", func(t *testing.T) {
+    service, taskRepo, shareRepo := newTestBulkService()
+    userID := bson.NewObjectID()
+    first := &models.Task{ID: bson.NewObjectID(), UserID: userID, Status: types.TaskStatusPending}
+    second := &models.Task{ID: bson.NewObjectID(), UserID: userID, Status: types.TaskStatusPending}
+    missingID := bson.NewObjectID()
+    status, priority := types.TaskStatusCompleted, types.TaskPriorityHigh
+
+    taskRepo.On("FindByID", mock.Anything, first.ID, userID).Return(first, nil)
+    taskRepo.On("FindByID", mock.Anything, second.ID, userID).Return(second, nil)
+    taskRepo.On("FindByID", mock.Anything, missingID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, missingID, userID).Return(nil, repositories.ErrTaskShareNotFound)
+    taskRepo.On("BulkUpdate", mock.Anything, mock.MatchedBy(func(writes []repositories.TaskWrite) bool {
+      return len(writes) == 2 && writes[0].ID == first.ID && writes[0].Set["status"] == status &&
+        writes[0].Set["priority"] == priority && writes[0].Set["completed_at"] != nil
+    })).Return([]error{nil, nil}, nil)
+
+    result, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Operations: []types.BulkTaskOperation{{
+        Action:   types.BulkActionUpdate,
+        IDs:      []string{first.ID.Hex(), second.ID.Hex(), missingID.Hex(), first.ID.Hex()},
+        Status:   &status,
+        Priority: &priority,
+      }},
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, 2, result.Applied)
+    assert.Equal(t, 1, result.Failed)
+    assert.Len(t, result.Results, 3)
+    assert.Equal(t, types.BulkResultFailed, result.Results[2].Status)
+    assert.Equal(t, ErrTaskNotFound.Error(), result.Results[2].Error)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should pick tasks by filter", func(t *testing.T) {
+    service, taskRepo, _ := newTestBulkService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Status: types.TaskStatusCompleted}
+
+    taskRepo.On("FindByUserID", mock.Anything, userID, types.TaskQueryParams{Status: types.TaskStatusCompleted, Page: 1, Limit: MaxBulkTasks}).Return([]models.Task{*task}, int64(1), nil)
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("BulkUpdate", mock.Anything, bulkWrites(task.ID)).Return([]error{nil}, nil)
+
+    result, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Operations: []types.BulkTaskOperation{{
+        Action: types.BulkActionArchive,
+        Filter: &types.TaskQueryParams{Status: types.TaskStatusCompleted, Sort: "title", Page: 3},
+      }},
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, 1, result.Applied)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should refuse filters matching too many tasks", func(t *testing.T) {
+    service, taskRepo, _ := newTestBulkService()
+    userID := bson.NewObjectID()
+
+    taskRepo.On("FindByUserID", mock.Anything, userID, mock.Anything).Return([]models.Task{}, int64(MaxBulkTasks+1), nil)
+
+    _, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Operations: []types.BulkTaskOperation{{Action: types.BulkActionDelete, Filter: &types.TaskQueryParams{}}},
+    })
+
+    assert.ErrorIs(t, err, ErrBulkTooLarge)
+    taskRepo.AssertNotCalled(t, "BulkUpdate", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should refuse operations without targets or changes", func(t *testing.T) {
+    service, _, _ := newTestBulkService()
+    userID := bson.NewObjectID()
+
+    for _, op := range []types.BulkTaskOperation{
+      {Action: types.BulkActionDelete},
+      {Action: types.BulkActionDelete, IDs: []string{bson.NewObjectID().Hex()}, Filter: &types.TaskQueryParams{}},
+      {Action: types.BulkActionUpdate, IDs: []string{bson.NewObjectID().Hex()}},
+    } {
+      _, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{Operations: []types.BulkTaskOperation{op}})
+      assert.ErrorIs(t, err, ErrInvalidBulkOperation)
+    }
+  })
+
+  t.Run("should refuse deleting tasks the user does not own", func(t *testing.T) {
+    service, taskRepo, shareRepo := newTestBulkService()
+    userID := bson.NewObjectID()
+    assigned := &models.Task{ID: bson.NewObjectID(), UserID: bson.NewObjectID(), AssigneeIDs: []bson.ObjectID{userID}}
+
+    taskRepo.On("FindByID", mock.Anything, assigned.ID, userID).Return(assigned, nil)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, assigned.ID, userID).Return(nil, repositories.ErrTaskShareNotFound)
+    taskRepo.On("BulkUpdate", mock.Anything, bulkWrites()).Return([]error{}, nil)
+
+    result, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Operations: []types.BulkTaskOperation{{Action: types.BulkActionDelete, IDs: []string{assigned.ID.Hex()}}},
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, ErrTaskOwnerRequired.Error(), result.Results[0].Error)
+  })
+
+  t.Run("should trash subtasks with deleted tasks", func(t *testing.T) {
+    service, taskRepo, _ := newTestBulkService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    childID := bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("BulkUpdate", mock.Anything, mock.MatchedBy(func(writes []repositories.TaskWrite) bool {
+      return len(writes) == 1 && writes[0].Set["deleted_at"] != nil
+    })).Return([]error{nil}, nil)
+    taskRepo.On("FindChildIDs", mock.Anything, []bson.ObjectID{task.ID}).Return([]bson.ObjectID{childID}, nil)
+    taskRepo.On("FindChildIDs", mock.Anything, []bson.ObjectID{childID}).Return([]bson.ObjectID{}, nil)
+    taskRepo.On("TrashByIDs", mock.Anything, []bson.ObjectID{childID}, mock.Anything, &task.ID).Return(nil)
+
+    result, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Operations: []types.BulkTaskOperation{{Action: types.BulkActionDelete, IDs: []string{task.ID.Hex()}}},
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, 1, result.Applied)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should keep blocked tasks from starting", func(t *testing.T) {
+    service, taskRepo, _ := newTestBulkService()
+    userID := bson.NewObjectID()
+    blocker := models.Task{ID: bson.NewObjectID(), UserID: userID, Status: types.TaskStatusPending}
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Status: types.TaskStatusPending, BlockedBy: []bson.ObjectID{blocker.ID}}
+    status := types.TaskStatusInProgress
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{blocker.ID}).Return([]models.Task{blocker}, nil)
+    taskRepo.On("BulkUpdate", mock.Anything, bulkWrites()).Return([]error{}, nil)
+
+    result, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Operations: []types.BulkTaskOperation{{Action: types.BulkActionUpdate, IDs: []string{task.ID.Hex()}, Status: &status}},
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, ErrTaskBlocked.Error(), result.Results[0].Error)
+  })
+
+  t.Run("should apply nothing in atomic mode when a task fails", func(t *testing.T) {
+    service, taskRepo, _ := newTestBulkService()
+    userID := bson.NewObjectID()
+    archivedAt := time.Now()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    archived := &models.Task{ID: bson.NewObjectID(), UserID: userID, ArchivedAt: &archivedAt}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("FindByID", mock.Anything, archived.ID, userID).Return(archived, nil)
+
+    result, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Atomic:     true,
+      Operations: []types.BulkTaskOperation{{Action: types.BulkActionArchive, IDs: []string{task.ID.Hex(), archived.ID.Hex()}}},
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, 0, result.Applied)
+    assert.Equal(t, types.BulkResultSkipped, result.Results[0].Status)
+    assert.Equal(t, types.BulkResultFailed, result.Results[1].Status)
+    taskRepo.AssertNotCalled(t, "WithTransaction", mock.Anything)
+    taskRepo.AssertNotCalled(t, "BulkUpdate", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should roll back in atomic mode when a write fails", func(t *testing.T) {
+    service, taskRepo, _ := newTestBulkService()
+    userID := bson.NewObjectID()
+    first := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    second := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    priority := types.TaskPriorityLow
+
+    taskRepo.On("FindByID", mock.Anything, first.ID, userID).Return(first, nil)
+    taskRepo.On("FindByID", mock.Anything, second.ID, userID).Return(second, nil)
+    taskRepo.On("WithTransaction", mock.Anything).Return(nil)
+    taskRepo.On("BulkUpdate", mock.Anything, bulkWrites(first.ID, second.ID)).Return([]error{nil, errors.New("write failed")}, nil)
+
+    result, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Atomic:     true,
+      Operations: []types.BulkTaskOperation{{Action: types.BulkActionUpdate, IDs: []string{first.ID.Hex(), second.ID.Hex()}, Priority: &priority}},
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, types.BulkResultSkipped, result.Results[0].Status)
+    assert.Equal(t, "internal error", result.Results[1].Error)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should fail tasks changed since they were read", func(t *testing.T) {
+    taskRepo, shareRepo, eventRepo := new(MockTaskRepository), new(MockTaskShareRepository), new(MockTaskEventRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), shareRepo, eventRepo, new(MockTaskPurger))
+    events := recordedEvents(eventRepo)
+    userID := bson.NewObjectID()
+    first := &models.Task{ID: bson.NewObjectID(), UserID: userID, Version: 3}
+    second := &models.Task{ID: bson.NewObjectID(), UserID: userID, Version: 7}
+    priority := types.TaskPriorityLow
+
+    taskRepo.On("FindByID", mock.Anything, first.ID, userID).Return(first, nil)
+    taskRepo.On("FindByID", mock.Anything, second.ID, userID).Return(second, nil)
+    taskRepo.On("BulkUpdate", mock.Anything, mock.MatchedBy(func(writes []repositories.TaskWrite) bool {
+      return len(writes) == 2 && writes[0].Version == 3 && writes[1].Version == 7
+    })).Return([]error{nil, repositories.ErrVersionConflict}, nil)
+
+    result, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Operations: []types.BulkTaskOperation{{Action: types.BulkActionUpdate, IDs: []string{first.ID.Hex(), second.ID.Hex()}, Priority: &priority}},
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, 1, result.Applied)
+    assert.Equal(t, types.BulkResultFailed, result.Results[1].Status)
+    assert.Equal(t, repositories.ErrVersionConflict.Error(), result.Results[1].Error)
+    assert.Len(t, *events, 1)
+    assert.Equal(t, first.ID, (*events)[0].TaskID)
+  })
+  t.Run("should roll back in atomic mode when a task changed since it was read", func(t *testing.T) {
+    service, taskRepo, _ := newTestBulkService()
+    userID := bson.NewObjectID()
+    first := &models.Task{ID: bson.NewObjectID(), UserID: userID, Version: 3}
+    second := &models.Task{ID: bson.NewObjectID(), UserID: userID, Version: 7}
+    priority := types.TaskPriorityLow
+
+    taskRepo.On("FindByID", mock.Anything, first.ID, userID).Return(first, nil)
+    taskRepo.On("FindByID", mock.Anything, second.ID, userID).Return(second, nil)
+    taskRepo.On("WithTransaction", mock.Anything).Return(nil)
+    taskRepo.On("BulkUpdate", mock.Anything, bulkWrites(first.ID, second.ID)).Return([]error{nil, repositories.ErrVersionConflict}, nil)
+
+    result, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Atomic:     true,
+      Operations: []types.BulkTaskOperation{{Action: types.BulkActionUpdate, IDs: []string{first.ID.Hex(), second.ID.Hex()}, Priority: &priority}},
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, 0, result.Applied)
+    assert.Equal(t, types.BulkResultSkipped, result.Results[0].Status)
+    assert.Equal(t, repositories.ErrVersionConflict.Error(), result.Results[1].Error)
+  })
+
+  t.Run("should change a task only once per request", func(t *testing.T) {
+    taskRepo, shareRepo, eventRepo := new(MockTaskRepository), new(MockTaskShareRepository), new(MockTaskEventRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), shareRepo, eventRepo, new(MockTaskPurger))
+    events := recordedEvents(eventRepo)
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Version: 2}
+    priority := types.TaskPriorityLow
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("BulkUpdate", mock.Anything, bulkWrites(task.ID)).Return([]error{nil}, nil)
+
+    result, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Operations: []types.BulkTaskOperation{
+        {Action: types.BulkActionUpdate, IDs: []string{task.ID.Hex()}, Priority: &priority},
+        {Action: types.BulkActionArchive, IDs: []string{task.ID.Hex()}},
+      },
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, 1, result.Applied)
+    assert.Equal(t, types.BulkResultApplied, result.Results[0].Status)
+    assert.Equal(t, types.BulkResultFailed, result.Results[1].Status)
+    assert.Equal(t, ErrBulkDuplicateTask.Error(), result.Results[1].Error)
+    assert.Len(t, *events, 1)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should apply nothing in atomic mode when a task is in two operations", func(t *testing.T) {
+    service, taskRepo, _ := newTestBulkService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    priority := types.TaskPriorityLow
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+    result, err := service.BulkTasks(context.Background(), userID, types.BulkTaskInput{
+      Atomic: true,
+      Operations: []types.BulkTaskOperation{
+        {Action: types.BulkActionUpdate, IDs: []string{task.ID.Hex()}, Priority: &priority},
+        {Action: types.BulkActionArchive, IDs: []string{task.ID.Hex()}},
+      },
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, types.BulkResultSkipped, result.Results[0].Status)
+    assert.Equal(t, ErrBulkDuplicateTask.Error(), result.Results[1].Error)
+    taskRepo.AssertNotCalled(t, "BulkUpdate", mock.Anything, mock.Anything)
+  })
+}
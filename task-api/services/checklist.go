@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// MaxChecklistItems - items allowed on one task
+const MaxChecklistItems = 100
+
+// Checklist errors that handlers map to specific HTTP statuses
+var (
+  ErrChecklistItemNotFound = errors.New("checklist item not found")
+  ErrChecklistMismatch     = errors.New("item_ids must list every checklist item once")
+  ErrChecklistFull         = errors.New("checklist is full")
+)
+
+// AddChecklistItem - append an unchecked item to the end of the checklist
+func (s *taskService) AddChecklistItem(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.AddChecklistItemInput) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  task, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return nil, err
+  }
+
+  if len(task.Checklist) >= MaxChecklistItems {
+    return nil, ErrChecklistFull
+  }
+
+  order := 0
+  for _, item := range task.Checklist {
+    order = max(order, item.Order+1)
+  }
+
+  item := models.ChecklistItem{
+    ID:    bson.NewObjectID(),
+    Text:  input.Text,
+    Order: order,
+  }
+  if err := s.taskRepo.AddChecklistItem(ctx, taskID, item, MaxChecklistItems); err != nil {
+    return nil, mapChecklistError(err)
+  }
+
+  s.recordEvent(ctx, taskID, userID, types.TaskEventUpdated, []models.FieldChange{
+    {Field: checklistField(item.ID), New: item.Text},
+  })
+
+  return s.GetTask(ctx, taskID, userID)
+}
+
+// UpdateChecklistItem - edit the text or check / uncheck one item
+func (s *taskService) UpdateChecklistItem(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, itemID bson.ObjectID, input types.UpdateChecklistItemInput) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  task, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return nil, err
+  }
+
+  updates := bson.M{}
+  if input.Text != nil {
+    updates["text"] = *input.Text
+  }
+  if input.Done != nil {
+    updates["done"] = *input.Done
+    if *input.Done {
+      updates["done_at"] = time.Now()
+    } else {
+      updates["done_at"] = nil
+    }
+  }
+
+  if err := s.taskRepo.UpdateChecklistItem(ctx, taskID, itemID, updates); err != nil {
+    return nil, mapChecklistError(err)
+  }
+
+  // An item added since the task was read has no earlier values
+  changes := []models.FieldChange{}
+  if before := checklistItem(task, itemID); before != nil {
+    field := checklistField(itemID)
+    if input.Text != nil {
+      changes = append(changes, historyChanges(field+".text", before.Text, *input.Text)...)
+    }
+    if input.Done != nil {
+      changes = append(changes, historyChanges(field+".done", before.Done, *input.Done)...)
+    }
+  }
+  s.recordEvent(ctx, taskID, userID, types.TaskEventUpdated, changes)
+
+  return s.GetTask(ctx, taskID, userID)
+}
+
+// RemoveChecklistItem - delete one item, the order of the others is kept
+func (s *taskService) RemoveChecklistItem(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, itemID bson.ObjectID) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  task, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return nil, err
+  }
+
+  if err := s.taskRepo.RemoveChecklistItem(ctx, taskID, itemID); err != nil {
+    return nil, mapChecklistError(err)
+  }
+
+  change := models.FieldChange{Field: checklistField(itemID)}
+  if item := checklistItem(task, itemID); item != nil {
+    change.Old = item.Text
+  }
+  s.recordEvent(ctx, taskID, userID, types.TaskEventUpdated, []models.FieldChange{change})
+
+  return s.GetTask(ctx, taskID, userID)
+}
+
+// ReorderChecklist - put the items in the order of itemIDs, which must list every item once
+func (s *taskService) ReorderChecklist(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, itemIDs []bson.ObjectID) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  task, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return nil, err
+  }
+
+  // The repository matches the current items, so a concurrent add or remove fails the whole reorder
+  if err := s.taskRepo.ReorderChecklist(ctx, taskID, itemIDs); err != nil {
+    return nil, mapChecklistError(err)
+  }
+
+  // Recorded as item IDs in list order
+  oldOrder := []string{}
+  for _, item := range types.ToChecklistResponse(task.Checklist) {
+    oldOrder = append(oldOrder, item.ID)
+  }
+  s.recordEvent(ctx, taskID, userID, types.TaskEventUpdated, historyChanges("checklist", oldOrder, itemIDs))
+
+  return s.GetTask(ctx, taskID, userID)
+}
+
+// checklistField - history field of one checklist item
+func checklistField(itemID bson.ObjectID) string {
+  return "checklist." + itemID.Hex()
+}
+
+// checklistItem - item of task with itemID, nil when it has none
+func checklistItem(task *models.Task, itemID bson.ObjectID) *models.ChecklistItem {
+  for i := range task.Checklist {
+    if task.Checklist[i].ID == itemID {
+      return &task.Checklist[i]
+    }
+  }
+  return nil
+}
+
+// mapChecklistError - translate repository checklist errors
+func mapChecklistError(err error) error {
+  switch {
+  case errors.Is(err, repositories.ErrChecklistItemNotFound):
+    return ErrChecklistItemNotFound
+  case errors.Is(err, repositories.ErrChecklistMismatch):
+    return ErrChecklistMismatch
+  case errors.Is(err, repositories.ErrChecklistFull):
+    return ErrChecklistFull
+  }
+  return mapTaskNotFound(err)
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+func TestTaskService_AddChecklistItem(t *testing.T) {
+  t.Run("should append item after the last one", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Checklist: []models.ChecklistItem{
+      {ID: bson.NewObjectID(), Order: 4},
+      {ID: bson.NewObjectID(), Order: 1},
+    }}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("AddChecklistItem", mock.Anything, task.ID, mock.MatchedBy(func(item models.ChecklistItem) bool {
+      return item.Text == "Write tests" && item.Order == 5 && !item.Done && !item.ID.IsZero()
+    }), MaxChecklistItems).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.AddChecklistItem(context.Background(), task.ID, userID, types.AddChecklistItemInput{Text: "Write tests"})
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should refuse a full checklist", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Checklist: make([]models.ChecklistItem, MaxChecklistItems)}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+    _, err := service.AddChecklistItem(context.Background(), task.ID, userID, types.AddChecklistItemInput{Text: "One more"})
+
+    assert.ErrorIs(t, err, ErrChecklistFull)
+    taskRepo.AssertNotCalled(t, "AddChecklistItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should refuse an item that a concurrent add left no room for", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Checklist: make([]models.ChecklistItem, MaxChecklistItems-1)}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("AddChecklistItem", mock.Anything, task.ID, mock.Anything, MaxChecklistItems).Return(repositories.ErrChecklistFull)
+
+    _, err := service.AddChecklistItem(context.Background(), task.ID, userID, types.AddChecklistItemInput{Text: "One more"})
+
+    assert.ErrorIs(t, err, ErrChecklistFull)
+  })
+
+  t.Run("should refuse viewers of a shared task", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: bson.NewObjectID()}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, task.ID, userID).Return(taskShare(task.ID, userID, types.SharePermissionView), nil)
+
+    _, err := service.AddChecklistItem(context.Background(), task.ID, userID, types.AddChecklistItemInput{Text: "Nope"})
+
+    assert.ErrorIs(t, err, ErrTaskPermissionDenied)
+  })
+}
+
+func TestTaskService_UpdateChecklistItem(t *testing.T) {
+  t.Run("should check item with completion time", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    itemID := bson.NewObjectID()
+    done := true
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("UpdateChecklistItem", mock.Anything, task.ID, itemID, mock.MatchedBy(func(updates bson.M) bool {
+      _, hasText := updates["text"]
+      return updates["done"] == true && updates["done_at"] != nil && !hasText
+    })).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateChecklistItem(context.Background(), task.ID, userID, itemID, types.UpdateChecklistItemInput{Done: &done})
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should clear completion time when unchecking", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    itemID := bson.NewObjectID()
+    done := false
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("UpdateChecklistItem", mock.Anything, task.ID, itemID, bson.M{"done": false, "done_at": nil}).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateChecklistItem(context.Background(), task.ID, userID, itemID, types.UpdateChecklistItemInput{Done: &done})
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should return error for unknown item", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    text := "Renamed"
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("UpdateChecklistItem", mock.Anything, task.ID, mock.Anything, bson.M{"text": text}).Return(repositories.ErrChecklistItemNotFound)
+
+    _, err := service.UpdateChecklistItem(context.Background(), task.ID, userID, bson.NewObjectID(), types.UpdateChecklistItemInput{Text: &text})
+
+    assert.ErrorIs(t, err, ErrChecklistItemNotFound)
+  })
+}
+
+func TestTaskService_ReorderChecklist(t *testing.T) {
+  t.Run("should map changed checklist to mismatch", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    itemIDs := []bson.ObjectID{bson.NewObjectID(), bson.NewObjectID()}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("ReorderChecklist", mock.Anything, task.ID, itemIDs).Return(repositories.ErrChecklistMismatch)
+
+    _, err := service.ReorderChecklist(context.Background(), task.ID, userID, itemIDs)
+
+    assert.ErrorIs(t, err, ErrChecklistMismatch)
+  })
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+var (
+  ErrCommentNotFound       = errors.New("comment not found")
+  ErrCommentAuthorRequired = errors.New("comment author required")
+)
+
+// Comment list limits
+const (
+  DefaultCommentLimit = 20
+  MaxMentions         = 20
+)
+
+// mentionPattern - "@" followed by an email address, at the start of the body or after whitespace
+var mentionPattern = regexp.MustCompile(`(?:^|\s)@([^\s@]+@[^\s@]+\.[^\s@]+)`)
+
+// CommentService - interface
+type CommentService interface {
+  CreateComment(ctx context.Context, taskID, userID bson.ObjectID, input types.CommentInput) (*types.CommentResponse, error)
+  GetComments(ctx context.Context, taskID, userID bson.ObjectID, query types.CommentQueryParams) (*types.CommentListResponse, error)
+  UpdateComment(ctx context.Context, taskID, commentID, userID bson.ObjectID, input types.CommentInput) (*types.CommentResponse, error)
+  DeleteComment(ctx context.Context, taskID, commentID, userID bson.ObjectID) error
+}
+
+// commentService - implementation
+type commentService struct {
+  commentRepo repositories.CommentRepository
+  taskRepo    repositories.TaskRepository
+  shareRepo   repositories.TaskShareRepository
+  userRepo    repositories.UserRepository
+}
+
+// NewCommentService - constructor
+func NewCommentService(commentRepo repositories.CommentRepository, taskRepo repositories.TaskRepository, shareRepo repositories.TaskShareRepository, userRepo repositories.UserRepository) CommentService {
+  return &commentService{
+    commentRepo: commentRepo,
+    taskRepo:    taskRepo,
+    shareRepo:   shareRepo,
+    userRepo:    userRepo,
+  }
+}
+
+// CreateComment - post a comment on a task the user can see
+func (s *commentService) CreateComment(ctx context.Context, taskID, userID bson.ObjectID, input types.CommentInput) (*types.CommentResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if err := s.requireTaskAccess(ctx, taskID, userID); err != nil {
+    return nil, err
+  }
+
+  mentions, err := s.mentionedUsers(ctx, taskID, input.Body)
+  if err != nil {
+    return nil, err
+  }
+
+  comment := &models.Comment{
+    TaskID:   taskID,
+    AuthorID: userID,
+    Body:     strings.TrimSpace(input.Body),
+    Mentions: mentions,
+  }
+  if err := s.commentRepo.Create(ctx, comment); err != nil {
+    return nil, err
+  }
+
+  response := types.ToCommentResponse(comment)
+  return &response, nil
+}
+
+// GetComments - page of comments oldest first, continuing after query.Cursor
+func (s *commentService) GetComments(ctx context.Context, taskID, userID bson.ObjectID, query types.CommentQueryParams) (*types.CommentListResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  if err := s.requireTaskAccess(ctx, taskID, userID); err != nil {
+    return nil, err
+  }
+
+  limit := query.Limit
+  if limit < 1 {
+    limit = DefaultCommentLimit
+  }
+
+  var after *bson.ObjectID
+  if query.Cursor != "" {
+    cursor, err := bson.ObjectIDFromHex(query.Cursor)
+    if err != nil {
+      return nil, err
+    }
+    after = &cursor
+  }
+
+  // One extra comment tells whether another page follows
+  comments, err := s.commentRepo.FindByTask(ctx, taskID, after, limit+1)
+  if err != nil {
+    return nil, err
+  }
+
+  response := &types.CommentListResponse{}
+  if len(comments) > limit {
+    comments = comments[:limit]
+    response.NextCursor = comments[limit-1].ID.Hex()
+  }
+
+  response.Comments = make([]types.CommentResponse, len(comments))
+  for i := range comments {
+    response.Comments[i] = types.ToCommentResponse(&comments[i])
+  }
+
+  return response, nil
+}
+
+// UpdateComment - edit the body, only by its author
+func (s *commentService) UpdateComment(ctx context.Context, taskID, commentID, userID bson.ObjectID, input types.CommentInput) (*types.CommentResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if _, err := s.authoredComment(ctx, taskID, commentID, userID); err != nil {
+    return nil, err
+  }
+
+  mentions, err := s.mentionedUsers(ctx, taskID, input.Body)
+  if err != nil {
+    return nil, err
+  }
+
+  comment, err := s.commentRepo.Update(ctx, taskID, commentID, strings.TrimSpace(input.Body), mentions)
+  if err != nil {
+    return nil, mapCommentNotFound(err)
+  }
+
+  response := types.ToCommentResponse(comment)
+  return &response, nil
+}
+
+// DeleteComment - delete a comment, only by its author
+func (s *commentService) DeleteComment(ctx context.Context, taskID, commentID, userID bson.ObjectID) error {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if _, err := s.authoredComment(ctx, taskID, commentID, userID); err != nil {
+    return err
+  }
+
+  return mapCommentNotFound(s.commentRepo.Delete(ctx, taskID, commentID))
+}
+
+// requireTaskAccess - the task must be visible to the user: the ownership check
+// of TaskRepository.FindByID, or a share of any permission
+func (s *commentService) requireTaskAccess(ctx context.Context, taskID, userID bson.ObjectID) error {
+  _, err := s.taskRepo.FindByID(ctx, taskID, userID)
+  if errors.Is(err, repositories.ErrTaskNotFound) {
+    return checkShare(ctx, s.shareRepo, taskID, userID, types.SharePermissionView, ErrTaskNotFound)
+  }
+  return err
+}
+
+// authoredComment - comment of a visible task written by the user
+func (s *commentService) authoredComment(ctx context.Context, taskID, commentID, userID bson.ObjectID) (*models.Comment, error) {
+  if err := s.requireTaskAccess(ctx, taskID, userID); err != nil {
+    return nil, err
+  }
+
+  comment, err := s.commentRepo.FindByID(ctx, taskID, commentID)
+  if err != nil {
+    return nil, mapCommentNotFound(err)
+  }
+
+  if comment.AuthorID != userID {
+    return nil, ErrCommentAuthorRequired
+  }
+  return comment, nil
+}
+
+// mentionedUsers - IDs of users mentioned as @email who can see the task. Unknown emails and
+// users without access are ignored alike, so mentions do not tell which emails are registered.
+func (s *commentService) mentionedUsers(ctx context.Context, taskID bson.ObjectID, body string) ([]bson.ObjectID, error) {
+  ids := []bson.ObjectID{}
+  seen := map[string]bool{}
+
+  for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+    // Punctuation after a mention is not part of the address
+    email := normalizeEmail(strings.TrimRight(match[1], ".,;:!?)"))
+    if seen[email] {
+      continue
+    }
+    seen[email] = true
+    if len(seen) > MaxMentions {
+      break
+    }
+
+    user, err := s.userRepo.FindByEmail(ctx, email)
+    if errors.Is(err, repositories.ErrUserNotFound) {
+      continue
+    }
+    if err != nil {
+      return nil, err
+    }
+
+    err = s.requireTaskAccess(ctx, taskID, user.ID)
+    if errors.Is(err, ErrTaskNotFound) {
+      continue
+    }
+    if err != nil {
+      return nil, err
+    }
+    ids = append(ids, user.ID)
+  }
+
+  return ids, nil
+}
+
+// mapCommentNotFound - translate repository comment errors
+func mapCommentNotFound(err error) error {
+  if errors.Is(err, repositories.ErrCommentNotFound) {
+    return ErrCommentNotFound
+  }
+  return err
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// MockCommentRepository - mock for testing
+type MockCommentRepository struct {
+  mock.Mock
+}
+
+func (m *MockCommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+  args := m.Called(ctx, comment)
+  return args.Error(0)
+}
+
+func (m *MockCommentRepository) FindByID(ctx context.Context, taskID, id bson.ObjectID) (*models.Comment, error) {
+  args := m.Called(ctx, taskID, id)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*models.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) FindByTask(ctx context.Context, taskID bson.ObjectID, after *bson.ObjectID, limit int) ([]models.Comment, error) {
+  args := m.Called(ctx, taskID, after, limit)
+  return args.Get(0).([]models.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) Update(ctx context.Context, taskID, id bson.ObjectID, body string, mentions []bson.ObjectID) (*models.Comment, error) {
+  args := m.Called(ctx, taskID, id, body, mentions)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*models.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) Delete(ctx context.Context, taskID, id bson.ObjectID) error {
+  args := m.Called(ctx, taskID, id)
+  return args.Error(0)
+}
+
+func (m *MockCommentRepository) DeleteByTasks(ctx context.Context, taskIDs []bson.ObjectID) error {
+  args := m.Called(ctx, taskIDs)
+  return args.Error(0)
+}
+
+func newTestCommentService() (CommentService, *MockCommentRepository, *MockTaskRepository, *MockTaskShareRepository, *MockUserRepository) {
+  commentRepo, taskRepo, shareRepo, userRepo := new(MockCommentRepository), new(MockTaskRepository), new(MockTaskShareRepository), new(MockUserRepository)
+  return NewCommentService(commentRepo, taskRepo, shareRepo, userRepo), commentRepo, taskRepo, shareRepo, userRepo
+}
+
+func TestCommentService_CreateComment(t *testing.T) {
+  t.Run("should record mentioned users", func(t *testing.T) {
+    service, commentRepo, taskRepo, _, userRepo := newTestCommentService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    alice := &models.User{ID: bson.NewObjectID(), Email: "alice@example.com"}
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID, UserID: userID}, nil)
+    taskRepo.On("FindByID", mock.Anything, taskID, alice.ID).Return(&models.Task{ID: taskID, UserID: userID}, nil)
+    userRepo.On("FindByEmail", mock.Anything, "alice@example.com").Return(alice, nil)
+    userRepo.On("FindByEmail", mock.Anything, "nobody@example.com").Return(nil, repositories.ErrUserNotFound)
+    commentRepo.On("Create", mock.Anything, mock.MatchedBy(func(c *models.Comment) bool {
+      return c.TaskID == taskID && c.AuthorID == userID && len(c.Mentions) == 1 && c.Mentions[0] == alice.ID
+    })).Return(nil)
+
+    body := "@Alice@Example.com, please review. Also @nobody@example.com and again @alice@example.com."
+    response, err := service.CreateComment(context.Background(), taskID, userID, types.CommentInput{Body: body})
+
+    assert.NoError(t, err)
+    assert.Equal(t, []string{alice.ID.Hex()}, response.Mentions)
+    commentRepo.AssertExpectations(t)
+    userRepo.AssertNumberOfCalls(t, "FindByEmail", 2)
+  })
+
+  t.Run("should ignore mentioned users who cannot see the task", func(t *testing.T) {
+    service, commentRepo, taskRepo, shareRepo, userRepo := newTestCommentService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    outsider := &models.User{ID: bson.NewObjectID(), Email: "outsider@example.com"}
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID, UserID: userID}, nil)
+    taskRepo.On("FindByID", mock.Anything, taskID, outsider.ID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, outsider.ID).Return(nil, repositories.ErrTaskShareNotFound)
+    userRepo.On("FindByEmail", mock.Anything, "outsider@example.com").Return(outsider, nil)
+    commentRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+    response, err := service.CreateComment(context.Background(), taskID, userID, types.CommentInput{Body: "@outsider@example.com are you there?"})
+
+    assert.NoError(t, err)
+    assert.Empty(t, response.Mentions)
+  })
+
+  t.Run("should allow commenting through a view share", func(t *testing.T) {
+    service, commentRepo, taskRepo, shareRepo, _ := newTestCommentService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(taskShare(taskID, userID, types.SharePermissionView), nil)
+    commentRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+    _, err := service.CreateComment(context.Background(), taskID, userID, types.CommentInput{Body: "Looks good"})
+
+    assert.NoError(t, err)
+  })
+
+  t.Run("should hide tasks the user cannot see", func(t *testing.T) {
+    service, commentRepo, taskRepo, shareRepo, _ := newTestCommentService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskShareNotFound)
+
+    _, err := service.CreateComment(context.Background(), taskID, userID, types.CommentInput{Body: "Hello"})
+
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+    commentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+}
+
+func TestCommentService_GetComments(t *testing.T) {
+  t.Run("should return next cursor when more comments follow", func(t *testing.T) {
+    service, commentRepo, taskRepo, _, _ := newTestCommentService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    cursor := bson.NewObjectID()
+    comments := []models.Comment{{ID: bson.NewObjectID()}, {ID: bson.NewObjectID()}, {ID: bson.NewObjectID()}}
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID}, nil)
+    commentRepo.On("FindByTask", mock.Anything, taskID, &cursor, 3).Return(comments, nil)
+
+    response, err := service.GetComments(context.Background(), taskID, userID, types.CommentQueryParams{Cursor: cursor.Hex(), Limit: 2})
+
+    assert.NoError(t, err)
+    assert.Len(t, response.Comments, 2)
+    assert.Equal(t, comments[1].ID.Hex(), response.NextCursor)
+  })
+
+  t.Run("should omit cursor on the last page", func(t *testing.T) {
+    service, commentRepo, taskRepo, _, _ := newTestCommentService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID}, nil)
+    commentRepo.On("FindByTask", mock.Anything, taskID, (*bson.ObjectID)(nil), DefaultCommentLimit+1).Return([]models.Comment{{ID: bson.NewObjectID()}}, nil)
+
+    response, err := service.GetComments(context.Background(), taskID, userID, types.CommentQueryParams{})
+
+    assert.NoError(t, err)
+    assert.Len(t, response.Comments, 1)
+    assert.Empty(t, response.NextCursor)
+  })
+}
+
+func TestCommentService_UpdateComment(t *testing.T) {
+  t.Run("should refuse editing another user's comment", func(t *testing.T) {
+    service, commentRepo, taskRepo, _, _ := newTestCommentService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    comment := &models.Comment{ID: bson.NewObjectID(), TaskID: taskID, AuthorID: bson.NewObjectID()}
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID}, nil)
+    commentRepo.On("FindByID", mock.Anything, taskID, comment.ID).Return(comment, nil)
+
+    _, err := service.UpdateComment(context.Background(), taskID, comment.ID, userID, types.CommentInput{Body: "Changed"})
+
+    assert.ErrorIs(t, err, ErrCommentAuthorRequired)
+    commentRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should update own comment", func(t *testing.T) {
+    service, commentRepo, taskRepo, _, _ := newTestCommentService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    comment := &models.Comment{ID: bson.NewObjectID(), TaskID: taskID, AuthorID: userID}
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID}, nil)
+    commentRepo.On("FindByID", mock.Anything, taskID, comment.ID).Return(comment, nil)
+    commentRepo.On("Update", mock.Anything, taskID, comment.ID, "Changed", []bson.ObjectID{}).
+      Return(&models.Comment{ID: comment.ID, TaskID: taskID, AuthorID: userID, Body: "Changed"}, nil)
+
+    response, err := service.UpdateComment(context.Background(), taskID, comment.ID, userID, types.CommentInput{Body: " Changed "})
+
+    assert.NoError(t, err)
+    assert.Equal(t, "Changed", response.Body)
+  })
+}
+
+func TestCommentService_DeleteComment(t *testing.T) {
+  t.Run("should return not found for unknown comment", func(t *testing.T) {
+    service, commentRepo, taskRepo, _, _ := newTestCommentService()
+    userID, taskID, commentID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID}, nil)
+    commentRepo.On("FindByID", mock.Anything, taskID, commentID).Return(nil, repositories.ErrCommentNotFound)
+
+    err := service.DeleteComment(context.Background(), taskID, commentID, userID)
+
+    assert.ErrorIs(t, err, ErrCommentNotFound)
+  })
+}
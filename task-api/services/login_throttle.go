@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"task-api/configs"
+	"task-api/repositories"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Brute-force protection errors, always wrapped in a LoginBlockedError
+var (
+  ErrAccountLocked   = errors.New("account temporarily locked")
+  ErrTooManyAttempts = errors.New("too many login attempts")
+)
+
+// ErrLoginUnavailable - attempt store failed, logins are refused rather than let through unthrottled
+var ErrLoginUnavailable = errors.New("login temporarily unavailable")
+
+// LoginBlockedError - login refused before the password was checked
+type LoginBlockedError struct {
+  Err        error // ErrAccountLocked or ErrTooManyAttempts
+  RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+  return e.Err.Error()
+}
+
+func (e *LoginBlockedError) Unwrap() error {
+  return e.Err
+}
+
+// LoginThrottleConfig - brute-force protection settings
+type LoginThrottleConfig struct {
+  Window           time.Duration // Failures older than this are forgotten
+  DelayAfter       int           // Failures before progressive delays start
+  BaseDelay        time.Duration // First delay, doubled on every further failure
+  MaxEmailFailures int           // Failures before the account is locked
+  MaxIPFailures    int           // Failures before the client IP is locked
+  LockoutDuration  time.Duration
+}
+
+// LoginThrottleConfigFromEnv - read settings from env with safe defaults
+func LoginThrottleConfigFromEnv() LoginThrottleConfig {
+  return LoginThrottleConfig{
+    Window:           configs.GetDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+    DelayAfter:       configs.GetInt("LOGIN_DELAY_AFTER", 3),
+    BaseDelay:        configs.GetDuration("LOGIN_BASE_DELAY", time.Second),
+    MaxEmailFailures: configs.GetInt("LOGIN_MAX_EMAIL_FAILURES", 5),
+    MaxIPFailures:    configs.GetInt("LOGIN_MAX_IP_FAILURES", 20),
+    LockoutDuration:  configs.GetDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+  }
+}
+
+// loginThrottle - tracks failed logins per email and per client IP
+type loginThrottle struct {
+  store  repositories.LoginAttemptStore
+  config LoginThrottleConfig
+}
+
+// emailAttemptKey - counter key for an account
+func emailAttemptKey(email string) string {
+  return "email:" + email
+}
+
+// ipAttemptKey - counter key for a client IP
+func ipAttemptKey(ip string) string {
+  return "ip:" + ip
+}
+
+// check - refuse the attempt while email or IP is locked or still inside its delay
+func (t *loginThrottle) check(ctx context.Context, email, ip string) error {
+  now := time.Now()
+
+  type limit struct {
+    key    string
+    locked error
+  }
+
+  limits := []limit{{emailAttemptKey(email), ErrAccountLocked}}
+  if ip != "" {
+    limits = append(limits, limit{ipAttemptKey(ip), ErrTooManyAttempts})
+  }
+
+  for _, k := range limits {
+    attempt, err := t.store.Get(ctx, k.key)
+    if err != nil {
+      return storeFailed(err)
+    }
+    if attempt == nil {
+      continue
+    }
+
+    if attempt.IsLocked(now) {
+      return &LoginBlockedError{Err: k.locked, RetryAfter: attempt.LockedUntil.Sub(now)}
+    }
+
+    if retryAt := attempt.LastFailureAt.Add(t.delay(attempt.Failures)); now.Before(retryAt) {
+      return &LoginBlockedError{Err: ErrTooManyAttempts, RetryAfter: retryAt.Sub(now)}
+    }
+  }
+
+  return nil
+}
+
+// recordFailure - count a failed attempt for email and IP and lock keys that
+// crossed their limit. Returns a LoginBlockedError when this attempt caused a lock.
+func (t *loginThrottle) recordFailure(ctx context.Context, email, ip string) error {
+  var blocked error
+
+  if ip != "" {
+    attempt, err := t.store.RecordFailure(ctx, ipAttemptKey(ip), t.config.Window)
+    if err != nil {
+      return storeFailed(err)
+    }
+    if attempt.Failures >= t.config.MaxIPFailures {
+      if err := t.lock(ctx, ipAttemptKey(ip)); err != nil {
+        return err
+      }
+      log.Warn().Str("ip", ip).Int("failures", attempt.Failures).Msg("Client IP locked after failed logins")
+      blocked = &LoginBlockedError{Err: ErrTooManyAttempts, RetryAfter: t.config.LockoutDuration}
+    }
+  }
+
+  attempt, err := t.store.RecordFailure(ctx, emailAttemptKey(email), t.config.Window)
+  if err != nil {
+    return storeFailed(err)
+  }
+  if attempt.Failures >= t.config.MaxEmailFailures {
+    if err := t.lock(ctx, emailAttemptKey(email)); err != nil {
+      return err
+    }
+    log.Warn().Str("email", email).Int("failures", attempt.Failures).Msg("Account locked after failed logins")
+    blocked = &LoginBlockedError{Err: ErrAccountLocked, RetryAfter: t.config.LockoutDuration}
+  }
+
+  return blocked
+}
+
+// reset - forget failures of an account after a successful login or password reset
+func (t *loginThrottle) reset(ctx context.Context, email string) error {
+  if err := t.store.Reset(ctx, emailAttemptKey(email)); err != nil {
+    return storeFailed(err)
+  }
+  return nil
+}
+
+// lock - lock key for the configured duration
+func (t *loginThrottle) lock(ctx context.Context, key string) error {
+  if err := t.store.Lock(ctx, key, time.Now().Add(t.config.LockoutDuration)); err != nil {
+    return storeFailed(err)
+  }
+  return nil
+}
+
+// storeFailed - wrap an attempt store error so handlers answer 503 instead of invalid credentials
+func storeFailed(err error) error {
+  return fmt.Errorf("%w: %v", ErrLoginUnavailable, err)
+}
+
+// delay - wait required after the given number of failures, doubling up to the lockout duration
+func (t *loginThrottle) delay(failures int) time.Duration {
+  if failures < t.config.DelayAfter {
+    return 0
+  }
+
+  delay := t.config.BaseDelay
+  for i := t.config.DelayAfter; i < failures && delay < t.config.LockoutDuration; i++ {
+    delay *= 2
+  }
+
+  if delay > t.config.LockoutDuration {
+    return t.config.LockoutDuration
+  }
+  return delay
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/configs"
+	"task-api/models"
+	"task-api/types"
+	"task-api/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Two-factor errors that handlers map to specific HTTP statuses
+var (
+  ErrMFAAlreadyEnabled = errors.New("mfa already enabled")
+  ErrMFANotSetUp       = errors.New("mfa setup not started")
+  ErrInvalidMFACode    = errors.New("invalid mfa code")
+  ErrInvalidMFAToken   = errors.New("invalid mfa token")
+)
+
+// recoveryCodeCount - recovery codes issued when 2FA is enabled
+const recoveryCodeCount = 10
+
+// SetupMFA - generate a new TOTP secret, active only after VerifyMFASetup
+func (s *authService) SetupMFA(ctx context.Context, userID bson.ObjectID) (*types.MFASetupResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  user, err := s.findUser(ctx, userID)
+  if err != nil {
+    return nil, err
+  }
+  if user.MFAEnabled {
+    return nil, ErrMFAAlreadyEnabled
+  }
+
+  secret, err := utils.GenerateTOTPSecret()
+  if err != nil {
+    return nil, errors.New("failed to generate secret")
+  }
+
+  if err := s.userRepo.Update(ctx, userID, bson.M{"totp_secret": secret}); err != nil {
+    return nil, err
+  }
+
+  issuer := configs.GetEnv("MFA_ISSUER", "Task API")
+
+  return &types.MFASetupResponse{
+    Secret:     secret,
+    OTPAuthURI: utils.TOTPURI(issuer, user.Email, secret),
+  }, nil
+}
+
+// VerifyMFASetup - enable 2FA once the user proves the app generates valid codes
+func (s *authService) VerifyMFASetup(ctx context.Context, userID bson.ObjectID, input types.MFAVerifyInput) (*types.MFAVerifyResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  user, err := s.findUser(ctx, userID)
+  if err != nil {
+    return nil, err
+  }
+  if user.MFAEnabled {
+    return nil, ErrMFAAlreadyEnabled
+  }
+  if user.TOTPSecret == "" {
+    return nil, ErrMFANotSetUp
+  }
+
+  step, ok := utils.ValidateTOTPCode(user.TOTPSecret, input.Code, time.Now())
+  if !ok {
+    return nil, ErrInvalidMFACode
+  }
+
+  codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+  if err != nil {
+    return nil, errors.New("failed to generate recovery codes")
+  }
+
+  hashes := make([]string, len(codes))
+  for i, code := range codes {
+    hashes[i] = utils.HashToken(code)
+  }
+
+  err = s.userRepo.Update(ctx, userID, bson.M{
+    "mfa_enabled":    true,
+    "totp_last_step": step,
+    "recovery_codes": hashes,
+  })
+  if err != nil {
+    return nil, err
+  }
+
+  log.Info().Str("user_id", userID.Hex()).Msg("Two-factor authentication enabled")
+
+  return &types.MFAVerifyResponse{RecoveryCodes: codes}, nil
+}
+
+// LoginMFA - exchange mfa token plus TOTP or recovery code for a session
+func (s *authService) LoginMFA(ctx context.Context, input types.MFALoginInput) (*types.LoginResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  claims, err := utils.ValidateMFAToken(input.MFAToken)
+  if err != nil {
+    return nil, ErrInvalidMFAToken
+  }
+
+  // MFA tokens are single use, the jti is revoked after a successful exchange
+  revoked, err := s.revocations.IsRevoked(ctx, claims.ID, claims.UserID, claims.IssuedAt.Time)
+  if err != nil {
+    return nil, err
+  }
+  if revoked {
+    return nil, ErrInvalidMFAToken
+  }
+
+  // Code guesses count towards the same lockout as password guesses
+  if err := s.throttle.check(ctx, claims.Email, input.ClientIP); err != nil {
+    return nil, err
+  }
+
+  user, err := s.findUser(ctx, claims.UserID)
+  if err != nil || !user.MFAEnabled || user.DisabledAt != nil {
+    return nil, ErrInvalidMFAToken
+  }
+
+  ok, err := s.verifySecondFactor(ctx, user, input)
+  if err != nil {
+    return nil, err
+  }
+  if !ok {
+    log.Warn().Str("user_id", user.ID.Hex()).Str("ip", input.ClientIP).Msg("Invalid two-factor code")
+    if err := s.throttle.recordFailure(ctx, claims.Email, input.ClientIP); err != nil {
+      return nil, err
+    }
+    return nil, ErrInvalidMFACode
+  }
+
+  if err := s.throttle.reset(ctx, claims.Email); err != nil {
+    return nil, err
+  }
+  if err := s.revocations.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+    return nil, err
+  }
+
+  return s.issueLoginResponse(ctx, user, bson.NewObjectID())
+}
+
+// verifySecondFactor - check TOTP code (refusing replays) or consume a recovery code
+func (s *authService) verifySecondFactor(ctx context.Context, user *models.User, input types.MFALoginInput) (bool, error) {
+  if input.Code != "" {
+    step, ok := utils.ValidateTOTPCode(user.TOTPSecret, input.Code, time.Now())
+    if !ok {
+      return false, nil
+    }
+    return s.userRepo.UseTOTPStep(ctx, user.ID, step)
+  }
+
+  codeHash := utils.HashToken(utils.NormalizeRecoveryCode(input.RecoveryCode))
+  used, err := s.userRepo.ConsumeRecoveryCode(ctx, user.ID, codeHash)
+  if err == nil && used {
+    log.Info().Str("user_id", user.ID.Hex()).Msg("Recovery code used")
+  }
+  return used, err
+}
+
+// issueMFAChallenge - password step passed, hand out a pending token instead of a session
+func (s *authService) issueMFAChallenge(user *models.User) (*types.LoginResponse, error) {
+  token, err := utils.GenerateMFAToken(user.ID, user.Email)
+  if err != nil {
+		log.Error().
+      Err(err).
+      Str("user_id", user.ID.Hex()).
+      Msg("Failed to generate MFA token")
+    return nil, errors.New("failed to generate token")
+  }
+
+  return &types.LoginResponse{
+    MFARequired: true,
+    MFAToken:    token,
+    User:        types.ToUserResponse(user),
+  }, nil
+}
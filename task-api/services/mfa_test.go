@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/types"
+	"task-api/utils"
+)
+
+// currentTOTPCode - code an authenticator app would show right now
+func currentTOTPCode(t *testing.T, secret string) (string, int64) {
+  step := utils.TOTPStep(time.Now())
+  code, err := utils.GenerateTOTPCode(secret, step)
+  assert.NoError(t, err)
+  return code, step
+}
+
+func TestAuthService_SetupMFA(t *testing.T) {
+  t.Run("should store secret and return otpauth URI", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    userID := bson.NewObjectID()
+    mocks.userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID, Email: "test@test.com"}, nil)
+
+    var stored bson.M
+    mocks.userRepo.On("Update", mock.Anything, userID, mock.AnythingOfType("bson.M")).
+      Run(func(args mock.Arguments) {
+        stored = args.Get(2).(bson.M)
+      }).
+      Return(nil)
+
+    result, err := service.SetupMFA(context.Background(), userID)
+
+    assert.NoError(t, err)
+    assert.NotEmpty(t, result.Secret)
+    assert.Equal(t, result.Secret, stored["totp_secret"])
+    assert.Contains(t, result.OTPAuthURI, "otpauth://totp/")
+    assert.Contains(t, result.OTPAuthURI, "secret="+result.Secret)
+    assert.NotContains(t, stored, "mfa_enabled")
+  })
+
+  t.Run("should refuse when already enabled", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    userID := bson.NewObjectID()
+    mocks.userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID, MFAEnabled: true}, nil)
+
+    result, err := service.SetupMFA(context.Background(), userID)
+
+    assert.Nil(t, result)
+    assert.ErrorIs(t, err, ErrMFAAlreadyEnabled)
+  })
+}
+
+func TestAuthService_VerifyMFASetup(t *testing.T) {
+  secret, _ := utils.GenerateTOTPSecret()
+
+  t.Run("should enable 2FA and return hashed-at-rest recovery codes", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    userID := bson.NewObjectID()
+    mocks.userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID, TOTPSecret: secret}, nil)
+
+    var stored bson.M
+    mocks.userRepo.On("Update", mock.Anything, userID, mock.AnythingOfType("bson.M")).
+      Run(func(args mock.Arguments) {
+        stored = args.Get(2).(bson.M)
+      }).
+      Return(nil)
+
+    code, step := currentTOTPCode(t, secret)
+    result, err := service.VerifyMFASetup(context.Background(), userID, types.MFAVerifyInput{Code: code})
+
+    assert.NoError(t, err)
+    assert.Len(t, result.RecoveryCodes, recoveryCodeCount)
+    assert.Equal(t, true, stored["mfa_enabled"])
+    assert.Equal(t, step, stored["totp_last_step"])
+
+    hashes := stored["recovery_codes"].([]string)
+    assert.Equal(t, utils.HashToken(result.RecoveryCodes[0]), hashes[0])
+  })
+
+  t.Run("should reject wrong code", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    userID := bson.NewObjectID()
+    mocks.userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID, TOTPSecret: secret}, nil)
+
+    result, err := service.VerifyMFASetup(context.Background(), userID, types.MFAVerifyInput{Code: "000000"})
+
+    assert.Nil(t, result)
+    assert.ErrorIs(t, err, ErrInvalidMFACode)
+    mocks.userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should require setup first", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+
+    userID := bson.NewObjectID()
+    mocks.userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID}, nil)
+
+    _, err := service.VerifyMFASetup(context.Background(), userID, types.MFAVerifyInput{Code: "123456"})
+
+    assert.ErrorIs(t, err, ErrMFANotSetUp)
+  })
+}
+
+func TestAuthService_LoginMFA(t *testing.T) {
+  secret, _ := utils.GenerateTOTPSecret()
+  hashedPassword, _ := utils.HashPassword("password123")
+
+  // startLogin - run password step for an MFA user and return the pending token
+  startLogin := func(t *testing.T, service AuthService, mocks *authServiceMocks) (*models.User, string) {
+    user := &models.User{
+      ID:            bson.NewObjectID(),
+      Email:         "test@test.com",
+      Password:      hashedPassword,
+      MFAEnabled:    true,
+      TOTPSecret:    secret,
+      RecoveryCodes: []string{utils.HashToken("abcde-fghij")},
+    }
+    mocks.userRepo.On("FindByEmail", mock.Anything, "test@test.com").Return(user, nil)
+    mocks.userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+    result, err := service.Login(context.Background(), types.LoginInput{Email: "test@test.com", Password: "password123"})
+
+    assert.NoError(t, err)
+    assert.True(t, result.MFARequired)
+    assert.Empty(t, result.Token)
+    assert.Empty(t, result.RefreshToken)
+    return user, result.MFAToken
+  }
+
+  t.Run("should issue session for valid TOTP code", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    user, mfaToken := startLogin(t, service, mocks)
+
+    code, step := currentTOTPCode(t, secret)
+    mocks.userRepo.On("UseTOTPStep", mock.Anything, user.ID, step).Return(true, nil)
+    mocks.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+    result, err := service.LoginMFA(context.Background(), types.MFALoginInput{MFAToken: mfaToken, Code: code})
+
+    assert.NoError(t, err)
+    assert.NotEmpty(t, result.Token)
+    assert.NotEmpty(t, result.RefreshToken)
+
+    // MFA token cannot be exchanged twice
+    _, err = service.LoginMFA(context.Background(), types.MFALoginInput{MFAToken: mfaToken, Code: code})
+    assert.ErrorIs(t, err, ErrInvalidMFAToken)
+  })
+
+  t.Run("should reject replayed TOTP step", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    user, mfaToken := startLogin(t, service, mocks)
+
+    code, step := currentTOTPCode(t, secret)
+    mocks.userRepo.On("UseTOTPStep", mock.Anything, user.ID, step).Return(false, nil)
+
+    result, err := service.LoginMFA(context.Background(), types.MFALoginInput{MFAToken: mfaToken, Code: code})
+
+    assert.Nil(t, result)
+    assert.ErrorIs(t, err, ErrInvalidMFACode)
+  })
+
+  t.Run("should accept recovery code", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    user, mfaToken := startLogin(t, service, mocks)
+
+    mocks.userRepo.On("ConsumeRecoveryCode", mock.Anything, user.ID, utils.HashToken("abcde-fghij")).Return(true, nil)
+    mocks.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+    result, err := service.LoginMFA(context.Background(), types.MFALoginInput{MFAToken: mfaToken, RecoveryCode: "ABCDEFGHIJ"})
+
+    assert.NoError(t, err)
+    assert.NotEmpty(t, result.Token)
+  })
+
+  t.Run("should lock account after repeated wrong codes", func(t *testing.T) {
+    service, mocks := newTestAuthService()
+    service.(*authService).throttle.config.DelayAfter = 100
+    service.(*authService).throttle.config.MaxEmailFailures = 2
+    _, mfaToken := startLogin(t, service, mocks)
+
+    _, err := service.LoginMFA(context.Background(), types.MFALoginInput{MFAToken: mfaToken, Code: "000000"})
+    assert.ErrorIs(t, err, ErrInvalidMFACode)
+
+    _, err = service.LoginMFA(context.Background(), types.MFALoginInput{MFAToken: mfaToken, Code: "000000"})
+    assert.ErrorIs(t, err, ErrAccountLocked)
+  })
+
+  t.Run("should reject access token as mfa token", func(t *testing.T) {
+    service, _ := newTestAuthService()
+
+    accessToken, _ := utils.GenerateToken(bson.NewObjectID(), "test@test.com", "member")
+
+    _, err := service.LoginMFA(context.Background(), types.MFALoginInput{MFAToken: accessToken, Code: "123456"})
+    assert.ErrorIs(t, err, ErrInvalidMFAToken)
+  })
+}
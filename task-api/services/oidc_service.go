@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/oidc"
+	"task-api/repositories"
+	"task-api/types"
+	"task-api/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+// oidcStateTTL - time the user has to finish signing in at the IdP
+const oidcStateTTL = 10 * time.Minute
+
+// Single sign-on errors that handlers map to specific HTTP statuses
+var (
+  ErrOIDCDisabled         = errors.New("oidc login not configured")
+  ErrInvalidOIDCState     = errors.New("invalid oidc state")
+  ErrOIDCLoginFailed      = errors.New("oidc login failed")
+  ErrOIDCEmailNotVerified = errors.New("oidc email not verified")
+  ErrOIDCAccountConflict  = errors.New("account linked to another identity")
+)
+
+// OIDCService - interface for OpenID Connect login
+type OIDCService interface {
+  Start(ctx context.Context) (*types.OIDCLoginStart, error)
+  Callback(ctx context.Context, input types.OIDCCallbackInput) (*types.LoginResponse, error)
+}
+
+// oidcService - implement OIDCService
+type oidcService struct {
+  provider    oidc.Provider // nil when SSO is not configured
+  stateRepo   repositories.OIDCStateRepository
+  userRepo    repositories.UserRepository
+  authService AuthService
+}
+
+// NewOIDCService - constructor
+func NewOIDCService(
+  provider oidc.Provider,
+  stateRepo repositories.OIDCStateRepository,
+  userRepo repositories.UserRepository,
+  authService AuthService,
+) OIDCService {
+  return &oidcService{
+    provider:    provider,
+    stateRepo:   stateRepo,
+    userRepo:    userRepo,
+    authService: authService,
+  }
+}
+
+// Start - create state, nonce and PKCE verifier and build the IdP authorization URL
+func (s *oidcService) Start(ctx context.Context) (*types.OIDCLoginStart, error) {
+  if s.provider == nil {
+    return nil, ErrOIDCDisabled
+  }
+
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  state, errState := utils.GenerateOpaqueToken()
+  nonce, errNonce := utils.GenerateOpaqueToken()
+  verifier, errVerifier := utils.GenerateOpaqueToken()
+  if err := errors.Join(errState, errNonce, errVerifier); err != nil {
+    return nil, errors.New("failed to generate state")
+  }
+
+  authURL, err := s.provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+  if err != nil {
+    return nil, err
+  }
+
+  now := time.Now()
+  err = s.stateRepo.Create(ctx, &models.OIDCState{
+    ID:           utils.HashToken(state),
+    Nonce:        nonce,
+    CodeVerifier: verifier,
+    ExpiresAt:    now.Add(oidcStateTTL),
+    CreatedAt:    now,
+  })
+  if err != nil {
+    return nil, err
+  }
+
+  return &types.OIDCLoginStart{
+    AuthorizationURL: authURL,
+    State:            state,
+    ExpiresIn:        int(oidcStateTTL.Seconds()),
+  }, nil
+}
+
+// Callback - redeem the code, validate the ID token and log the matching user in
+func (s *oidcService) Callback(ctx context.Context, input types.OIDCCallbackInput) (*types.LoginResponse, error) {
+  if s.provider == nil {
+    return nil, ErrOIDCDisabled
+  }
+
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  // State is consumed first so every callback URL works at most once
+  pending, err := s.stateRepo.Consume(ctx, utils.HashToken(input.State))
+  if err != nil {
+    if errors.Is(err, repositories.ErrOIDCStateInvalid) {
+      return nil, ErrInvalidOIDCState
+    }
+    return nil, err
+  }
+
+  if input.Error != "" {
+    return nil, fmt.Errorf("%w: %s %s", ErrOIDCLoginFailed, input.Error, input.ErrorDescription)
+  }
+
+  tokens, err := s.provider.Exchange(ctx, input.Code, pending.CodeVerifier)
+  if err != nil {
+    return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+  }
+
+  claims, err := s.provider.VerifyIDToken(ctx, tokens.IDToken, pending.Nonce)
+  if err != nil {
+    return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+  }
+
+  user, err := s.resolveUser(ctx, claims)
+  if err != nil {
+    return nil, err
+  }
+
+  return s.authService.LoginExternal(ctx, user)
+}
+
+// resolveUser - user linked to the identity, else link the account with the same
+// verified email, else provision a new account just in time
+func (s *oidcService) resolveUser(ctx context.Context, claims *oidc.Claims) (*models.User, error) {
+  issuer := s.provider.Issuer()
+
+  user, err := s.userRepo.FindByOIDCSubject(ctx, issuer, claims.Subject)
+  if err == nil {
+    return user, nil
+  }
+  if !errors.Is(err, repositories.ErrUserNotFound) {
+    return nil, err
+  }
+
+  // Unverified emails could be chosen freely at the IdP and take over local accounts
+  email := normalizeEmail(claims.Email)
+  if email == "" || !claims.EmailVerified {
+    return nil, ErrOIDCEmailNotVerified
+  }
+
+  user, err = s.userRepo.FindByEmail(ctx, email)
+  switch {
+  case err == nil:
+    return s.linkUser(ctx, user, issuer, claims.Subject)
+  case errors.Is(err, repositories.ErrUserNotFound):
+    return s.provisionUser(ctx, email, issuer, claims)
+  default:
+    return nil, err
+  }
+}
+
+// linkUser - attach identity to an existing account that has none yet
+func (s *oidcService) linkUser(ctx context.Context, user *models.User, issuer, subject string) (*models.User, error) {
+  linked, err := s.userRepo.LinkOIDCIdentity(ctx, user.ID, issuer, subject)
+  if err != nil {
+    return nil, err
+  }
+  if !linked {
+    return nil, ErrOIDCAccountConflict
+  }
+
+  log.Info().
+    Str("user_id", user.ID.Hex()).
+    Str("issuer", issuer).
+    Msg("SSO identity linked to existing account")
+
+  user.OIDCIssuer = issuer
+  user.OIDCSubject = subject
+  return user, nil
+}
+
+// provisionUser - create account without local password for a first SSO login
+func (s *oidcService) provisionUser(ctx context.Context, email, issuer string, claims *oidc.Claims) (*models.User, error) {
+  name := strings.TrimSpace(claims.Name)
+  if len(name) < 2 {
+    name = strings.SplitN(email, "@", 2)[0]
+  }
+
+  now := time.Now()
+  user := &models.User{
+    ID:          bson.NewObjectID(),
+    Email:       email,
+    Name:        name,
+    OIDCIssuer:  issuer,
+    OIDCSubject: claims.Subject,
+    Role:        types.RoleMember,
+    CreatedAt:   now,
+    UpdatedAt:   now,
+  }
+
+  if err := s.userRepo.Create(ctx, user); err != nil {
+    // Concurrent first login or registration with the same email
+    if errors.Is(err, repositories.ErrEmailAlreadyExists) {
+      return nil, ErrOIDCAccountConflict
+    }
+    return nil, err
+  }
+
+  log.Info().
+    Str("user_id", user.ID.Hex()).
+    Str("issuer", issuer).
+    Msg("User provisioned from SSO login")
+
+  return user, nil
+}
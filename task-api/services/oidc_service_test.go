@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/oidc"
+	"task-api/oidc/oidctest"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// fakeOIDCStateRepository - in-memory OIDCStateRepository
+type fakeOIDCStateRepository struct {
+  states map[string]*models.OIDCState
+}
+
+func (r *fakeOIDCStateRepository) Create(ctx context.Context, state *models.OIDCState) error {
+  r.states[state.ID] = state
+  return nil
+}
+
+func (r *fakeOIDCStateRepository) Consume(ctx context.Context, stateHash string) (*models.OIDCState, error) {
+  state, ok := r.states[stateHash]
+  if !ok {
+    return nil, repositories.ErrOIDCStateInvalid
+  }
+  delete(r.states, stateHash)
+  return state, nil
+}
+
+// oidcTestEnv - OIDC service wired to the mock IdP, an in-memory state store and mocked users
+type oidcTestEnv struct {
+  idp      *oidctest.IdP
+  service  OIDCService
+  userRepo *MockUserRepository
+}
+
+// newOIDCTestEnv - start mock IdP and wire the service
+func newOIDCTestEnv(t *testing.T) *oidcTestEnv {
+  idp := oidctest.NewIdP("task-api", "client-secret")
+  t.Cleanup(idp.Close)
+
+  provider := oidc.NewProvider(oidc.Config{
+    Issuer:       idp.Issuer(),
+    ClientID:     idp.ClientID,
+    ClientSecret: idp.ClientSecret,
+    RedirectURL:  "http://localhost:8080/auth/oidc/callback",
+  }, nil)
+
+  authService, mocks := newTestAuthService()
+  mocks.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+  return &oidcTestEnv{
+    idp:      idp,
+    service:  NewOIDCService(provider, &fakeOIDCStateRepository{states: make(map[string]*models.OIDCState)}, mocks.userRepo, authService),
+    userRepo: mocks.userRepo,
+  }
+}
+
+// login - run the browser side of the flow and call back with the IdP's answer
+func (e *oidcTestEnv) login(t *testing.T) (*types.LoginResponse, error) {
+  start, err := e.service.Start(context.Background())
+  assert.NoError(t, err)
+
+  code, state, err := e.idp.Authorize(start.AuthorizationURL)
+  assert.NoError(t, err)
+  assert.Equal(t, start.State, state)
+
+  return e.service.Callback(context.Background(), types.OIDCCallbackInput{Code: code, State: state})
+}
+
+func TestOIDCService_Callback(t *testing.T) {
+  t.Run("should log in user already linked to the identity", func(t *testing.T) {
+    env := newOIDCTestEnv(t)
+    user := &models.User{ID: bson.NewObjectID(), Email: "user@example.com", Name: "Linked"}
+    env.userRepo.On("FindByOIDCSubject", mock.Anything, env.idp.Issuer(), "user-1").Return(user, nil)
+
+    result, err := env.login(t)
+
+    assert.NoError(t, err)
+    assert.NotEmpty(t, result.Token)
+    assert.Equal(t, user.ID.Hex(), result.User.ID)
+    env.userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should link existing account with the same verified email", func(t *testing.T) {
+    env := newOIDCTestEnv(t)
+    user := &models.User{ID: bson.NewObjectID(), Email: "user@example.com", Name: "Local"}
+    env.userRepo.On("FindByOIDCSubject", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrUserNotFound)
+    env.userRepo.On("FindByEmail", mock.Anything, "user@example.com").Return(user, nil)
+    env.userRepo.On("LinkOIDCIdentity", mock.Anything, user.ID, env.idp.Issuer(), "user-1").Return(true, nil)
+
+    result, err := env.login(t)
+
+    assert.NoError(t, err)
+    assert.Equal(t, user.ID.Hex(), result.User.ID)
+    env.userRepo.AssertExpectations(t)
+  })
+
+  t.Run("should provision new user just in time", func(t *testing.T) {
+    env := newOIDCTestEnv(t)
+    env.idp.SetUser(oidctest.Identity{Subject: "sub-9", Email: "New.User@Example.com", EmailVerified: true, Name: "New User"})
+    env.userRepo.On("FindByOIDCSubject", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrUserNotFound)
+    env.userRepo.On("FindByEmail", mock.Anything, "new.user@example.com").Return(nil, repositories.ErrUserNotFound)
+
+    var created *models.User
+    env.userRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).
+      Run(func(args mock.Arguments) { created = args.Get(1).(*models.User) }).
+      Return(nil)
+
+    result, err := env.login(t)
+
+    assert.NoError(t, err)
+    assert.NotEmpty(t, result.Token)
+    assert.Equal(t, "new.user@example.com", created.Email)
+    assert.Equal(t, "New User", created.Name)
+    assert.Equal(t, "sub-9", created.OIDCSubject)
+    assert.Empty(t, created.Password)
+  })
+
+  t.Run("should refuse unverified email", func(t *testing.T) {
+    env := newOIDCTestEnv(t)
+    env.idp.SetUser(oidctest.Identity{Subject: "sub-2", Email: "user@example.com", EmailVerified: false})
+    env.userRepo.On("FindByOIDCSubject", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrUserNotFound)
+
+    result, err := env.login(t)
+
+    assert.Nil(t, result)
+    assert.ErrorIs(t, err, ErrOIDCEmailNotVerified)
+    env.userRepo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should refuse account linked to another identity", func(t *testing.T) {
+    env := newOIDCTestEnv(t)
+    user := &models.User{ID: bson.NewObjectID(), Email: "user@example.com", OIDCSubject: "someone-else"}
+    env.userRepo.On("FindByOIDCSubject", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrUserNotFound)
+    env.userRepo.On("FindByEmail", mock.Anything, mock.Anything).Return(user, nil)
+    env.userRepo.On("LinkOIDCIdentity", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+
+    _, err := env.login(t)
+
+    assert.ErrorIs(t, err, ErrOIDCAccountConflict)
+  })
+
+  t.Run("should challenge 2FA enabled accounts", func(t *testing.T) {
+    env := newOIDCTestEnv(t)
+    user := &models.User{ID: bson.NewObjectID(), Email: "user@example.com", MFAEnabled: true}
+    env.userRepo.On("FindByOIDCSubject", mock.Anything, mock.Anything, mock.Anything).Return(user, nil)
+
+    result, err := env.login(t)
+
+    assert.NoError(t, err)
+    assert.True(t, result.MFARequired)
+    assert.Empty(t, result.Token)
+  })
+
+  t.Run("should reject unknown or replayed state", func(t *testing.T) {
+    env := newOIDCTestEnv(t)
+    env.userRepo.On("FindByOIDCSubject", mock.Anything, mock.Anything, mock.Anything).
+      Return(&models.User{ID: bson.NewObjectID()}, nil)
+
+    start, _ := env.service.Start(context.Background())
+    code, state, _ := env.idp.Authorize(start.AuthorizationURL)
+
+    _, err := env.service.Callback(context.Background(), types.OIDCCallbackInput{Code: code, State: "forged"})
+    assert.ErrorIs(t, err, ErrInvalidOIDCState)
+
+    _, err = env.service.Callback(context.Background(), types.OIDCCallbackInput{Code: code, State: state})
+    assert.NoError(t, err)
+
+    _, err = env.service.Callback(context.Background(), types.OIDCCallbackInput{Code: code, State: state})
+    assert.ErrorIs(t, err, ErrInvalidOIDCState)
+  })
+
+  t.Run("should fail when the IdP reports an error", func(t *testing.T) {
+    env := newOIDCTestEnv(t)
+
+    start, _ := env.service.Start(context.Background())
+
+    _, err := env.service.Callback(context.Background(), types.OIDCCallbackInput{State: start.State, Error: "access_denied"})
+    assert.ErrorIs(t, err, ErrOIDCLoginFailed)
+  })
+}
+
+func TestOIDCService_Disabled(t *testing.T) {
+  service := NewOIDCService(nil, &fakeOIDCStateRepository{}, new(MockUserRepository), nil)
+
+  _, err := service.Start(context.Background())
+  assert.ErrorIs(t, err, ErrOIDCDisabled)
+
+  _, err = service.Callback(context.Background(), types.OIDCCallbackInput{State: "state"})
+  assert.ErrorIs(t, err, ErrOIDCDisabled)
+}
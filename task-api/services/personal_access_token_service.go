@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+	"task-api/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+// PersonalAccessTokenPrefix - marks personal access tokens so AuthMiddleware can tell them from JWTs
+const PersonalAccessTokenPrefix = "mtp_"
+
+// maxTokensPerUser - cap on personal access tokens per user
+const maxTokensPerUser = 50
+
+// lastUsedResolution - skip last_used_at writes for tokens used more recently than this
+const lastUsedResolution = time.Minute
+
+// Personal access token errors that handlers map to specific HTTP statuses
+var (
+  ErrPersonalAccessTokenNotFound = errors.New("personal access token not found")
+  ErrInvalidPersonalAccessToken  = errors.New("invalid personal access token")
+  ErrTokenLimitReached           = errors.New("personal access token limit reached")
+)
+
+// PersonalAccessTokenService - interface for personal access token service
+type PersonalAccessTokenService interface {
+  Create(ctx context.Context, userID bson.ObjectID, input types.CreatePersonalAccessTokenInput) (*types.CreatePersonalAccessTokenResponse, error)
+  List(ctx context.Context, userID bson.ObjectID) ([]types.PersonalAccessTokenResponse, error)
+  Revoke(ctx context.Context, userID, tokenID bson.ObjectID) error
+  Authenticate(ctx context.Context, token string) (*models.PersonalAccessToken, *models.User, error)
+}
+
+// personalAccessTokenService - implement PersonalAccessTokenService
+type personalAccessTokenService struct {
+  tokenRepo repositories.PersonalAccessTokenRepository
+  userRepo  repositories.UserRepository
+}
+
+// NewPersonalAccessTokenService - constructor
+func NewPersonalAccessTokenService(tokenRepo repositories.PersonalAccessTokenRepository, userRepo repositories.UserRepository) PersonalAccessTokenService {
+  return &personalAccessTokenService{
+    tokenRepo: tokenRepo,
+    userRepo:  userRepo,
+  }
+}
+
+// Create - mint a new token, the plain value is only returned here
+func (s *personalAccessTokenService) Create(ctx context.Context, userID bson.ObjectID, input types.CreatePersonalAccessTokenInput) (*types.CreatePersonalAccessTokenResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  count, err := s.tokenRepo.CountByUserID(ctx, userID)
+  if err != nil {
+    return nil, err
+  }
+  if count >= maxTokensPerUser {
+    return nil, ErrTokenLimitReached
+  }
+
+  secret, err := utils.GenerateOpaqueToken()
+  if err != nil {
+    return nil, errors.New("failed to generate token")
+  }
+  plain := PersonalAccessTokenPrefix + secret
+
+  now := time.Now()
+  token := &models.PersonalAccessToken{
+    ID:        bson.NewObjectID(),
+    UserID:    userID,
+    Name:      strings.TrimSpace(input.Name),
+    TokenHash: utils.HashToken(plain),
+    Prefix:    plain[:len(PersonalAccessTokenPrefix)+6],
+    Scopes:    uniqueScopes(input.Scopes),
+    CreatedAt: now,
+  }
+  if input.ExpiresInDays != nil {
+    expiresAt := now.AddDate(0, 0, *input.ExpiresInDays)
+    token.ExpiresAt = &expiresAt
+  }
+
+  if err := s.tokenRepo.Create(ctx, token); err != nil {
+    return nil, err
+  }
+
+  log.Info().
+    Str("user_id", userID.Hex()).
+    Str("token_id", token.ID.Hex()).
+    Strs("scopes", token.Scopes).
+    Msg("Personal access token created")
+
+  return &types.CreatePersonalAccessTokenResponse{
+    PersonalAccessTokenResponse: types.ToPersonalAccessTokenResponse(token),
+    Token:                       plain,
+  }, nil
+}
+
+// List - tokens of user without their secrets
+func (s *personalAccessTokenService) List(ctx context.Context, userID bson.ObjectID) ([]types.PersonalAccessTokenResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  tokens, err := s.tokenRepo.FindByUserID(ctx, userID)
+  if err != nil {
+    return nil, err
+  }
+
+  return types.ToPersonalAccessTokenResponseList(tokens), nil
+}
+
+// Revoke - delete token of user
+func (s *personalAccessTokenService) Revoke(ctx context.Context, userID, tokenID bson.ObjectID) error {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if err := s.tokenRepo.Delete(ctx, tokenID, userID); err != nil {
+    if errors.Is(err, repositories.ErrPersonalAccessTokenNotFound) {
+      return ErrPersonalAccessTokenNotFound
+    }
+    return err
+  }
+
+  log.Info().Str("user_id", userID.Hex()).Str("token_id", tokenID.Hex()).Msg("Personal access token revoked")
+
+  return nil
+}
+
+// Authenticate - resolve a presented token to its record and owner
+func (s *personalAccessTokenService) Authenticate(ctx context.Context, token string) (*models.PersonalAccessToken, *models.User, error) {
+  if !strings.HasPrefix(token, PersonalAccessTokenPrefix) {
+    return nil, nil, ErrInvalidPersonalAccessToken
+  }
+
+  stored, err := s.tokenRepo.FindByHash(ctx, utils.HashToken(token))
+  if err != nil {
+    if errors.Is(err, repositories.ErrPersonalAccessTokenNotFound) {
+      return nil, nil, ErrInvalidPersonalAccessToken
+    }
+    return nil, nil, err
+  }
+
+  now := time.Now()
+  if stored.ExpiresAt != nil && now.After(*stored.ExpiresAt) {
+    return nil, nil, ErrInvalidPersonalAccessToken
+  }
+
+  user, err := s.userRepo.FindByID(ctx, stored.UserID)
+  if err != nil {
+    if errors.Is(err, repositories.ErrUserNotFound) {
+      return nil, nil, ErrInvalidPersonalAccessToken
+    }
+    return nil, nil, err
+  }
+  if user.DisabledAt != nil {
+    return nil, nil, ErrInvalidPersonalAccessToken
+  }
+
+  // Usage tracking is informational, never fail the request over it
+  if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) > lastUsedResolution {
+    if err := s.tokenRepo.TouchLastUsed(ctx, stored.ID, now); err != nil {
+      log.Warn().Err(err).Str("token_id", stored.ID.Hex()).Msg("Failed to record token usage")
+    }
+  }
+
+  return stored, user, nil
+}
+
+// uniqueScopes - drop duplicate scopes keeping order
+func uniqueScopes(scopes []string) []string {
+  seen := make(map[string]bool, len(scopes))
+  result := make([]string, 0, len(scopes))
+  for _, scope := range scopes {
+    if !seen[scope] {
+      seen[scope] = true
+      result = append(result, scope)
+    }
+  }
+  return result
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+	"task-api/utils"
+)
+
+// MockPersonalAccessTokenRepository
+type MockPersonalAccessTokenRepository struct {
+  mock.Mock
+}
+
+func (m *MockPersonalAccessTokenRepository) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+  args := m.Called(ctx, token)
+  return args.Error(0)
+}
+
+func (m *MockPersonalAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error) {
+  args := m.Called(ctx, tokenHash)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*models.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) FindByUserID(ctx context.Context, userID bson.ObjectID) ([]models.PersonalAccessToken, error) {
+  args := m.Called(ctx, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]models.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) CountByUserID(ctx context.Context, userID bson.ObjectID) (int64, error) {
+  args := m.Called(ctx, userID)
+  return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) Delete(ctx context.Context, id, userID bson.ObjectID) error {
+  args := m.Called(ctx, id, userID)
+  return args.Error(0)
+}
+
+func (m *MockPersonalAccessTokenRepository) TouchLastUsed(ctx context.Context, id bson.ObjectID, usedAt time.Time) error {
+  args := m.Called(ctx, id, usedAt)
+  return args.Error(0)
+}
+
+func TestPersonalAccessTokenService_Create(t *testing.T) {
+  t.Run("should store hash only and return token once", func(t *testing.T) {
+    tokenRepo := new(MockPersonalAccessTokenRepository)
+    service := NewPersonalAccessTokenService(tokenRepo, new(MockUserRepository))
+
+    userID := bson.NewObjectID()
+    days := 30
+    input := types.CreatePersonalAccessTokenInput{
+      Name:          " CI ",
+      Scopes:        []string{types.ScopeTasksRead, types.ScopeTasksRead, types.ScopeTasksWrite},
+      ExpiresInDays: &days,
+    }
+
+    var stored *models.PersonalAccessToken
+    tokenRepo.On("CountByUserID", mock.Anything, userID).Return(int64(0), nil)
+    tokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.PersonalAccessToken")).
+      Run(func(args mock.Arguments) {
+        stored = args.Get(1).(*models.PersonalAccessToken)
+      }).
+      Return(nil)
+
+    result, err := service.Create(context.Background(), userID, input)
+
+    assert.NoError(t, err)
+    assert.True(t, strings.HasPrefix(result.Token, PersonalAccessTokenPrefix))
+    assert.Equal(t, utils.HashToken(result.Token), stored.TokenHash)
+    assert.NotContains(t, stored.TokenHash, result.Token)
+    assert.True(t, strings.HasPrefix(result.Token, result.Prefix))
+    assert.Equal(t, "CI", result.Name)
+    assert.Equal(t, []string{types.ScopeTasksRead, types.ScopeTasksWrite}, result.Scopes)
+    assert.WithinDuration(t, time.Now().AddDate(0, 0, 30), *result.ExpiresAt, time.Minute)
+  })
+
+  t.Run("should refuse above limit", func(t *testing.T) {
+    tokenRepo := new(MockPersonalAccessTokenRepository)
+    service := NewPersonalAccessTokenService(tokenRepo, new(MockUserRepository))
+
+    tokenRepo.On("CountByUserID", mock.Anything, mock.Anything).Return(int64(maxTokensPerUser), nil)
+
+    result, err := service.Create(context.Background(), bson.NewObjectID(), types.CreatePersonalAccessTokenInput{
+      Name:   "CI",
+      Scopes: []string{types.ScopeTasksRead},
+    })
+
+    assert.Nil(t, result)
+    assert.ErrorIs(t, err, ErrTokenLimitReached)
+    tokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+}
+
+func TestPersonalAccessTokenService_Revoke(t *testing.T) {
+  t.Run("should map missing token", func(t *testing.T) {
+    tokenRepo := new(MockPersonalAccessTokenRepository)
+    service := NewPersonalAccessTokenService(tokenRepo, new(MockUserRepository))
+
+    tokenRepo.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(repositories.ErrPersonalAccessTokenNotFound)
+
+    err := service.Revoke(context.Background(), bson.NewObjectID(), bson.NewObjectID())
+
+    assert.ErrorIs(t, err, ErrPersonalAccessTokenNotFound)
+  })
+}
+
+func TestPersonalAccessTokenService_Authenticate(t *testing.T) {
+  user := &models.User{ID: bson.NewObjectID(), Email: "ci@test.com"}
+
+  t.Run("should resolve token and record usage", func(t *testing.T) {
+    tokenRepo := new(MockPersonalAccessTokenRepository)
+    userRepo := new(MockUserRepository)
+    service := NewPersonalAccessTokenService(tokenRepo, userRepo)
+
+    stored := &models.PersonalAccessToken{ID: bson.NewObjectID(), UserID: user.ID, Scopes: []string{types.ScopeTasksRead}}
+    tokenRepo.On("FindByHash", mock.Anything, utils.HashToken("mtp_secret")).Return(stored, nil)
+    tokenRepo.On("TouchLastUsed", mock.Anything, stored.ID, mock.AnythingOfType("time.Time")).Return(nil)
+    userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+    pat, owner, err := service.Authenticate(context.Background(), "mtp_secret")
+
+    assert.NoError(t, err)
+    assert.Equal(t, stored, pat)
+    assert.Equal(t, "ci@test.com", owner.Email)
+    tokenRepo.AssertExpectations(t)
+  })
+
+  t.Run("should skip usage write when recently used", func(t *testing.T) {
+    tokenRepo := new(MockPersonalAccessTokenRepository)
+    userRepo := new(MockUserRepository)
+    service := NewPersonalAccessTokenService(tokenRepo, userRepo)
+
+    recently := time.Now().Add(-10 * time.Second)
+    stored := &models.PersonalAccessToken{ID: bson.NewObjectID(), UserID: user.ID, LastUsedAt: &recently}
+    tokenRepo.On("FindByHash", mock.Anything, mock.Anything).Return(stored, nil)
+    userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+    _, _, err := service.Authenticate(context.Background(), "mtp_secret")
+
+    assert.NoError(t, err)
+    tokenRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should reject token of disabled user", func(t *testing.T) {
+    tokenRepo := new(MockPersonalAccessTokenRepository)
+    userRepo := new(MockUserRepository)
+    service := NewPersonalAccessTokenService(tokenRepo, userRepo)
+
+    disabledAt := time.Now()
+    disabled := &models.User{ID: bson.NewObjectID(), DisabledAt: &disabledAt}
+    stored := &models.PersonalAccessToken{ID: bson.NewObjectID(), UserID: disabled.ID}
+    tokenRepo.On("FindByHash", mock.Anything, mock.Anything).Return(stored, nil)
+    userRepo.On("FindByID", mock.Anything, disabled.ID).Return(disabled, nil)
+
+    _, _, err := service.Authenticate(context.Background(), "mtp_secret")
+
+    assert.ErrorIs(t, err, ErrInvalidPersonalAccessToken)
+  })
+
+  t.Run("should reject expired token", func(t *testing.T) {
+    tokenRepo := new(MockPersonalAccessTokenRepository)
+    service := NewPersonalAccessTokenService(tokenRepo, new(MockUserRepository))
+
+    expired := time.Now().Add(-time.Hour)
+    tokenRepo.On("FindByHash", mock.Anything, mock.Anything).
+      Return(&models.PersonalAccessToken{UserID: user.ID, ExpiresAt: &expired}, nil)
+
+    _, _, err := service.Authenticate(context.Background(), "mtp_secret")
+
+    assert.ErrorIs(t, err, ErrInvalidPersonalAccessToken)
+  })
+
+  t.Run("should reject unknown and malformed tokens", func(t *testing.T) {
+    tokenRepo := new(MockPersonalAccessTokenRepository)
+    service := NewPersonalAccessTokenService(tokenRepo, new(MockUserRepository))
+
+    tokenRepo.On("FindByHash", mock.Anything, mock.Anything).Return(nil, repositories.ErrPersonalAccessTokenNotFound)
+
+    _, _, err := service.Authenticate(context.Background(), "mtp_unknown")
+    assert.ErrorIs(t, err, ErrInvalidPersonalAccessToken)
+
+    _, _, err = service.Authenticate(context.Background(), "no-prefix")
+    assert.ErrorIs(t, err, ErrInvalidPersonalAccessToken)
+  })
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/recurrence"
+	"task-api/types"
+)
+
+// ErrInvalidRecurrence - recurrence rejected, wrapped with the reason
+var ErrInvalidRecurrence = recurrence.ErrInvalidRule
+
+// startSeries - make task the first occurrence of its recurrence rule, expanded in the user's timezone
+func (s *taskService) startSeries(ctx context.Context, task *models.Task, userID bson.ObjectID) error {
+  if task.Recurrence == "" {
+    return nil
+  }
+  if _, err := recurrence.Parse(task.Recurrence); err != nil {
+    return err
+  }
+  if task.DueDate == nil {
+    return fmt.Errorf("%w: recurring tasks need a due_date", ErrInvalidRecurrence)
+  }
+
+  task.Series = &models.TaskSeries{
+    ID:          task.ID,
+    Occurrence:  1,
+    Timezone:    s.userTimezone(ctx, userID),
+    Start:       *task.DueDate,
+    Scheduled:   *task.DueDate,
+    Title:       task.Title,
+    Description: task.Description,
+    Priority:    task.Priority,
+    Tags:        task.Tags,
+  }
+  return nil
+}
+
+// seriesUpdates - add recurrence changes of input to updates, current is the task before the update
+func (s *taskService) seriesUpdates(ctx context.Context, current *models.Task, userID bson.ObjectID, input types.UpdateTaskInput, updates bson.M) error {
+  if input.Recurrence != nil {
+    updates["recurrence"] = *input.Recurrence
+
+    // A one-off task starts a series with its values after this update
+    if *input.Recurrence != "" && current.Series == nil {
+      task := *current
+      task.Recurrence = *input.Recurrence
+      applyTemplate(&task, input)
+      if err := s.startSeries(ctx, &task, userID); err != nil {
+        return err
+      }
+      updates["series"] = task.Series
+      return nil
+    }
+
+    if *input.Recurrence != "" {
+      if _, err := recurrence.Parse(*input.Recurrence); err != nil {
+        return err
+      }
+    }
+  }
+
+  // Edits for all future occurrences also change what the next occurrence starts from
+  if input.Scope != types.RecurrenceScopeFuture || current.Series == nil {
+    return nil
+  }
+  if input.Title != nil {
+    updates["series.title"] = *input.Title
+  }
+  if input.Description != nil {
+    updates["series.description"] = *input.Description
+  }
+  if input.Priority != nil {
+    updates["series.priority"] = *input.Priority
+  }
+  if input.Tags != nil {
+    updates["series.tags"] = input.Tags
+  }
+  if input.DueDate != nil {
+    // The schedule restarts from the new due date
+    updates["series.start"] = *input.DueDate
+    updates["series.scheduled"] = *input.DueDate
+  }
+  return nil
+}
+
+// spawnNext - create the occurrence after a completed recurring task, nil when the series has ended
+func (s *taskService) spawnNext(ctx context.Context, taskID bson.ObjectID) (*models.Task, error) {
+  tasks, err := s.taskRepo.FindByIDs(ctx, []bson.ObjectID{taskID})
+  if err != nil {
+    return nil, err
+  }
+  if len(tasks) == 0 || tasks[0].Recurrence == "" || tasks[0].Series == nil {
+    return nil, nil
+  }
+  task := &tasks[0]
+
+  rule, err := recurrence.Parse(task.Recurrence)
+  if err != nil {
+    return nil, err
+  }
+  if rule.Count > 0 && task.Series.Occurrence >= rule.Count {
+    return nil, nil
+  }
+
+  loc, err := time.LoadLocation(task.Series.Timezone)
+  if err != nil {
+    loc = time.UTC
+  }
+
+  // The schedule follows the rule, not due dates moved for a single occurrence
+  due, ok := rule.Next(task.Series.Start, task.Series.Scheduled, loc)
+  if !ok {
+    return nil, nil
+  }
+
+  series := *task.Series
+  series.Occurrence++
+  series.Scheduled = due
+
+  now := time.Now()
+  next := models.Task{
+    ID:          bson.NewObjectID(),
+    UserID:      task.UserID,
+    WorkspaceID: task.WorkspaceID,
+    ParentID:    task.ParentID,
+    Title:       series.Title,
+    Description: series.Description,
+    Status:      types.TaskStatusPending,
+    Priority:    series.Priority,
+    DueDate:     &due,
+    Tags:        series.Tags,
+    AssigneeIDs: task.AssigneeIDs,
+    WatcherIDs:  task.WatcherIDs,
+    Recurrence:  task.Recurrence,
+    Series:      &series,
+    CreatedAt:   now,
+    UpdatedAt:   now,
+  }
+  if next.Tags == nil {
+    next.Tags = []string{}
+  }
+
+  if err := s.taskRepo.Create(ctx, &next); err != nil {
+    return nil, err
+  }
+  return &next, nil
+}
+
+// userTimezone - IANA timezone of the user's profile, UTC when unset
+func (s *taskService) userTimezone(ctx context.Context, userID bson.ObjectID) string {
+  user, err := s.userRepo.FindByID(ctx, userID)
+  if err != nil || user.Timezone == "" {
+    return "UTC"
+  }
+  return user.Timezone
+}
+
+// applyTemplate - copy the fields of input that occurrences inherit onto task
+func applyTemplate(task *models.Task, input types.UpdateTaskInput) {
+  if input.Title != nil {
+    task.Title = *input.Title
+  }
+  if input.Description != nil {
+    task.Description = *input.Description
+  }
+  if input.Priority != nil {
+    task.Priority = *input.Priority
+  }
+  if input.Tags != nil {
+    task.Tags = input.Tags
+  }
+  if input.DueDate != nil {
+    task.DueDate = input.DueDate
+  }
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/types"
+)
+
+// recurringTask - occurrence of a weekly series in Asia/Jakarta
+func recurringTask(userID bson.ObjectID, occurrence int, rule string) *models.Task {
+  jakarta, _ := time.LoadLocation("Asia/Jakarta")
+  due := time.Date(2025, 1, 6, 8, 0, 0, 0, jakarta) // Monday
+  id := bson.NewObjectID()
+  return &models.Task{
+    ID:         id,
+    UserID:     userID,
+    Title:      "Weekly report",
+    Status:     types.TaskStatusInProgress,
+    Priority:   types.TaskPriorityMedium,
+    DueDate:    &due,
+    Recurrence: rule,
+    Series: &models.TaskSeries{
+      ID:         id,
+      Occurrence: occurrence,
+      Timezone:   "Asia/Jakarta",
+      Start:      due,
+      Scheduled:  due,
+      Title:      "Weekly report",
+      Priority:   types.TaskPriorityHigh,
+      Tags:       []string{"reports"},
+    },
+  }
+}
+
+func TestTaskService_CreateRecurringTask(t *testing.T) {
+  t.Run("should start a series in the user's timezone", func(t *testing.T) {
+    service, taskRepo, userRepo, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    due := time.Date(2025, 1, 6, 1, 0, 0, 0, time.UTC)
+
+    userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID, Timezone: "Asia/Jakarta"}, nil)
+    taskRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+      return task.Series != nil && task.Series.ID == task.ID && task.Series.Occurrence == 1 &&
+        task.Series.Timezone == "Asia/Jakarta" && task.Series.Start.Equal(due)
+    })).Return(nil)
+
+    result, err := service.CreateTask(context.Background(), userID, types.CreateTaskInput{
+      Title:      "Vehicle check",
+      DueDate:    &due,
+      Recurrence: "FREQ=DAILY",
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, "FREQ=DAILY", result.Recurrence)
+    assert.Equal(t, result.ID, result.SeriesID)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should reject invalid rules and missing due dates", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    due := time.Now()
+
+    _, err := service.CreateTask(context.Background(), bson.NewObjectID(), types.CreateTaskInput{Title: "Check", DueDate: &due, Recurrence: "FREQ=HOURLY"})
+    assert.ErrorIs(t, err, ErrInvalidRecurrence)
+
+    _, err = service.CreateTask(context.Background(), bson.NewObjectID(), types.CreateTaskInput{Title: "Check", Recurrence: "FREQ=DAILY"})
+    assert.ErrorIs(t, err, ErrInvalidRecurrence)
+
+    taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+}
+
+func TestTaskService_CompleteRecurringTask(t *testing.T) {
+  t.Run("should create the next occurrence from the series", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := recurringTask(userID, 1, "FREQ=WEEKLY")
+    completed := *task
+    completed.Status = types.TaskStatusCompleted
+    status := types.TaskStatusCompleted
+    jakarta, _ := time.LoadLocation("Asia/Jakarta")
+
+    var next *models.Task
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("Update", mock.Anything, task.ID, userID, mock.Anything).Return(nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{task.ID}).Return([]models.Task{completed}, nil)
+    taskRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+      next = args.Get(1).(*models.Task)
+    }).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    result, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{Status: &status}, nil)
+
+    assert.NoError(t, err)
+    assert.Equal(t, next.ID.Hex(), result.NextID)
+    assert.True(t, next.DueDate.Equal(time.Date(2025, 1, 13, 8, 0, 0, 0, jakarta)))
+    assert.Equal(t, types.TaskStatusPending, next.Status)
+    assert.Equal(t, types.TaskPriorityHigh, next.Priority)
+    assert.Equal(t, []string{"reports"}, next.Tags)
+    assert.Equal(t, 2, next.Series.Occurrence)
+    assert.Equal(t, task.ID, next.Series.ID)
+  })
+
+  t.Run("should end the series after COUNT occurrences", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := recurringTask(userID, 3, "FREQ=WEEKLY;COUNT=3")
+    status := types.TaskStatusCompleted
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("Update", mock.Anything, task.ID, userID, mock.Anything).Return(nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{task.ID}).Return([]models.Task{*task}, nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    result, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{Status: &status}, nil)
+
+    assert.NoError(t, err)
+    assert.Empty(t, result.NextID)
+    taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should not spawn twice for an already completed task", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := recurringTask(userID, 1, "FREQ=WEEKLY")
+    task.Status = types.TaskStatusCompleted
+    status := types.TaskStatusCompleted
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("Update", mock.Anything, task.ID, userID, mock.Anything).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{Status: &status}, nil)
+
+    assert.NoError(t, err)
+    taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+}
+
+func TestTaskService_EditRecurringTask(t *testing.T) {
+  t.Run("should carry future edits into the series", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := recurringTask(userID, 2, "FREQ=WEEKLY")
+    title := "Weekly fleet report"
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("Update", mock.Anything, task.ID, userID, mock.MatchedBy(func(updates bson.M) bool {
+      return updates["title"] == title && updates["series.title"] == title
+    })).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{Title: &title, Scope: types.RecurrenceScopeFuture}, nil)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should keep edits of this occurrence out of the series", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    title := "Report, late this week"
+
+    taskRepo.On("Update", mock.Anything, taskID, userID, mock.MatchedBy(func(updates bson.M) bool {
+      _, series := updates["series.title"]
+      return updates["title"] == title && !series
+    })).Return(nil)
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID, UserID: userID}, nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), taskID, userID, types.UpdateTaskInput{Title: &title, Scope: types.RecurrenceScopeThis}, nil)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should start a series when a one-off task gets a rule", func(t *testing.T) {
+    service, taskRepo, userRepo, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    due := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Title: "Vehicle check", DueDate: &due}
+    rule := "FREQ=DAILY"
+
+    userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID}, nil)
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("Update", mock.Anything, task.ID, userID, mock.MatchedBy(func(updates bson.M) bool {
+      series, ok := updates["series"].(*models.TaskSeries)
+      return ok && updates["recurrence"] == rule && series.ID == task.ID && series.Timezone == "UTC"
+    })).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{Recurrence: &rule}, nil)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+  })
+}
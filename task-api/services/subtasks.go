@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// Subtask errors that handlers map to specific HTTP statuses
+var (
+  ErrTaskCycle         = errors.New("task cannot be moved below itself")
+  ErrTaskDepthExceeded = errors.New("task hierarchy too deep")
+  ErrInvalidParentTask = errors.New("parent task belongs to another owner")
+)
+
+// MaxTaskDepth - levels of a task hierarchy, a top-level task is level 1
+const MaxTaskDepth = 5
+
+// GetSubtasks - direct subtasks of a task the user can see
+func (s *taskService) GetSubtasks(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) ([]types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  if _, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionView); err != nil {
+    return nil, err
+  }
+
+  children, err := s.taskRepo.FindChildren(ctx, taskID)
+  if err != nil {
+    return nil, err
+  }
+
+  return s.toResponses(ctx, children)
+}
+
+// CreateSubtask - create a subtask, it belongs to the parent's owner and workspace so access follows the parent
+func (s *taskService) CreateSubtask(ctx context.Context, parentID bson.ObjectID, userID bson.ObjectID, input types.CreateTaskInput) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  parent, err := s.accessibleTask(ctx, parentID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return nil, err
+  }
+
+  depth, _, err := s.ancestry(ctx, parent)
+  if err != nil {
+    return nil, err
+  }
+  if depth+1 > MaxTaskDepth {
+    return nil, ErrTaskDepthExceeded
+  }
+
+  task := input.ToTask(parent.UserID)
+  task.WorkspaceID = parent.WorkspaceID
+  task.ParentID = &parent.ID
+  if err := s.startSeries(ctx, &task, userID); err != nil {
+    return nil, err
+  }
+
+  if err := s.taskRepo.Create(ctx, &task); err != nil {
+    return nil, err
+  }
+  s.recordEvent(ctx, task.ID, userID, types.TaskEventCreated, taskChanges(nil, &task))
+
+  response := types.ToTaskResponse(&task)
+  return &response, nil
+}
+
+// moveTarget - validate moving a task below parentHex, empty moves it to the top level
+func (s *taskService) moveTarget(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, parentHex string) (*bson.ObjectID, error) {
+  if parentHex == "" {
+    return nil, nil
+  }
+
+  parentID, err := bson.ObjectIDFromHex(parentHex)
+  if err != nil {
+    return nil, ErrInvalidParentTask
+  }
+  if parentID == taskID {
+    return nil, ErrTaskCycle
+  }
+
+  task, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return nil, err
+  }
+
+  parent, err := s.accessibleTask(ctx, parentID, userID, types.SharePermissionEdit)
+  if err != nil {
+    if errors.Is(err, ErrTaskNotFound) {
+      return nil, ErrInvalidParentTask
+    }
+    return nil, err
+  }
+
+  // Subtasks share the owner and workspace of their parent
+  if parent.UserID != task.UserID || !sameWorkspace(parent.WorkspaceID, task.WorkspaceID) {
+    return nil, ErrInvalidParentTask
+  }
+
+  depth, ancestors, err := s.ancestry(ctx, parent)
+  if err != nil {
+    return nil, err
+  }
+  for _, id := range ancestors {
+    if id == taskID {
+      return nil, ErrTaskCycle
+    }
+  }
+
+  height, err := s.subtreeHeight(ctx, taskID)
+  if err != nil {
+    return nil, err
+  }
+  if depth+height > MaxTaskDepth {
+    return nil, ErrTaskDepthExceeded
+  }
+
+  return &parentID, nil
+}
+
+// accessibleTask - task the user owns, sees through a workspace or assignment, or was shared at the required permission
+func (s *taskService) accessibleTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, required string) (*models.Task, error) {
+  return findAccessibleTask(ctx, s.taskRepo, s.shareRepo, taskID, userID, required)
+}
+
+// findAccessibleTask - accessibleTask for services holding only the task and share repositories
+func findAccessibleTask(ctx context.Context, taskRepo repositories.TaskRepository, shareRepo repositories.TaskShareRepository, taskID bson.ObjectID, userID bson.ObjectID, required string) (*models.Task, error) {
+  task, err := taskRepo.FindByID(ctx, taskID, userID)
+  if errors.Is(err, repositories.ErrTaskNotFound) {
+    return findSharedTask(ctx, taskRepo, shareRepo, taskID, userID, required, ErrTaskNotFound)
+  }
+  if err != nil {
+    return nil, err
+  }
+  return task, nil
+}
+
+// ancestry - level of task and IDs of its ancestors, nearest first
+func (s *taskService) ancestry(ctx context.Context, task *models.Task) (int, []bson.ObjectID, error) {
+  depth := 1
+  ancestors := []bson.ObjectID{}
+
+  // Bounded walk, stored data deeper than allowed counts as too deep
+  for current := task; current.ParentID != nil && depth <= MaxTaskDepth; depth++ {
+    parents, err := s.taskRepo.FindByIDs(ctx, []bson.ObjectID{*current.ParentID})
+    if err != nil {
+      return 0, nil, err
+    }
+    if len(parents) == 0 {
+      break
+    }
+    current = &parents[0]
+    ancestors = append(ancestors, current.ID)
+  }
+
+  return depth, ancestors, nil
+}
+
+// subtreeHeight - levels of task and its subtasks, 1 without subtasks
+func (s *taskService) subtreeHeight(ctx context.Context, taskID bson.ObjectID) (int, error) {
+  height := 1
+  level := []bson.ObjectID{taskID}
+
+  for height <= MaxTaskDepth {
+    children, err := s.taskRepo.FindChildIDs(ctx, level)
+    if err != nil {
+      return 0, err
+    }
+    if len(children) == 0 {
+      break
+    }
+    height++
+    level = children
+  }
+
+  return height, nil
+}
+
+// descendantIDs - IDs of all subtasks below task
+func (s *taskService) descendantIDs(ctx context.Context, taskID bson.ObjectID) ([]bson.ObjectID, error) {
+  ids := []bson.ObjectID{}
+  level := []bson.ObjectID{taskID}
+
+  // Depth is limited, the bound also stops on corrupted cycles
+  for i := 0; i < MaxTaskDepth && len(level) > 0; i++ {
+    children, err := s.taskRepo.FindChildIDs(ctx, level)
+    if err != nil {
+      return nil, err
+    }
+    ids = append(ids, children...)
+    level = children
+  }
+
+  return ids, nil
+}
+
+// toResponses - convert tasks to responses with subtask progress
+func (s *taskService) toResponses(ctx context.Context, tasks []models.Task) ([]types.TaskResponse, error) {
+  responses := types.ToTaskResponseList(tasks)
+  if len(tasks) == 0 {
+    return responses, nil
+  }
+
+  ids := make([]bson.ObjectID, len(tasks))
+  for i := range tasks {
+    ids[i] = tasks[i].ID
+  }
+
+  progress, err := s.taskRepo.ChildProgress(ctx, ids)
+  if err != nil {
+    return nil, err
+  }
+
+  for i := range responses {
+    if p, ok := progress[tasks[i].ID]; ok {
+      responses[i].Progress = &p
+    }
+  }
+  return responses, nil
+}
+
+// sameWorkspace - both personal, or both in the same workspace
+func sameWorkspace(a, b *bson.ObjectID) bool {
+  if a == nil || b == nil {
+    return a == nil && b == nil
+  }
+  return *a == *b
+}
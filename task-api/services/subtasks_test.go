@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// taskChain - tasks of one owner where each task is the parent of the next
+func taskChain(ownerID bson.ObjectID, n int) []models.Task {
+  tasks := make([]models.Task, n)
+  for i := range tasks {
+    tasks[i] = models.Task{ID: bson.NewObjectID(), UserID: ownerID}
+    if i > 0 {
+      tasks[i].ParentID = &tasks[i-1].ID
+    }
+  }
+  return tasks
+}
+
+// expectChain - ancestor lookups along chain
+func expectChain(taskRepo *MockTaskRepository, chain []models.Task) {
+  for _, task := range chain {
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{task.ID}).Return([]models.Task{task}, nil)
+  }
+}
+
+func TestTaskService_CreateSubtask(t *testing.T) {
+  t.Run("should create subtask in parent's owner and workspace", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID, ownerID, workspaceID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+    parent := &models.Task{ID: bson.NewObjectID(), UserID: ownerID, WorkspaceID: &workspaceID}
+
+    taskRepo.On("FindByID", mock.Anything, parent.ID, userID).Return(parent, nil)
+    taskRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+      return task.UserID == ownerID && *task.WorkspaceID == workspaceID && *task.ParentID == parent.ID
+    })).Return(nil)
+
+    result, err := service.CreateSubtask(context.Background(), parent.ID, userID, types.CreateTaskInput{Title: "Step"})
+
+    assert.NoError(t, err)
+    assert.Equal(t, parent.ID.Hex(), result.ParentID)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should refuse subtasks below the maximum depth", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    chain := taskChain(userID, MaxTaskDepth)
+    deepest := chain[len(chain)-1]
+
+    taskRepo.On("FindByID", mock.Anything, deepest.ID, userID).Return(&deepest, nil)
+    expectChain(taskRepo, chain)
+
+    _, err := service.CreateSubtask(context.Background(), deepest.ID, userID, types.CreateTaskInput{Title: "Too deep"})
+
+    assert.ErrorIs(t, err, ErrTaskDepthExceeded)
+    taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should refuse view shares creating subtasks", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    userID, parentID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, parentID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, parentID, userID).Return(taskShare(parentID, userID, types.SharePermissionView), nil)
+
+    _, err := service.CreateSubtask(context.Background(), parentID, userID, types.CreateTaskInput{Title: "Step"})
+
+    assert.ErrorIs(t, err, ErrTaskPermissionDenied)
+  })
+}
+
+func TestTaskService_MoveTask(t *testing.T) {
+  t.Run("should move task below a sibling", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    parent := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    parentHex := parent.ID.Hex()
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("FindByID", mock.Anything, parent.ID, userID).Return(parent, nil)
+    taskRepo.On("FindChildIDs", mock.Anything, []bson.ObjectID{task.ID}).Return([]bson.ObjectID{}, nil)
+    taskRepo.On("Update", mock.Anything, task.ID, userID, mock.MatchedBy(func(updates bson.M) bool {
+      return *updates["parent_id"].(*bson.ObjectID) == parent.ID
+    })).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{ParentID: &parentHex}, nil)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should move task to the top level", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    top := ""
+
+    taskRepo.On("Update", mock.Anything, taskID, userID, mock.MatchedBy(func(updates bson.M) bool {
+      return updates["parent_id"].(*bson.ObjectID) == nil
+    })).Return(nil)
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID, UserID: userID}, nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), taskID, userID, types.UpdateTaskInput{ParentID: &top}, nil)
+
+    assert.NoError(t, err)
+  })
+
+  t.Run("should refuse moving task below its own subtask", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    chain := taskChain(userID, 3)
+    parentHex := chain[2].ID.Hex()
+
+    taskRepo.On("FindByID", mock.Anything, chain[0].ID, userID).Return(&chain[0], nil)
+    taskRepo.On("FindByID", mock.Anything, chain[2].ID, userID).Return(&chain[2], nil)
+    expectChain(taskRepo, chain)
+
+    _, err := service.UpdateTask(context.Background(), chain[0].ID, userID, types.UpdateTaskInput{ParentID: &parentHex}, nil)
+
+    assert.ErrorIs(t, err, ErrTaskCycle)
+    taskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should refuse moving task below itself", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    parentHex := taskID.Hex()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID, UserID: userID}, nil)
+
+    _, err := service.UpdateTask(context.Background(), taskID, userID, types.UpdateTaskInput{ParentID: &parentHex}, nil)
+
+    assert.ErrorIs(t, err, ErrTaskCycle)
+  })
+
+  t.Run("should refuse parent of another owner", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    parent := &models.Task{ID: bson.NewObjectID(), UserID: bson.NewObjectID()}
+    parentHex := parent.ID.Hex()
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("FindByID", mock.Anything, parent.ID, userID).Return(parent, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{ParentID: &parentHex}, nil)
+
+    assert.ErrorIs(t, err, ErrInvalidParentTask)
+  })
+
+  t.Run("should refuse moves exceeding the maximum depth", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    chain := taskChain(userID, MaxTaskDepth-1)
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    parent := chain[len(chain)-1]
+    parentHex := parent.ID.Hex()
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("FindByID", mock.Anything, parent.ID, userID).Return(&parent, nil)
+    expectChain(taskRepo, chain)
+    taskRepo.On("FindChildIDs", mock.Anything, []bson.ObjectID{task.ID}).Return([]bson.ObjectID{bson.NewObjectID()}, nil)
+    taskRepo.On("FindChildIDs", mock.Anything, mock.Anything).Return([]bson.ObjectID{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{ParentID: &parentHex}, nil)
+
+    assert.ErrorIs(t, err, ErrTaskDepthExceeded)
+  })
+}
+
+func TestTaskService_GetSubtasks(t *testing.T) {
+  t.Run("should list subtasks with their progress", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID, parentID := bson.NewObjectID(), bson.NewObjectID()
+    children := []models.Task{{ID: bson.NewObjectID(), UserID: userID, ParentID: &parentID}, {ID: bson.NewObjectID(), UserID: userID, ParentID: &parentID}}
+
+    taskRepo.On("FindByID", mock.Anything, parentID, userID).Return(&models.Task{ID: parentID, UserID: userID}, nil)
+    taskRepo.On("FindChildren", mock.Anything, parentID).Return(children, nil)
+    taskRepo.On("ChildProgress", mock.Anything, []bson.ObjectID{children[0].ID, children[1].ID}).Return(map[bson.ObjectID]types.TaskProgress{
+      children[0].ID: types.NewTaskProgress(4, 1),
+    }, nil)
+
+    result, err := service.GetSubtasks(context.Background(), parentID, userID)
+
+    assert.NoError(t, err)
+    assert.Len(t, result, 2)
+    assert.Equal(t, 25, result[0].Progress.Percent)
+    assert.Nil(t, result[1].Progress)
+  })
+
+  t.Run("should hide subtasks of inaccessible task", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+
+    taskRepo.On("FindByID", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrTaskShareNotFound)
+
+    _, err := service.GetSubtasks(context.Background(), bson.NewObjectID(), bson.NewObjectID())
+
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+    taskRepo.AssertNotCalled(t, "FindChildren", mock.Anything, mock.Anything)
+  })
+}
+
+func TestTaskService_DeleteTaskCascade(t *testing.T) {
+  t.Run("should move subtasks to the trash with their parent", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    userID, taskID, childID, grandchildID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("Trash", mock.Anything, taskID, userID, mock.Anything).Return(nil)
+    taskRepo.On("FindChildIDs", mock.Anything, []bson.ObjectID{taskID}).Return([]bson.ObjectID{childID}, nil)
+    taskRepo.On("FindChildIDs", mock.Anything, []bson.ObjectID{childID}).Return([]bson.ObjectID{grandchildID}, nil)
+    taskRepo.On("FindChildIDs", mock.Anything, []bson.ObjectID{grandchildID}).Return([]bson.ObjectID{}, nil)
+    taskRepo.On("TrashByIDs", mock.Anything, []bson.ObjectID{childID, grandchildID}, mock.Anything, &taskID).Return(nil)
+
+    err := service.DeleteTask(context.Background(), taskID, userID, nil)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+    // Shares are kept for a restore
+    shareRepo.AssertNotCalled(t, "DeleteByTask", mock.Anything, mock.Anything)
+  })
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"task-api/configs"
+	"task-api/repositories"
+)
+
+// AutoArchiveAfter - how long completed tasks stay in the task list (AUTO_ARCHIVE_DAYS, default 0 which turns it off)
+func AutoArchiveAfter() time.Duration {
+  return time.Duration(configs.GetInt("AUTO_ARCHIVE_DAYS", 0)) * 24 * time.Hour
+}
+
+// TaskArchiver - archives tasks that were completed long ago
+type TaskArchiver interface {
+  ArchiveCompleted(ctx context.Context) (int64, error)
+  Run(ctx context.Context)
+}
+
+// taskArchiver - implementation
+type taskArchiver struct {
+  taskRepo repositories.TaskRepository
+  after    time.Duration
+  interval time.Duration
+}
+
+// NewTaskArchiver - constructor
+func NewTaskArchiver(taskRepo repositories.TaskRepository) TaskArchiver {
+  return &taskArchiver{
+    taskRepo: taskRepo,
+    after:    AutoArchiveAfter(),
+    interval: configs.GetDuration("AUTO_ARCHIVE_INTERVAL", time.Hour),
+  }
+}
+
+// ArchiveCompleted - archive tasks completed longer ago than the policy, returns how many
+func (a *taskArchiver) ArchiveCompleted(ctx context.Context) (int64, error) {
+  if a.after <= 0 {
+    return 0, nil
+  }
+
+  now := time.Now()
+  return a.taskRepo.ArchiveCompleted(ctx, now.Add(-a.after), now)
+}
+
+// Run - archive completed tasks now and then every interval, until ctx is done
+func (a *taskArchiver) Run(ctx context.Context) {
+  if a.after <= 0 {
+    return
+  }
+
+  ticker := time.NewTicker(a.interval)
+  defer ticker.Stop()
+
+  for {
+    runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+    archived, err := a.ArchiveCompleted(runCtx)
+    cancel()
+
+    if err != nil {
+      log.Error().Err(err).Msg("Failed to archive completed tasks")
+    } else if archived > 0 {
+      log.Info().Int64("archived", archived).Msg("Archived completed tasks")
+    }
+
+    select {
+    case <-ctx.Done():
+      return
+    case <-ticker.C:
+    }
+  }
+}
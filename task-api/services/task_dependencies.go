@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/types"
+)
+
+// Dependency errors that handlers map to specific HTTP statuses
+var (
+  ErrTaskBlocked       = errors.New("task has open blockers")
+  ErrDependencyCycle   = errors.New("dependency would create a cycle")
+  ErrInvalidDependency = errors.New("blocker belongs to another owner")
+)
+
+// TaskBlockedError - status change refused while blockers are open
+type TaskBlockedError struct {
+  Blockers []types.TaskDependencyNode
+}
+
+func (e *TaskBlockedError) Error() string {
+  return ErrTaskBlocked.Error()
+}
+
+func (e *TaskBlockedError) Unwrap() error {
+  return ErrTaskBlocked
+}
+
+// AddBlocker - task cannot start before blocker is completed
+func (s *taskService) AddBlocker(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, blockerID bson.ObjectID) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if blockerID == taskID {
+    return nil, ErrDependencyCycle
+  }
+
+  task, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return nil, err
+  }
+
+  blocker, err := s.accessibleTask(ctx, blockerID, userID, types.SharePermissionView)
+  if err != nil {
+    if errors.Is(err, ErrTaskNotFound) {
+      return nil, ErrInvalidDependency
+    }
+    return nil, err
+  }
+
+  // Dependencies stay within one owner and workspace
+  if blocker.UserID != task.UserID || !sameWorkspace(blocker.WorkspaceID, task.WorkspaceID) {
+    return nil, ErrInvalidDependency
+  }
+
+  // The blocker must not itself wait for the task
+  cycle, err := s.dependsOn(ctx, blocker, taskID)
+  if err != nil {
+    return nil, err
+  }
+  if cycle {
+    return nil, ErrDependencyCycle
+  }
+
+  if err := s.taskRepo.AddBlocker(ctx, taskID, blockerID); err != nil {
+    return nil, mapTaskNotFound(err)
+  }
+
+  after := *task
+  if !slices.Contains(task.BlockedBy, blockerID) {
+    after.BlockedBy = append(slices.Clone(task.BlockedBy), blockerID)
+  }
+  s.recordEvent(ctx, taskID, userID, types.TaskEventUpdated, taskChanges(task, &after))
+
+  return s.GetTask(ctx, taskID, userID)
+}
+
+// RemoveBlocker - drop a blocker from task
+func (s *taskService) RemoveBlocker(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, blockerID bson.ObjectID) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  task, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return nil, err
+  }
+
+  if err := s.taskRepo.RemoveBlocker(ctx, taskID, blockerID); err != nil {
+    return nil, mapTaskNotFound(err)
+  }
+
+  after := *task
+  after.BlockedBy = slices.DeleteFunc(slices.Clone(task.BlockedBy), func(id bson.ObjectID) bool { return id == blockerID })
+  s.recordEvent(ctx, taskID, userID, types.TaskEventUpdated, taskChanges(task, &after))
+
+  return s.GetTask(ctx, taskID, userID)
+}
+
+// GetTaskDependencies - tasks the task waits for and tasks waiting for it, transitively
+func (s *taskService) GetTaskDependencies(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskDependencyGraph, error) {
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  task, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionView)
+  if err != nil {
+    return nil, err
+  }
+
+  nodes := []models.Task{*task}
+  seen := map[bson.ObjectID]bool{task.ID: true}
+
+  // Upstream: blockers of blockers
+  for level := []models.Task{*task}; len(level) > 0; {
+    ids := []bson.ObjectID{}
+    for _, t := range level {
+      for _, id := range t.BlockedBy {
+        if !seen[id] {
+          seen[id] = true
+          ids = append(ids, id)
+        }
+      }
+    }
+    if len(ids) == 0 {
+      break
+    }
+
+    level, err = s.taskRepo.FindByIDs(ctx, ids)
+    if err != nil {
+      return nil, err
+    }
+    nodes = append(nodes, level...)
+  }
+
+  // Downstream: tasks waiting for the task, and for those
+  for level := []bson.ObjectID{task.ID}; len(level) > 0; {
+    blocked, err := s.taskRepo.FindBlockedBy(ctx, level)
+    if err != nil {
+      return nil, err
+    }
+
+    level = nil
+    for _, t := range blocked {
+      if !seen[t.ID] {
+        seen[t.ID] = true
+        nodes = append(nodes, t)
+        level = append(level, t.ID)
+      }
+    }
+  }
+
+  graph := &types.TaskDependencyGraph{
+    TaskID: task.ID.Hex(),
+    Edges:  []types.TaskDependencyEdge{},
+  }
+  if graph.Nodes, err = s.dependencyNodes(ctx, userID, nodes); err != nil {
+    return nil, err
+  }
+  // Blockers in the trash are seen but not found, they block nothing and get no edge
+  found := make(map[bson.ObjectID]bool, len(nodes))
+  for i := range nodes {
+    found[nodes[i].ID] = true
+  }
+  for i := range nodes {
+    for _, id := range nodes[i].BlockedBy {
+      if found[id] {
+        graph.Edges = append(graph.Edges, types.TaskDependencyEdge{From: id.Hex(), To: nodes[i].ID.Hex()})
+      }
+    }
+  }
+
+  return graph, nil
+}
+
+// requireUnblocked - refuse starting or completing a task with open blockers
+func (s *taskService) requireUnblocked(ctx context.Context, task *models.Task, userID bson.ObjectID) error {
+  if len(task.BlockedBy) == 0 {
+    return nil
+  }
+
+  blockers, err := s.taskRepo.FindByIDs(ctx, task.BlockedBy)
+  if err != nil {
+    return err
+  }
+
+  open := []models.Task{}
+  for i := range blockers {
+    if blockers[i].Status != types.TaskStatusCompleted {
+      open = append(open, blockers[i])
+    }
+  }
+  if len(open) == 0 {
+    return nil
+  }
+
+  nodes, err := s.dependencyNodes(ctx, userID, open)
+  if err != nil {
+    return err
+  }
+  return &TaskBlockedError{Blockers: nodes}
+}
+
+// dependencyNodes - graph nodes of tasks, those the user cannot see show their ID only
+func (s *taskService) dependencyNodes(ctx context.Context, userID bson.ObjectID, tasks []models.Task) ([]types.TaskDependencyNode, error) {
+  nodes := make([]types.TaskDependencyNode, len(tasks))
+  for i := range tasks {
+    visible, err := s.canView(ctx, &tasks[i], userID)
+    if err != nil {
+      return nil, err
+    }
+    nodes[i] = types.TaskDependencyNode{ID: tasks[i].ID.Hex()}
+    if visible {
+      nodes[i] = types.ToTaskDependencyNode(&tasks[i])
+    }
+  }
+  return nodes, nil
+}
+
+// canView - whether the user may see task, own personal tasks need no lookup
+func (s *taskService) canView(ctx context.Context, task *models.Task, userID bson.ObjectID) (bool, error) {
+  if task.UserID == userID && task.WorkspaceID == nil {
+    return true, nil
+  }
+
+  _, err := s.accessibleTask(ctx, task.ID, userID, types.SharePermissionView)
+  if errors.Is(err, ErrTaskNotFound) || errors.Is(err, ErrTaskPermissionDenied) {
+    return false, nil
+  }
+  return err == nil, err
+}
+
+// dependsOn - whether task waits for targetID, directly or through other blockers
+func (s *taskService) dependsOn(ctx context.Context, task *models.Task, targetID bson.ObjectID) (bool, error) {
+  seen := map[bson.ObjectID]bool{task.ID: true}
+  level := []models.Task{*task}
+
+  for len(level) > 0 {
+    ids := []bson.ObjectID{}
+    for _, t := range level {
+      for _, id := range t.BlockedBy {
+        if id == targetID {
+          return true, nil
+        }
+        if !seen[id] {
+          seen[id] = true
+          ids = append(ids, id)
+        }
+      }
+    }
+    if len(ids) == 0 {
+      break
+    }
+
+    var err error
+    level, err = s.taskRepo.FindByIDs(ctx, ids)
+    if err != nil {
+      return false, err
+    }
+  }
+
+  return false, nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+func TestTaskService_AddBlocker(t *testing.T) {
+  t.Run("should add blocker of the same owner", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    blocker := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("FindByID", mock.Anything, blocker.ID, userID).Return(blocker, nil)
+    taskRepo.On("AddBlocker", mock.Anything, task.ID, blocker.ID).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.AddBlocker(context.Background(), task.ID, userID, blocker.ID)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should refuse blocker waiting for the task", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    middle := models.Task{ID: bson.NewObjectID(), UserID: userID, BlockedBy: []bson.ObjectID{task.ID}}
+    blocker := &models.Task{ID: bson.NewObjectID(), UserID: userID, BlockedBy: []bson.ObjectID{middle.ID}}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("FindByID", mock.Anything, blocker.ID, userID).Return(blocker, nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{middle.ID}).Return([]models.Task{middle}, nil)
+
+    _, err := service.AddBlocker(context.Background(), task.ID, userID, blocker.ID)
+
+    assert.ErrorIs(t, err, ErrDependencyCycle)
+    taskRepo.AssertNotCalled(t, "AddBlocker", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should refuse task blocking itself", func(t *testing.T) {
+    service, _, _, _ := newTestShareTaskService()
+    taskID := bson.NewObjectID()
+
+    _, err := service.AddBlocker(context.Background(), taskID, bson.NewObjectID(), taskID)
+
+    assert.ErrorIs(t, err, ErrDependencyCycle)
+  })
+
+  t.Run("should refuse blocker of another owner", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+    blocker := &models.Task{ID: bson.NewObjectID(), UserID: bson.NewObjectID()}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("FindByID", mock.Anything, blocker.ID, userID).Return(blocker, nil)
+
+    _, err := service.AddBlocker(context.Background(), task.ID, userID, blocker.ID)
+
+    assert.ErrorIs(t, err, ErrInvalidDependency)
+  })
+}
+
+func TestTaskService_BlockedStatus(t *testing.T) {
+  t.Run("should refuse starting task with open blockers", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    open := models.Task{ID: bson.NewObjectID(), UserID: userID, Title: "Design", Status: types.TaskStatusInProgress}
+    done := models.Task{ID: bson.NewObjectID(), Status: types.TaskStatusCompleted}
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, BlockedBy: []bson.ObjectID{open.ID, done.ID}}
+    status := types.TaskStatusInProgress
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("FindByIDs", mock.Anything, task.BlockedBy).Return([]models.Task{open, done}, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{Status: &status}, nil)
+
+    var blocked *TaskBlockedError
+    assert.True(t, errors.As(err, &blocked))
+    assert.ErrorIs(t, err, ErrTaskBlocked)
+    assert.Equal(t, []types.TaskDependencyNode{{ID: open.ID.Hex(), Title: "Design", Status: types.TaskStatusInProgress}}, blocked.Blockers)
+    taskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should show only the ID of blockers the user cannot see", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    ownerID, userID := bson.NewObjectID(), bson.NewObjectID()
+    hidden := models.Task{ID: bson.NewObjectID(), UserID: ownerID, Title: "Private", Status: types.TaskStatusPending}
+    task := &models.Task{ID: bson.NewObjectID(), UserID: ownerID, BlockedBy: []bson.ObjectID{hidden.ID}}
+    status := types.TaskStatusInProgress
+
+    // Shared with the user for editing, the blocker is not
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(nil, repositories.ErrTaskNotFound)
+    taskRepo.On("FindByID", mock.Anything, hidden.ID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, task.ID, userID).Return(&models.TaskShare{Permission: types.SharePermissionEdit}, nil)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, hidden.ID, userID).Return(nil, repositories.ErrTaskShareNotFound)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{task.ID}).Return([]models.Task{*task}, nil)
+    taskRepo.On("FindByIDs", mock.Anything, task.BlockedBy).Return([]models.Task{hidden}, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{Status: &status}, nil)
+
+    var blocked *TaskBlockedError
+    assert.True(t, errors.As(err, &blocked))
+    assert.Equal(t, []types.TaskDependencyNode{{ID: hidden.ID.Hex()}}, blocked.Blockers)
+  })
+
+  t.Run("should complete task once blockers are completed", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    done := models.Task{ID: bson.NewObjectID(), Status: types.TaskStatusCompleted}
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, BlockedBy: []bson.ObjectID{done.ID}}
+    status := types.TaskStatusCompleted
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("FindByIDs", mock.Anything, task.BlockedBy).Return([]models.Task{done}, nil)
+    taskRepo.On("Update", mock.Anything, task.ID, userID, mock.Anything).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{Status: &status}, nil)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+  })
+}
+
+func TestTaskService_GetTaskDependencies(t *testing.T) {
+  t.Run("should walk blockers and blocked tasks", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    root := models.Task{ID: bson.NewObjectID(), UserID: userID}
+    blocker := models.Task{ID: bson.NewObjectID(), UserID: userID, BlockedBy: []bson.ObjectID{root.ID}}
+    task := models.Task{ID: bson.NewObjectID(), UserID: userID, BlockedBy: []bson.ObjectID{blocker.ID}}
+    waiting := models.Task{ID: bson.NewObjectID(), UserID: userID, Title: "Release", Status: types.TaskStatusPending, BlockedBy: []bson.ObjectID{task.ID}}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(&task, nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{blocker.ID}).Return([]models.Task{blocker}, nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{root.ID}).Return([]models.Task{root}, nil)
+    taskRepo.On("FindBlockedBy", mock.Anything, []bson.ObjectID{task.ID}).Return([]models.Task{waiting}, nil)
+    taskRepo.On("FindBlockedBy", mock.Anything, []bson.ObjectID{waiting.ID}).Return([]models.Task{}, nil)
+
+    graph, err := service.GetTaskDependencies(context.Background(), task.ID, userID)
+
+    assert.NoError(t, err)
+    assert.Equal(t, task.ID.Hex(), graph.TaskID)
+    assert.Len(t, graph.Nodes, 4)
+    assert.Contains(t, graph.Nodes, types.TaskDependencyNode{ID: waiting.ID.Hex(), Title: "Release", Status: types.TaskStatusPending})
+    assert.ElementsMatch(t, []types.TaskDependencyEdge{
+      {From: root.ID.Hex(), To: blocker.ID.Hex()},
+      {From: blocker.ID.Hex(), To: task.ID.Hex()},
+      {From: task.ID.Hex(), To: waiting.ID.Hex()},
+    }, graph.Edges)
+  })
+  t.Run("should leave out blockers in the trash", func(t *testing.T) {
+    service, taskRepo, _, _ := newTestShareTaskService()
+    userID, trashedID := bson.NewObjectID(), bson.NewObjectID()
+    task := models.Task{ID: bson.NewObjectID(), UserID: userID, BlockedBy: []bson.ObjectID{trashedID}}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(&task, nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{trashedID}).Return([]models.Task{}, nil)
+    taskRepo.On("FindBlockedBy", mock.Anything, []bson.ObjectID{task.ID}).Return([]models.Task{}, nil)
+
+    graph, err := service.GetTaskDependencies(context.Background(), task.ID, userID)
+
+    assert.NoError(t, err)
+    assert.Len(t, graph.Nodes, 1)
+    assert.Empty(t, graph.Edges)
+  })
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// DefaultHistoryLimit - events per page of the task history
+const DefaultHistoryLimit = 20
+
+// trackedFields - task fields recorded in the history, by their bson name
+var trackedFields = []struct {
+  name  string
+  value func(task *models.Task) any
+}{
+  {"title", func(task *models.Task) any { return task.Title }},
+  {"description", func(task *models.Task) any { return task.Description }},
+  {"status", func(task *models.Task) any { return task.Status }},
+  {"priority", func(task *models.Task) any { return task.Priority }},
+  {"due_date", func(task *models.Task) any { return task.DueDate }},
+  {"tags", func(task *models.Task) any { return task.Tags }},
+  {"workspace_id", func(task *models.Task) any { return task.WorkspaceID }},
+  {"parent_id", func(task *models.Task) any { return task.ParentID }},
+  {"assignee_ids", func(task *models.Task) any { return task.AssigneeIDs }},
+  {"blocked_by", func(task *models.Task) any { return task.BlockedBy }},
+  {"recurrence", func(task *models.Task) any { return task.Recurrence }},
+  {"completed_at", func(task *models.Task) any { return task.CompletedAt }},
+  {"archived_at", func(task *models.Task) any { return task.ArchivedAt }},
+}
+
+// GetTaskHistory - events of a task the user can see, newest first
+func (s *taskService) GetTaskHistory(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, query types.TaskHistoryQueryParams) (*types.TaskHistoryResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  if _, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionView); err != nil {
+    return nil, err
+  }
+
+  limit := query.Limit
+  if limit < 1 {
+    limit = DefaultHistoryLimit
+  }
+
+  var before *bson.ObjectID
+  if query.Cursor != "" {
+    cursor, err := bson.ObjectIDFromHex(query.Cursor)
+    if err != nil {
+      return nil, err
+    }
+    before = &cursor
+  }
+
+  // One extra event tells whether another page follows
+  events, err := s.eventRepo.FindByTask(ctx, taskID, before, limit+1)
+  if err != nil {
+    return nil, err
+  }
+
+  response := &types.TaskHistoryResponse{}
+  if len(events) > limit {
+    events = events[:limit]
+    response.NextCursor = events[limit-1].ID.Hex()
+  }
+
+  response.Events = make([]types.TaskEventResponse, len(events))
+  for i := range events {
+    response.Events[i] = types.ToTaskEventResponse(&events[i])
+  }
+
+  return response, nil
+}
+
+// recordEvent - add an entry to the history of a task, failures are only logged as the change is already saved
+func (s *taskService) recordEvent(ctx context.Context, taskID bson.ObjectID, actorID bson.ObjectID, action string, changes []models.FieldChange) {
+  recordTaskEvent(ctx, s.eventRepo, taskID, actorID, action, changes)
+}
+
+// recordTaskEvent - recordEvent for services holding only the event repository
+func recordTaskEvent(ctx context.Context, eventRepo repositories.TaskEventRepository, taskID bson.ObjectID, actorID bson.ObjectID, action string, changes []models.FieldChange) {
+  if action == types.TaskEventUpdated && len(changes) == 0 {
+    return
+  }
+
+  event := &models.TaskEvent{
+    TaskID:  taskID,
+    ActorID: actorID,
+    Action:  action,
+    Changes: changes,
+  }
+  if err := eventRepo.Create(ctx, event); err != nil {
+    log.Error().Err(err).Str("task_id", taskID.Hex()).Str("action", action).Msg("Failed to record task event")
+  }
+}
+
+// taskChanges - tracked fields that differ between before and after, either may be nil
+func taskChanges(before *models.Task, after *models.Task) []models.FieldChange {
+  changes := []models.FieldChange{}
+  for _, field := range trackedFields {
+    var oldValue, newValue any
+    if before != nil {
+      oldValue = historyValue(field.value(before))
+    }
+    if after != nil {
+      newValue = historyValue(field.value(after))
+    }
+
+    if !reflect.DeepEqual(oldValue, newValue) {
+      changes = append(changes, models.FieldChange{Field: field.name, Old: oldValue, New: newValue})
+    }
+  }
+  return changes
+}
+
+// historyChanges - change of field when oldValue and newValue differ as stored in the history
+func historyChanges(field string, oldValue any, newValue any) []models.FieldChange {
+  oldValue, newValue = historyValue(oldValue), historyValue(newValue)
+  if reflect.DeepEqual(oldValue, newValue) {
+    return nil
+  }
+  return []models.FieldChange{{Field: field, Old: oldValue, New: newValue}}
+}
+
+// historyValue - field value as stored in the history, nil for empty values and IDs as hex
+func historyValue(value any) any {
+  switch v := value.(type) {
+  case string:
+    if v == "" {
+      return nil
+    }
+    return v
+  case *time.Time:
+    if v == nil {
+      return nil
+    }
+    // Dates are stored with millisecond precision
+    return v.UTC().Truncate(time.Millisecond)
+  case *bson.ObjectID:
+    if v == nil {
+      return nil
+    }
+    return v.Hex()
+  case []string:
+    if len(v) == 0 {
+      return nil
+    }
+    return v
+  case []bson.ObjectID:
+    if len(v) == 0 {
+      return nil
+    }
+    ids := make([]string, len(v))
+    for i, id := range v {
+      ids[i] = id.Hex()
+    }
+    return ids
+  default:
+    return v
+  }
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// MockTaskEventRepository - mock for TaskEventRepository
+type MockTaskEventRepository struct {
+  mock.Mock
+}
+
+func (m *MockTaskEventRepository) Create(ctx context.Context, event *models.TaskEvent) error {
+  args := m.Called(ctx, event)
+  return args.Error(0)
+}
+
+func (m *MockTaskEventRepository) FindByTask(ctx context.Context, taskID bson.ObjectID, before *bson.ObjectID, limit int) ([]models.TaskEvent, error) {
+  args := m.Called(ctx, taskID, before, limit)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]models.TaskEvent), args.Error(1)
+}
+
+func (m *MockTaskEventRepository) DeleteByTasks(ctx context.Context, taskIDs []bson.ObjectID) error {
+  args := m.Called(ctx, taskIDs)
+  return args.Error(0)
+}
+
+// newMockTaskEventRepository - event repository that accepts any event, for tests that do not look at the history
+func newMockTaskEventRepository() *MockTaskEventRepository {
+  eventRepo := new(MockTaskEventRepository)
+  eventRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+  return eventRepo
+}
+
+// recordedEvents - capture events passed to Create
+func recordedEvents(eventRepo *MockTaskEventRepository) *[]*models.TaskEvent {
+  events := &[]*models.TaskEvent{}
+  eventRepo.On("Create", mock.Anything, mock.Anything).
+    Run(func(args mock.Arguments) {
+      *events = append(*events, args.Get(1).(*models.TaskEvent))
+    }).
+    Return(nil)
+  return events
+}
+
+func TestTaskService_History(t *testing.T) {
+  t.Run("should record fields set on create", func(t *testing.T) {
+    taskRepo, eventRepo := new(MockTaskRepository), new(MockTaskEventRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), eventRepo, new(MockTaskPurger))
+    events := recordedEvents(eventRepo)
+    userID := bson.NewObjectID()
+
+    taskRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+    _, err := service.CreateTask(context.Background(), userID, types.CreateTaskInput{Title: "Write report", Priority: types.TaskPriorityHigh})
+
+    assert.NoError(t, err)
+    assert.Len(t, *events, 1)
+    event := (*events)[0]
+    assert.Equal(t, types.TaskEventCreated, event.Action)
+    assert.Equal(t, userID, event.ActorID)
+    assert.Contains(t, event.Changes, models.FieldChange{Field: "title", Old: nil, New: "Write report"})
+    assert.NotContains(t, event.Changes, models.FieldChange{Field: "description", Old: nil, New: nil})
+  })
+
+  t.Run("should record old and new values on update", func(t *testing.T) {
+    taskRepo, eventRepo := new(MockTaskRepository), new(MockTaskEventRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), eventRepo, new(MockTaskPurger))
+    events := recordedEvents(eventRepo)
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    due := time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)
+    before := &models.Task{ID: taskID, UserID: userID, Title: "Report", Status: types.TaskStatusPending}
+    after := &models.Task{ID: taskID, UserID: userID, Title: "Report", Status: types.TaskStatusPending, DueDate: &due}
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(before, nil).Once()
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(after, nil).Once()
+    taskRepo.On("Update", mock.Anything, taskID, userID, mock.Anything).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), taskID, userID, types.UpdateTaskInput{DueDate: &due}, nil)
+
+    assert.NoError(t, err)
+    assert.Len(t, *events, 1)
+    assert.Equal(t, types.TaskEventUpdated, (*events)[0].Action)
+    assert.Equal(t, []models.FieldChange{{Field: "due_date", Old: nil, New: due}}, (*events)[0].Changes)
+  })
+
+  t.Run("should not record updates that change nothing", func(t *testing.T) {
+    taskRepo, eventRepo := new(MockTaskRepository), new(MockTaskEventRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), eventRepo, new(MockTaskPurger))
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    title := "Report"
+    task := &models.Task{ID: taskID, UserID: userID, Title: title}
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(task, nil)
+    taskRepo.On("Update", mock.Anything, taskID, userID, mock.Anything).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), taskID, userID, types.UpdateTaskInput{Title: &title}, nil)
+
+    assert.NoError(t, err)
+    eventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should record checked checklist items", func(t *testing.T) {
+    taskRepo, eventRepo := new(MockTaskRepository), new(MockTaskEventRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), eventRepo, new(MockTaskPurger))
+    events := recordedEvents(eventRepo)
+    userID, itemID := bson.NewObjectID(), bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Checklist: []models.ChecklistItem{{ID: itemID, Text: "Draft"}}}
+    done := true
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("UpdateChecklistItem", mock.Anything, task.ID, itemID, mock.Anything).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateChecklistItem(context.Background(), task.ID, userID, itemID, types.UpdateChecklistItemInput{Done: &done})
+
+    assert.NoError(t, err)
+    assert.Len(t, *events, 1)
+    assert.Equal(t, []models.FieldChange{{Field: "checklist." + itemID.Hex() + ".done", Old: false, New: true}}, (*events)[0].Changes)
+  })
+
+  t.Run("should record the checklist order as item IDs", func(t *testing.T) {
+    taskRepo, eventRepo := new(MockTaskRepository), new(MockTaskEventRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), eventRepo, new(MockTaskPurger))
+    events := recordedEvents(eventRepo)
+    userID, first, second := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Checklist: []models.ChecklistItem{{ID: second, Order: 1}, {ID: first, Order: 0}}}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("ReorderChecklist", mock.Anything, task.ID, mock.Anything).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.ReorderChecklist(context.Background(), task.ID, userID, []bson.ObjectID{second, first})
+
+    assert.NoError(t, err)
+    assert.Len(t, *events, 1)
+    assert.Equal(t, []models.FieldChange{{
+      Field: "checklist",
+      Old:   []string{first.Hex(), second.Hex()},
+      New:   []string{second.Hex(), first.Hex()},
+    }}, (*events)[0].Changes)
+  })
+
+  t.Run("should record removed blockers", func(t *testing.T) {
+    taskRepo, eventRepo := new(MockTaskRepository), new(MockTaskEventRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), eventRepo, new(MockTaskPurger))
+    events := recordedEvents(eventRepo)
+    userID, blockerID := bson.NewObjectID(), bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, BlockedBy: []bson.ObjectID{blockerID}}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("RemoveBlocker", mock.Anything, task.ID, blockerID).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.RemoveBlocker(context.Background(), task.ID, userID, blockerID)
+
+    assert.NoError(t, err)
+    assert.Len(t, *events, 1)
+    assert.Equal(t, []models.FieldChange{{Field: "blocked_by", Old: []string{blockerID.Hex()}, New: nil}}, (*events)[0].Changes)
+  })
+
+  t.Run("should page history newest first", func(t *testing.T) {
+    taskRepo, eventRepo := new(MockTaskRepository), new(MockTaskEventRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), eventRepo, new(MockTaskPurger))
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID, UserID: userID}, nil)
+    events := []models.TaskEvent{
+      {ID: bson.NewObjectID(), TaskID: taskID, Action: types.TaskEventUpdated},
+      {ID: bson.NewObjectID(), TaskID: taskID, Action: types.TaskEventUpdated},
+      {ID: bson.NewObjectID(), TaskID: taskID, Action: types.TaskEventCreated},
+    }
+    eventRepo.On("FindByTask", mock.Anything, taskID, (*bson.ObjectID)(nil), 3).Return(events, nil)
+
+    result, err := service.GetTaskHistory(context.Background(), taskID, userID, types.TaskHistoryQueryParams{Limit: 2})
+
+    assert.NoError(t, err)
+    assert.Len(t, result.Events, 2)
+    assert.Equal(t, events[1].ID.Hex(), result.NextCursor)
+  })
+
+  t.Run("should hide history of invisible task", func(t *testing.T) {
+    taskRepo, shareRepo := new(MockTaskRepository), new(MockTaskShareRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), shareRepo, new(MockTaskEventRepository), new(MockTaskPurger))
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskShareNotFound)
+
+    _, err := service.GetTaskHistory(context.Background(), taskID, userID, types.TaskHistoryQueryParams{})
+
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+  })
+}
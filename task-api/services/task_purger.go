@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/configs"
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/storage"
+)
+
+// purgeBatchSize - expired tasks deleted per round
+const purgeBatchSize = 100
+
+// TrashRetention - how long deleted tasks stay in the trash (TRASH_RETENTION, default 30 days)
+func TrashRetention() time.Duration {
+  return configs.GetDuration("TRASH_RETENTION", 30*24*time.Hour)
+}
+
+// TaskPurger - deletes tasks for good, with their shares, comments, history and attachment contents
+type TaskPurger interface {
+  PurgeTasks(ctx context.Context, tasks []models.Task) error
+  PurgeExpired(ctx context.Context) (int, error)
+  Run(ctx context.Context)
+}
+
+// taskPurger - implementation
+type taskPurger struct {
+  taskRepo    repositories.TaskRepository
+  shareRepo   repositories.TaskShareRepository
+  commentRepo repositories.CommentRepository
+  eventRepo   repositories.TaskEventRepository
+  blobs       storage.BlobStore
+  retention   time.Duration
+  interval    time.Duration
+}
+
+// NewTaskPurger - constructor
+func NewTaskPurger(taskRepo repositories.TaskRepository, shareRepo repositories.TaskShareRepository, commentRepo repositories.CommentRepository, eventRepo repositories.TaskEventRepository, blobs storage.BlobStore) TaskPurger {
+  return &taskPurger{
+    taskRepo:    taskRepo,
+    shareRepo:   shareRepo,
+    commentRepo: commentRepo,
+    eventRepo:   eventRepo,
+    blobs:       blobs,
+    retention:   TrashRetention(),
+    interval:    configs.GetDuration("TRASH_PURGE_INTERVAL", time.Hour),
+  }
+}
+
+// PurgeTasks - delete tasks and everything attached to them, callers authorize.
+// The tasks go last, after a failure they are still found and purged again.
+func (p *taskPurger) PurgeTasks(ctx context.Context, tasks []models.Task) error {
+  if len(tasks) == 0 {
+    return nil
+  }
+
+  ids := make([]bson.ObjectID, len(tasks))
+  for i := range tasks {
+    ids[i] = tasks[i].ID
+  }
+
+  for i := range tasks {
+    for _, attachment := range tasks[i].Attachments {
+      if err := p.blobs.Delete(ctx, attachment.ID.Hex()); err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+        return err
+      }
+    }
+  }
+
+  for _, id := range ids {
+    if err := p.shareRepo.DeleteByTask(ctx, id); err != nil {
+      return err
+    }
+  }
+  if err := p.commentRepo.DeleteByTasks(ctx, ids); err != nil {
+    return err
+  }
+  if err := p.eventRepo.DeleteByTasks(ctx, ids); err != nil {
+    return err
+  }
+
+  // Deleted tasks no longer block anything
+  if err := p.taskRepo.PullBlockers(ctx, ids); err != nil {
+    return err
+  }
+
+  return p.taskRepo.DeleteByIDs(ctx, ids)
+}
+
+// PurgeExpired - delete tasks that have been in the trash longer than the retention, returns how many
+func (p *taskPurger) PurgeExpired(ctx context.Context) (int, error) {
+  cutoff := time.Now().Add(-p.retention)
+  purged := 0
+
+  for {
+    tasks, err := p.taskRepo.FindExpired(ctx, cutoff, purgeBatchSize)
+    if err != nil {
+      return purged, err
+    }
+
+    if err := p.PurgeTasks(ctx, tasks); err != nil {
+      return purged, err
+    }
+    purged += len(tasks)
+
+    if len(tasks) < purgeBatchSize {
+      return purged, nil
+    }
+  }
+}
+
+// Run - purge expired tasks now and then every interval, until ctx is done
+func (p *taskPurger) Run(ctx context.Context) {
+  ticker := time.NewTicker(p.interval)
+  defer ticker.Stop()
+
+  for {
+    runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+    purged, err := p.PurgeExpired(runCtx)
+    cancel()
+
+    if err != nil {
+      log.Error().Err(err).Int("purged", purged).Msg("Failed to purge trash")
+    } else if purged > 0 {
+      log.Info().Int("purged", purged).Msg("Purged expired tasks from trash")
+    }
+
+    select {
+    case <-ctx.Done():
+      return
+    case <-ticker.C:
+    }
+  }
+}
@@ -2,32 +2,78 @@ package services
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 
+	"task-api/models"
 	"task-api/repositories"
 	"task-api/types"
 )
 
+var (
+  ErrTaskNotFound      = errors.New("task not found")
+  ErrTaskOwnerRequired = errors.New("task owner required")
+  ErrAssigneeNotMember = errors.New("assignee is not a workspace member")
+  ErrTaskNotTrashed    = errors.New("task is not in the trash")
+  ErrTaskArchived      = errors.New("task is already archived")
+  ErrTaskNotArchived   = errors.New("task is not archived")
+)
+
 // TaskService - interface
 type TaskService interface {
   CreateTask(ctx context.Context, userID bson.ObjectID, input types.CreateTaskInput) (*types.TaskResponse, error)
   GetTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error)
   GetTasks(ctx context.Context, userID bson.ObjectID, query types.TaskQueryParams) (*types.TaskListResponse, error)
-  UpdateTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.UpdateTaskInput) (*types.TaskResponse, error)
-  DeleteTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) error
+  UpdateTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.UpdateTaskInput, versions []int64) (*types.TaskResponse, error)
+  DeleteTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, versions []int64) error
+  PurgeTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, versions []int64) error
+  GetTrash(ctx context.Context, userID bson.ObjectID, query types.TrashQueryParams) (*types.TaskListResponse, error)
+  RestoreTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error)
+  ArchiveTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error)
+  UnarchiveTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error)
+  BulkTasks(ctx context.Context, userID bson.ObjectID, input types.BulkTaskInput) (*types.BulkTaskResponse, error)
+  AssignTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) (*types.TaskResponse, error)
+  UnassignTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) (*types.TaskResponse, error)
+  WatchTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error)
+  UnwatchTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error)
+  ShareTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.ShareTaskInput) (*types.TaskShareResponse, error)
+  GetTaskShares(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) ([]types.TaskShareResponse, error)
+  RevokeTaskShare(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, sharedWithID bson.ObjectID) error
+  GetSharedTasks(ctx context.Context, userID bson.ObjectID, query types.SharedTaskQueryParams) (*types.SharedTaskListResponse, error)
+  GetSubtasks(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) ([]types.TaskResponse, error)
+  CreateSubtask(ctx context.Context, parentID bson.ObjectID, userID bson.ObjectID, input types.CreateTaskInput) (*types.TaskResponse, error)
+  AddBlocker(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, blockerID bson.ObjectID) (*types.TaskResponse, error)
+  RemoveBlocker(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, blockerID bson.ObjectID) (*types.TaskResponse, error)
+  GetTaskDependencies(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskDependencyGraph, error)
+  AddChecklistItem(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.AddChecklistItemInput) (*types.TaskResponse, error)
+  UpdateChecklistItem(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, itemID bson.ObjectID, input types.UpdateChecklistItemInput) (*types.TaskResponse, error)
+  RemoveChecklistItem(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, itemID bson.ObjectID) (*types.TaskResponse, error)
+  ReorderChecklist(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, itemIDs []bson.ObjectID) (*types.TaskResponse, error)
+  GetTaskHistory(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, query types.TaskHistoryQueryParams) (*types.TaskHistoryResponse, error)
 }
 
 // taskService - implementation
 type taskService struct {
-  taskRepo repositories.TaskRepository
+  taskRepo      repositories.TaskRepository
+  workspaceRepo repositories.WorkspaceRepository
+  userRepo      repositories.UserRepository
+  shareRepo     repositories.TaskShareRepository
+  eventRepo     repositories.TaskEventRepository
+  purger        TaskPurger
 }
 
 // NewTaskService - constructor
-func NewTaskService(taskRepo repositories.TaskRepository) TaskService {
+func NewTaskService(taskRepo repositories.TaskRepository, workspaceRepo repositories.WorkspaceRepository, userRepo repositories.UserRepository, shareRepo repositories.TaskShareRepository, eventRepo repositories.TaskEventRepository, purger TaskPurger) TaskService {
   return &taskService{
-    taskRepo: taskRepo,
+    taskRepo:      taskRepo,
+    workspaceRepo: workspaceRepo,
+    userRepo:      userRepo,
+    shareRepo:     shareRepo,
+    eventRepo:     eventRepo,
+    purger:        purger,
   }
 }
 
@@ -38,12 +84,25 @@ func (s *taskService) CreateTask(ctx context.Context, userID bson.ObjectID, inpu
   
   // Convert input to model
   task := input.ToTask(userID)
+  if err := s.startSeries(ctx, &task, userID); err != nil {
+    return nil, err
+  }
+  
+  // Only members may add tasks to a workspace
+  if input.WorkspaceID != "" {
+    workspaceID, err := s.memberWorkspaceID(ctx, input.WorkspaceID, userID)
+    if err != nil {
+      return nil, err
+    }
+    task.WorkspaceID = &workspaceID
+  }
   
   // Save to database
   err := s.taskRepo.Create(ctx, &task)
   if err != nil {
     return nil, err
   }
+  s.recordEvent(ctx, task.ID, userID, types.TaskEventCreated, taskChanges(nil, &task))
   
   // Convert to response
   response := types.ToTaskResponse(&task)
@@ -56,12 +115,18 @@ func (s *taskService) GetTask(ctx context.Context, taskID bson.ObjectID, userID
   defer cancel()
   
   task, err := s.taskRepo.FindByID(ctx, taskID, userID)
+  if errors.Is(err, repositories.ErrTaskNotFound) {
+    task, err = s.sharedTask(ctx, taskID, userID, types.SharePermissionView, err)
+  }
   if err != nil {
     return nil, err
   }
   
-  response := types.ToTaskResponse(task)
-  return &response, nil
+  responses, err := s.toResponses(ctx, []models.Task{*task})
+  if err != nil {
+    return nil, err
+  }
+  return &responses[0], nil
 }
 
 // GetTasks - get tasks with filter
@@ -69,13 +134,23 @@ func (s *taskService) GetTasks(ctx context.Context, userID bson.ObjectID, query
   ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
   defer cancel()
   
+  // Non-members would only get an empty list, tell them the workspace does not exist
+  if query.WorkspaceID != "" {
+    if _, err := s.memberWorkspaceID(ctx, query.WorkspaceID, userID); err != nil {
+      return nil, err
+    }
+  }
+  
   tasks, total, err := s.taskRepo.FindByUserID(ctx, userID, query)
   if err != nil {
     return nil, err
   }
   
   // Convert to response
-  taskResponses := types.ToTaskResponseList(tasks)
+  taskResponses, err := s.toResponses(ctx, tasks)
+  if err != nil {
+    return nil, err
+  }
   
   // Pagination meta
   page := 1
@@ -108,11 +183,21 @@ func (s *taskService) GetTasks(ctx context.Context, userID bson.ObjectID, query
   }, nil
 }
 
-// UpdateTask - update task
-func (s *taskService) UpdateTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.UpdateTaskInput) (*types.TaskResponse, error) {
+// UpdateTask - update task, with versions only while it is at one of them
+func (s *taskService) UpdateTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.UpdateTaskInput, versions []int64) (*types.TaskResponse, error) {
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
   
+  // Status rules, recurring tasks and the history need the task as it is now
+  current, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionEdit)
+  if err != nil {
+    return nil, err
+  }
+  if err := s.checkVersion(ctx, current, versions); err != nil {
+    return nil, err
+  }
+  starts := input.Status != nil && (*input.Status == types.TaskStatusInProgress || *input.Status == types.TaskStatusCompleted)
+  
   // Build update document
   updates := bson.M{}
   
@@ -125,6 +210,13 @@ func (s *taskService) UpdateTask(ctx context.Context, taskID bson.ObjectID, user
   }
   
   if input.Status != nil {
+    // Work cannot start or finish while blockers are open
+    if starts {
+      if err := s.requireUnblocked(ctx, current, userID); err != nil {
+        return nil, err
+      }
+    }
+    
     updates["status"] = *input.Status
     
     // If status is completed, set completed_at
@@ -148,26 +240,252 @@ func (s *taskService) UpdateTask(ctx context.Context, taskID bson.ObjectID, user
     updates["tags"] = input.Tags
   }
   
-  // Update
-  err := s.taskRepo.Update(ctx, taskID, userID, updates)
+  // Move to another parent, or to the top level
+  if input.ParentID != nil {
+    parentID, err := s.moveTarget(ctx, taskID, userID, *input.ParentID)
+    if err != nil {
+      return nil, err
+    }
+    updates["parent_id"] = parentID
+  }
+  
+  if err := s.seriesUpdates(ctx, current, userID, input, updates); err != nil {
+    return nil, err
+  }
+  
+  // Update, only at the version checked above when the client sent versions
+  if versions != nil {
+    err = s.setIfVersion(ctx, taskID, userID, current.Version, updates)
+  } else if err = s.taskRepo.Update(ctx, taskID, userID, updates); errors.Is(err, repositories.ErrTaskNotFound) {
+    // Tasks shared with edit permission
+    if err = s.requireShare(ctx, taskID, userID, types.SharePermissionEdit, err); err == nil {
+      err = s.taskRepo.UpdateByID(ctx, taskID, updates)
+    }
+  }
   if err != nil {
     return nil, err
   }
   
-  // Get updated task
-  task, err := s.taskRepo.FindByID(ctx, taskID, userID)
+  // Completing an occurrence of a recurring task creates the next one
+  var next *models.Task
+  recurring := current.Recurrence != "" || input.Recurrence != nil && *input.Recurrence != ""
+  if recurring && starts && *input.Status == types.TaskStatusCompleted && current.Status != types.TaskStatusCompleted {
+    if next, err = s.spawnNext(ctx, taskID); err != nil {
+      return nil, err
+    }
+  }
+  
+  // Get updated task, the history records what actually changed
+  updated, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionView)
+  if err != nil {
+    return nil, err
+  }
+  s.recordEvent(ctx, taskID, userID, types.TaskEventUpdated, taskChanges(current, updated))
+  if next != nil {
+    s.recordEvent(ctx, next.ID, userID, types.TaskEventCreated, taskChanges(nil, next))
+  }
+  
+  responses, err := s.toResponses(ctx, []models.Task{*updated})
+  if err != nil {
+    return nil, err
+  }
+  response := &responses[0]
+  if next != nil {
+    response.NextID = next.ID.Hex()
+  }
+  return response, nil
+}
+
+// DeleteTask - move task to the trash, subtasks go with it; with versions only while it is at one of them
+func (s *taskService) DeleteTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, versions []int64) error {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+  
+  now := time.Now()
+  var err error
+  if versions != nil {
+    err = s.trashIfVersion(ctx, taskID, userID, versions, now)
+  } else if err = s.taskRepo.Trash(ctx, taskID, userID, now); errors.Is(err, repositories.ErrTaskNotFound) {
+    // Tasks shared with owner permission
+    if err = s.requireShare(ctx, taskID, userID, types.SharePermissionOwner, err); err == nil {
+      err = s.taskRepo.TrashByIDs(ctx, []bson.ObjectID{taskID}, now, nil)
+    }
+  }
+  if err != nil {
+    return err
+  }
+  
+  // Subtasks go with their parent and come back with it
+  ids, err := s.descendantIDs(ctx, taskID)
+  if err != nil {
+    return err
+  }
+  if len(ids) > 0 {
+    if err := s.taskRepo.TrashByIDs(ctx, ids, now, &taskID); err != nil {
+      return err
+    }
+  }
+  
+  ids = append(ids, taskID)
+  for _, id := range ids {
+    s.recordEvent(ctx, id, userID, types.TaskEventDeleted, nil)
+  }
+  return nil
+}
+
+// AssignTask - assign user to task, the owner or workspace members only
+func (s *taskService) AssignTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+  
+  task, err := s.findTask(ctx, taskID, userID)
   if err != nil {
     return nil, err
   }
   
+  if err := s.requireTaskOwner(ctx, task, userID); err != nil {
+    return nil, err
+  }
+  
+  if _, err := s.userRepo.FindByID(ctx, assigneeID); err != nil {
+    if errors.Is(err, repositories.ErrUserNotFound) {
+      return nil, ErrUserNotFound
+    }
+    return nil, err
+  }
+  
+  // Workspace tasks are assigned within the workspace
+  if task.WorkspaceID != nil {
+    if _, err := findWorkspaceMember(ctx, s.workspaceRepo, *task.WorkspaceID, assigneeID); err != nil {
+      if errors.Is(err, ErrWorkspaceNotFound) {
+        return nil, ErrAssigneeNotMember
+      }
+      return nil, err
+    }
+  }
+  
+  if err := s.taskRepo.AddAssignee(ctx, taskID, userID, assigneeID); err != nil {
+    return nil, mapTaskNotFound(err)
+  }
+  
+  if !slices.Contains(task.AssigneeIDs, assigneeID) {
+    assigned := *task
+    assigned.AssigneeIDs = append(slices.Clone(task.AssigneeIDs), assigneeID)
+    s.recordEvent(ctx, taskID, userID, types.TaskEventUpdated, taskChanges(task, &assigned))
+  }
+  
+  return s.GetTask(ctx, taskID, userID)
+}
+
+// UnassignTask - remove assignee, assignees may also unassign themselves
+func (s *taskService) UnassignTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+  
+  task, err := s.findTask(ctx, taskID, userID)
+  if err != nil {
+    return nil, err
+  }
+  
+  if assigneeID != userID {
+    if err := s.requireTaskOwner(ctx, task, userID); err != nil {
+      return nil, err
+    }
+  }
+  
+  if err := s.taskRepo.RemoveAssignee(ctx, taskID, userID, assigneeID); err != nil {
+    return nil, mapTaskNotFound(err)
+  }
+  
+  // Not read back, unassigning oneself can drop access to the task
+  before := *task
+  task.AssigneeIDs = removeID(task.AssigneeIDs, assigneeID)
+  task.UpdatedAt = time.Now()
+  s.recordEvent(ctx, taskID, userID, types.TaskEventUpdated, taskChanges(&before, task))
+  
   response := types.ToTaskResponse(task)
   return &response, nil
 }
 
-// DeleteTask - delete task
-func (s *taskService) DeleteTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) error {
+// WatchTask - watch a task the user can see
+func (s *taskService) WatchTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+  
+  if err := s.taskRepo.AddWatcher(ctx, taskID, userID); err != nil {
+    return nil, mapTaskNotFound(err)
+  }
+  
+  return s.GetTask(ctx, taskID, userID)
+}
+
+// UnwatchTask - stop watching a task
+func (s *taskService) UnwatchTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error) {
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
   
-  return s.taskRepo.Delete(ctx, taskID, userID)
+  if err := s.taskRepo.RemoveWatcher(ctx, taskID, userID); err != nil {
+    return nil, mapTaskNotFound(err)
+  }
+  
+  return s.GetTask(ctx, taskID, userID)
+}
+
+// findTask - task visible to the user
+func (s *taskService) findTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*models.Task, error) {
+  task, err := s.taskRepo.FindByID(ctx, taskID, userID)
+  if err != nil {
+    return nil, mapTaskNotFound(err)
+  }
+  return task, nil
+}
+
+// requireTaskOwner - personal tasks are managed by their author, workspace tasks by members
+func (s *taskService) requireTaskOwner(ctx context.Context, task *models.Task, userID bson.ObjectID) error {
+  if task.WorkspaceID == nil {
+    if task.UserID != userID {
+      return ErrTaskOwnerRequired
+    }
+    return nil
+  }
+
+  if _, err := findWorkspaceMember(ctx, s.workspaceRepo, *task.WorkspaceID, userID); err != nil {
+    if errors.Is(err, ErrWorkspaceNotFound) {
+      return ErrTaskOwnerRequired
+    }
+    return err
+  }
+  return nil
+}
+
+// mapTaskNotFound - translate repository not found error
+func mapTaskNotFound(err error) error {
+  if errors.Is(err, repositories.ErrTaskNotFound) {
+    return ErrTaskNotFound
+  }
+  return err
+}
+
+// removeID - copy of ids without id
+func removeID(ids []bson.ObjectID, id bson.ObjectID) []bson.ObjectID {
+  result := make([]bson.ObjectID, 0, len(ids))
+  for _, existing := range ids {
+    if existing != id {
+      result = append(result, existing)
+    }
+  }
+  return result
+}
+
+// memberWorkspaceID - parse workspace ID and check that user is a member
+func (s *taskService) memberWorkspaceID(ctx context.Context, hexID string, userID bson.ObjectID) (bson.ObjectID, error) {
+  workspaceID, err := bson.ObjectIDFromHex(hexID)
+  if err != nil {
+    return bson.ObjectID{}, ErrWorkspaceNotFound
+  }
+
+  if _, err := findWorkspaceMember(ctx, s.workspaceRepo, workspaceID, userID); err != nil {
+    return bson.ObjectID{}, err
+  }
+  return workspaceID, nil
 }
@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 
 	"task-api/models"
+	"task-api/repositories"
 	"task-api/types"
 )
 
@@ -45,15 +46,201 @@ func (m *MockTaskRepository) Update(ctx context.Context, id bson.ObjectID, userI
   return args.Error(0)
 }
 
-func (m *MockTaskRepository) Delete(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) error {
+func (m *MockTaskRepository) AddAssignee(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) error {
+  args := m.Called(ctx, id, userID, assigneeID)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) RemoveAssignee(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, assigneeID bson.ObjectID) error {
+  args := m.Called(ctx, id, userID, assigneeID)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) AddWatcher(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) error {
+  args := m.Called(ctx, id, userID)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) RemoveWatcher(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) error {
+  args := m.Called(ctx, id, userID)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) FindByIDs(ctx context.Context, ids []bson.ObjectID) ([]models.Task, error) {
+  args := m.Called(ctx, ids)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) UpdateByID(ctx context.Context, id bson.ObjectID, updates bson.M) error {
+  args := m.Called(ctx, id, updates)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) FindChildren(ctx context.Context, parentID bson.ObjectID) ([]models.Task, error) {
+  args := m.Called(ctx, parentID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) FindChildIDs(ctx context.Context, parentIDs []bson.ObjectID) ([]bson.ObjectID, error) {
+  args := m.Called(ctx, parentIDs)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]bson.ObjectID), args.Error(1)
+}
+
+func (m *MockTaskRepository) ChildProgress(ctx context.Context, parentIDs []bson.ObjectID) (map[bson.ObjectID]types.TaskProgress, error) {
+  args := m.Called(ctx, parentIDs)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(map[bson.ObjectID]types.TaskProgress), args.Error(1)
+}
+
+func (m *MockTaskRepository) DeleteByIDs(ctx context.Context, ids []bson.ObjectID) error {
+  args := m.Called(ctx, ids)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) AddBlocker(ctx context.Context, id bson.ObjectID, blockerID bson.ObjectID) error {
+  args := m.Called(ctx, id, blockerID)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) RemoveBlocker(ctx context.Context, id bson.ObjectID, blockerID bson.ObjectID) error {
+  args := m.Called(ctx, id, blockerID)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) FindBlockedBy(ctx context.Context, blockerIDs []bson.ObjectID) ([]models.Task, error) {
+  args := m.Called(ctx, blockerIDs)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) PullBlockers(ctx context.Context, blockerIDs []bson.ObjectID) error {
+  args := m.Called(ctx, blockerIDs)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) PullWorkspaceMember(ctx context.Context, workspaceID bson.ObjectID, userID bson.ObjectID) error {
+  args := m.Called(ctx, workspaceID, userID)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) AddChecklistItem(ctx context.Context, id bson.ObjectID, item models.ChecklistItem, maxItems int) error {
+  args := m.Called(ctx, id, item, maxItems)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) UpdateChecklistItem(ctx context.Context, id bson.ObjectID, itemID bson.ObjectID, updates bson.M) error {
+  args := m.Called(ctx, id, itemID, updates)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) RemoveChecklistItem(ctx context.Context, id bson.ObjectID, itemID bson.ObjectID) error {
+  args := m.Called(ctx, id, itemID)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) ReorderChecklist(ctx context.Context, id bson.ObjectID, itemIDs []bson.ObjectID) error {
+  args := m.Called(ctx, id, itemIDs)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) AddAttachment(ctx context.Context, id bson.ObjectID, attachment models.Attachment, maxAttachments int) error {
+  args := m.Called(ctx, id, attachment, maxAttachments)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) RemoveAttachment(ctx context.Context, id bson.ObjectID, attachmentID bson.ObjectID) error {
+  args := m.Called(ctx, id, attachmentID)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) Trash(ctx context.Context, id bson.ObjectID, userID bson.ObjectID, deletedAt time.Time) error {
+  args := m.Called(ctx, id, userID, deletedAt)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) TrashByIDs(ctx context.Context, ids []bson.ObjectID, deletedAt time.Time, deletedWith *bson.ObjectID) error {
+  args := m.Called(ctx, ids, deletedAt, deletedWith)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) FindOwned(ctx context.Context, id bson.ObjectID, userID bson.ObjectID) (*models.Task, error) {
   args := m.Called(ctx, id, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) FindTrash(ctx context.Context, userID bson.ObjectID, page int, limit int) ([]models.Task, int64, error) {
+  args := m.Called(ctx, userID, page, limit)
+  if args.Get(0) == nil {
+    return nil, 0, args.Error(2)
+  }
+  return args.Get(0).([]models.Task), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskRepository) FindTrashedWith(ctx context.Context, id bson.ObjectID) ([]models.Task, error) {
+  args := m.Called(ctx, id)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) FindExpired(ctx context.Context, before time.Time, limit int) ([]models.Task, error) {
+  args := m.Called(ctx, before, limit)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) Restore(ctx context.Context, id bson.ObjectID) error {
+  args := m.Called(ctx, id)
+  return args.Error(0)
+}
+
+func (m *MockTaskRepository) BulkUpdate(ctx context.Context, writes []repositories.TaskWrite) ([]error, error) {
+  args := m.Called(ctx, writes)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockTaskRepository) SetIfVersion(ctx context.Context, id bson.ObjectID, version int64, set bson.M) error {
+  args := m.Called(ctx, id, version, set)
   return args.Error(0)
 }
 
+// WithTransaction - runs fn directly, mocks have nothing to roll back
+func (m *MockTaskRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+  m.Called(ctx)
+  return fn(ctx)
+}
+
+func (m *MockTaskRepository) ArchiveCompleted(ctx context.Context, completedBefore time.Time, archivedAt time.Time) (int64, error) {
+  args := m.Called(ctx, completedBefore, archivedAt)
+  return args.Get(0).(int64), args.Error(1)
+}
+
 func TestTaskService_CreateTask(t *testing.T) {
   t.Run("should create task successfully", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     input := types.CreateTaskInput{
@@ -80,7 +267,7 @@ func TestTaskService_CreateTask(t *testing.T) {
 
   t.Run("should handle repository error", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     input := types.CreateTaskInput{
@@ -102,7 +289,7 @@ func TestTaskService_CreateTask(t *testing.T) {
 
   t.Run("should handle context timeout", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     ctx, cancel := context.WithTimeout(context.Background(), 1*time.Nanosecond)
     defer cancel()
@@ -122,12 +309,43 @@ func TestTaskService_CreateTask(t *testing.T) {
     assert.Error(t, err)
     assert.Nil(t, result)
   })
+
+  t.Run("should create task in workspace of member", func(t *testing.T) {
+    mockRepo := new(MockTaskRepository)
+    workspaceRepo := new(MockWorkspaceRepository)
+    service := NewTaskService(mockRepo, workspaceRepo, new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
+
+    userID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+    workspaceRepo.On("FindMember", mock.Anything, workspaceID, userID).Return(membership(workspaceID, userID, types.WorkspaceRoleMember), nil)
+    mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+      return task.WorkspaceID != nil && *task.WorkspaceID == workspaceID && task.UserID == userID
+    })).Return(nil)
+
+    result, err := service.CreateTask(context.Background(), userID, types.CreateTaskInput{Title: "Shared", WorkspaceID: workspaceID.Hex()})
+
+    assert.NoError(t, err)
+    assert.Equal(t, workspaceID.Hex(), result.WorkspaceID)
+    mockRepo.AssertExpectations(t)
+  })
+
+  t.Run("should refuse workspace of non-member", func(t *testing.T) {
+    mockRepo := new(MockTaskRepository)
+    workspaceRepo := new(MockWorkspaceRepository)
+    service := NewTaskService(mockRepo, workspaceRepo, new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
+
+    workspaceRepo.On("FindMember", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrWorkspaceMemberNotFound)
+
+    _, err := service.CreateTask(context.Background(), bson.NewObjectID(), types.CreateTaskInput{Title: "Shared", WorkspaceID: bson.NewObjectID().Hex()})
+
+    assert.ErrorIs(t, err, ErrWorkspaceNotFound)
+    mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+  })
 }
 
 func TestTaskService_GetTask(t *testing.T) {
   t.Run("should get task successfully", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     taskID := bson.NewObjectID()
@@ -144,6 +362,7 @@ func TestTaskService_GetTask(t *testing.T) {
     }
 
     mockRepo.On("FindByID", mock.Anything, taskID, userID).Return(mockTask, nil)
+    mockRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
 
     result, err := service.GetTask(context.Background(), taskID, userID)
 
@@ -157,7 +376,7 @@ func TestTaskService_GetTask(t *testing.T) {
 
   t.Run("should return error when task not found", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     taskID := bson.NewObjectID()
@@ -176,9 +395,22 @@ func TestTaskService_GetTask(t *testing.T) {
 }
 
 func TestTaskService_GetTasks(t *testing.T) {
+  t.Run("should refuse workspace filter of non-member", func(t *testing.T) {
+    mockRepo := new(MockTaskRepository)
+    workspaceRepo := new(MockWorkspaceRepository)
+    service := NewTaskService(mockRepo, workspaceRepo, new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
+
+    workspaceRepo.On("FindMember", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrWorkspaceMemberNotFound)
+
+    _, err := service.GetTasks(context.Background(), bson.NewObjectID(), types.TaskQueryParams{WorkspaceID: bson.NewObjectID().Hex()})
+
+    assert.ErrorIs(t, err, ErrWorkspaceNotFound)
+    mockRepo.AssertNotCalled(t, "FindByUserID", mock.Anything, mock.Anything, mock.Anything)
+  })
+
   t.Run("should get all tasks with pagination", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     query := types.TaskQueryParams{
@@ -193,6 +425,7 @@ func TestTaskService_GetTasks(t *testing.T) {
 
     mockRepo.On("FindByUserID", mock.Anything, userID, query).
       Return(mockTasks, int64(2), nil)
+    mockRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
 
     result, err := service.GetTasks(context.Background(), userID, query)
 
@@ -211,7 +444,7 @@ func TestTaskService_GetTasks(t *testing.T) {
 
   t.Run("should calculate pagination correctly", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     query := types.TaskQueryParams{
@@ -225,6 +458,7 @@ func TestTaskService_GetTasks(t *testing.T) {
 
     mockRepo.On("FindByUserID", mock.Anything, userID, query).
       Return(mockTasks, int64(25), nil)
+    mockRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
 
     result, err := service.GetTasks(context.Background(), userID, query)
 
@@ -240,7 +474,7 @@ func TestTaskService_GetTasks(t *testing.T) {
 
   t.Run("should use default pagination values", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     query := types.TaskQueryParams{} // No page/limit
@@ -259,7 +493,7 @@ func TestTaskService_GetTasks(t *testing.T) {
 
   t.Run("should handle repository error", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     query := types.TaskQueryParams{}
@@ -279,7 +513,7 @@ func TestTaskService_GetTasks(t *testing.T) {
 func TestTaskService_UpdateTask(t *testing.T) {
   t.Run("should update task successfully", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     taskID := bson.NewObjectID()
@@ -301,8 +535,9 @@ func TestTaskService_UpdateTask(t *testing.T) {
       Status: "in_progress",
     }
     mockRepo.On("FindByID", mock.Anything, taskID, userID).Return(updatedTask, nil)
+    mockRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
 
-    result, err := service.UpdateTask(context.Background(), taskID, userID, input)
+    result, err := service.UpdateTask(context.Background(), taskID, userID, input, nil)
 
     assert.NoError(t, err)
     assert.NotNil(t, result)
@@ -314,7 +549,7 @@ func TestTaskService_UpdateTask(t *testing.T) {
 
   t.Run("should set completed_at when status is completed", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     taskID := bson.NewObjectID()
@@ -338,8 +573,9 @@ func TestTaskService_UpdateTask(t *testing.T) {
       CompletedAt: timePtr(time.Now()),
     }
     mockRepo.On("FindByID", mock.Anything, taskID, userID).Return(completedTask, nil)
+    mockRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
 
-    result, err := service.UpdateTask(context.Background(), taskID, userID, input)
+    result, err := service.UpdateTask(context.Background(), taskID, userID, input, nil)
 
     assert.NoError(t, err)
     assert.NotNil(t, result)
@@ -351,7 +587,7 @@ func TestTaskService_UpdateTask(t *testing.T) {
 
   t.Run("should clear completed_at when status changes from completed", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     taskID := bson.NewObjectID()
@@ -374,8 +610,9 @@ func TestTaskService_UpdateTask(t *testing.T) {
       Status: "pending",
     }
     mockRepo.On("FindByID", mock.Anything, taskID, userID).Return(task, nil)
+    mockRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
 
-    _, err := service.UpdateTask(context.Background(), taskID, userID, input)
+    _, err := service.UpdateTask(context.Background(), taskID, userID, input, nil)
 
     assert.NoError(t, err)
     assert.Nil(t, capturedUpdates["completed_at"])
@@ -385,7 +622,7 @@ func TestTaskService_UpdateTask(t *testing.T) {
 
   t.Run("should return error when task not found", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     taskID := bson.NewObjectID()
@@ -393,10 +630,11 @@ func TestTaskService_UpdateTask(t *testing.T) {
     title := "Updated"
     input := types.UpdateTaskInput{Title: &title}
 
+    mockRepo.On("FindByID", mock.Anything, taskID, userID).Return(&models.Task{ID: taskID, UserID: userID}, nil)
     mockRepo.On("Update", mock.Anything, taskID, userID, mock.AnythingOfType("bson.M")).
       Return(errors.New("task not found"))
 
-    result, err := service.UpdateTask(context.Background(), taskID, userID, input)
+    result, err := service.UpdateTask(context.Background(), taskID, userID, input, nil)
 
     assert.Error(t, err)
     assert.Nil(t, result)
@@ -406,7 +644,7 @@ func TestTaskService_UpdateTask(t *testing.T) {
 
   t.Run("should handle partial updates", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     taskID := bson.NewObjectID()
@@ -425,8 +663,9 @@ func TestTaskService_UpdateTask(t *testing.T) {
       Priority: "high",
     }
     mockRepo.On("FindByID", mock.Anything, taskID, userID).Return(task, nil)
+    mockRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
 
-    result, err := service.UpdateTask(context.Background(), taskID, userID, input)
+    result, err := service.UpdateTask(context.Background(), taskID, userID, input, nil)
 
     assert.NoError(t, err)
     assert.Equal(t, "high", result.Priority)
@@ -436,33 +675,36 @@ func TestTaskService_UpdateTask(t *testing.T) {
 }
 
 func TestTaskService_DeleteTask(t *testing.T) {
-  t.Run("should delete task successfully", func(t *testing.T) {
+  t.Run("should move task to the trash successfully", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    purger := new(MockTaskPurger)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), purger)
 
     userID := bson.NewObjectID()
     taskID := bson.NewObjectID()
 
-    mockRepo.On("Delete", mock.Anything, taskID, userID).Return(nil)
+    mockRepo.On("Trash", mock.Anything, taskID, userID, mock.Anything).Return(nil)
+    mockRepo.On("FindChildIDs", mock.Anything, mock.Anything).Return([]bson.ObjectID{}, nil)
 
-    err := service.DeleteTask(context.Background(), taskID, userID)
+    err := service.DeleteTask(context.Background(), taskID, userID, nil)
 
     assert.NoError(t, err)
 
     mockRepo.AssertExpectations(t)
+    purger.AssertNotCalled(t, "PurgeTasks", mock.Anything, mock.Anything)
   })
 
   t.Run("should return error when task not found", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     taskID := bson.NewObjectID()
 
-    mockRepo.On("Delete", mock.Anything, taskID, userID).
+    mockRepo.On("Trash", mock.Anything, taskID, userID, mock.Anything).
       Return(errors.New("task not found"))
 
-    err := service.DeleteTask(context.Background(), taskID, userID)
+    err := service.DeleteTask(context.Background(), taskID, userID, nil)
 
     assert.Error(t, err)
     assert.Equal(t, "task not found", err.Error())
@@ -472,15 +714,15 @@ func TestTaskService_DeleteTask(t *testing.T) {
 
   t.Run("should handle repository error", func(t *testing.T) {
     mockRepo := new(MockTaskRepository)
-    service := NewTaskService(mockRepo)
+    service := NewTaskService(mockRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
 
     userID := bson.NewObjectID()
     taskID := bson.NewObjectID()
 
-    mockRepo.On("Delete", mock.Anything, taskID, userID).
+    mockRepo.On("Trash", mock.Anything, taskID, userID, mock.Anything).
       Return(errors.New("database error"))
 
-    err := service.DeleteTask(context.Background(), taskID, userID)
+    err := service.DeleteTask(context.Background(), taskID, userID, nil)
 
     assert.Error(t, err)
 
@@ -492,3 +734,112 @@ func TestTaskService_DeleteTask(t *testing.T) {
 func timePtr(t time.Time) *time.Time {
   return &t
 }
+
+func TestTaskService_AssignTask(t *testing.T) {
+  t.Run("should assign user to own task", func(t *testing.T) {
+    taskRepo, userRepo := new(MockTaskRepository), new(MockUserRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), userRepo, new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
+    userID, taskID, assigneeID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+    task := &models.Task{ID: taskID, UserID: userID, Title: "Task"}
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(task, nil)
+    userRepo.On("FindByID", mock.Anything, assigneeID).Return(&models.User{ID: assigneeID}, nil)
+    taskRepo.On("AddAssignee", mock.Anything, taskID, userID, assigneeID).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    result, err := service.AssignTask(context.Background(), taskID, userID, assigneeID)
+
+    assert.NoError(t, err)
+    assert.Equal(t, taskID.Hex(), result.ID)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should refuse assignees managing assignees", func(t *testing.T) {
+    taskRepo, userRepo := new(MockTaskRepository), new(MockUserRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), userRepo, new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
+    assigneeID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, assigneeID).
+      Return(&models.Task{ID: taskID, UserID: bson.NewObjectID(), AssigneeIDs: []bson.ObjectID{assigneeID}}, nil)
+
+    _, err := service.AssignTask(context.Background(), taskID, assigneeID, bson.NewObjectID())
+
+    assert.ErrorIs(t, err, ErrTaskOwnerRequired)
+    taskRepo.AssertNotCalled(t, "AddAssignee", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should only assign workspace members to workspace tasks", func(t *testing.T) {
+    taskRepo, workspaceRepo, userRepo := new(MockTaskRepository), new(MockWorkspaceRepository), new(MockUserRepository)
+    service := NewTaskService(taskRepo, workspaceRepo, userRepo, new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
+    userID, taskID, outsiderID, workspaceID := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).
+      Return(&models.Task{ID: taskID, UserID: bson.NewObjectID(), WorkspaceID: &workspaceID}, nil)
+    workspaceRepo.On("FindMember", mock.Anything, workspaceID, userID).Return(membership(workspaceID, userID, types.WorkspaceRoleMember), nil)
+    userRepo.On("FindByID", mock.Anything, outsiderID).Return(&models.User{ID: outsiderID}, nil)
+    workspaceRepo.On("FindMember", mock.Anything, workspaceID, outsiderID).Return(nil, repositories.ErrWorkspaceMemberNotFound)
+
+    _, err := service.AssignTask(context.Background(), taskID, userID, outsiderID)
+
+    assert.ErrorIs(t, err, ErrAssigneeNotMember)
+    taskRepo.AssertNotCalled(t, "AddAssignee", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should return not found for invisible task", func(t *testing.T) {
+    taskRepo := new(MockTaskRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
+
+    taskRepo.On("FindByID", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrTaskNotFound)
+
+    _, err := service.AssignTask(context.Background(), bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID())
+
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+  })
+}
+
+func TestTaskService_UnassignTask(t *testing.T) {
+  t.Run("should let assignees unassign themselves", func(t *testing.T) {
+    taskRepo := new(MockTaskRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
+    assigneeID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, assigneeID).
+      Return(&models.Task{ID: taskID, UserID: bson.NewObjectID(), AssigneeIDs: []bson.ObjectID{assigneeID}}, nil)
+    taskRepo.On("RemoveAssignee", mock.Anything, taskID, assigneeID, assigneeID).Return(nil)
+
+    result, err := service.UnassignTask(context.Background(), taskID, assigneeID, assigneeID)
+
+    assert.NoError(t, err)
+    assert.Empty(t, result.AssigneeIDs)
+    taskRepo.AssertExpectations(t)
+  })
+}
+
+func TestTaskService_WatchTask(t *testing.T) {
+  t.Run("should watch visible task", func(t *testing.T) {
+    taskRepo := new(MockTaskRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("AddWatcher", mock.Anything, taskID, userID).Return(nil)
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).
+      Return(&models.Task{ID: taskID, UserID: userID, WatcherIDs: []bson.ObjectID{userID}}, nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    result, err := service.WatchTask(context.Background(), taskID, userID)
+
+    assert.NoError(t, err)
+    assert.Equal(t, []string{userID.Hex()}, result.WatcherIDs)
+  })
+
+  t.Run("should return not found for invisible task", func(t *testing.T) {
+    taskRepo := new(MockTaskRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), new(MockTaskPurger))
+
+    taskRepo.On("AddWatcher", mock.Anything, mock.Anything, mock.Anything).Return(repositories.ErrTaskNotFound)
+
+    _, err := service.WatchTask(context.Background(), bson.NewObjectID(), bson.NewObjectID())
+
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+  })
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// Task share errors that handlers map to specific HTTP statuses
+var (
+  ErrTaskShareNotFound    = errors.New("task share not found")
+  ErrTaskPermissionDenied = errors.New("task permission denied")
+  ErrCannotShareWithSelf  = errors.New("cannot share task with yourself")
+)
+
+// sharePermissionLevels - rank of share permissions, higher includes lower
+var sharePermissionLevels = map[string]int{
+  types.SharePermissionView:  1,
+  types.SharePermissionEdit:  2,
+  types.SharePermissionOwner: 3,
+}
+
+// ShareTask - grant another user access to a task by email, sharing again changes the permission
+func (s *taskService) ShareTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, input types.ShareTaskInput) (*types.TaskShareResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if err := s.requireShareManager(ctx, taskID, userID); err != nil {
+    return nil, err
+  }
+
+  user, err := s.userRepo.FindByEmail(ctx, normalizeEmail(input.Email))
+  if err != nil {
+    if errors.Is(err, repositories.ErrUserNotFound) {
+      return nil, ErrUserNotFound
+    }
+    return nil, err
+  }
+  if user.ID == userID {
+    return nil, ErrCannotShareWithSelf
+  }
+
+  share := &models.TaskShare{
+    TaskID:     taskID,
+    UserID:     user.ID,
+    Permission: input.Permission,
+    SharedBy:   userID,
+  }
+  if err := s.shareRepo.Upsert(ctx, share); err != nil {
+    return nil, err
+  }
+
+  response := types.ToTaskShareResponse(share, user)
+  return &response, nil
+}
+
+// GetTaskShares - users a task is shared with
+func (s *taskService) GetTaskShares(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) ([]types.TaskShareResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  if err := s.requireShareManager(ctx, taskID, userID); err != nil {
+    return nil, err
+  }
+
+  shares, err := s.shareRepo.FindByTask(ctx, taskID)
+  if err != nil {
+    return nil, err
+  }
+
+  responses := make([]types.TaskShareResponse, 0, len(shares))
+  for i := range shares {
+    user, err := s.userRepo.FindByID(ctx, shares[i].UserID)
+    if err != nil {
+      // Shares of deleted accounts are not listed
+      if errors.Is(err, repositories.ErrUserNotFound) {
+        continue
+      }
+      return nil, err
+    }
+    responses = append(responses, types.ToTaskShareResponse(&shares[i], user))
+  }
+  return responses, nil
+}
+
+// RevokeTaskShare - remove a share, users may always give up their own share
+func (s *taskService) RevokeTaskShare(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, sharedWithID bson.ObjectID) error {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if sharedWithID != userID {
+    if err := s.requireShareManager(ctx, taskID, userID); err != nil {
+      return err
+    }
+  }
+
+  if err := s.shareRepo.Delete(ctx, taskID, sharedWithID); err != nil {
+    if errors.Is(err, repositories.ErrTaskShareNotFound) {
+      return ErrTaskShareNotFound
+    }
+    return err
+  }
+  return nil
+}
+
+// GetSharedTasks - tasks shared with the user, newest share first
+func (s *taskService) GetSharedTasks(ctx context.Context, userID bson.ObjectID, query types.SharedTaskQueryParams) (*types.SharedTaskListResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  page := 1
+  if query.Page > 0 {
+    page = query.Page
+  }
+
+  limit := 10
+  if query.Limit > 0 {
+    limit = query.Limit
+  }
+
+  shares, total, err := s.shareRepo.FindByUser(ctx, userID, page, limit)
+  if err != nil {
+    return nil, err
+  }
+
+  responses := []types.SharedTaskResponse{}
+  if len(shares) > 0 {
+    ids := make([]bson.ObjectID, len(shares))
+    for i, share := range shares {
+      ids[i] = share.TaskID
+    }
+
+    tasks, err := s.taskRepo.FindByIDs(ctx, ids)
+    if err != nil {
+      return nil, err
+    }
+
+    byID := make(map[bson.ObjectID]*models.Task, len(tasks))
+    for i := range tasks {
+      byID[tasks[i].ID] = &tasks[i]
+    }
+
+    // Keep share order, skip shares of tasks deleted meanwhile
+    for i := range shares {
+      if task, ok := byID[shares[i].TaskID]; ok {
+        responses = append(responses, types.ToSharedTaskResponse(task, &shares[i]))
+      }
+    }
+  }
+
+  totalPages := int(total) / limit
+  if int(total)%limit != 0 {
+    totalPages++
+  }
+
+  return &types.SharedTaskListResponse{
+    Tasks: responses,
+    Meta: types.PaginationMeta{
+      Page:        page,
+      Limit:       limit,
+      Total:       total,
+      TotalPages:  totalPages,
+      HasNextPage: page < totalPages,
+      HasPrevPage: page > 1,
+    },
+  }, nil
+}
+
+// sharedTask - task shared with the user at the required permission, notFound is returned without a share
+func (s *taskService) sharedTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, required string, notFound error) (*models.Task, error) {
+  return findSharedTask(ctx, s.taskRepo, s.shareRepo, taskID, userID, required, notFound)
+}
+
+// findSharedTask - sharedTask for services holding only the task and share repositories
+func findSharedTask(ctx context.Context, taskRepo repositories.TaskRepository, shareRepo repositories.TaskShareRepository, taskID bson.ObjectID, userID bson.ObjectID, required string, notFound error) (*models.Task, error) {
+  if err := checkShare(ctx, shareRepo, taskID, userID, required, notFound); err != nil {
+    return nil, err
+  }
+
+  tasks, err := taskRepo.FindByIDs(ctx, []bson.ObjectID{taskID})
+  if err != nil {
+    return nil, err
+  }
+  if len(tasks) == 0 {
+    return nil, notFound
+  }
+  return &tasks[0], nil
+}
+
+// requireShare - user needs a share of at least the required permission,
+// notFound is returned without a share so shared-only access is not revealed
+func (s *taskService) requireShare(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, required string, notFound error) error {
+  return checkShare(ctx, s.shareRepo, taskID, userID, required, notFound)
+}
+
+// checkShare - requireShare for services holding only the share repository
+func checkShare(ctx context.Context, shareRepo repositories.TaskShareRepository, taskID bson.ObjectID, userID bson.ObjectID, required string, notFound error) error {
+  share, err := shareRepo.FindByTaskAndUser(ctx, taskID, userID)
+  if err != nil {
+    if errors.Is(err, repositories.ErrTaskShareNotFound) {
+      return notFound
+    }
+    return err
+  }
+
+  if sharePermissionLevels[share.Permission] < sharePermissionLevels[required] {
+    return ErrTaskPermissionDenied
+  }
+  return nil
+}
+
+// requireShareManager - shares are managed by the task owner, workspace members and owner shares
+func (s *taskService) requireShareManager(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) error {
+  notFound := ErrTaskNotFound
+
+  task, err := s.taskRepo.FindByID(ctx, taskID, userID)
+  switch {
+  case err == nil:
+    err = s.requireTaskOwner(ctx, task, userID)
+    if !errors.Is(err, ErrTaskOwnerRequired) {
+      return err
+    }
+    // Visible through assignment only
+    notFound = ErrTaskOwnerRequired
+  case !errors.Is(err, repositories.ErrTaskNotFound):
+    return err
+  }
+
+  return s.requireShare(ctx, taskID, userID, types.SharePermissionOwner, notFound)
+}
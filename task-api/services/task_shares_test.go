@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// MockTaskShareRepository mocks the TaskShareRepository interface
+type MockTaskShareRepository struct {
+  mock.Mock
+}
+
+func (m *MockTaskShareRepository) Upsert(ctx context.Context, share *models.TaskShare) error {
+  args := m.Called(ctx, share)
+  return args.Error(0)
+}
+
+func (m *MockTaskShareRepository) FindByTaskAndUser(ctx context.Context, taskID, userID bson.ObjectID) (*models.TaskShare, error) {
+  args := m.Called(ctx, taskID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*models.TaskShare), args.Error(1)
+}
+
+func (m *MockTaskShareRepository) FindByTask(ctx context.Context, taskID bson.ObjectID) ([]models.TaskShare, error) {
+  args := m.Called(ctx, taskID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]models.TaskShare), args.Error(1)
+}
+
+func (m *MockTaskShareRepository) FindByUser(ctx context.Context, userID bson.ObjectID, page, limit int) ([]models.TaskShare, int64, error) {
+  args := m.Called(ctx, userID, page, limit)
+  if args.Get(0) == nil {
+    return nil, 0, args.Error(2)
+  }
+  return args.Get(0).([]models.TaskShare), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskShareRepository) Delete(ctx context.Context, taskID, userID bson.ObjectID) error {
+  args := m.Called(ctx, taskID, userID)
+  return args.Error(0)
+}
+
+func (m *MockTaskShareRepository) DeleteByTask(ctx context.Context, taskID bson.ObjectID) error {
+  args := m.Called(ctx, taskID)
+  return args.Error(0)
+}
+
+// taskShare - share of task with user at permission
+func taskShare(taskID, userID bson.ObjectID, permission string) *models.TaskShare {
+  return &models.TaskShare{ID: bson.NewObjectID(), TaskID: taskID, UserID: userID, Permission: permission, SharedBy: bson.NewObjectID()}
+}
+
+// newTestShareTaskService - task service with mocked task, user and share repositories
+func newTestShareTaskService() (TaskService, *MockTaskRepository, *MockUserRepository, *MockTaskShareRepository) {
+  taskRepo, userRepo, shareRepo := new(MockTaskRepository), new(MockUserRepository), new(MockTaskShareRepository)
+  return NewTaskService(taskRepo, new(MockWorkspaceRepository), userRepo, shareRepo, newMockTaskEventRepository(), new(MockTaskPurger)), taskRepo, userRepo, shareRepo
+}
+
+func TestTaskService_ShareTask(t *testing.T) {
+  t.Run("should share own task by email", func(t *testing.T) {
+    service, taskRepo, userRepo, shareRepo := newTestShareTaskService()
+    ownerID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    colleague := &models.User{ID: bson.NewObjectID(), Email: "colleague@test.com"}
+
+    taskRepo.On("FindByID", mock.Anything, taskID, ownerID).Return(&models.Task{ID: taskID, UserID: ownerID}, nil)
+    userRepo.On("FindByEmail", mock.Anything, "colleague@test.com").Return(colleague, nil)
+    shareRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(share *models.TaskShare) bool {
+      return share.TaskID == taskID && share.UserID == colleague.ID && share.Permission == types.SharePermissionEdit && share.SharedBy == ownerID
+    })).Return(nil)
+
+    result, err := service.ShareTask(context.Background(), taskID, ownerID, types.ShareTaskInput{Email: "Colleague@test.com", Permission: types.SharePermissionEdit})
+
+    assert.NoError(t, err)
+    assert.Equal(t, colleague.ID.Hex(), result.UserID)
+    shareRepo.AssertExpectations(t)
+  })
+
+  t.Run("should let owner shares reshare", func(t *testing.T) {
+    service, taskRepo, userRepo, shareRepo := newTestShareTaskService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(taskShare(taskID, userID, types.SharePermissionOwner), nil)
+    userRepo.On("FindByEmail", mock.Anything, mock.Anything).Return(&models.User{ID: bson.NewObjectID()}, nil)
+    shareRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+    _, err := service.ShareTask(context.Background(), taskID, userID, types.ShareTaskInput{Email: "other@test.com", Permission: types.SharePermissionView})
+
+    assert.NoError(t, err)
+  })
+
+  t.Run("should refuse edit shares managing shares", func(t *testing.T) {
+    service, taskRepo, userRepo, shareRepo := newTestShareTaskService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(taskShare(taskID, userID, types.SharePermissionEdit), nil)
+
+    _, err := service.ShareTask(context.Background(), taskID, userID, types.ShareTaskInput{Email: "other@test.com", Permission: types.SharePermissionView})
+
+    assert.ErrorIs(t, err, ErrTaskPermissionDenied)
+    userRepo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should hide task without any access", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+
+    taskRepo.On("FindByID", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrTaskShareNotFound)
+
+    _, err := service.ShareTask(context.Background(), bson.NewObjectID(), bson.NewObjectID(), types.ShareTaskInput{Email: "other@test.com", Permission: types.SharePermissionView})
+
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+  })
+
+  t.Run("should refuse sharing with yourself", func(t *testing.T) {
+    service, taskRepo, userRepo, shareRepo := newTestShareTaskService()
+    ownerID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, ownerID).Return(&models.Task{ID: taskID, UserID: ownerID}, nil)
+    userRepo.On("FindByEmail", mock.Anything, mock.Anything).Return(&models.User{ID: ownerID}, nil)
+
+    _, err := service.ShareTask(context.Background(), taskID, ownerID, types.ShareTaskInput{Email: "me@test.com", Permission: types.SharePermissionView})
+
+    assert.ErrorIs(t, err, ErrCannotShareWithSelf)
+    shareRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+  })
+}
+
+func TestTaskService_SharedAccess(t *testing.T) {
+  t.Run("should read task shared with view permission", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(taskShare(taskID, userID, types.SharePermissionView), nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{taskID}).Return([]models.Task{{ID: taskID, Title: "Shared"}}, nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    result, err := service.GetTask(context.Background(), taskID, userID)
+
+    assert.NoError(t, err)
+    assert.Equal(t, "Shared", result.Title)
+  })
+
+  t.Run("should refuse updates with view permission", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    title := "Changed"
+
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(taskShare(taskID, userID, types.SharePermissionView), nil)
+
+    _, err := service.UpdateTask(context.Background(), taskID, userID, types.UpdateTaskInput{Title: &title}, nil)
+
+    assert.ErrorIs(t, err, ErrTaskPermissionDenied)
+    taskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should update with edit permission", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+    title := "Changed"
+    share := taskShare(taskID, userID, types.SharePermissionEdit)
+
+    taskRepo.On("Update", mock.Anything, taskID, userID, mock.Anything).Return(repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(share, nil)
+    taskRepo.On("UpdateByID", mock.Anything, taskID, mock.AnythingOfType("bson.M")).Return(nil)
+    taskRepo.On("FindByID", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskNotFound)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{taskID}).Return([]models.Task{{ID: taskID, Title: title}}, nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    result, err := service.UpdateTask(context.Background(), taskID, userID, types.UpdateTaskInput{Title: &title}, nil)
+
+    assert.NoError(t, err)
+    assert.Equal(t, title, result.Title)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should refuse delete with edit permission", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("Trash", mock.Anything, taskID, userID, mock.Anything).Return(repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(taskShare(taskID, userID, types.SharePermissionEdit), nil)
+
+    err := service.DeleteTask(context.Background(), taskID, userID, nil)
+
+    assert.ErrorIs(t, err, ErrTaskPermissionDenied)
+    taskRepo.AssertNotCalled(t, "TrashByIDs", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should trash with owner permission and keep shares", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("Trash", mock.Anything, taskID, userID, mock.Anything).Return(repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(taskShare(taskID, userID, types.SharePermissionOwner), nil)
+    taskRepo.On("TrashByIDs", mock.Anything, []bson.ObjectID{taskID}, mock.Anything, (*bson.ObjectID)(nil)).Return(nil)
+    taskRepo.On("FindChildIDs", mock.Anything, mock.Anything).Return([]bson.ObjectID{}, nil)
+
+    err := service.DeleteTask(context.Background(), taskID, userID, nil)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+    shareRepo.AssertExpectations(t)
+    shareRepo.AssertNotCalled(t, "DeleteByTask", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should keep not found without share", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+
+    taskRepo.On("Trash", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrTaskShareNotFound)
+
+    err := service.DeleteTask(context.Background(), bson.NewObjectID(), bson.NewObjectID(), nil)
+
+    assert.ErrorIs(t, err, repositories.ErrTaskNotFound)
+  })
+}
+
+func TestTaskService_RevokeTaskShare(t *testing.T) {
+  t.Run("should let users give up their own share", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    shareRepo.On("Delete", mock.Anything, taskID, userID).Return(nil)
+
+    err := service.RevokeTaskShare(context.Background(), taskID, userID, userID)
+
+    assert.NoError(t, err)
+    taskRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should return not found for missing share", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    ownerID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindByID", mock.Anything, taskID, ownerID).Return(&models.Task{ID: taskID, UserID: ownerID}, nil)
+    shareRepo.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(repositories.ErrTaskShareNotFound)
+
+    err := service.RevokeTaskShare(context.Background(), taskID, ownerID, bson.NewObjectID())
+
+    assert.ErrorIs(t, err, ErrTaskShareNotFound)
+  })
+}
+
+func TestTaskService_GetSharedTasks(t *testing.T) {
+  t.Run("should list shared tasks in share order skipping deleted tasks", func(t *testing.T) {
+    service, taskRepo, _, shareRepo := newTestShareTaskService()
+    userID := bson.NewObjectID()
+    first, second, deleted := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+
+    shareRepo.On("FindByUser", mock.Anything, userID, 1, 10).Return([]models.TaskShare{
+      *taskShare(first, userID, types.SharePermissionEdit),
+      *taskShare(deleted, userID, types.SharePermissionView),
+      *taskShare(second, userID, types.SharePermissionView),
+    }, int64(3), nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{first, deleted, second}).
+      Return([]models.Task{{ID: second, Title: "Second"}, {ID: first, Title: "First"}}, nil)
+
+    result, err := service.GetSharedTasks(context.Background(), userID, types.SharedTaskQueryParams{})
+
+    assert.NoError(t, err)
+    assert.Len(t, result.Tasks, 2)
+    assert.Equal(t, "First", result.Tasks[0].Title)
+    assert.Equal(t, types.SharePermissionEdit, result.Tasks[0].Permission)
+    assert.Equal(t, int64(3), result.Meta.Total)
+  })
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// ErrVersionMismatch - the task changed since the version the client based its write on
+var ErrVersionMismatch = errors.New("task version mismatch")
+
+// TaskVersionError - write refused, Current is the task as it is now so clients can merge
+type TaskVersionError struct {
+  Current *types.TaskResponse
+}
+
+func (e *TaskVersionError) Error() string {
+  return ErrVersionMismatch.Error()
+}
+
+func (e *TaskVersionError) Unwrap() error {
+  return ErrVersionMismatch
+}
+
+// checkVersion - nil without versions (no If-Match) or when the task is at one of them
+func (s *taskService) checkVersion(ctx context.Context, task *models.Task, versions []int64) error {
+  if versions == nil || slices.Contains(versions, task.Version) {
+    return nil
+  }
+  return s.versionMismatch(ctx, task)
+}
+
+// versionMismatch - TaskVersionError carrying task
+func (s *taskService) versionMismatch(ctx context.Context, task *models.Task) error {
+  responses, err := s.toResponses(ctx, []models.Task{*task})
+  if err != nil {
+    return err
+  }
+  return &TaskVersionError{Current: &responses[0]}
+}
+
+// setIfVersion - set fields of a task the user was authorized for while it is still at version,
+// a write in between is reported with the task as it is now
+func (s *taskService) setIfVersion(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, version int64, set bson.M) error {
+  err := s.taskRepo.SetIfVersion(ctx, taskID, version, set)
+  if !errors.Is(err, repositories.ErrVersionConflict) {
+    return err
+  }
+
+  // Trashed meanwhile, the task is gone for the client too
+  task, err := s.accessibleTask(ctx, taskID, userID, types.SharePermissionView)
+  if err != nil {
+    return err
+  }
+  return s.versionMismatch(ctx, task)
+}
+
+// trashIfVersion - move a task the user may delete to the trash, only while it is at one of versions
+func (s *taskService) trashIfVersion(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, versions []int64, now time.Time) error {
+  // Found like Trash finds it, so the header does not change the answer for users who may not delete
+  task, err := s.taskRepo.FindOwned(ctx, taskID, userID)
+  if err == nil && task.DeletedAt != nil {
+    err = repositories.ErrTaskNotFound
+  }
+  if errors.Is(err, repositories.ErrTaskNotFound) {
+    // Tasks shared with owner permission
+    task, err = s.sharedTask(ctx, taskID, userID, types.SharePermissionOwner, ErrTaskNotFound)
+  }
+  if err != nil {
+    return err
+  }
+  if err := s.checkVersion(ctx, task, versions); err != nil {
+    return err
+  }
+  return s.setIfVersion(ctx, taskID, userID, task.Version, bson.M{"deleted_at": now})
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+func TestTaskService_UpdateTaskVersion(t *testing.T) {
+  t.Run("should refuse a stale version with the current task", func(t *testing.T) {
+    service, taskRepo, _ := newTestTrashService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Title: "Theirs", Version: 3}
+    title := "Mine"
+    versions := []int64{2}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{Title: &title}, versions)
+
+    var versionErr *TaskVersionError
+    assert.ErrorAs(t, err, &versionErr)
+    assert.ErrorIs(t, err, ErrVersionMismatch)
+    assert.Equal(t, int64(3), versionErr.Current.Version)
+    assert.Equal(t, "Theirs", versionErr.Current.Title)
+    taskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    taskRepo.AssertNotCalled(t, "SetIfVersion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should write only at the listed version the task is at", func(t *testing.T) {
+    service, taskRepo, _ := newTestTrashService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Version: 2}
+    title := "Mine"
+    versions := []int64{1, 2}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("SetIfVersion", mock.Anything, task.ID, int64(2), mock.MatchedBy(func(set bson.M) bool {
+      return set["title"] == "Mine"
+    })).Return(nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{Title: &title}, versions)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+    taskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should report a write that got in between", func(t *testing.T) {
+    service, taskRepo, _ := newTestTrashService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Version: 2}
+    changed := &models.Task{ID: task.ID, UserID: userID, Version: 3}
+    title := "Mine"
+    versions := []int64{2}
+
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(task, nil).Once()
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(changed, nil).Once()
+    taskRepo.On("SetIfVersion", mock.Anything, task.ID, int64(2), mock.Anything).Return(repositories.ErrVersionConflict)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    _, err := service.UpdateTask(context.Background(), task.ID, userID, types.UpdateTaskInput{Title: &title}, versions)
+
+    var versionErr *TaskVersionError
+    assert.ErrorAs(t, err, &versionErr)
+    assert.Equal(t, int64(3), versionErr.Current.Version)
+  })
+}
+
+func TestTaskService_DeleteTaskVersion(t *testing.T) {
+  t.Run("should trash the task at the version the client read", func(t *testing.T) {
+    service, taskRepo, _ := newTestTrashService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Version: 4}
+    versions := []int64{4}
+
+    taskRepo.On("FindOwned", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("SetIfVersion", mock.Anything, task.ID, int64(4), mock.MatchedBy(func(set bson.M) bool {
+      _, ok := set["deleted_at"].(time.Time)
+      return ok
+    })).Return(nil)
+    taskRepo.On("FindChildIDs", mock.Anything, mock.Anything).Return([]bson.ObjectID{}, nil)
+
+    err := service.DeleteTask(context.Background(), task.ID, userID, versions)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+    taskRepo.AssertNotCalled(t, "Trash", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should keep a task that changed since", func(t *testing.T) {
+    service, taskRepo, _ := newTestTrashService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, Version: 5}
+    versions := []int64{4}
+
+    taskRepo.On("FindOwned", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    err := service.DeleteTask(context.Background(), task.ID, userID, versions)
+
+    assert.ErrorIs(t, err, ErrVersionMismatch)
+    taskRepo.AssertNotCalled(t, "SetIfVersion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    taskRepo.AssertNotCalled(t, "Trash", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+  t.Run("should answer assignees like without If-Match", func(t *testing.T) {
+    taskRepo, shareRepo := new(MockTaskRepository), new(MockTaskShareRepository)
+    service := NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), shareRepo, newMockTaskEventRepository(), new(MockTaskPurger))
+    userID, taskID := bson.NewObjectID(), bson.NewObjectID()
+
+    taskRepo.On("FindOwned", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskNotFound)
+    shareRepo.On("FindByTaskAndUser", mock.Anything, taskID, userID).Return(nil, repositories.ErrTaskShareNotFound)
+
+    err := service.DeleteTask(context.Background(), taskID, userID, []int64{1})
+
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+    taskRepo.AssertNotCalled(t, "SetIfVersion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+  })
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// PurgeTask - delete a task and its subtasks for good, from the trash or directly
+func (s *taskService) PurgeTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID, versions []int64) error {
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  task, err := s.taskRepo.FindOwned(ctx, taskID, userID)
+  if errors.Is(err, repositories.ErrTaskNotFound) {
+    // Tasks shared with owner permission, shares do not reach into the trash
+    task, err = s.sharedTask(ctx, taskID, userID, types.SharePermissionOwner, err)
+  }
+  if err != nil {
+    return err
+  }
+
+  // Subtasks trashed with an ancestor are purged through it
+  if task.DeletedWith != nil {
+    return ErrTaskNotFound
+  }
+  if err := s.checkVersion(ctx, task, versions); err != nil {
+    return err
+  }
+
+  tasks := []models.Task{*task}
+  if task.DeletedAt != nil {
+    trashed, err := s.taskRepo.FindTrashedWith(ctx, task.ID)
+    if err != nil {
+      return err
+    }
+    tasks = append(tasks, trashed...)
+  } else {
+    ids, err := s.descendantIDs(ctx, task.ID)
+    if err != nil {
+      return err
+    }
+    if len(ids) > 0 {
+      children, err := s.taskRepo.FindByIDs(ctx, ids)
+      if err != nil {
+        return err
+      }
+      tasks = append(tasks, children...)
+    }
+  }
+
+  return s.purger.PurgeTasks(ctx, tasks)
+}
+
+// GetTrash - trashed tasks the user owns, most recently deleted first
+func (s *taskService) GetTrash(ctx context.Context, userID bson.ObjectID, query types.TrashQueryParams) (*types.TaskListResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  page := 1
+  if query.Page > 0 {
+    page = query.Page
+  }
+
+  limit := 10
+  if query.Limit > 0 {
+    limit = query.Limit
+  }
+
+  tasks, total, err := s.taskRepo.FindTrash(ctx, userID, page, limit)
+  if err != nil {
+    return nil, err
+  }
+
+  responses := types.ToTaskResponseList(tasks)
+
+  totalPages := int(total) / limit
+  if int(total)%limit != 0 {
+    totalPages++
+  }
+
+  return &types.TaskListResponse{
+    Tasks: responses,
+    Meta: types.PaginationMeta{
+      Page:        page,
+      Limit:       limit,
+      Total:       total,
+      TotalPages:  totalPages,
+      HasNextPage: page < totalPages,
+      HasPrevPage: page > 1,
+    },
+  }, nil
+}
+
+// RestoreTask - take a task and the subtasks deleted with it out of the trash
+func (s *taskService) RestoreTask(ctx context.Context, taskID bson.ObjectID, userID bson.ObjectID) (*types.TaskResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  task, err := s.taskRepo.FindOwned(ctx, taskID, userID)
+  if err != nil {
+    return nil, mapTaskNotFound(err)
+  }
+
+  // Subtasks trashed with an ancestor come back with it
+  if task.DeletedWith != nil {
+    return nil, ErrTaskNotFound
+  }
+  if task.DeletedAt == nil {
+    return nil, ErrTaskNotTrashed
+  }
+
+  if err := s.taskRepo.Restore(ctx, taskID); err != nil {
+    return nil, mapTaskNotFound(err)
+  }
+
+  // A subtask whose parent is gone or still in the trash comes back at the top level
+  if task.ParentID != nil {
+    parents, err := s.taskRepo.FindByIDs(ctx, []bson.ObjectID{*task.ParentID})
+    if err != nil {
+      return nil, err
+    }
+    if len(parents) == 0 {
+      if err := s.taskRepo.UpdateByID(ctx, taskID, bson.M{"parent_id": nil}); err != nil {
+        return nil, err
+      }
+    }
+  }
+  s.recordEvent(ctx, taskID, userID, types.TaskEventRestored, nil)
+
+  return s.GetTask(ctx, taskID, userID)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/storage"
+	"task-api/types"
+)
+
+// MockTaskPurger - mock for TaskPurger
+type MockTaskPurger struct {
+  mock.Mock
+}
+
+func (m *MockTaskPurger) PurgeTasks(ctx context.Context, tasks []models.Task) error {
+  args := m.Called(ctx, tasks)
+  return args.Error(0)
+}
+
+func (m *MockTaskPurger) PurgeExpired(ctx context.Context) (int, error) {
+  args := m.Called(ctx)
+  return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskPurger) Run(ctx context.Context) {
+  m.Called(ctx)
+}
+
+func newTestTrashService() (TaskService, *MockTaskRepository, *MockTaskPurger) {
+  taskRepo, purger := new(MockTaskRepository), new(MockTaskPurger)
+  return NewTaskService(taskRepo, new(MockWorkspaceRepository), new(MockUserRepository), new(MockTaskShareRepository), newMockTaskEventRepository(), purger), taskRepo, purger
+}
+
+func TestTaskService_PurgeTask(t *testing.T) {
+  t.Run("should purge a trashed task with the subtasks deleted with it", func(t *testing.T) {
+    service, taskRepo, purger := newTestTrashService()
+    userID := bson.NewObjectID()
+    deletedAt := time.Now()
+    task := models.Task{ID: bson.NewObjectID(), UserID: userID, DeletedAt: &deletedAt}
+    child := models.Task{ID: bson.NewObjectID(), UserID: userID, DeletedAt: &deletedAt, DeletedWith: &task.ID}
+
+    taskRepo.On("FindOwned", mock.Anything, task.ID, userID).Return(&task, nil)
+    taskRepo.On("FindTrashedWith", mock.Anything, task.ID).Return([]models.Task{child}, nil)
+    purger.On("PurgeTasks", mock.Anything, []models.Task{task, child}).Return(nil)
+
+    err := service.PurgeTask(context.Background(), task.ID, userID, nil)
+
+    assert.NoError(t, err)
+    purger.AssertExpectations(t)
+  })
+
+  t.Run("should purge a live task with its subtasks", func(t *testing.T) {
+    service, taskRepo, purger := newTestTrashService()
+    userID := bson.NewObjectID()
+    task := models.Task{ID: bson.NewObjectID(), UserID: userID}
+    child := models.Task{ID: bson.NewObjectID(), UserID: userID, ParentID: &task.ID}
+
+    taskRepo.On("FindOwned", mock.Anything, task.ID, userID).Return(&task, nil)
+    taskRepo.On("FindChildIDs", mock.Anything, []bson.ObjectID{task.ID}).Return([]bson.ObjectID{child.ID}, nil)
+    taskRepo.On("FindChildIDs", mock.Anything, []bson.ObjectID{child.ID}).Return([]bson.ObjectID{}, nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{child.ID}).Return([]models.Task{child}, nil)
+    purger.On("PurgeTasks", mock.Anything, []models.Task{task, child}).Return(nil)
+
+    err := service.PurgeTask(context.Background(), task.ID, userID, nil)
+
+    assert.NoError(t, err)
+    purger.AssertExpectations(t)
+  })
+
+  t.Run("should hide subtasks trashed with their parent", func(t *testing.T) {
+    service, taskRepo, purger := newTestTrashService()
+    userID, parentID := bson.NewObjectID(), bson.NewObjectID()
+    deletedAt := time.Now()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, DeletedAt: &deletedAt, DeletedWith: &parentID}
+
+    taskRepo.On("FindOwned", mock.Anything, task.ID, userID).Return(task, nil)
+
+    err := service.PurgeTask(context.Background(), task.ID, userID, nil)
+
+    assert.ErrorIs(t, err, ErrTaskNotFound)
+    purger.AssertNotCalled(t, "PurgeTasks", mock.Anything, mock.Anything)
+  })
+}
+
+func TestTaskService_GetTrash(t *testing.T) {
+  t.Run("should page trashed tasks", func(t *testing.T) {
+    service, taskRepo, _ := newTestTrashService()
+    userID := bson.NewObjectID()
+    deletedAt := time.Now()
+    tasks := []models.Task{{ID: bson.NewObjectID(), UserID: userID, Title: "Old", DeletedAt: &deletedAt}}
+
+    taskRepo.On("FindTrash", mock.Anything, userID, 2, 1).Return(tasks, int64(3), nil)
+
+    result, err := service.GetTrash(context.Background(), userID, types.TrashQueryParams{Page: 2, Limit: 1})
+
+    assert.NoError(t, err)
+    assert.Len(t, result.Tasks, 1)
+    assert.NotNil(t, result.Tasks[0].DeletedAt)
+    assert.Equal(t, 3, result.Meta.TotalPages)
+    assert.True(t, result.Meta.HasNextPage)
+    assert.True(t, result.Meta.HasPrevPage)
+  })
+}
+
+func TestTaskService_RestoreTask(t *testing.T) {
+  t.Run("should refuse tasks that are not in the trash", func(t *testing.T) {
+    service, taskRepo, _ := newTestTrashService()
+    userID := bson.NewObjectID()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID}
+
+    taskRepo.On("FindOwned", mock.Anything, task.ID, userID).Return(task, nil)
+
+    _, err := service.RestoreTask(context.Background(), task.ID, userID)
+
+    assert.ErrorIs(t, err, ErrTaskNotTrashed)
+    taskRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should move a subtask to the top level when its parent is gone", func(t *testing.T) {
+    service, taskRepo, _ := newTestTrashService()
+    userID, parentID := bson.NewObjectID(), bson.NewObjectID()
+    deletedAt := time.Now()
+    task := &models.Task{ID: bson.NewObjectID(), UserID: userID, ParentID: &parentID, DeletedAt: &deletedAt}
+
+    taskRepo.On("FindOwned", mock.Anything, task.ID, userID).Return(task, nil)
+    taskRepo.On("Restore", mock.Anything, task.ID).Return(nil)
+    taskRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{parentID}).Return([]models.Task{}, nil)
+    taskRepo.On("UpdateByID", mock.Anything, task.ID, bson.M{"parent_id": nil}).Return(nil)
+    taskRepo.On("FindByID", mock.Anything, task.ID, userID).Return(&models.Task{ID: task.ID, UserID: userID}, nil)
+    taskRepo.On("ChildProgress", mock.Anything, mock.Anything).Return(map[bson.ObjectID]types.TaskProgress{}, nil)
+
+    result, err := service.RestoreTask(context.Background(), task.ID, userID)
+
+    assert.NoError(t, err)
+    assert.Nil(t, result.DeletedAt)
+    taskRepo.AssertExpectations(t)
+  })
+}
+
+func TestTaskPurger_PurgeTasks(t *testing.T) {
+  t.Run("should delete tasks with everything attached to them", func(t *testing.T) {
+    taskRepo, shareRepo := new(MockTaskRepository), new(MockTaskShareRepository)
+    commentRepo, eventRepo := new(MockCommentRepository), new(MockTaskEventRepository)
+    blobs := storage.NewLocalStore(t.TempDir())
+    purger := &taskPurger{taskRepo: taskRepo, shareRepo: shareRepo, commentRepo: commentRepo, eventRepo: eventRepo, blobs: blobs}
+
+    attachment := models.Attachment{ID: bson.NewObjectID(), Name: "notes.txt"}
+    blobs.Put(context.Background(), attachment.ID.Hex(), strings.NewReader("notes"))
+    tasks := []models.Task{{ID: bson.NewObjectID(), Attachments: []models.Attachment{attachment}}, {ID: bson.NewObjectID()}}
+    ids := []bson.ObjectID{tasks[0].ID, tasks[1].ID}
+
+    taskRepo.On("DeleteByIDs", mock.Anything, ids).Return(nil)
+    shareRepo.On("DeleteByTask", mock.Anything, ids[0]).Return(nil)
+    shareRepo.On("DeleteByTask", mock.Anything, ids[1]).Return(nil)
+    commentRepo.On("DeleteByTasks", mock.Anything, ids).Return(nil)
+    eventRepo.On("DeleteByTasks", mock.Anything, ids).Return(nil)
+    taskRepo.On("PullBlockers", mock.Anything, ids).Return(nil)
+
+    err := purger.PurgeTasks(context.Background(), tasks)
+
+    assert.NoError(t, err)
+    taskRepo.AssertExpectations(t)
+    shareRepo.AssertExpectations(t)
+    commentRepo.AssertExpectations(t)
+    eventRepo.AssertExpectations(t)
+
+    _, err = blobs.Open(context.Background(), attachment.ID.Hex())
+    assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+  })
+  t.Run("should keep the tasks for the next run when attached data fails to delete", func(t *testing.T) {
+    taskRepo, shareRepo := new(MockTaskRepository), new(MockTaskShareRepository)
+    commentRepo, eventRepo := new(MockCommentRepository), new(MockTaskEventRepository)
+    purger := &taskPurger{taskRepo: taskRepo, shareRepo: shareRepo, commentRepo: commentRepo, eventRepo: eventRepo, blobs: storage.NewLocalStore(t.TempDir())}
+
+    tasks := []models.Task{{ID: bson.NewObjectID()}}
+    ids := []bson.ObjectID{tasks[0].ID}
+
+    shareRepo.On("DeleteByTask", mock.Anything, ids[0]).Return(nil)
+    commentRepo.On("DeleteByTasks", mock.Anything, ids).Return(errors.New("database down"))
+
+    err := purger.PurgeTasks(context.Background(), tasks)
+
+    assert.Error(t, err)
+    taskRepo.AssertNotCalled(t, "DeleteByIDs", mock.Anything, mock.Anything)
+  })
+}
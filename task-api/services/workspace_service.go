@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+var (
+  ErrWorkspaceNotFound       = errors.New("workspace not found")
+  ErrWorkspaceOwnerRequired  = errors.New("workspace owner required")
+  ErrWorkspaceMemberNotFound = errors.New("workspace member not found")
+  ErrAlreadyWorkspaceMember  = errors.New("already a workspace member")
+  ErrLastWorkspaceOwner      = errors.New("workspace needs an owner")
+)
+
+// WorkspaceService - interface
+type WorkspaceService interface {
+  CreateWorkspace(ctx context.Context, userID bson.ObjectID, input types.CreateWorkspaceInput) (*types.WorkspaceResponse, error)
+  GetWorkspaces(ctx context.Context, userID bson.ObjectID) ([]types.WorkspaceResponse, error)
+  GetWorkspace(ctx context.Context, workspaceID, userID bson.ObjectID) (*types.WorkspaceResponse, error)
+  GetMembers(ctx context.Context, workspaceID, userID bson.ObjectID) ([]types.WorkspaceMemberResponse, error)
+  AddMember(ctx context.Context, workspaceID, userID bson.ObjectID, input types.AddWorkspaceMemberInput) (*types.WorkspaceMemberResponse, error)
+  RemoveMember(ctx context.Context, workspaceID, userID, memberID bson.ObjectID) error
+}
+
+// workspaceService - implementation
+type workspaceService struct {
+  workspaceRepo repositories.WorkspaceRepository
+  userRepo      repositories.UserRepository
+  taskRepo      repositories.TaskRepository
+}
+
+// NewWorkspaceService - constructor
+func NewWorkspaceService(workspaceRepo repositories.WorkspaceRepository, userRepo repositories.UserRepository, taskRepo repositories.TaskRepository) WorkspaceService {
+  return &workspaceService{
+    workspaceRepo: workspaceRepo,
+    userRepo:      userRepo,
+    taskRepo:      taskRepo,
+  }
+}
+
+// CreateWorkspace - create workspace with the creator as owner
+func (s *workspaceService) CreateWorkspace(ctx context.Context, userID bson.ObjectID, input types.CreateWorkspaceInput) (*types.WorkspaceResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  now := time.Now()
+  workspace := &models.Workspace{
+    ID:        bson.NewObjectID(),
+    Name:      strings.TrimSpace(input.Name),
+    CreatedBy: userID,
+    CreatedAt: now,
+    UpdatedAt: now,
+  }
+
+  if err := s.workspaceRepo.Create(ctx, workspace); err != nil {
+    return nil, err
+  }
+
+  owner := &models.WorkspaceMember{
+    ID:          bson.NewObjectID(),
+    WorkspaceID: workspace.ID,
+    UserID:      userID,
+    Role:        types.WorkspaceRoleOwner,
+    CreatedAt:   now,
+  }
+  if err := s.workspaceRepo.AddMember(ctx, owner); err != nil {
+    return nil, err
+  }
+
+  response := types.ToWorkspaceResponse(workspace, owner.Role)
+  return &response, nil
+}
+
+// GetWorkspaces - workspaces the user is a member of
+func (s *workspaceService) GetWorkspaces(ctx context.Context, userID bson.ObjectID) ([]types.WorkspaceResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  memberships, err := s.workspaceRepo.FindMembershipsByUser(ctx, userID)
+  if err != nil {
+    return nil, err
+  }
+
+  roles := make(map[bson.ObjectID]string, len(memberships))
+  ids := make([]bson.ObjectID, len(memberships))
+  for i, member := range memberships {
+    roles[member.WorkspaceID] = member.Role
+    ids[i] = member.WorkspaceID
+  }
+
+  responses := []types.WorkspaceResponse{}
+  if len(ids) == 0 {
+    return responses, nil
+  }
+
+  workspaces, err := s.workspaceRepo.FindByIDs(ctx, ids)
+  if err != nil {
+    return nil, err
+  }
+
+  for i := range workspaces {
+    responses = append(responses, types.ToWorkspaceResponse(&workspaces[i], roles[workspaces[i].ID]))
+  }
+  return responses, nil
+}
+
+// GetWorkspace - single workspace, members only
+func (s *workspaceService) GetWorkspace(ctx context.Context, workspaceID, userID bson.ObjectID) (*types.WorkspaceResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  member, err := s.requireMember(ctx, workspaceID, userID)
+  if err != nil {
+    return nil, err
+  }
+
+  workspace, err := s.workspaceRepo.FindByID(ctx, workspaceID)
+  if err != nil {
+    if errors.Is(err, repositories.ErrWorkspaceNotFound) {
+      return nil, ErrWorkspaceNotFound
+    }
+    return nil, err
+  }
+
+  response := types.ToWorkspaceResponse(workspace, member.Role)
+  return &response, nil
+}
+
+// GetMembers - members of workspace, members only
+func (s *workspaceService) GetMembers(ctx context.Context, workspaceID, userID bson.ObjectID) ([]types.WorkspaceMemberResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  if _, err := s.requireMember(ctx, workspaceID, userID); err != nil {
+    return nil, err
+  }
+
+  members, err := s.workspaceRepo.FindMembers(ctx, workspaceID)
+  if err != nil {
+    return nil, err
+  }
+
+  responses := make([]types.WorkspaceMemberResponse, 0, len(members))
+  for i := range members {
+    user, err := s.userRepo.FindByID(ctx, members[i].UserID)
+    if err != nil {
+      // Deleted accounts keep no visible membership
+      if errors.Is(err, repositories.ErrUserNotFound) {
+        continue
+      }
+      return nil, err
+    }
+    responses = append(responses, types.ToWorkspaceMemberResponse(&members[i], user))
+  }
+  return responses, nil
+}
+
+// AddMember - add existing user by email, owners only
+func (s *workspaceService) AddMember(ctx context.Context, workspaceID, userID bson.ObjectID, input types.AddWorkspaceMemberInput) (*types.WorkspaceMemberResponse, error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if err := s.requireOwner(ctx, workspaceID, userID); err != nil {
+    return nil, err
+  }
+
+  user, err := s.userRepo.FindByEmail(ctx, normalizeEmail(input.Email))
+  if err != nil {
+    if errors.Is(err, repositories.ErrUserNotFound) {
+      return nil, ErrUserNotFound
+    }
+    return nil, err
+  }
+
+  role := types.WorkspaceRoleMember
+  if input.Role != "" {
+    role = input.Role
+  }
+
+  member := &models.WorkspaceMember{
+    ID:          bson.NewObjectID(),
+    WorkspaceID: workspaceID,
+    UserID:      user.ID,
+    Role:        role,
+    CreatedAt:   time.Now(),
+  }
+  if err := s.workspaceRepo.AddMember(ctx, member); err != nil {
+    if errors.Is(err, repositories.ErrAlreadyWorkspaceMember) {
+      return nil, ErrAlreadyWorkspaceMember
+    }
+    return nil, err
+  }
+
+  response := types.ToWorkspaceMemberResponse(member, user)
+  return &response, nil
+}
+
+// RemoveMember - owners remove anyone, members may only leave themselves
+func (s *workspaceService) RemoveMember(ctx context.Context, workspaceID, userID, memberID bson.ObjectID) error {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  if memberID != userID {
+    if err := s.requireOwner(ctx, workspaceID, userID); err != nil {
+      return err
+    }
+  } else if _, err := s.requireMember(ctx, workspaceID, userID); err != nil {
+    return err
+  }
+
+  target, err := s.workspaceRepo.FindMember(ctx, workspaceID, memberID)
+  if err != nil {
+    if errors.Is(err, repositories.ErrWorkspaceMemberNotFound) {
+      return ErrWorkspaceMemberNotFound
+    }
+    return err
+  }
+
+  // Tasks of a workspace without owner could no longer be managed
+  if target.Role == types.WorkspaceRoleOwner {
+    owners, err := s.workspaceRepo.CountOwners(ctx, workspaceID)
+    if err != nil {
+      return err
+    }
+    if owners <= 1 {
+      return ErrLastWorkspaceOwner
+    }
+  }
+
+  // Assignments would keep read and update access to workspace tasks, so they go first
+  if err := s.taskRepo.PullWorkspaceMember(ctx, workspaceID, memberID); err != nil {
+    return err
+  }
+
+  remove := s.workspaceRepo.RemoveMember
+  if target.Role == types.WorkspaceRoleOwner {
+    // Another owner may be leaving at the same time, the count above is only a first check
+    remove = s.workspaceRepo.RemoveOwner
+  }
+
+  if err := remove(ctx, workspaceID, memberID); err != nil {
+    switch {
+    case errors.Is(err, repositories.ErrWorkspaceMemberNotFound):
+      return ErrWorkspaceMemberNotFound
+    case errors.Is(err, repositories.ErrLastWorkspaceOwner):
+      return ErrLastWorkspaceOwner
+    }
+    return err
+  }
+  return nil
+}
+
+// requireMember - membership of user, non-members get not found so workspaces are not revealed
+func (s *workspaceService) requireMember(ctx context.Context, workspaceID, userID bson.ObjectID) (*models.WorkspaceMember, error) {
+  return findWorkspaceMember(ctx, s.workspaceRepo, workspaceID, userID)
+}
+
+// requireOwner - user must own the workspace
+func (s *workspaceService) requireOwner(ctx context.Context, workspaceID, userID bson.ObjectID) error {
+  member, err := s.requireMember(ctx, workspaceID, userID)
+  if err != nil {
+    return err
+  }
+  if member.Role != types.WorkspaceRoleOwner {
+    return ErrWorkspaceOwnerRequired
+  }
+  return nil
+}
+
+// findWorkspaceMember - membership lookup shared with the task service
+func findWorkspaceMember(ctx context.Context, workspaceRepo repositories.WorkspaceRepository, workspaceID, userID bson.ObjectID) (*models.WorkspaceMember, error) {
+  member, err := workspaceRepo.FindMember(ctx, workspaceID, userID)
+  if err != nil {
+    if errors.Is(err, repositories.ErrWorkspaceMemberNotFound) {
+      return nil, ErrWorkspaceNotFound
+    }
+    return nil, err
+  }
+  return member, nil
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+	"task-api/repositories"
+	"task-api/types"
+)
+
+// MockWorkspaceRepository mocks the WorkspaceRepository interface
+type MockWorkspaceRepository struct {
+  mock.Mock
+}
+
+func (m *MockWorkspaceRepository) Create(ctx context.Context, workspace *models.Workspace) error {
+  args := m.Called(ctx, workspace)
+  return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) FindByID(ctx context.Context, id bson.ObjectID) (*models.Workspace, error) {
+  args := m.Called(ctx, id)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*models.Workspace), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) FindByIDs(ctx context.Context, ids []bson.ObjectID) ([]models.Workspace, error) {
+  args := m.Called(ctx, ids)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]models.Workspace), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) AddMember(ctx context.Context, member *models.WorkspaceMember) error {
+  args := m.Called(ctx, member)
+  return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) FindMember(ctx context.Context, workspaceID, userID bson.ObjectID) (*models.WorkspaceMember, error) {
+  args := m.Called(ctx, workspaceID, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).(*models.WorkspaceMember), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) FindMembers(ctx context.Context, workspaceID bson.ObjectID) ([]models.WorkspaceMember, error) {
+  args := m.Called(ctx, workspaceID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]models.WorkspaceMember), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) FindMembershipsByUser(ctx context.Context, userID bson.ObjectID) ([]models.WorkspaceMember, error) {
+  args := m.Called(ctx, userID)
+  if args.Get(0) == nil {
+    return nil, args.Error(1)
+  }
+  return args.Get(0).([]models.WorkspaceMember), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) CountOwners(ctx context.Context, workspaceID bson.ObjectID) (int64, error) {
+  args := m.Called(ctx, workspaceID)
+  return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID bson.ObjectID) error {
+  args := m.Called(ctx, workspaceID, userID)
+  return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) RemoveOwner(ctx context.Context, workspaceID, userID bson.ObjectID) error {
+  args := m.Called(ctx, workspaceID, userID)
+  return args.Error(0)
+}
+
+// membership - workspace membership with role
+func membership(workspaceID, userID bson.ObjectID, role string) *models.WorkspaceMember {
+  return &models.WorkspaceMember{ID: bson.NewObjectID(), WorkspaceID: workspaceID, UserID: userID, Role: role}
+}
+
+func TestWorkspaceService_CreateWorkspace(t *testing.T) {
+  t.Run("should create workspace with creator as owner", func(t *testing.T) {
+    workspaceRepo := new(MockWorkspaceRepository)
+    service := NewWorkspaceService(workspaceRepo, new(MockUserRepository), new(MockTaskRepository))
+    userID := bson.NewObjectID()
+
+    var owner *models.WorkspaceMember
+    workspaceRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Workspace")).Return(nil)
+    workspaceRepo.On("AddMember", mock.Anything, mock.AnythingOfType("*models.WorkspaceMember")).
+      Run(func(args mock.Arguments) { owner = args.Get(1).(*models.WorkspaceMember) }).
+      Return(nil)
+
+    result, err := service.CreateWorkspace(context.Background(), userID, types.CreateWorkspaceInput{Name: " Team "})
+
+    assert.NoError(t, err)
+    assert.Equal(t, "Team", result.Name)
+    assert.Equal(t, types.WorkspaceRoleOwner, result.Role)
+    assert.Equal(t, userID, owner.UserID)
+    assert.Equal(t, result.ID, owner.WorkspaceID.Hex())
+  })
+}
+
+func TestWorkspaceService_GetWorkspaces(t *testing.T) {
+  t.Run("should list workspaces with role of user", func(t *testing.T) {
+    workspaceRepo := new(MockWorkspaceRepository)
+    service := NewWorkspaceService(workspaceRepo, new(MockUserRepository), new(MockTaskRepository))
+    userID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+
+    workspaceRepo.On("FindMembershipsByUser", mock.Anything, userID).
+      Return([]models.WorkspaceMember{*membership(workspaceID, userID, types.WorkspaceRoleMember)}, nil)
+    workspaceRepo.On("FindByIDs", mock.Anything, []bson.ObjectID{workspaceID}).
+      Return([]models.Workspace{{ID: workspaceID, Name: "Team"}}, nil)
+
+    result, err := service.GetWorkspaces(context.Background(), userID)
+
+    assert.NoError(t, err)
+    assert.Len(t, result, 1)
+    assert.Equal(t, types.WorkspaceRoleMember, result[0].Role)
+  })
+
+  t.Run("should return empty list without memberships", func(t *testing.T) {
+    workspaceRepo := new(MockWorkspaceRepository)
+    service := NewWorkspaceService(workspaceRepo, new(MockUserRepository), new(MockTaskRepository))
+
+    workspaceRepo.On("FindMembershipsByUser", mock.Anything, mock.Anything).Return([]models.WorkspaceMember{}, nil)
+
+    result, err := service.GetWorkspaces(context.Background(), bson.NewObjectID())
+
+    assert.NoError(t, err)
+    assert.NotNil(t, result)
+    workspaceRepo.AssertNotCalled(t, "FindByIDs", mock.Anything, mock.Anything)
+  })
+}
+
+func TestWorkspaceService_GetWorkspace(t *testing.T) {
+  t.Run("should hide workspace from non-members", func(t *testing.T) {
+    workspaceRepo := new(MockWorkspaceRepository)
+    service := NewWorkspaceService(workspaceRepo, new(MockUserRepository), new(MockTaskRepository))
+
+    workspaceRepo.On("FindMember", mock.Anything, mock.Anything, mock.Anything).Return(nil, repositories.ErrWorkspaceMemberNotFound)
+
+    _, err := service.GetWorkspace(context.Background(), bson.NewObjectID(), bson.NewObjectID())
+
+    assert.ErrorIs(t, err, ErrWorkspaceNotFound)
+    workspaceRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+  })
+}
+
+func TestWorkspaceService_AddMember(t *testing.T) {
+  t.Run("should add user by email as member", func(t *testing.T) {
+    workspaceRepo := new(MockWorkspaceRepository)
+    userRepo := new(MockUserRepository)
+    service := NewWorkspaceService(workspaceRepo, userRepo, new(MockTaskRepository))
+    ownerID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+    user := &models.User{ID: bson.NewObjectID(), Email: "teammate@test.com", Name: "Teammate"}
+
+    workspaceRepo.On("FindMember", mock.Anything, workspaceID, ownerID).Return(membership(workspaceID, ownerID, types.WorkspaceRoleOwner), nil)
+    userRepo.On("FindByEmail", mock.Anything, "teammate@test.com").Return(user, nil)
+    workspaceRepo.On("AddMember", mock.Anything, mock.MatchedBy(func(m *models.WorkspaceMember) bool {
+      return m.UserID == user.ID && m.WorkspaceID == workspaceID && m.Role == types.WorkspaceRoleMember
+    })).Return(nil)
+
+    result, err := service.AddMember(context.Background(), workspaceID, ownerID, types.AddWorkspaceMemberInput{Email: "Teammate@Test.com"})
+
+    assert.NoError(t, err)
+    assert.Equal(t, "teammate@test.com", result.Email)
+    workspaceRepo.AssertExpectations(t)
+  })
+
+  t.Run("should refuse members managing members", func(t *testing.T) {
+    workspaceRepo := new(MockWorkspaceRepository)
+    userRepo := new(MockUserRepository)
+    service := NewWorkspaceService(workspaceRepo, userRepo, new(MockTaskRepository))
+    userID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+
+    workspaceRepo.On("FindMember", mock.Anything, workspaceID, userID).Return(membership(workspaceID, userID, types.WorkspaceRoleMember), nil)
+
+    _, err := service.AddMember(context.Background(), workspaceID, userID, types.AddWorkspaceMemberInput{Email: "someone@test.com"})
+
+    assert.ErrorIs(t, err, ErrWorkspaceOwnerRequired)
+    userRepo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+  })
+
+  t.Run("should report existing membership", func(t *testing.T) {
+    workspaceRepo := new(MockWorkspaceRepository)
+    userRepo := new(MockUserRepository)
+    service := NewWorkspaceService(workspaceRepo, userRepo, new(MockTaskRepository))
+    ownerID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+
+    workspaceRepo.On("FindMember", mock.Anything, workspaceID, ownerID).Return(membership(workspaceID, ownerID, types.WorkspaceRoleOwner), nil)
+    userRepo.On("FindByEmail", mock.Anything, mock.Anything).Return(&models.User{ID: bson.NewObjectID()}, nil)
+    workspaceRepo.On("AddMember", mock.Anything, mock.Anything).Return(repositories.ErrAlreadyWorkspaceMember)
+
+    _, err := service.AddMember(context.Background(), workspaceID, ownerID, types.AddWorkspaceMemberInput{Email: "someone@test.com"})
+
+    assert.ErrorIs(t, err, ErrAlreadyWorkspaceMember)
+  })
+}
+
+func TestWorkspaceService_RemoveMember(t *testing.T) {
+  t.Run("should let members leave", func(t *testing.T) {
+    workspaceRepo, taskRepo := new(MockWorkspaceRepository), new(MockTaskRepository)
+    service := NewWorkspaceService(workspaceRepo, new(MockUserRepository), taskRepo)
+    userID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+    member := membership(workspaceID, userID, types.WorkspaceRoleMember)
+
+    workspaceRepo.On("FindMember", mock.Anything, workspaceID, userID).Return(member, nil)
+    workspaceRepo.On("RemoveMember", mock.Anything, workspaceID, userID).Return(nil)
+    taskRepo.On("PullWorkspaceMember", mock.Anything, workspaceID, userID).Return(nil)
+
+    err := service.RemoveMember(context.Background(), workspaceID, userID, userID)
+
+    assert.NoError(t, err)
+    workspaceRepo.AssertExpectations(t)
+    taskRepo.AssertExpectations(t)
+  })
+
+  t.Run("should refuse members removing others", func(t *testing.T) {
+    workspaceRepo := new(MockWorkspaceRepository)
+    service := NewWorkspaceService(workspaceRepo, new(MockUserRepository), new(MockTaskRepository))
+    userID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+
+    workspaceRepo.On("FindMember", mock.Anything, workspaceID, userID).Return(membership(workspaceID, userID, types.WorkspaceRoleMember), nil)
+
+    err := service.RemoveMember(context.Background(), workspaceID, userID, bson.NewObjectID())
+
+    assert.ErrorIs(t, err, ErrWorkspaceOwnerRequired)
+    workspaceRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should keep the last owner", func(t *testing.T) {
+    workspaceRepo := new(MockWorkspaceRepository)
+    service := NewWorkspaceService(workspaceRepo, new(MockUserRepository), new(MockTaskRepository))
+    ownerID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+
+    workspaceRepo.On("FindMember", mock.Anything, workspaceID, ownerID).Return(membership(workspaceID, ownerID, types.WorkspaceRoleOwner), nil)
+    workspaceRepo.On("CountOwners", mock.Anything, workspaceID).Return(int64(1), nil)
+
+    err := service.RemoveMember(context.Background(), workspaceID, ownerID, ownerID)
+
+    assert.ErrorIs(t, err, ErrLastWorkspaceOwner)
+    workspaceRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should remove owners with the guarded delete", func(t *testing.T) {
+    workspaceRepo, taskRepo := new(MockWorkspaceRepository), new(MockTaskRepository)
+    service := NewWorkspaceService(workspaceRepo, new(MockUserRepository), taskRepo)
+    ownerID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+
+    workspaceRepo.On("FindMember", mock.Anything, workspaceID, ownerID).Return(membership(workspaceID, ownerID, types.WorkspaceRoleOwner), nil)
+    workspaceRepo.On("CountOwners", mock.Anything, workspaceID).Return(int64(2), nil)
+    workspaceRepo.On("RemoveOwner", mock.Anything, workspaceID, ownerID).Return(nil)
+    taskRepo.On("PullWorkspaceMember", mock.Anything, workspaceID, ownerID).Return(nil)
+
+    err := service.RemoveMember(context.Background(), workspaceID, ownerID, ownerID)
+
+    assert.NoError(t, err)
+    workspaceRepo.AssertExpectations(t)
+    workspaceRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+  })
+
+  t.Run("should keep the last owner when another owner left meanwhile", func(t *testing.T) {
+    workspaceRepo, taskRepo := new(MockWorkspaceRepository), new(MockTaskRepository)
+    service := NewWorkspaceService(workspaceRepo, new(MockUserRepository), taskRepo)
+    ownerID, workspaceID := bson.NewObjectID(), bson.NewObjectID()
+
+    workspaceRepo.On("FindMember", mock.Anything, workspaceID, ownerID).Return(membership(workspaceID, ownerID, types.WorkspaceRoleOwner), nil)
+    workspaceRepo.On("CountOwners", mock.Anything, workspaceID).Return(int64(2), nil)
+    workspaceRepo.On("RemoveOwner", mock.Anything, workspaceID, ownerID).Return(repositories.ErrLastWorkspaceOwner)
+    taskRepo.On("PullWorkspaceMember", mock.Anything, workspaceID, ownerID).Return(nil)
+
+    err := service.RemoveMember(context.Background(), workspaceID, ownerID, ownerID)
+
+    assert.ErrorIs(t, err, ErrLastWorkspaceOwner)
+  })
+}
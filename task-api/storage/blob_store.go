@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"task-api/configs"
+)
+
+var (
+  ErrBlobNotFound = errors.New("blob not found")
+  ErrInvalidKey   = errors.New("invalid blob key")
+)
+
+// BlobStore - interface for storing file contents by key, metadata is kept by callers
+type BlobStore interface {
+  Put(ctx context.Context, key string, r io.Reader) (int64, error)
+  Open(ctx context.Context, key string) (io.ReadCloser, error)
+  Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStoreFromEnv - build BlobStore from BLOB_STORE env ("gridfs" or "local", default "local")
+func NewBlobStoreFromEnv(db *mongo.Database) BlobStore {
+  switch strings.ToLower(configs.GetEnv("BLOB_STORE", "local")) {
+  case "gridfs":
+    bucket := configs.GetEnv("BLOB_GRIDFS_BUCKET", "attachments")
+    log.Info().Str("bucket", bucket).Msg("Using GridFS blob store")
+    return NewGridFSStore(db, bucket)
+  default:
+    dir := configs.GetEnv("BLOB_DIR", "./data/attachments")
+    log.Info().Str("dir", dir).Msg("Using local blob store")
+    return NewLocalStore(dir)
+  }
+}
+
+// validKey - keys are plain names, never paths
+func validKey(key string) bool {
+  return key != "" && key != "." && key != ".." && !strings.ContainsAny(key, `/\`)
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// gridFSStore - BlobStore in a GridFS bucket, the key is the file ID
+type gridFSStore struct {
+  bucket *mongo.GridFSBucket
+}
+
+// NewGridFSStore - constructor
+func NewGridFSStore(db *mongo.Database, bucket string) BlobStore {
+  return &gridFSStore{
+    bucket: db.GridFSBucket(options.GridFSBucket().SetName(bucket)),
+  }
+}
+
+// Put - upload r as the file of key, a failed upload leaves no file behind
+func (s *gridFSStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+  if !validKey(key) {
+    return 0, ErrInvalidKey
+  }
+
+  stream, err := s.bucket.OpenUploadStreamWithID(ctx, key, key)
+  if err != nil {
+    return 0, err
+  }
+
+  size, err := io.Copy(stream, r)
+  if err != nil {
+    stream.Abort()
+    return 0, err
+  }
+
+  if err := stream.Close(); err != nil {
+    return 0, err
+  }
+  return size, nil
+}
+
+// Open - download stream of the file of key
+func (s *gridFSStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+  stream, err := s.bucket.OpenDownloadStream(ctx, key)
+  if errors.Is(err, mongo.ErrFileNotFound) {
+    return nil, ErrBlobNotFound
+  }
+  if err != nil {
+    return nil, err
+  }
+  return stream, nil
+}
+
+// Delete - remove the file of key and its chunks
+func (s *gridFSStore) Delete(ctx context.Context, key string) error {
+  err := s.bucket.Delete(ctx, key)
+  if errors.Is(err, mongo.ErrFileNotFound) {
+    return ErrBlobNotFound
+  }
+  return err
+}
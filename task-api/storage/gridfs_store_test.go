@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tryvium-travels/memongo"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestGridFSStore(t *testing.T) {
+  if testing.Short() {
+    t.Skip("Skipping integration test")
+  }
+
+  t.Run("should put, open and delete blobs", func(t *testing.T) {
+    mongoServer, err := memongo.StartWithOptions(&memongo.Options{StartupTimeout: 30 * time.Second, MongoVersion: "6.0.4"})
+    if err != nil {
+      t.Skipf("Skipping test: MongoDB not available - %v", err)
+    }
+    t.Cleanup(mongoServer.Stop)
+
+    client, err := mongo.Connect(options.Client().ApplyURI(mongoServer.URI()))
+    if err != nil {
+      t.Fatalf("Failed to connect to in-memory MongoDB: %v", err)
+    }
+    store := NewGridFSStore(client.Database("test_db"), "attachments")
+    ctx := context.Background()
+
+    size, err := store.Put(ctx, "abc", strings.NewReader("hello"))
+    assert.NoError(t, err)
+    assert.Equal(t, int64(5), size)
+
+    reader, err := store.Open(ctx, "abc")
+    assert.NoError(t, err)
+    content, _ := io.ReadAll(reader)
+    reader.Close()
+    assert.Equal(t, "hello", string(content))
+
+    assert.NoError(t, store.Delete(ctx, "abc"))
+
+    _, err = store.Open(ctx, "abc")
+    assert.ErrorIs(t, err, ErrBlobNotFound)
+  })
+}
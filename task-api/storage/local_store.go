@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// localStore - BlobStore keeping one file per key in a directory
+type localStore struct {
+  dir string
+}
+
+// NewLocalStore - constructor, dir is created on the first Put
+func NewLocalStore(dir string) BlobStore {
+  return &localStore{dir: dir}
+}
+
+// Put - write r to the file of key, replacing it only once fully written
+func (s *localStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+  if !validKey(key) {
+    return 0, ErrInvalidKey
+  }
+
+  if err := os.MkdirAll(s.dir, 0750); err != nil {
+    return 0, err
+  }
+
+  tmp, err := os.CreateTemp(s.dir, ".upload-*")
+  if err != nil {
+    return 0, err
+  }
+  defer os.Remove(tmp.Name()) // no-op after the rename
+
+  size, err := io.Copy(tmp, r)
+  if closeErr := tmp.Close(); err == nil {
+    err = closeErr
+  }
+  if err != nil {
+    return 0, err
+  }
+
+  if err := os.Rename(tmp.Name(), filepath.Join(s.dir, key)); err != nil {
+    return 0, err
+  }
+  return size, nil
+}
+
+// Open - reader for the file of key
+func (s *localStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+  if !validKey(key) {
+    return nil, ErrInvalidKey
+  }
+
+  file, err := os.Open(filepath.Join(s.dir, key))
+  if errors.Is(err, fs.ErrNotExist) {
+    return nil, ErrBlobNotFound
+  }
+  return file, err
+}
+
+// Delete - remove the file of key
+func (s *localStore) Delete(ctx context.Context, key string) error {
+  if !validKey(key) {
+    return ErrInvalidKey
+  }
+
+  err := os.Remove(filepath.Join(s.dir, key))
+  if errors.Is(err, fs.ErrNotExist) {
+    return ErrBlobNotFound
+  }
+  return err
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStore(t *testing.T) {
+  t.Run("should put, open and delete blobs", func(t *testing.T) {
+    store := NewLocalStore(t.TempDir() + "/blobs")
+    ctx := context.Background()
+
+    size, err := store.Put(ctx, "abc", strings.NewReader("hello"))
+    assert.NoError(t, err)
+    assert.Equal(t, int64(5), size)
+
+    reader, err := store.Open(ctx, "abc")
+    assert.NoError(t, err)
+    content, _ := io.ReadAll(reader)
+    reader.Close()
+    assert.Equal(t, "hello", string(content))
+
+    assert.NoError(t, store.Delete(ctx, "abc"))
+
+    _, err = store.Open(ctx, "abc")
+    assert.ErrorIs(t, err, ErrBlobNotFound)
+    assert.ErrorIs(t, store.Delete(ctx, "abc"), ErrBlobNotFound)
+  })
+
+  t.Run("should reject keys that are paths", func(t *testing.T) {
+    store := NewLocalStore(t.TempDir())
+    ctx := context.Background()
+
+    for _, key := range []string{"", "..", "../escape", `a\b`} {
+      _, err := store.Put(ctx, key, strings.NewReader("x"))
+      assert.ErrorIs(t, err, ErrInvalidKey, key)
+
+      _, err = store.Open(ctx, key)
+      assert.ErrorIs(t, err, ErrInvalidKey, key)
+    }
+  })
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"time"
+
+	"task-api/models"
+)
+
+// ========== INPUT DTOs ==========
+
+// UserQueryParams - query params for GET /admin/users
+type UserQueryParams struct {
+  Role   string `form:"role" binding:"omitempty,oneof=admin member viewer"`
+  Status string `form:"status" binding:"omitempty,oneof=active disabled"`
+  Search string `form:"search" binding:"omitempty,max=100"`
+  Page   int    `form:"page" binding:"omitempty,min=1"`
+  Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// UpdateUserRoleInput - request body for PATCH /admin/users/:id/role
+type UpdateUserRoleInput struct {
+  Role string `json:"role" binding:"required,oneof=admin member viewer"`
+}
+
+// ========== OUTPUT DTOs ==========
+
+// AdminUserResponse - user as seen by administrators
+type AdminUserResponse struct {
+  UserResponse
+  Disabled   bool       `json:"disabled"`
+  DisabledAt *time.Time `json:"disabled_at,omitempty"`
+  CreatedAt  time.Time  `json:"created_at"`
+}
+
+// UserListResponse - paginated users
+type UserListResponse struct {
+  Users []AdminUserResponse `json:"users"`
+  Meta  PaginationMeta      `json:"meta"`
+}
+
+// ========== CONVERTERS ==========
+
+// ToAdminUserResponse - convert models.User to types.AdminUserResponse
+func ToAdminUserResponse(user *models.User) AdminUserResponse {
+  return AdminUserResponse{
+    UserResponse: ToUserResponse(user),
+    Disabled:     user.DisabledAt != nil,
+    DisabledAt:   user.DisabledAt,
+    CreatedAt:    user.CreatedAt,
+  }
+}
+
+// ToAdminUserResponseList - convert []models.User to []types.AdminUserResponse
+func ToAdminUserResponseList(users []models.User) []AdminUserResponse {
+  responses := make([]AdminUserResponse, len(users))
+  for i, user := range users {
+    responses[i] = ToAdminUserResponse(&user)
+  }
+  return responses
+}
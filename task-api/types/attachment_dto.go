@@ -0,0 +1,46 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+)
+
+// ========== OUTPUT DTOs ==========
+
+// AttachmentResponse - file attached to a task
+type AttachmentResponse struct {
+  ID          string    `json:"id"`
+  Name        string    `json:"name"`
+  ContentType string    `json:"content_type"`
+  Size        int64     `json:"size"`
+  UploadedBy  string    `json:"uploaded_by"`
+  UploadedAt  time.Time `json:"uploaded_at"`
+  URL         string    `json:"url"` // download path, relative to the API root
+}
+
+// ========== CONVERTERS ==========
+
+// ToAttachmentResponse - convert attachment of task to response
+func ToAttachmentResponse(taskID bson.ObjectID, attachment *models.Attachment) AttachmentResponse {
+  return AttachmentResponse{
+    ID:          attachment.ID.Hex(),
+    Name:        attachment.Name,
+    ContentType: attachment.ContentType,
+    Size:        attachment.Size,
+    UploadedBy:  attachment.UploadedBy.Hex(),
+    UploadedAt:  attachment.UploadedAt,
+    URL:         "/tasks/" + taskID.Hex() + "/attachments/" + attachment.ID.Hex(),
+  }
+}
+
+// ToAttachmentResponses - convert attachments in upload order, never nil
+func ToAttachmentResponses(taskID bson.ObjectID, attachments []models.Attachment) []AttachmentResponse {
+  responses := make([]AttachmentResponse, len(attachments))
+  for i := range attachments {
+    responses[i] = ToAttachmentResponse(taskID, &attachments[i])
+  }
+  return responses
+}
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"sort"
+	"time"
+
+	"task-api/models"
+)
+
+// ========== INPUT DTOs ==========
+
+// AddChecklistItemInput - for POST /tasks/:id/checklist
+type AddChecklistItemInput struct {
+  Text string `json:"text" binding:"required,min=1,max=200"`
+}
+
+// UpdateChecklistItemInput - for PATCH /tasks/:id/checklist/:item
+type UpdateChecklistItemInput struct {
+  Text *string `json:"text" binding:"omitempty,min=1,max=200"`
+  Done *bool   `json:"done"`
+}
+
+// ReorderChecklistInput - for PUT /tasks/:id/checklist/order, every item ID in the new order
+type ReorderChecklistInput struct {
+  ItemIDs []string `json:"item_ids" binding:"required,min=1,unique,dive,mongodb"`
+}
+
+// ========== OUTPUT DTOs ==========
+
+// ChecklistItemResponse - checklist item of a task
+type ChecklistItemResponse struct {
+  ID     string     `json:"id"`
+  Text   string     `json:"text"`
+  Done   bool       `json:"done"`
+  Order  int        `json:"order"`
+  DoneAt *time.Time `json:"done_at,omitempty"`
+}
+
+// ========== CONVERTERS ==========
+
+// ToChecklistResponse - convert checklist items in list order, never nil
+func ToChecklistResponse(items []models.ChecklistItem) []ChecklistItemResponse {
+  sorted := make([]models.ChecklistItem, len(items))
+  copy(sorted, items)
+
+  // Items added concurrently may share an order, the older one comes first
+  sort.SliceStable(sorted, func(i, j int) bool {
+    if sorted[i].Order != sorted[j].Order {
+      return sorted[i].Order < sorted[j].Order
+    }
+    return sorted[i].ID.Hex() < sorted[j].ID.Hex()
+  })
+
+  responses := make([]ChecklistItemResponse, len(sorted))
+  for i, item := range sorted {
+    responses[i] = ChecklistItemResponse{
+      ID:     item.ID.Hex(),
+      Text:   item.Text,
+      Done:   item.Done,
+      Order:  item.Order,
+      DoneAt: item.DoneAt,
+    }
+  }
+  return responses
+}
+
+// checklistProgress - checked items, nil without a checklist
+func checklistProgress(items []models.ChecklistItem) *TaskProgress {
+  if len(items) == 0 {
+    return nil
+  }
+
+  done := 0
+  for _, item := range items {
+    if item.Done {
+      done++
+    }
+  }
+
+  progress := NewTaskProgress(len(items), done)
+  return &progress
+}
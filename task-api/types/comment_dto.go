@@ -0,0 +1,56 @@
+package types
+
+import (
+	"time"
+
+	"task-api/models"
+)
+
+// ========== INPUT DTOs ==========
+
+// CommentInput - for POST /tasks/:id/comments and PUT /tasks/:id/comments/:cid
+type CommentInput struct {
+  Body string `json:"body" binding:"required,min=1,max=2000"`
+}
+
+// CommentQueryParams - for GET /tasks/:id/comments
+type CommentQueryParams struct {
+  Cursor string `form:"cursor" binding:"omitempty,mongodb"` // next_cursor of the previous page
+  Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ========== OUTPUT DTOs ==========
+
+// CommentResponse - comment on a task
+type CommentResponse struct {
+  ID        string    `json:"id"`
+  TaskID    string    `json:"task_id"`
+  AuthorID  string    `json:"author_id"`
+  Body      string    `json:"body"`
+  Mentions  []string  `json:"mentions"`
+  Edited    bool      `json:"edited"`
+  CreatedAt time.Time `json:"created_at"`
+  UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CommentListResponse - for GET /tasks/:id/comments, oldest first
+type CommentListResponse struct {
+  Comments   []CommentResponse `json:"comments"`
+  NextCursor string            `json:"next_cursor,omitempty"` // empty on the last page
+}
+
+// ========== CONVERTERS ==========
+
+// ToCommentResponse - convert comment model to response
+func ToCommentResponse(comment *models.Comment) CommentResponse {
+  return CommentResponse{
+    ID:        comment.ID.Hex(),
+    TaskID:    comment.TaskID.Hex(),
+    AuthorID:  comment.AuthorID.Hex(),
+    Body:      comment.Body,
+    Mentions:  toHexList(comment.Mentions),
+    Edited:    comment.UpdatedAt.After(comment.CreatedAt),
+    CreatedAt: comment.CreatedAt,
+    UpdatedAt: comment.UpdatedAt,
+  }
+}
@@ -12,10 +12,92 @@ const (
 	// Login
   MsgLoginSuccess     = "Login successful"
   MsgLoginFailed      = "Login failed"
+  MsgAccountLocked    = "Account temporarily locked due to too many failed login attempts"
+  MsgTooManyAttempts  = "Too many login attempts, please try again later"
   MsgInvalidCredentials = "Invalid email or password"
+  MsgLoginUnavailable = "Login is temporarily unavailable, please try again later"
   MsgValidationFailed = "Validation failed"
   MsgInternalError    = "Internal server error"
 
+	// Register
+  MsgRegisterSuccess        = "Registration successful"
+  MsgRegisterFailed         = "Registration failed"
+  MsgEmailAlreadyRegistered = "Email is already registered"
+  MsgWeakPassword           = "Password does not meet strength requirements"
+
+	// Refresh
+  MsgTokenRefreshed       = "Token refreshed successfully"
+  MsgRefreshFailed        = "Token refresh failed"
+  MsgInvalidRefreshToken  = "Invalid or expired refresh token"
+
+	// Logout
+  MsgLogoutSuccess    = "Logged out successfully"
+  MsgLogoutAllSuccess = "Logged out from all sessions"
+
+	// Password reset
+  MsgPasswordResetRequested = "If the email is registered, a password reset link has been sent"
+  MsgPasswordResetSuccess   = "Password has been reset, please log in again"
+  MsgPasswordResetFailed    = "Password reset failed"
+  MsgInvalidResetToken      = "Invalid or expired reset token"
+
+	// Profile
+  MsgProfileRetrieved       = "Profile retrieved successfully"
+  MsgProfileUpdated         = "Profile updated successfully"
+  MsgPasswordChanged        = "Password changed successfully, other sessions have been logged out"
+  MsgPasswordChangeFailed   = "Password change failed"
+  MsgInvalidCurrentPassword = "Current password is incorrect"
+  MsgInvalidTimezone        = "Invalid timezone"
+  MsgUserNotFound           = "User not found"
+
+	// Two-factor authentication
+  MsgMFASetupStarted   = "Add the otpauth URI to your authenticator app, then verify a code"
+  MsgMFAEnabled        = "Two-factor authentication enabled, store the recovery codes safely"
+  MsgMFARequired       = "Two-factor code required"
+  MsgMFAFailed         = "Two-factor verification failed"
+  MsgMFAAlreadyEnabled = "Two-factor authentication is already enabled"
+  MsgMFANotSetUp       = "Two-factor setup has not been started"
+  MsgInvalidMFACode    = "Invalid two-factor or recovery code"
+  MsgInvalidMFAToken   = "Invalid or expired MFA token"
+
+	// Personal access tokens
+  MsgTokenCreated       = "Personal access token created, copy it now as it will not be shown again"
+  MsgTokensRetrieved    = "Personal access tokens retrieved successfully"
+  MsgTokenRevoked       = "Personal access token revoked"
+  MsgTokenNotFound      = "Personal access token not found"
+  MsgTokenLimitReached  = "Personal access token limit reached"
+  MsgInsufficientScope  = "Token does not have the required scope"
+  MsgSessionRequired    = "This endpoint requires a login session, personal access tokens are not accepted"
+
+	// Single sign-on
+  MsgOIDCDisabled         = "Single sign-on is not configured"
+  MsgOIDCUnavailable      = "Identity provider is unavailable, please try again later"
+  MsgOIDCLoginFailed      = "Single sign-on failed"
+  MsgOIDCInvalidState     = "Invalid or expired login state, please start again"
+  MsgOIDCEmailNotVerified = "Identity provider did not return a verified email"
+  MsgOIDCAccountConflict  = "Account is already linked to another identity"
+
+	// Admin
+  MsgForbidden        = "You do not have permission to perform this action"
+  MsgAccountDisabled  = "Account has been disabled"
+  MsgUsersRetrieved   = "Users retrieved successfully"
+  MsgUserDisabled     = "User disabled, all sessions have been logged out"
+  MsgUserEnabled      = "User enabled"
+  MsgRoleUpdated      = "User role updated"
+  MsgCannotModifySelf = "Administrators cannot disable or demote themselves"
+
+	// Workspace
+  MsgWorkspaceCreated    = "Workspace created successfully"
+  MsgWorkspacesRetrieved = "Workspaces retrieved successfully"
+  MsgWorkspaceRetrieved  = "Workspace retrieved successfully"
+  MsgWorkspaceNotFound   = "Workspace not found"
+  MsgMembersRetrieved    = "Workspace members retrieved successfully"
+  MsgMemberAdded         = "Member added to workspace"
+  MsgMemberRemoved       = "Member removed from workspace"
+  MsgMemberNotFound      = "Workspace member not found"
+  MsgAlreadyMember       = "User is already a member of this workspace"
+  MsgWorkspaceOwnerOnly  = "Only workspace owners can manage members"
+  MsgLastWorkspaceOwner  = "A workspace needs at least one owner"
+
 	// Task
   MsgTaskCreated    = "Task created successfully"
   MsgTaskUpdated    = "Task updated successfully"
@@ -23,6 +105,90 @@ const (
   MsgTasksRetrieved = "Tasks retrieved successfully"
   MsgTaskRetrieved  = "Task retrieved successfully"
   MsgTaskNotFound   = "Task not found"
+
+	// Task assignees and watchers
+  MsgTaskAssigned      = "Task assigned successfully"
+  MsgTaskUnassigned    = "Task unassigned successfully"
+  MsgTaskWatched       = "Task watched successfully"
+  MsgTaskUnwatched     = "Task unwatched successfully"
+  MsgTaskOwnerOnly     = "Only the task owner or its workspace members can manage assignees and shares"
+  MsgAssigneeNotMember = "Assignee must be a member of the task's workspace"
+
+	// Task shares
+  MsgTaskShared           = "Task shared successfully"
+  MsgTaskSharesRetrieved  = "Task shares retrieved successfully"
+  MsgTaskShareRevoked     = "Task share revoked successfully"
+  MsgTaskShareNotFound    = "Task share not found"
+  MsgSharedTasksRetrieved = "Shared tasks retrieved successfully"
+  MsgTaskPermissionDenied = "Your access to this task does not allow this action"
+  MsgCannotShareWithSelf  = "Cannot share a task with yourself"
+
+	// Subtasks
+  MsgSubtasksRetrieved = "Subtasks retrieved successfully"
+  MsgTaskCycle         = "A task cannot be moved below itself or its subtasks"
+  MsgTaskDepthExceeded = "Task hierarchy is too deep"
+  MsgInvalidParentTask = "Parent task must exist and belong to the same owner and workspace"
+
+	// Task dependencies
+  MsgBlockerAdded          = "Blocker added successfully"
+  MsgBlockerRemoved        = "Blocker removed successfully"
+  MsgDependenciesRetrieved = "Task dependencies retrieved successfully"
+  MsgTaskBlocked           = "Task cannot start or complete while blockers are open"
+  MsgDependencyCycle       = "Dependency would create a cycle"
+  MsgInvalidDependency     = "Blocker must exist and belong to the same owner and workspace"
+
+	// Recurring tasks
+  MsgInvalidRecurrence = "Invalid recurrence rule"
+
+	// Checklists
+  MsgChecklistItemAdded    = "Checklist item added successfully"
+  MsgChecklistItemUpdated  = "Checklist item updated successfully"
+  MsgChecklistItemRemoved  = "Checklist item removed successfully"
+  MsgChecklistReordered    = "Checklist reordered successfully"
+  MsgChecklistItemNotFound = "Checklist item not found"
+  MsgChecklistMismatch     = "item_ids must list every checklist item exactly once"
+  MsgChecklistFull         = "Checklist has reached the maximum number of items"
+
+	// Comments
+  MsgCommentCreated    = "Comment created successfully"
+  MsgCommentsRetrieved = "Comments retrieved successfully"
+  MsgCommentUpdated    = "Comment updated successfully"
+  MsgCommentDeleted    = "Comment deleted successfully"
+  MsgCommentNotFound   = "Comment not found"
+  MsgCommentAuthorOnly = "Only the author can change this comment"
+
+	// Attachments
+  MsgAttachmentUploaded = "Attachment uploaded successfully"
+  MsgAttachmentDeleted  = "Attachment deleted successfully"
+  MsgAttachmentNotFound = "Attachment not found"
+  MsgAttachmentTooLarge = "Attachment exceeds the maximum file size"
+  MsgAttachmentType     = "Only JPEG, PNG, GIF, WebP images and PDF documents can be attached"
+  MsgTooManyAttachments = "Task has reached the maximum number of attachments"
+
+	// History
+  MsgTaskHistoryRetrieved = "Task history retrieved successfully"
+
+	// Trash
+  MsgTaskTrashed    = "Task moved to the trash"
+  MsgTaskPurged     = "Task deleted permanently"
+  MsgTaskRestored   = "Task restored successfully"
+  MsgTrashRetrieved = "Trash retrieved successfully"
+  MsgTaskNotTrashed = "Task is not in the trash"
+
+	// Archive
+  MsgTaskArchived    = "Task archived successfully"
+  MsgTaskUnarchived  = "Task unarchived successfully"
+  MsgTaskIsArchived  = "Task is already archived"
+  MsgTaskNotArchived = "Task is not archived"
+
+	// Bulk
+  MsgBulkCompleted        = "Bulk operation completed"
+  MsgBulkAborted          = "Bulk operation failed, no changes were applied"
+  MsgInvalidBulkOperation = "Each operation needs either ids or a filter, and updates need status, priority or tags"
+  MsgBulkTooLarge         = "Bulk operation matches too many tasks"
+
+	// Concurrency
+  MsgTaskVersionMismatch = "Task was changed by someone else, merge with the current version and retry"
 )
 
 // Task Status
@@ -39,8 +205,81 @@ const (
   TaskPriorityHigh   = "high"
 )
 
+// Task history actions
+const (
+  TaskEventCreated  = "created"
+  TaskEventUpdated  = "updated"
+  TaskEventDeleted  = "deleted"
+  TaskEventRestored = "restored"
+)
+
+// Task list filter for tasks assigned to the caller
+const AssignedToMe = "me"
+
+// User roles, users without a stored role are members
+const (
+  RoleAdmin  = "admin"
+  RoleMember = "member"
+  RoleViewer = "viewer"
+)
+
+// Workspace member roles
+const (
+  WorkspaceRoleOwner  = "owner"
+  WorkspaceRoleMember = "member"
+)
+
+// Task list filter on checklist state
+const (
+  ChecklistOpen = "open"
+  ChecklistDone = "done"
+)
+
+// Task list filter on archived state, archived tasks are excluded by default
+const (
+  ArchivedOnly = "true"
+  ArchivedAll  = "all"
+)
+
+// Bulk task actions
+const (
+  BulkActionUpdate  = "update"
+  BulkActionDelete  = "delete"
+  BulkActionArchive = "archive"
+)
+
+// Outcome of one task in a bulk request
+const (
+  BulkResultApplied = "applied"
+  BulkResultFailed  = "failed"
+  BulkResultSkipped = "skipped"
+)
+
+// Scope of an edit to a recurring task
+const (
+  RecurrenceScopeThis   = "this"
+  RecurrenceScopeFuture = "future"
+)
+
+// Task share permissions, each level includes the ones before it
+const (
+  SharePermissionView  = "view"
+  SharePermissionEdit  = "edit"
+  SharePermissionOwner = "owner"
+)
+
+// Personal access token scopes
+const (
+  ScopeTasksRead  = "tasks:read"
+  ScopeTasksWrite = "tasks:write"
+)
+
 // Validation Arrays
 var (
-  ValidTaskStatuses   = []string{TaskStatusPending, TaskStatusInProgress, TaskStatusCompleted}
-  ValidTaskPriorities = []string{TaskPriorityLow, TaskPriorityMedium, TaskPriorityHigh}
+  ValidTaskStatuses     = []string{TaskStatusPending, TaskStatusInProgress, TaskStatusCompleted}
+  ValidTaskPriorities   = []string{TaskPriorityLow, TaskPriorityMedium, TaskPriorityHigh}
+  ValidTokenScopes      = []string{ScopeTasksRead, ScopeTasksWrite}
+  ValidRoles            = []string{RoleAdmin, RoleMember, RoleViewer}
+  ValidWorkspaceRoles   = []string{WorkspaceRoleOwner, WorkspaceRoleMember}
+  ValidSharePermissions = []string{SharePermissionView, SharePermissionEdit, SharePermissionOwner}
 )
@@ -0,0 +1,20 @@
+package types
+
+// ========== OUTPUT DTOs ==========
+
+// JWK - public JSON Web Key (RFC 7517), RSA keys fill N/E, Ed25519 keys fill Crv/X
+type JWK struct {
+  Kty string `json:"kty"`
+  Kid string `json:"kid"`
+  Use string `json:"use"`
+  Alg string `json:"alg"`
+  N   string `json:"n,omitempty"`
+  E   string `json:"e,omitempty"`
+  Crv string `json:"crv,omitempty"`
+  X   string `json:"x,omitempty"`
+}
+
+// JWKS - key set served at /.well-known/jwks.json
+type JWKS struct {
+  Keys []JWK `json:"keys"`
+}
@@ -0,0 +1,20 @@
+package types
+
+// ========== INPUT DTOs ==========
+
+// OIDCCallbackInput - query of GET /auth/oidc/callback, IdPs send error instead of code on failure
+type OIDCCallbackInput struct {
+  Code             string `form:"code" binding:"required_without=Error"`
+  State            string `form:"state" binding:"required"`
+  Error            string `form:"error"`
+  ErrorDescription string `form:"error_description"`
+}
+
+// ========== OUTPUT DTOs ==========
+
+// OIDCLoginStart - where to send the browser, State goes into the state cookie
+type OIDCLoginStart struct {
+  AuthorizationURL string
+  State            string
+  ExpiresIn        int // seconds
+}
@@ -0,0 +1,36 @@
+package types
+
+// ========== INPUT DTOs ==========
+
+// BulkTaskInput - for POST /tasks/bulk
+type BulkTaskInput struct {
+  Operations []BulkTaskOperation `json:"operations" binding:"required,min=1,max=20,dive"`
+  Atomic     bool                `json:"atomic"` // apply every change or none of them
+}
+
+// BulkTaskOperation - one action on the tasks picked by ids or by filter, never both
+type BulkTaskOperation struct {
+  Action   string           `json:"action" binding:"required,oneof=update delete archive"`
+  IDs      []string         `json:"ids" binding:"omitempty,max=100,dive,mongodb"`
+  Filter   *TaskQueryParams `json:"filter"`                                                         // same filters as GET /tasks, paging and sort are ignored
+  Status   *string          `json:"status" binding:"omitempty,oneof=pending in_progress completed"` // update only
+  Priority *string          `json:"priority" binding:"omitempty,oneof=low medium high"`             // update only
+  Tags     []string         `json:"tags"`                                                           // update only, replaces the tags
+}
+
+// ========== OUTPUT DTOs ==========
+
+// BulkTaskResult - outcome for one task of an operation
+type BulkTaskResult struct {
+  Operation int    `json:"operation"` // index in operations
+  TaskID    string `json:"task_id"`
+  Status    string `json:"status"` // applied, failed, or skipped when an atomic request failed elsewhere
+  Error     string `json:"error,omitempty"`
+}
+
+// BulkTaskResponse - for response API
+type BulkTaskResponse struct {
+  Applied int              `json:"applied"`
+  Failed  int              `json:"failed"`
+  Results []BulkTaskResult `json:"results"`
+}
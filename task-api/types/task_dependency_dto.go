@@ -0,0 +1,45 @@
+package types
+
+import (
+	"task-api/models"
+)
+
+// ========== INPUT DTOs ==========
+
+// AddBlockerInput - for POST /tasks/:id/blockers
+type AddBlockerInput struct {
+  TaskID string `json:"task_id" binding:"required,mongodb"`
+}
+
+// ========== OUTPUT DTOs ==========
+
+// TaskDependencyNode - task in a dependency graph, only ID for tasks the user cannot see
+type TaskDependencyNode struct {
+  ID     string `json:"id"`
+  Title  string `json:"title,omitempty"`
+  Status string `json:"status,omitempty"`
+}
+
+// TaskDependencyEdge - From must be completed before To can start
+type TaskDependencyEdge struct {
+  From string `json:"from"`
+  To   string `json:"to"`
+}
+
+// TaskDependencyGraph - for GET /tasks/:id/dependencies
+type TaskDependencyGraph struct {
+  TaskID string               `json:"task_id"`
+  Nodes  []TaskDependencyNode `json:"nodes"`
+  Edges  []TaskDependencyEdge `json:"edges"`
+}
+
+// ========== CONVERTERS ==========
+
+// ToTaskDependencyNode - convert models.Task to types.TaskDependencyNode
+func ToTaskDependencyNode(task *models.Task) TaskDependencyNode {
+  return TaskDependencyNode{
+    ID:     task.ID.Hex(),
+    Title:  task.Title,
+    Status: task.Status,
+  }
+}
@@ -18,6 +18,8 @@ type CreateTaskInput struct {
   Priority    string     `json:"priority" binding:"omitempty,oneof=low medium high"`
   DueDate     *time.Time `json:"due_date"`
   Tags        []string   `json:"tags"`
+  WorkspaceID string     `json:"workspace_id" binding:"omitempty,mongodb"` // omit for a personal task
+  Recurrence  string     `json:"recurrence" binding:"omitempty,max=200"`   // iCalendar RRULE, needs due_date
 }
 
 // UpdateTaskInput - for PUT /tasks/:id
@@ -28,33 +30,79 @@ type UpdateTaskInput struct {
   Priority    *string    `json:"priority" binding:"omitempty,oneof=low medium high"`
   DueDate     *time.Time `json:"due_date"`
   Tags        []string   `json:"tags"`
+  ParentID    *string    `json:"parent_id" binding:"omitempty,len=0|mongodb"` // empty string moves the task to the top level
+  Recurrence  *string    `json:"recurrence" binding:"omitempty,max=200"`      // empty string ends the series
+  Scope       string     `json:"scope" binding:"omitempty,oneof=this future"` // recurring tasks: this occurrence (default) or all future ones
 }
 
-// TaskQueryParams - for GET /tasks
+// TaskQueryParams - for GET /tasks, and as filter of POST /tasks/bulk
 type TaskQueryParams struct {
-  Status   string `form:"status" binding:"omitempty,oneof=pending in_progress completed"`
-  Priority string `form:"priority" binding:"omitempty,oneof=low medium high"`
-  Search   string `form:"search"`
-  Sort     string `form:"sort" binding:"omitempty,oneof=created_at -created_at due_date -due_date priority -priority title -title"`
-  Page     int    `form:"page" binding:"omitempty,min=1"`
-  Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+  Status      string `form:"status" json:"status" binding:"omitempty,oneof=pending in_progress completed"`
+  Priority    string `form:"priority" json:"priority" binding:"omitempty,oneof=low medium high"`
+  Search      string `form:"search" json:"search"`
+  Sort        string `form:"sort" json:"sort" binding:"omitempty,oneof=created_at -created_at due_date -due_date priority -priority title -title"`
+  Page        int    `form:"page" json:"page" binding:"omitempty,min=1"`
+  Limit       int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
+  WorkspaceID string `form:"workspace_id" json:"workspace_id" binding:"omitempty,mongodb"`      // only tasks of this workspace
+  AssignedTo  string `form:"assigned_to" json:"assigned_to" binding:"omitempty,oneof=me"`       // only tasks assigned to the caller
+  Checklist   string `form:"checklist" json:"checklist" binding:"omitempty,oneof=open done"`    // open: unchecked items left, done: every item checked
+  Archived    string `form:"archived" json:"archived" binding:"omitempty,oneof=true false all"` // archived tasks are left out unless true or all
+}
+
+// TrashQueryParams - for GET /tasks/trash
+type TrashQueryParams struct {
+  Page  int `form:"page" binding:"omitempty,min=1"`
+  Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// DeleteTaskQueryParams - for DELETE /tasks/:id
+type DeleteTaskQueryParams struct {
+  Permanent bool `form:"permanent"` // skip the trash and delete for good
+}
+
+// AssignTaskInput - for POST /tasks/:id/assignees
+type AssignTaskInput struct {
+  UserID string `json:"user_id" binding:"required,mongodb"`
 }
 
 // ========== OUTPUT DTOs ==========
 
 // TaskResponse - for response API
 type TaskResponse struct {
-  ID          string     `json:"id"`
-  UserID      string     `json:"user_id"`
-  Title       string     `json:"title"`
-  Description string     `json:"description"`
-  Status      string     `json:"status"`
-  Priority    string     `json:"priority"`
-  DueDate     *time.Time `json:"due_date,omitempty"`
-  Tags        []string   `json:"tags"`
-  CreatedAt   time.Time  `json:"created_at"`
-  UpdatedAt   time.Time  `json:"updated_at"`
-  CompletedAt *time.Time `json:"completed_at,omitempty"`
+  ID          string                  `json:"id"`
+  UserID      string                  `json:"user_id"`
+  WorkspaceID string                  `json:"workspace_id,omitempty"`
+  ParentID    string                  `json:"parent_id,omitempty"`
+  Title       string                  `json:"title"`
+  Description string                  `json:"description"`
+  Status      string                  `json:"status"`
+  Priority    string                  `json:"priority"`
+  DueDate     *time.Time              `json:"due_date,omitempty"`
+  Tags        []string                `json:"tags"`
+  AssigneeIDs []string                `json:"assignee_ids"`
+  WatcherIDs  []string                `json:"watcher_ids"`
+  BlockedBy   []string                `json:"blocked_by"`
+  Recurrence  string                  `json:"recurrence,omitempty"`
+  SeriesID    string                  `json:"series_id,omitempty"`
+  Occurrence  int                     `json:"occurrence,omitempty"`
+  Checklist   []ChecklistItemResponse `json:"checklist"`
+  Checked     *TaskProgress           `json:"checklist_progress,omitempty"` // only for tasks with a checklist
+  Attachments []AttachmentResponse    `json:"attachments"`
+  CreatedAt   time.Time               `json:"created_at"`
+  UpdatedAt   time.Time               `json:"updated_at"`
+  Version     int64                   `json:"version"`
+  CompletedAt *time.Time              `json:"completed_at,omitempty"`
+  ArchivedAt  *time.Time              `json:"archived_at,omitempty"`
+  DeletedAt   *time.Time              `json:"deleted_at,omitempty"`         // only for tasks in the trash
+  Progress    *TaskProgress           `json:"progress,omitempty"`           // only for tasks with subtasks
+  NextID      string                  `json:"next_occurrence_id,omitempty"` // set when completing created the next occurrence
+}
+
+// TaskProgress - completion roll-up of direct subtasks
+type TaskProgress struct {
+  Total     int `json:"total"`
+  Completed int `json:"completed"`
+  Percent   int `json:"percent"`
 }
 
 // TaskListResponse - for list with pagination
@@ -67,8 +115,25 @@ type TaskListResponse struct {
 
 // ToTaskResponse - convert models.Task to types.TaskResponse
 func ToTaskResponse(task *models.Task) TaskResponse {
+  workspaceID := ""
+  if task.WorkspaceID != nil {
+    workspaceID = task.WorkspaceID.Hex()
+  }
+
+  parentID := ""
+  if task.ParentID != nil {
+    parentID = task.ParentID.Hex()
+  }
+
+  seriesID, occurrence := "", 0
+  if task.Series != nil {
+    seriesID, occurrence = task.Series.ID.Hex(), task.Series.Occurrence
+  }
+
   return TaskResponse{
     ID:          task.ID.Hex(),
+    WorkspaceID: workspaceID,
+    ParentID:    parentID,
     UserID:      task.UserID.Hex(),
     Title:       task.Title,
     Description: task.Description,
@@ -76,9 +141,21 @@ func ToTaskResponse(task *models.Task) TaskResponse {
     Priority:    task.Priority,
     DueDate:     task.DueDate,
     Tags:        task.Tags,
+    AssigneeIDs: toHexList(task.AssigneeIDs),
+    WatcherIDs:  toHexList(task.WatcherIDs),
+    BlockedBy:   toHexList(task.BlockedBy),
+    Recurrence:  task.Recurrence,
+    SeriesID:    seriesID,
+    Occurrence:  occurrence,
+    Checklist:   ToChecklistResponse(task.Checklist),
+    Checked:     checklistProgress(task.Checklist),
+    Attachments: ToAttachmentResponses(task.ID, task.Attachments),
     CreatedAt:   task.CreatedAt,
     UpdatedAt:   task.UpdatedAt,
+    Version:     task.Version,
     CompletedAt: task.CompletedAt,
+    ArchivedAt:  task.ArchivedAt,
+    DeletedAt:   task.DeletedAt,
   }
 }
 
@@ -91,6 +168,24 @@ func ToTaskResponseList(tasks []models.Task) []TaskResponse {
   return responses
 }
 
+// NewTaskProgress - progress with percentage rounded down
+func NewTaskProgress(total, completed int) TaskProgress {
+  percent := 0
+  if total > 0 {
+    percent = completed * 100 / total
+  }
+  return TaskProgress{Total: total, Completed: completed, Percent: percent}
+}
+
+// toHexList - convert IDs to hex strings, never nil
+func toHexList(ids []bson.ObjectID) []string {
+  hexIDs := make([]string, len(ids))
+  for i, id := range ids {
+    hexIDs[i] = id.Hex()
+  }
+  return hexIDs
+}
+
 // ToTask - convert CreateTaskInput to models.Task
 func (input *CreateTaskInput) ToTask(userID bson.ObjectID) models.Task {
   now := time.Now()
@@ -119,6 +214,7 @@ func (input *CreateTaskInput) ToTask(userID bson.ObjectID) models.Task {
     Priority:    priority,
     DueDate:     input.DueDate,
     Tags:        tags,
+    Recurrence:  input.Recurrence,
     CreatedAt:   now,
     UpdatedAt:   now,
   }
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"task-api/models"
+)
+
+// ========== INPUT DTOs ==========
+
+// TaskHistoryQueryParams - for GET /tasks/:id/history
+type TaskHistoryQueryParams struct {
+  Cursor string `form:"cursor" binding:"omitempty,mongodb"` // next_cursor of the previous page
+  Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ========== OUTPUT DTOs ==========
+
+// FieldChangeResponse - old and new value of a field, null when unset
+type FieldChangeResponse struct {
+  Field string `json:"field"`
+  Old   any    `json:"old"`
+  New   any    `json:"new"`
+}
+
+// TaskEventResponse - entry in the history of a task
+type TaskEventResponse struct {
+  ID        string                `json:"id"`
+  TaskID    string                `json:"task_id"`
+  ActorID   string                `json:"actor_id"`
+  Action    string                `json:"action"`
+  Changes   []FieldChangeResponse `json:"changes"`
+  CreatedAt time.Time             `json:"created_at"`
+}
+
+// TaskHistoryResponse - for GET /tasks/:id/history, newest first
+type TaskHistoryResponse struct {
+  Events     []TaskEventResponse `json:"events"`
+  NextCursor string              `json:"next_cursor,omitempty"` // empty on the last page
+}
+
+// ========== CONVERTERS ==========
+
+// ToTaskEventResponse - convert task event model to response
+func ToTaskEventResponse(event *models.TaskEvent) TaskEventResponse {
+  changes := make([]FieldChangeResponse, 0, len(event.Changes))
+  for _, change := range event.Changes {
+    changes = append(changes, FieldChangeResponse{
+      Field: change.Field,
+      Old:   eventValue(change.Old),
+      New:   eventValue(change.New),
+    })
+  }
+
+  return TaskEventResponse{
+    ID:        event.ID.Hex(),
+    TaskID:    event.TaskID.Hex(),
+    ActorID:   event.ActorID.Hex(),
+    Action:    event.Action,
+    Changes:   changes,
+    CreatedAt: event.CreatedAt,
+  }
+}
+
+// eventValue - stored value as plain JSON, dates decode from the database as bson.DateTime
+func eventValue(value any) any {
+  switch v := value.(type) {
+  case bson.DateTime:
+    return v.Time().UTC()
+  case bson.ObjectID:
+    return v.Hex()
+  case bson.A:
+    values := make([]any, 0, len(v))
+    for _, item := range v {
+      values = append(values, eventValue(item))
+    }
+    return values
+  default:
+    return v
+  }
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"time"
+
+	"task-api/models"
+)
+
+// ========== INPUT DTOs ==========
+
+// ShareTaskInput - for POST /tasks/:id/shares
+type ShareTaskInput struct {
+  Email      string `json:"email" binding:"required,email"`
+  Permission string `json:"permission" binding:"required,oneof=view edit owner"`
+}
+
+// SharedTaskQueryParams - for GET /tasks/shared
+type SharedTaskQueryParams struct {
+  Page  int `form:"page" binding:"omitempty,min=1"`
+  Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ========== OUTPUT DTOs ==========
+
+// TaskShareResponse - user a task is shared with
+type TaskShareResponse struct {
+  UserID     string    `json:"user_id"`
+  Email      string    `json:"email"`
+  Name       string    `json:"name"`
+  Permission string    `json:"permission"`
+  SharedBy   string    `json:"shared_by"`
+  SharedAt   time.Time `json:"shared_at"`
+}
+
+// SharedTaskResponse - task shared with the caller and the granted permission
+type SharedTaskResponse struct {
+  TaskResponse
+  Permission string    `json:"permission"`
+  SharedBy   string    `json:"shared_by"`
+  SharedAt   time.Time `json:"shared_at"`
+}
+
+// SharedTaskListResponse - for GET /tasks/shared
+type SharedTaskListResponse struct {
+  Tasks []SharedTaskResponse `json:"tasks"`
+  Meta  PaginationMeta       `json:"meta"`
+}
+
+// ========== CONVERTERS ==========
+
+// ToTaskShareResponse - convert share and user to response
+func ToTaskShareResponse(share *models.TaskShare, user *models.User) TaskShareResponse {
+  return TaskShareResponse{
+    UserID:     share.UserID.Hex(),
+    Email:      user.Email,
+    Name:       user.Name,
+    Permission: share.Permission,
+    SharedBy:   share.SharedBy.Hex(),
+    SharedAt:   share.CreatedAt,
+  }
+}
+
+// ToSharedTaskResponse - convert task and share to response
+func ToSharedTaskResponse(task *models.Task, share *models.TaskShare) SharedTaskResponse {
+  return SharedTaskResponse{
+    TaskResponse: ToTaskResponse(task),
+    Permission:   share.Permission,
+    SharedBy:     share.SharedBy.Hex(),
+    SharedAt:     share.CreatedAt,
+  }
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"time"
+
+	"task-api/models"
+)
+
+// ========== INPUT DTOs ==========
+
+// CreatePersonalAccessTokenInput - for POST /auth/tokens
+type CreatePersonalAccessTokenInput struct {
+  Name          string   `json:"name" binding:"required,min=1,max=100"`
+  Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=tasks:read tasks:write"`
+  ExpiresInDays *int     `json:"expires_in_days" binding:"omitempty,min=1,max=365"` // omit for a token that never expires
+}
+
+// ========== OUTPUT DTOs ==========
+
+// PersonalAccessTokenResponse - token metadata, never contains the secret
+type PersonalAccessTokenResponse struct {
+  ID         string     `json:"id"`
+  Name       string     `json:"name"`
+  Prefix     string     `json:"prefix"`
+  Scopes     []string   `json:"scopes"`
+  ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+  LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+  CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatePersonalAccessTokenResponse - metadata plus the token, shown only once
+type CreatePersonalAccessTokenResponse struct {
+  PersonalAccessTokenResponse
+  Token string `json:"token"`
+}
+
+// ========== CONVERTERS ==========
+
+// ToPersonalAccessTokenResponse - convert models.PersonalAccessToken to response
+func ToPersonalAccessTokenResponse(token *models.PersonalAccessToken) PersonalAccessTokenResponse {
+  return PersonalAccessTokenResponse{
+    ID:         token.ID.Hex(),
+    Name:       token.Name,
+    Prefix:     token.Prefix,
+    Scopes:     token.Scopes,
+    ExpiresAt:  token.ExpiresAt,
+    LastUsedAt: token.LastUsedAt,
+    CreatedAt:  token.CreatedAt,
+  }
+}
+
+// ToPersonalAccessTokenResponseList - convert slice
+func ToPersonalAccessTokenResponseList(tokens []models.PersonalAccessToken) []PersonalAccessTokenResponse {
+  responses := make([]PersonalAccessTokenResponse, len(tokens))
+  for i := range tokens {
+    responses[i] = ToPersonalAccessTokenResponse(&tokens[i])
+  }
+  return responses
+}
@@ -13,27 +13,108 @@ import (
 type LoginInput struct {
   Email    string `json:"email" binding:"required,email"`
   Password string `json:"password" binding:"required,min=8"`
+  ClientIP string `json:"-"` // Set by handler for brute-force protection
+}
+
+// RegisterInput - request body for register
+type RegisterInput struct {
+  Email    string `json:"email" binding:"required,email"`
+  Password string `json:"password" binding:"required,min=8,max=72"`
+  Name     string `json:"name" binding:"required,min=2,max=100"`
+}
+
+// RefreshInput - request body for token refresh
+type RefreshInput struct {
+  RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutInput - optional body for logout, also revokes the refresh token family
+type LogoutInput struct {
+  RefreshToken string `json:"refresh_token"`
+}
+
+// ForgotPasswordInput - request body for forgot password
+type ForgotPasswordInput struct {
+  Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordInput - request body for reset password
+type ResetPasswordInput struct {
+  Token    string `json:"token" binding:"required"`
+  Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// UpdateProfileInput - request body for PATCH /auth/me
+type UpdateProfileInput struct {
+  Name     *string `json:"name" binding:"omitempty,min=2,max=100"`
+  Timezone *string `json:"timezone" binding:"omitempty,max=64"`
+  Locale   *string `json:"locale" binding:"omitempty,bcp47_language_tag"`
+}
+
+// ChangePasswordInput - request body for change password
+type ChangePasswordInput struct {
+  CurrentPassword string `json:"current_password" binding:"required"`
+  NewPassword     string `json:"new_password" binding:"required,min=8,max=72"`
+}
+
+// MFAVerifyInput - request body to confirm TOTP enrollment
+type MFAVerifyInput struct {
+  Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// MFALoginInput - second login step, exchanges the mfa token and a code for a session
+type MFALoginInput struct {
+  MFAToken     string `json:"mfa_token" binding:"required"`
+  Code         string `json:"code" binding:"required_without=RecoveryCode"`
+  RecoveryCode string `json:"recovery_code" binding:"required_without=Code"`
+  ClientIP     string `json:"-"` // Set by handler for brute-force protection
 }
 
 // ========== OUTPUT DTOs ==========
 
-// JWTClaims - JWT claims structure
+// JWTClaims - JWT claims structure,
+// RegisteredClaims.ID carries the jti used for revocation.
+// Purpose is empty for access tokens, "mfa" for pending 2FA logins.
+// Role is the user's role when the token was issued.
 type JWTClaims struct {
-  UserID bson.ObjectID `json:"user_id"`
-  Email  string        `json:"email"`
+  UserID  bson.ObjectID `json:"user_id"`
+  Email   string        `json:"email"`
+  Role    string        `json:"role,omitempty"`
+  Purpose string        `json:"purpose,omitempty"`
   jwt.RegisteredClaims
 }
 
 // UserResponse - response user
 type UserResponse struct {
+  ID        string    `json:"id,omitempty"`
   Email     string    `json:"email"`
   Name      string    `json:"name"`
+  Timezone  string    `json:"timezone,omitempty"`
+  Locale    string    `json:"locale,omitempty"`
+  MFAEnabled bool     `json:"mfa_enabled"`
+  Role      string    `json:"role"`
 }
 
-// LoginResponse - response login with token (final response)
+// LoginResponse - response login with token (final response),
+// or only MFARequired and MFAToken when a second factor is still needed
 type LoginResponse struct {
-  Token string       `json:"token"`
-  User  UserResponse `json:"user"`
+  Token        string       `json:"token,omitempty"`
+  RefreshToken string       `json:"refresh_token,omitempty"`
+  ExpiresIn    int64        `json:"expires_in,omitempty"` // access token lifetime in seconds
+  User         UserResponse `json:"user"`
+  MFARequired  bool         `json:"mfa_required,omitempty"`
+  MFAToken     string       `json:"mfa_token,omitempty"`
+}
+
+// MFASetupResponse - secret to enroll in an authenticator app
+type MFASetupResponse struct {
+  Secret     string `json:"secret"`
+  OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFAVerifyResponse - recovery codes, shown only once
+type MFAVerifyResponse struct {
+  RecoveryCodes []string `json:"recovery_codes"`
 }
 
 // ========== CONVERTERS ==========
@@ -41,7 +122,20 @@ type LoginResponse struct {
 // ToUserResponse - convert models.User to types.UserResponse
 func ToUserResponse(user *models.User) UserResponse {
   return UserResponse{
+    ID:        user.ID.Hex(),
     Email:     user.Email,
     Name:      user.Name,
+    Timezone:  user.Timezone,
+    Locale:    user.Locale,
+    MFAEnabled: user.MFAEnabled,
+    Role:      EffectiveRole(user.Role),
+  }
+}
+
+// EffectiveRole - stored role, accounts created before roles existed are members
+func EffectiveRole(role string) string {
+  if role == "" {
+    return RoleMember
   }
+  return role
 }
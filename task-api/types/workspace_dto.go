@@ -0,0 +1,62 @@
+package types
+
+import (
+	"time"
+
+	"task-api/models"
+)
+
+// ========== INPUT DTOs ==========
+
+// CreateWorkspaceInput - for POST /workspaces
+type CreateWorkspaceInput struct {
+  Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// AddWorkspaceMemberInput - for POST /workspaces/:wid/members
+type AddWorkspaceMemberInput struct {
+  Email string `json:"email" binding:"required,email"`
+  Role  string `json:"role" binding:"omitempty,oneof=owner member"`
+}
+
+// ========== OUTPUT DTOs ==========
+
+// WorkspaceResponse - workspace with the caller's role in it
+type WorkspaceResponse struct {
+  ID        string    `json:"id"`
+  Name      string    `json:"name"`
+  Role      string    `json:"role"`
+  CreatedAt time.Time `json:"created_at"`
+}
+
+// WorkspaceMemberResponse - member with basic profile
+type WorkspaceMemberResponse struct {
+  UserID   string    `json:"user_id"`
+  Email    string    `json:"email"`
+  Name     string    `json:"name"`
+  Role     string    `json:"role"`
+  JoinedAt time.Time `json:"joined_at"`
+}
+
+// ========== CONVERTERS ==========
+
+// ToWorkspaceResponse - convert models.Workspace to response for a member with role
+func ToWorkspaceResponse(workspace *models.Workspace, role string) WorkspaceResponse {
+  return WorkspaceResponse{
+    ID:        workspace.ID.Hex(),
+    Name:      workspace.Name,
+    Role:      role,
+    CreatedAt: workspace.CreatedAt,
+  }
+}
+
+// ToWorkspaceMemberResponse - convert membership and user to response
+func ToWorkspaceMemberResponse(member *models.WorkspaceMember, user *models.User) WorkspaceMemberResponse {
+  return WorkspaceMemberResponse{
+    UserID:   member.UserID.Hex(),
+    Email:    user.Email,
+    Name:     user.Name,
+    Role:     member.Role,
+    JoinedAt: member.CreatedAt,
+  }
+}
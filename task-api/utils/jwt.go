@@ -2,7 +2,7 @@ package utils
 
 import (
 	"errors"
-	"os"
+	"task-api/configs"
 	"task-api/types"
 	"time"
 
@@ -10,37 +10,65 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-// GenerateToken - generate JWT token
-func GenerateToken(userID bson.ObjectID, email string) (string, error) {
-  jwtSecret := os.Getenv("JWT_SECRET")
-  if jwtSecret == "" {
-    return "", errors.New("JWT_SECRET not configured")
+// AccessTokenTTL - lifetime of access tokens, short because refresh tokens renew them
+func AccessTokenTTL() time.Duration {
+  return configs.GetDuration("ACCESS_TOKEN_TTL", 15*time.Minute)
+}
+
+// RefreshTokenTTL - lifetime of refresh tokens
+func RefreshTokenTTL() time.Duration {
+  return configs.GetDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+}
+
+// TokenPurposeMFA - purpose claim of tokens that only prove the password step of a 2FA login
+const TokenPurposeMFA = "mfa"
+
+// MFATokenTTL - time the user has to enter the second factor
+func MFATokenTTL() time.Duration {
+  return configs.GetDuration("MFA_TOKEN_TTL", 5*time.Minute)
+}
+
+// GenerateToken - generate JWT token carrying the user's role
+func GenerateToken(userID bson.ObjectID, email, role string) (string, error) {
+  return signToken(userID, email, role, "", AccessTokenTTL())
+}
+
+// GenerateMFAToken - generate short-lived "mfa pending" token, rejected by AuthMiddleware
+func GenerateMFAToken(userID bson.ObjectID, email string) (string, error) {
+  return signToken(userID, email, "", TokenPurposeMFA, MFATokenTTL())
+}
+
+// signToken - sign claims for user with given role, purpose and lifetime
+func signToken(userID bson.ObjectID, email, role, purpose string, ttl time.Duration) (string, error) {
+  keys, err := currentKeys()
+  if err != nil {
+    return "", err
   }
 
 	// set token data
   claims := types.JWTClaims{
-    UserID: userID,
-    Email:  email,
+    UserID:  userID,
+    Email:   email,
+    Role:    role,
+    Purpose: purpose,
     RegisteredClaims: jwt.RegisteredClaims{
-      ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+      ID:        bson.NewObjectID().Hex(), // jti, used for revocation
+      ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
       IssuedAt:  jwt.NewNumericDate(time.Now()),
     },
   }
 
-  token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-  return token.SignedString([]byte(jwtSecret))
+  return keys.sign(claims)
 }
 
-// ValidateToken - validate JWT token
+// ValidateToken - validate JWT token against the key named by its kid header
 func ValidateToken(tokenString string) (*types.JWTClaims, error) {
-  jwtSecret := os.Getenv("JWT_SECRET")
-  if jwtSecret == "" {
-    return nil, errors.New("JWT_SECRET not configured")
+  keys, err := currentKeys()
+  if err != nil {
+    return nil, err
   }
 
-  token, err := jwt.ParseWithClaims(tokenString, &types.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-    return []byte(jwtSecret), nil
-  })
+  token, err := jwt.ParseWithClaims(tokenString, &types.JWTClaims{}, keys.keyFunc)
 
   if err != nil {
     return nil, err
@@ -53,3 +81,15 @@ func ValidateToken(tokenString string) (*types.JWTClaims, error) {
 
   return claims, nil
 }
+
+// ValidateMFAToken - validate token issued by GenerateMFAToken
+func ValidateMFAToken(tokenString string) (*types.JWTClaims, error) {
+  claims, err := ValidateToken(tokenString)
+  if err != nil {
+    return nil, err
+  }
+  if claims.Purpose != TokenPurposeMFA {
+    return nil, errors.New("invalid token")
+  }
+  return claims, nil
+}
@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+
+	"task-api/configs"
+	"task-api/types"
+)
+
+// minRSAKeyBits - smaller RSA keys are refused when loading the key directory
+const minRSAKeyBits = 2048
+
+// jwtKey - one key of the set, Private is nil for verify-only (retired) keys
+type jwtKey struct {
+  ID      string
+  Method  jwt.SigningMethod
+  Private crypto.PrivateKey
+  Public  crypto.PublicKey
+}
+
+// KeySet - signing key plus every key still accepted for verification
+type KeySet struct {
+  signing *jwtKey
+  verify  map[string]*jwtKey // by kid, "" is the legacy HS256 secret
+}
+
+var (
+  keysMu     sync.RWMutex
+  activeKeys *KeySet
+)
+
+// NewHMACKeySet - HS256 key set from a shared secret, tokens carry no kid
+func NewHMACKeySet(secret string) *KeySet {
+  key := &jwtKey{Method: jwt.SigningMethodHS256, Private: []byte(secret), Public: []byte(secret)}
+  return &KeySet{
+    signing: key,
+    verify:  map[string]*jwtKey{"": key},
+  }
+}
+
+// LoadKeySet - read every *.pem file in dir, the file name without extension is the kid.
+// Private keys (PKCS#8 RSA/Ed25519 or PKCS#1 RSA) can sign, PUBLIC KEY files only verify,
+// which keeps tokens of a retired key valid until they expire. signingKID picks the
+// signing key, by default the private key whose kid sorts last (e.g. date-named files).
+func LoadKeySet(dir, signingKID string) (*KeySet, error) {
+  paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+  if err != nil {
+    return nil, err
+  }
+  sort.Strings(paths)
+
+  set := &KeySet{verify: make(map[string]*jwtKey)}
+  for _, path := range paths {
+    kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+
+    key, err := loadPEMKey(path, kid)
+    if err != nil {
+      return nil, fmt.Errorf("load key %s: %w", path, err)
+    }
+    set.verify[kid] = key
+
+    if key.Private != nil && (signingKID == "" || signingKID == kid) {
+      set.signing = key
+    }
+  }
+
+  if set.signing == nil {
+    if signingKID != "" {
+      return nil, fmt.Errorf("signing key %q not found in %s", signingKID, dir)
+    }
+    return nil, fmt.Errorf("no private key found in %s", dir)
+  }
+
+  return set, nil
+}
+
+// loadPEMKey - parse a single PEM encoded key
+func loadPEMKey(path, kid string) (*jwtKey, error) {
+  data, err := os.ReadFile(path)
+  if err != nil {
+    return nil, err
+  }
+
+  block, _ := pem.Decode(data)
+  if block == nil {
+    return nil, errors.New("no PEM block found")
+  }
+
+  var parsed interface{}
+  switch block.Type {
+  case "PRIVATE KEY":
+    parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+  case "RSA PRIVATE KEY":
+    parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+  case "PUBLIC KEY":
+    parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+  default:
+    return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+  }
+  if err != nil {
+    return nil, err
+  }
+
+  switch k := parsed.(type) {
+  case *rsa.PrivateKey:
+    if k.N.BitLen() < minRSAKeyBits {
+      return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+    }
+    return &jwtKey{ID: kid, Method: jwt.SigningMethodRS256, Private: k, Public: &k.PublicKey}, nil
+  case *rsa.PublicKey:
+    if k.N.BitLen() < minRSAKeyBits {
+      return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+    }
+    return &jwtKey{ID: kid, Method: jwt.SigningMethodRS256, Public: k}, nil
+  case ed25519.PrivateKey:
+    return &jwtKey{ID: kid, Method: jwt.SigningMethodEdDSA, Private: k, Public: k.Public()}, nil
+  case ed25519.PublicKey:
+    return &jwtKey{ID: kid, Method: jwt.SigningMethodEdDSA, Public: k}, nil
+  default:
+    return nil, fmt.Errorf("unsupported key type %T", parsed)
+  }
+}
+
+// KeySetFromEnv - JWT_KEY_DIR (asymmetric, optional JWT_SIGNING_KEY_ID) or JWT_SECRET (HS256).
+// When both are set the secret stays verify-only so HS256 tokens survive a migration.
+func KeySetFromEnv() (*KeySet, error) {
+  secret := os.Getenv("JWT_SECRET")
+
+  dir := configs.GetEnv("JWT_KEY_DIR", "")
+  if dir == "" {
+    if secret == "" {
+      return nil, errors.New("JWT_SECRET or JWT_KEY_DIR must be configured")
+    }
+    return NewHMACKeySet(secret), nil
+  }
+
+  set, err := LoadKeySet(dir, configs.GetEnv("JWT_SIGNING_KEY_ID", ""))
+  if err != nil {
+    return nil, err
+  }
+  if secret != "" {
+    set.verify[""] = NewHMACKeySet(secret).signing
+  }
+  return set, nil
+}
+
+// InitKeys - load key set from env, call again (e.g. on SIGHUP) after rotating key files
+func InitKeys() error {
+  set, err := KeySetFromEnv()
+  if err != nil {
+    return err
+  }
+
+  SetKeySet(set)
+  log.Info().
+    Str("kid", set.signing.ID).
+    Str("alg", set.signing.Method.Alg()).
+    Int("verification_keys", len(set.verify)).
+    Msg("JWT keys loaded")
+  return nil
+}
+
+// SetKeySet - replace active keys
+func SetKeySet(set *KeySet) {
+  keysMu.Lock()
+  defer keysMu.Unlock()
+  activeKeys = set
+}
+
+// currentKeys - active key set, loaded from env on first use
+func currentKeys() (*KeySet, error) {
+  keysMu.RLock()
+  set := activeKeys
+  keysMu.RUnlock()
+  if set != nil {
+    return set, nil
+  }
+
+  if err := InitKeys(); err != nil {
+    return nil, err
+  }
+  return currentKeys()
+}
+
+// sign - sign claims with the signing key, adding its kid header
+func (ks *KeySet) sign(claims jwt.Claims) (string, error) {
+  token := jwt.NewWithClaims(ks.signing.Method, claims)
+  if ks.signing.ID != "" {
+    token.Header["kid"] = ks.signing.ID
+  }
+  return token.SignedString(ks.signing.Private)
+}
+
+// keyFunc - pick verification key by kid and refuse algorithms other than the key's own
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+  kid, _ := token.Header["kid"].(string)
+
+  key, ok := ks.verify[kid]
+  if !ok {
+    return nil, fmt.Errorf("unknown key id %q", kid)
+  }
+  if token.Method.Alg() != key.Method.Alg() {
+    return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+  }
+  return key.Public, nil
+}
+
+// JWKS - public keys for other services, symmetric secrets are never published
+func (ks *KeySet) JWKS() types.JWKS {
+  kids := make([]string, 0, len(ks.verify))
+  for kid := range ks.verify {
+    kids = append(kids, kid)
+  }
+  sort.Strings(kids)
+
+  jwks := types.JWKS{Keys: []types.JWK{}}
+  for _, kid := range kids {
+    key := ks.verify[kid]
+    jwk := types.JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+
+    switch pub := key.Public.(type) {
+    case *rsa.PublicKey:
+      jwk.Kty = "RSA"
+      jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+      jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+    case ed25519.PublicKey:
+      jwk.Kty = "OKP"
+      jwk.Crv = "Ed25519"
+      jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+    default:
+      continue
+    }
+
+    jwks.Keys = append(jwks.Keys, jwk)
+  }
+
+  return jwks
+}
+
+// CurrentJWKS - JWKS of the active key set
+func CurrentJWKS() (types.JWKS, error) {
+  set, err := currentKeys()
+  if err != nil {
+    return types.JWKS{}, err
+  }
+  return set.JWKS(), nil
+}
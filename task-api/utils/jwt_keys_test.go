@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// writePEM - write key to dir/<kid>.pem
+func writePEM(t *testing.T, dir, kid, blockType string, der []byte) {
+  data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+  assert.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0600))
+}
+
+// withKeySet - run test with keys active, restoring the env based set afterwards
+func withKeySet(t *testing.T, set *KeySet) {
+  SetKeySet(set)
+  t.Cleanup(func() { SetKeySet(nil) })
+}
+
+func TestLoadKeySet(t *testing.T) {
+  dir := t.TempDir()
+
+  rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+  assert.NoError(t, err)
+  rsaDER, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+  writePEM(t, dir, "2026-01-rsa", "PRIVATE KEY", rsaDER)
+
+  _, edKey, err := ed25519.GenerateKey(rand.Reader)
+  assert.NoError(t, err)
+  edDER, _ := x509.MarshalPKCS8PrivateKey(edKey)
+  writePEM(t, dir, "2026-02-ed25519", "PRIVATE KEY", edDER)
+
+  retiredKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+  retiredDER, _ := x509.MarshalPKIXPublicKey(&retiredKey.PublicKey)
+  writePEM(t, dir, "2025-12-retired", "PUBLIC KEY", retiredDER)
+
+  t.Run("should sign with the last private key and set kid", func(t *testing.T) {
+    set, err := LoadKeySet(dir, "")
+    assert.NoError(t, err)
+    withKeySet(t, set)
+
+    userID := bson.NewObjectID()
+    token, err := GenerateToken(userID, "test@test.com", "member")
+    assert.NoError(t, err)
+
+    parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+    assert.NoError(t, err)
+    assert.Equal(t, "2026-02-ed25519", parsed.Header["kid"])
+    assert.Equal(t, "EdDSA", parsed.Header["alg"])
+
+    claims, err := ValidateToken(token)
+    assert.NoError(t, err)
+    assert.Equal(t, userID, claims.UserID)
+  })
+
+  t.Run("should sign with configured key and keep verifying the others", func(t *testing.T) {
+    rsaSet, err := LoadKeySet(dir, "2026-01-rsa")
+    assert.NoError(t, err)
+    withKeySet(t, rsaSet)
+
+    token, err := GenerateToken(bson.NewObjectID(), "test@test.com", "member")
+    assert.NoError(t, err)
+
+    parsed, _, _ := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+    assert.Equal(t, "RS256", parsed.Header["alg"])
+
+    // Rotated to the Ed25519 key: RS256 token still validates
+    edSet, _ := LoadKeySet(dir, "2026-02-ed25519")
+    SetKeySet(edSet)
+
+    _, err = ValidateToken(token)
+    assert.NoError(t, err)
+  })
+
+  t.Run("should refuse public-only key as signing key", func(t *testing.T) {
+    _, err := LoadKeySet(dir, "2025-12-retired")
+    assert.Error(t, err)
+  })
+
+  t.Run("should reject unknown kid and mismatched algorithm", func(t *testing.T) {
+    set, _ := LoadKeySet(dir, "")
+    withKeySet(t, set)
+
+    unknown := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.RegisteredClaims{})
+    unknown.Header["kid"] = "does-not-exist"
+    signed, _ := unknown.SignedString(edKey)
+
+    _, err := ValidateToken(signed)
+    assert.Error(t, err)
+
+    // HS256 token using the RSA kid must not be accepted
+    confused := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{})
+    confused.Header["kid"] = "2026-01-rsa"
+    signed, _ = confused.SignedString([]byte("secret"))
+
+    _, err = ValidateToken(signed)
+    assert.Error(t, err)
+  })
+
+  t.Run("should publish public keys as JWKS", func(t *testing.T) {
+    set, _ := LoadKeySet(dir, "")
+
+    jwks := set.JWKS()
+
+    assert.Len(t, jwks.Keys, 3)
+    assert.Equal(t, "2025-12-retired", jwks.Keys[0].Kid)
+    assert.Equal(t, "RSA", jwks.Keys[1].Kty)
+    assert.Equal(t, "RS256", jwks.Keys[1].Alg)
+    assert.Equal(t, "AQAB", jwks.Keys[1].E)
+    assert.Equal(t, "OKP", jwks.Keys[2].Kty)
+    assert.Equal(t, "Ed25519", jwks.Keys[2].Crv)
+    assert.NotEmpty(t, jwks.Keys[2].X)
+  })
+
+  t.Run("should refuse weak RSA keys", func(t *testing.T) {
+    weakDir := t.TempDir()
+    weakKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+    writePEM(t, weakDir, "weak", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(weakKey))
+
+    _, err := LoadKeySet(weakDir, "")
+    assert.Error(t, err)
+  })
+}
+
+func TestNewHMACKeySet(t *testing.T) {
+  set := NewHMACKeySet("test-secret")
+  withKeySet(t, set)
+
+  token, err := GenerateToken(bson.NewObjectID(), "test@test.com", "member")
+  assert.NoError(t, err)
+
+  parsed, _, _ := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+  assert.Nil(t, parsed.Header["kid"])
+
+  _, err = ValidateToken(token)
+  assert.NoError(t, err)
+
+  // Shared secrets are never published
+  assert.Empty(t, set.JWKS().Keys)
+}
@@ -25,7 +25,7 @@ func TestGenerateToken(t *testing.T) {
     userID := bson.NewObjectID()
     email := "test@test.com"
 
-    token, err := GenerateToken(userID, email)
+    token, err := GenerateToken(userID, email, "member")
 
     assert.NoError(t, err)
     assert.NotEmpty(t, token)
@@ -36,18 +36,30 @@ func TestGenerateToken(t *testing.T) {
     userID1 := bson.NewObjectID()
     userID2 := bson.NewObjectID()
 
-    token1, _ := GenerateToken(userID1, "test@test.com")
-    token2, _ := GenerateToken(userID2, "test@test.com")
+    token1, _ := GenerateToken(userID1, "test@test.com", "member")
+    token2, _ := GenerateToken(userID2, "test@test.com", "member")
 
     assert.NotEqual(t, token1, token2)
   })
+
+  t.Run("should give every token a unique jti", func(t *testing.T) {
+    userID := bson.NewObjectID()
+
+    token1, _ := GenerateToken(userID, "test@test.com", "member")
+    token2, _ := GenerateToken(userID, "test@test.com", "member")
+
+    claims1, _ := ValidateToken(token1)
+    claims2, _ := ValidateToken(token2)
+
+    assert.NotEqual(t, claims1.ID, claims2.ID)
+  })
 }
 
 func TestValidateToken(t *testing.T) {
   t.Run("should validate correct token", func(t *testing.T) {
     userID := bson.NewObjectID()
     email := "test@test.com"
-    token, _ := GenerateToken(userID, email)
+    token, _ := GenerateToken(userID, email, "admin")
 
     claims, err := ValidateToken(token)
 
@@ -55,6 +67,8 @@ func TestValidateToken(t *testing.T) {
     assert.NotNil(t, claims)
     assert.Equal(t, userID, claims.UserID)
     assert.Equal(t, email, claims.Email)
+    assert.Equal(t, "admin", claims.Role)
+    assert.NotEmpty(t, claims.ID)
   })
 
   t.Run("should reject invalid token", func(t *testing.T) {
@@ -64,3 +78,26 @@ func TestValidateToken(t *testing.T) {
     assert.Nil(t, claims)
   })
 }
+
+func TestValidateMFAToken(t *testing.T) {
+  t.Run("should accept mfa pending token", func(t *testing.T) {
+    userID := bson.NewObjectID()
+    token, err := GenerateMFAToken(userID, "test@test.com")
+    assert.NoError(t, err)
+
+    claims, err := ValidateMFAToken(token)
+
+    assert.NoError(t, err)
+    assert.Equal(t, userID, claims.UserID)
+    assert.Equal(t, TokenPurposeMFA, claims.Purpose)
+  })
+
+  t.Run("should reject access token", func(t *testing.T) {
+    token, _ := GenerateToken(bson.NewObjectID(), "test@test.com", "member")
+
+    claims, err := ValidateMFAToken(token)
+
+    assert.Error(t, err)
+    assert.Nil(t, claims)
+  })
+}
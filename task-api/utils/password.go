@@ -1,9 +1,19 @@
 package utils
 
 import (
+	"errors"
+	"strings"
+	"unicode"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Password strength rules
+const (
+  PasswordMinLength = 8
+  PasswordMaxLength = 72 // bcrypt ignores anything beyond 72 bytes
+)
+
 // HashPassword - function for hash password
 func HashPassword(password string) (string, error) {
   bytes, err := bcrypt.GenerateFromPassword([]byte(password), 10)
@@ -15,3 +25,45 @@ func CheckPassword(hashedPassword, password string) bool {
   err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
   return err == nil 
 }
+
+// ValidatePasswordStrength - check password against strength rules,
+// returns an error listing every rule that is not satisfied
+func ValidatePasswordStrength(password string) error {
+  var problems []string
+
+  if len(password) < PasswordMinLength {
+    problems = append(problems, "be at least 8 characters long")
+  }
+
+  if len(password) > PasswordMaxLength {
+    problems = append(problems, "be at most 72 bytes long")
+  }
+
+  var hasUpper, hasLower, hasDigit bool
+  for _, ch := range password {
+    switch {
+    case unicode.IsUpper(ch):
+      hasUpper = true
+    case unicode.IsLower(ch):
+      hasLower = true
+    case unicode.IsDigit(ch):
+      hasDigit = true
+    }
+  }
+
+  if !hasUpper {
+    problems = append(problems, "contain an uppercase letter")
+  }
+  if !hasLower {
+    problems = append(problems, "contain a lowercase letter")
+  }
+  if !hasDigit {
+    problems = append(problems, "contain a digit")
+  }
+
+  if len(problems) > 0 {
+    return errors.New("password must " + strings.Join(problems, ", "))
+  }
+
+  return nil
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -78,3 +79,31 @@ func TestCheckPassword(t *testing.T) {
     assert.False(t, result)
   })
 }
+
+func TestValidatePasswordStrength(t *testing.T) {
+  t.Run("should accept strong password", func(t *testing.T) {
+    err := ValidatePasswordStrength("Password123")
+    assert.NoError(t, err)
+  })
+
+  t.Run("should reject short password", func(t *testing.T) {
+    err := ValidatePasswordStrength("Pa1")
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "at least 8 characters")
+  })
+
+  t.Run("should reject password longer than bcrypt limit", func(t *testing.T) {
+    long := "Aa1" + strings.Repeat("x", 80)
+    err := ValidatePasswordStrength(long)
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "at most 72 bytes")
+  })
+
+  t.Run("should require uppercase, lowercase and digit", func(t *testing.T) {
+    err := ValidatePasswordStrength("password")
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "uppercase letter")
+    assert.Contains(t, err.Error(), "digit")
+    assert.NotContains(t, err.Error(), "lowercase letter")
+  })
+}
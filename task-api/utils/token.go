@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateOpaqueToken - generate a random URL-safe token (256 bits of entropy)
+func GenerateOpaqueToken() (string, error) {
+  bytes := make([]byte, 32)
+  if _, err := rand.Read(bytes); err != nil {
+    return "", err
+  }
+  return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// HashToken - SHA-256 hash of an opaque token, only the hash is stored in database
+func HashToken(token string) string {
+  sum := sha256.Sum256([]byte(token))
+  return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateOpaqueToken(t *testing.T) {
+  t.Run("should generate random URL-safe tokens", func(t *testing.T) {
+    token1, err := GenerateOpaqueToken()
+    assert.NoError(t, err)
+
+    token2, err := GenerateOpaqueToken()
+    assert.NoError(t, err)
+
+    assert.Len(t, token1, 43)
+    assert.NotEqual(t, token1, token2)
+    assert.NotContains(t, token1, "+")
+    assert.NotContains(t, token1, "/")
+  })
+}
+
+func TestHashToken(t *testing.T) {
+  t.Run("should be deterministic", func(t *testing.T) {
+    assert.Equal(t, HashToken("abc"), HashToken("abc"))
+  })
+
+  t.Run("should differ from token and between tokens", func(t *testing.T) {
+    hash := HashToken("abc")
+    assert.NotEqual(t, "abc", hash)
+    assert.NotEqual(t, HashToken("abd"), hash)
+    assert.Len(t, hash, 64)
+  })
+}
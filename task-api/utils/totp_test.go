@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 test secret "12345678901234567890"
+const rfcTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTPCode(t *testing.T) {
+  t.Run("should match RFC 6238 test vectors", func(t *testing.T) {
+    vectors := map[int64]string{
+      59:         "287082",
+      1111111109: "081804",
+      1234567890: "005924",
+      2000000000: "279037",
+    }
+
+    for unix, expected := range vectors {
+      code, err := GenerateTOTPCode(rfcTOTPSecret, TOTPStep(time.Unix(unix, 0)))
+      assert.NoError(t, err)
+      assert.Equal(t, expected, code, "time %d", unix)
+    }
+  })
+
+  t.Run("should reject invalid secret", func(t *testing.T) {
+    _, err := GenerateTOTPCode("not base32!", 1)
+    assert.Error(t, err)
+  })
+}
+
+func TestValidateTOTPCode(t *testing.T) {
+  now := time.Unix(1111111109, 0)
+
+  t.Run("should accept current and adjacent steps", func(t *testing.T) {
+    for _, offset := range []time.Duration{-TOTPPeriod, 0, TOTPPeriod} {
+      code, _ := GenerateTOTPCode(rfcTOTPSecret, TOTPStep(now.Add(offset)))
+
+      step, ok := ValidateTOTPCode(rfcTOTPSecret, code, now)
+      assert.True(t, ok)
+      assert.Equal(t, TOTPStep(now.Add(offset)), step)
+    }
+  })
+
+  t.Run("should reject codes outside the window", func(t *testing.T) {
+    code, _ := GenerateTOTPCode(rfcTOTPSecret, TOTPStep(now.Add(-3*TOTPPeriod)))
+
+    _, ok := ValidateTOTPCode(rfcTOTPSecret, code, now)
+    assert.False(t, ok)
+
+    _, ok = ValidateTOTPCode(rfcTOTPSecret, "12345", now)
+    assert.False(t, ok)
+  })
+}
+
+func TestTOTPURI(t *testing.T) {
+  uri := TOTPURI("Task API", "test@example.com", "ABC")
+
+  assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Task%20API:test@example.com?"))
+  assert.Contains(t, uri, "secret=ABC")
+  assert.Contains(t, uri, "issuer=Task+API")
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+  codes, err := GenerateRecoveryCodes(10)
+
+  assert.NoError(t, err)
+  assert.Len(t, codes, 10)
+  assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, codes[0])
+  assert.NotEqual(t, codes[0], codes[1])
+  assert.Equal(t, codes[0], NormalizeRecoveryCode(strings.ToUpper(strings.ReplaceAll(codes[0], "-", " "))))
+}